@@ -5,53 +5,48 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"flag"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strconv"
+	"sync"
+	"sync/atomic"
 
 	"github.com/golang/snappy"
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/extents"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
+const (
+	defaultStreams = 4
+
+	// the progress phases match the ones of the importer writing the transferred data
+	progressPhaseTransferDataFile = "TransferDataFile"
+	progressPhaseTransferDataDir  = "TransferDataDir"
+	progressPhaseComplete         = "Complete"
+)
+
 var (
 	contentType string
 	mountPoint  string
 	uploadBytes uint64
+	streams     int
 )
 
-type execReader struct {
-	cmd    *exec.Cmd
-	stdout io.ReadCloser
-	stderr io.ReadCloser
-}
-
-func (er *execReader) Read(p []byte) (n int, err error) {
-	n, err = er.stdout.Read(p)
-	if err == io.EOF {
-		if err2 := er.cmd.Wait(); err2 != nil {
-			errBytes, _ := ioutil.ReadAll(er.stderr)
-			klog.Fatalf("Subprocess did not execute successfully, result is: %q\n%s", er.cmd.ProcessState.ExitCode(), string(errBytes))
-		}
-	}
-	return
-}
-
-func (er *execReader) Close() error {
-	return er.stdout.Close()
-}
-
 func init() {
 	flag.StringVar(&contentType, "content-type", "", "filesystem-clone|blockdevice-clone")
 	flag.StringVar(&mountPoint, "mount", "", "pvc mount point")
 	flag.Uint64Var(&uploadBytes, "upload-bytes", 0, "approx number of bytes in input")
+	flag.IntVar(&streams, "streams", defaultStreams, "number of concurrent streams when cloning extents")
 	klog.InitFlags(nil)
 }
 
@@ -93,7 +88,7 @@ func startPrometheus() {
 	prometheusutil.StartPrometheusEndpoint(certsDirectory)
 }
 
-func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes uint64) io.ReadCloser {
+func createProgressReader(readCloser io.ReadCloser, ownerUID string, totalBytes uint64) *prometheusutil.ProgressReader {
 	progress := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "clone_progress",
@@ -144,35 +139,12 @@ func validateMount() {
 	}
 }
 
-func newTarReader(preallocation bool) (io.ReadCloser, error) {
-	args := "cv"
-	if !preallocation {
-		// -S is used to handle sparse files. It can only be used when preallocation is not requested
-		args = "S" + args
-	}
-	cmd := exec.Command("/usr/bin/tar", args, ".")
-	cmd.Dir = mountPoint
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err = cmd.Start(); err != nil {
-		return nil, err
-	}
-
-	return &execReader{cmd: cmd, stdout: stdout, stderr: ioutil.NopCloser(&stderr)}, nil
-}
-
 func getInputStream(preallocation bool) (rc io.ReadCloser) {
 	var err error
 	switch contentType {
 	case "filesystem-clone":
-		rc, err = newTarReader(preallocation)
+		// -S is used to handle sparse files. It can only be used when preallocation is not requested
+		rc, err = util.NewTarReader(mountPoint, !preallocation)
 		if err != nil {
 			klog.Fatalf("Error creating tar reader for %q: %+v", mountPoint, err)
 		}
@@ -187,6 +159,132 @@ func getInputStream(preallocation bool) (rc io.ReadCloser) {
 	return
 }
 
+// getExtentsSource returns the file to clone as sparse extents, with its content type and size. It returns nil if the
+// source has to be streamed as a whole, because preallocation was requested or the filesystem holds other files than
+// the disk image. A filesystem cloned to a block device always sends just the disk image, the target has no
+// filesystem to hold other files and is fully allocated anyway.
+func getExtentsSource(preallocation bool, targetVolumeMode string) (*os.File, string, int64) {
+	toBlock := contentType == "filesystem-clone" && targetVolumeMode == "block"
+	if preallocation && !toBlock {
+		return nil, "", 0
+	}
+
+	path, extentsContentType := mountPoint, common.BlockdeviceExtentsClone
+	if contentType == "filesystem-clone" {
+		diskImage, onlyFile := getDiskImage()
+		if !onlyFile && !toBlock {
+			return nil, "", 0
+		}
+		path, extentsContentType = diskImage, common.FilesystemExtentsClone
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		klog.Fatalf("Error opening %q: %+v", path, err)
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		klog.Fatalf("Error getting size of %q: %+v", path, err)
+	}
+	return f, extentsContentType, size
+}
+
+// getDiskImage returns the path of the disk image, and true if it is the only file on the filesystem
+func getDiskImage() (string, bool) {
+	entries, err := ioutil.ReadDir(mountPoint)
+	if err != nil {
+		klog.Fatalf("Error reading %q: %+v", mountPoint, err)
+	}
+	found := false
+	for _, entry := range entries {
+		switch {
+		case entry.Name() == common.DiskImageName && entry.Mode().IsRegular():
+			found = true
+		case entry.Name() == "lost+found" && entry.IsDir():
+		default:
+			klog.Infof("Found %q besides the disk image", entry.Name())
+			return filepath.Join(mountPoint, common.DiskImageName), false
+		}
+	}
+	return filepath.Join(mountPoint, common.DiskImageName), found
+}
+
+// cloneExtents sends the data extents of the source over concurrent streams, holes and zero blocks are sent without
+// their data. The streams carry an attempt ID, so the upload server rejects streams of an earlier run of the cloner.
+func cloneExtents(client *http.Client, url string, source *os.File, extentsContentType string, size int64, progress *prometheusutil.ProgressReader) error {
+	dataExtents, err := extents.DataExtents(source, size)
+	if err != nil {
+		return err
+	}
+	chunks := extents.Plan(dataExtents, size)
+	attempt := string(uuid.NewUUID())
+	klog.Infof("Cloning %d bytes in %d chunks with %d streams, attempt %s", size, len(chunks), streams, attempt)
+
+	parts := extents.Partition(chunks, streams)
+	errs := make(chan error, len(parts))
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		wg.Add(1)
+		go func(i int, part []extents.Chunk) {
+			defer wg.Done()
+			if err := postExtents(client, url, source, extentsContentType, attempt, size, len(parts), part, progress); err != nil {
+				errs <- fmt.Errorf("stream %d: %v", i, err)
+			}
+		}(i, part)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	progress.Done = true
+	return nil
+}
+
+func postExtents(client *http.Client, url string, source io.ReaderAt, extentsContentType, attempt string, size int64, numStreams int, chunks []extents.Chunk, progress *prometheusutil.ProgressReader) error {
+	pr, pw := io.Pipe()
+	go func() {
+		sbw := snappy.NewBufferedWriter(pw)
+		err := extents.WriteChunks(sbw, source, chunks, func(n int64) {
+			atomic.AddUint64(&progress.Current, uint64(n))
+		})
+		if err == nil {
+			err = sbw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, _ := http.NewRequest("POST", url, pr)
+	req.Header.Set(common.UploadContentTypeHeader, extentsContentType)
+	req.Header.Set(common.CloneStreamsHeader, strconv.Itoa(numStreams))
+	req.Header.Set(common.CloneSizeHeader, strconv.FormatInt(size, 10))
+	req.Header.Set(common.CloneAttemptHeader, attempt)
+
+	return doRequest(client, req)
+}
+
+func doRequest(client *http.Client, req *http.Request) error {
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error %s POSTing to %s", err, req.URL)
+	}
+	defer response.Body.Close()
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, response.Body)
+	if err != nil {
+		return fmt.Errorf("error %s copying response body", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", response.StatusCode, buf.String())
+	}
+
+	klog.V(1).Infof("Response body:\n%s", buf.String())
+	return nil
+}
+
 func main() {
 	flag.Parse()
 	defer klog.Flush()
@@ -212,37 +310,45 @@ func main() {
 
 	klog.V(1).Infoln("Starting cloner target")
 
-	reader := pipeToSnappy(createProgressReader(getInputStream(preallocation), ownerUID, uploadBytes))
+	client := createHTTPClient(clientKey, clientCert, serverCert)
 
-	startPrometheus()
+	targetVolumeMode := os.Getenv("TARGET_VOLUME_MODE")
+	klog.Infof("target volume mode is %q", targetVolumeMode)
 
-	client := createHTTPClient(clientKey, clientCert, serverCert)
+	if source, extentsContentType, size := getExtentsSource(preallocation, targetVolumeMode); source != nil {
+		progress := createProgressReader(source, ownerUID, uint64(size))
+		prometheusutil.SetProgressPhase(progressPhaseTransferDataFile)
 
-	req, _ := http.NewRequest("POST", url, reader)
+		startPrometheus()
 
-	if contentType != "" {
-		req.Header.Set("x-cdi-content-type", contentType)
-		klog.Infof("Set header to %s", contentType)
-	}
+		if err := cloneExtents(client, url, source, extentsContentType, size, progress); err != nil {
+			klog.Fatalf("Error cloning extents: %+v", err)
+		}
+		source.Close()
+	} else {
+		reader := pipeToSnappy(createProgressReader(getInputStream(preallocation), ownerUID, uploadBytes))
+		if contentType == common.FilesystemCloneContentType {
+			prometheusutil.SetProgressPhase(progressPhaseTransferDataDir)
+		} else {
+			prometheusutil.SetProgressPhase(progressPhaseTransferDataFile)
+		}
 
-	response, err := client.Do(req)
-	if err != nil {
-		klog.Fatalf("Error %s POSTing to %s", err, url)
-	}
+		startPrometheus()
 
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		klog.Fatalf("Unexpected status code %d", response.StatusCode)
-	}
+		req, _ := http.NewRequest("POST", url, reader)
 
-	var buf bytes.Buffer
-	_, err = io.Copy(&buf, response.Body)
-	if err != nil {
-		klog.Fatalf("Error %s copying response body", err)
-	}
+		if contentType != "" {
+			req.Header.Set(common.UploadContentTypeHeader, contentType)
+			klog.Infof("Set header to %s", contentType)
+		}
 
-	klog.V(1).Infof("Response body:\n%s", buf.String())
+		if err := doRequest(client, req); err != nil {
+			klog.Fatalf("%+v", err)
+		}
+	}
 
 	klog.V(1).Infoln("clone complete")
+	prometheusutil.SetProgressPhase(progressPhaseComplete)
 	message := "Clone Complete"
 	if preallocation {
 		message += ", " + common.PreallocationApplied
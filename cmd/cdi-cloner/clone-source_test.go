@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/golang/snappy"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/extents"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
@@ -29,6 +39,146 @@ var _ = Describe("Prometheus Endpoint", func() {
 	})
 })
 
+var _ = Describe("Extents clone", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "clone-source")
+		Expect(err).ToNot(HaveOccurred())
+		mountPoint = filepath.Join(tempDir, "source")
+		Expect(os.Mkdir(mountPoint, 0755)).To(Succeed())
+		contentType = "filesystem-clone"
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("Should clone the disk image as extents if it is the only file", func() {
+		Expect(os.Mkdir(filepath.Join(mountPoint, "lost+found"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, common.DiskImageName), []byte("data"), 0644)).To(Succeed())
+
+		source, extentsContentType, size := getExtentsSource(false, "filesystem")
+		Expect(source).ToNot(BeNil())
+		defer source.Close()
+		Expect(extentsContentType).To(Equal(common.FilesystemExtentsClone))
+		Expect(size).To(Equal(int64(4)))
+	})
+
+	It("Should stream the whole filesystem if it holds other files", func() {
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, common.DiskImageName), []byte("data"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, "other"), []byte("data"), 0644)).To(Succeed())
+
+		source, _, _ := getExtentsSource(false, "filesystem")
+		Expect(source).To(BeNil())
+	})
+
+	It("Should stream the whole filesystem if preallocation is requested", func() {
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, common.DiskImageName), []byte("data"), 0644)).To(Succeed())
+
+		source, _, _ := getExtentsSource(true, "filesystem")
+		Expect(source).To(BeNil())
+	})
+
+	It("Should clone just the disk image to a block device", func() {
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, common.DiskImageName), []byte("data"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, "other"), []byte("data"), 0644)).To(Succeed())
+
+		source, extentsContentType, size := getExtentsSource(true, "block")
+		Expect(source).ToNot(BeNil())
+		defer source.Close()
+		Expect(source.Name()).To(Equal(filepath.Join(mountPoint, common.DiskImageName)))
+		Expect(extentsContentType).To(Equal(common.FilesystemExtentsClone))
+		Expect(size).To(Equal(int64(4)))
+	})
+
+	It("Should clone a block device to a filesystem as extents", func() {
+		contentType = "blockdevice-clone"
+		mountPoint = filepath.Join(tempDir, "device")
+		Expect(ioutil.WriteFile(mountPoint, []byte("device"), 0644)).To(Succeed())
+
+		source, extentsContentType, size := getExtentsSource(false, "filesystem")
+		Expect(source).ToNot(BeNil())
+		defer source.Close()
+		Expect(extentsContentType).To(Equal(common.BlockdeviceExtentsClone))
+		Expect(size).To(Equal(int64(6)))
+	})
+
+	It("Should send the extents over concurrent streams", func() {
+		size := int64(3*extents.ChunkSize + 100)
+		sourcePath := filepath.Join(mountPoint, common.DiskImageName)
+		f, err := os.Create(sourcePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(f.Truncate(size)).To(Succeed())
+		_, err = f.WriteAt([]byte("first"), 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = f.WriteAt([]byte("last"), size-4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		targetPath := filepath.Join(tempDir, "target")
+		sink, err := extents.NewFileSink(targetPath, size)
+		Expect(err).ToNot(HaveOccurred())
+
+		var mutex sync.Mutex
+		requests := 0
+		attempts := map[string]bool{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get(common.UploadContentTypeHeader)).To(Equal(common.FilesystemExtentsClone))
+			Expect(r.Header.Get(common.CloneStreamsHeader)).To(Equal("2"))
+			Expect(r.Header.Get(common.CloneSizeHeader)).To(Equal(strconv.FormatInt(size, 10)))
+			_, err := extents.ReadFrames(snappy.NewReader(r.Body), sink)
+			Expect(err).ToNot(HaveOccurred())
+			mutex.Lock()
+			requests++
+			attempts[r.Header.Get(common.CloneAttemptHeader)] = true
+			mutex.Unlock()
+		}))
+		defer server.Close()
+
+		streams = 2
+		defer func() {
+			streams = defaultStreams
+		}()
+
+		source, extentsContentType, sourceSize := getExtentsSource(false, "filesystem")
+		Expect(source).ToNot(BeNil())
+		defer source.Close()
+		progress := prometheusutil.NewProgressReader(source, uint64(sourceSize), prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_progress"}, []string{"ownerUID"}), "uid")
+
+		Expect(cloneExtents(server.Client(), server.URL, source, extentsContentType, sourceSize, progress)).To(Succeed())
+		Expect(requests).To(Equal(2))
+		Expect(attempts).To(HaveLen(1))
+		Expect(attempts).ToNot(HaveKey(""))
+		Expect(progress.Current).To(Equal(uint64(size)))
+		Expect(progress.Done).To(BeTrue())
+		Expect(sink.Close()).To(Succeed())
+
+		expected, err := ioutil.ReadFile(sourcePath)
+		Expect(err).ToNot(HaveOccurred())
+		actual, err := ioutil.ReadFile(targetPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(actual, expected)).To(BeTrue())
+	})
+
+	It("Should fail if the target rejects a stream", func() {
+		Expect(ioutil.WriteFile(filepath.Join(mountPoint, common.DiskImageName), []byte("data"), 0644)).To(Succeed())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		source, extentsContentType, size := getExtentsSource(false, "filesystem")
+		Expect(source).ToNot(BeNil())
+		defer source.Close()
+		progress := prometheusutil.NewProgressReader(source, uint64(size), prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_progress"}, []string{"ownerUID"}), "uid")
+
+		Expect(cloneExtents(server.Client(), server.URL, source, extentsContentType, size, progress)).ToNot(Succeed())
+	})
+})
+
 func isDirEmpty(dirName string) (bool, error) {
 	f, err := os.Open(dirName)
 	if err != nil {
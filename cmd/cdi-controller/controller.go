@@ -13,6 +13,7 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	crdinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -26,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
@@ -33,6 +35,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 const (
@@ -160,18 +163,28 @@ func start(ctx context.Context, cfg *rest.Config) {
 		os.Exit(1)
 	}
 
+	if _, err := controller.NewDataSourceController(mgr, log); err != nil {
+		klog.Errorf("Unable to setup datasource controller: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewDataImportCronController(mgr, log); err != nil {
+		klog.Errorf("Unable to setup dataimportcron controller: %v", err)
+		os.Exit(1)
+	}
+
 	// TODO: Current DV controller had threadiness 3, should we do the same here, defaults to one thread.
-	if _, err := controller.NewDatavolumeController(mgr, extClient, log, clonerImage, pullPolicy, getAPIServerPublicKey()); err != nil {
+	if _, err := controller.NewDatavolumeController(mgr, extClient, log, clonerImage, pullPolicy, getAPIServerPublicKey(), uploadClientCertGenerator, uploadServerBundleFetcher); err != nil {
 		klog.Errorf("Unable to setup datavolume controller: %v", err)
 		os.Exit(1)
 	}
 
-	if _, err := controller.NewImportController(mgr, log, importerImage, pullPolicy, verbose); err != nil {
+	if _, err := controller.NewImportController(mgr, log, importerImage, pullPolicy, verbose, uploadServerCertGenerator, uploadClientBundleFetcher); err != nil {
 		klog.Errorf("Unable to setup import controller: %v", err)
 		os.Exit(1)
 	}
 
-	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, getAPIServerPublicKey()); err != nil {
+	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, uploadServerCertGenerator, uploadClientBundleFetcher, getAPIServerPublicKey()); err != nil {
 		klog.Errorf("Unable to setup clone controller: %v", err)
 		os.Exit(1)
 	}
@@ -181,6 +194,11 @@ func start(ctx context.Context, cfg *rest.Config) {
 		os.Exit(1)
 	}
 
+	if _, err := controller.NewDownloadController(mgr, log, uploadServerImage, pullPolicy, verbose, uploadServerCertGenerator, uploadClientBundleFetcher); err != nil {
+		klog.Errorf("Unable to setup download controller: %v", err)
+		os.Exit(1)
+	}
+
 	if _, err := transfer.NewObjectTransferController(mgr, log); err != nil {
 		klog.Errorf("Unable to setup transfer controller: %v", err)
 		os.Exit(1)
@@ -193,6 +211,8 @@ func start(ctx context.Context, cfg *rest.Config) {
 	// Add Crd informer, so we can start the smart clone controller if we detect the CSI CRDs being installed.
 	addCrdInformerEventHandlers(crdInformer, extClient, mgr, log)
 
+	startMetrics()
+
 	if err := mgr.Start(ctx); err != nil {
 		klog.Errorf("Error running manager: %v", err)
 		os.Exit(1)
@@ -239,6 +259,17 @@ func main() {
 	klog.V(2).Infoln("cdi controller exited")
 }
 
+// startMetrics serves the metrics of the controllers, and the metrics of the controller runtime, on the https
+// metrics port. The controller runtime keeps serving its metrics on :8080 as well.
+func startMetrics() {
+	certsDirectory, err := ioutil.TempDir("", "certsdir")
+	if err != nil {
+		klog.Fatalf("Error %s creating temp dir", err)
+	}
+
+	prometheusutil.StartMetricsEndpoint(certsDirectory, prometheus.Gatherers{metrics.Registry, prometheus.DefaultGatherer})
+}
+
 func createReadyFile() error {
 	f, err := os.Create(readyFile)
 	if err != nil {
@@ -0,0 +1,100 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/downloadserver"
+)
+
+const (
+	defaultListenPort    = 8443
+	defaultListenAddress = "0.0.0.0"
+
+	defaultSource = common.UploadServerDataDir
+)
+
+func init() {
+	klog.InitFlags(nil)
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	listenAddress, listenPort := getListenAddressAndPort()
+
+	source := getSource()
+
+	server := downloadserver.NewDownloadServer(
+		listenAddress,
+		listenPort,
+		source,
+		common.DownloadServerScratchDir,
+		os.Getenv("TLS_KEY"),
+		os.Getenv("TLS_CERT"),
+		os.Getenv("CLIENT_CERT"),
+		os.Getenv("CLIENT_NAME"),
+	)
+
+	klog.Infof("Download source: %s", source)
+
+	klog.Infof("Running server on %s:%d", listenAddress, listenPort)
+
+	if err := server.Run(); err != nil {
+		klog.Errorf("DownloadServer failed: %s", err)
+		os.Exit(1)
+	}
+}
+
+func getListenAddressAndPort() (string, int) {
+	addr, port := defaultListenAddress, defaultListenPort
+
+	// empty value okay here
+	if val, exists := os.LookupEnv("LISTEN_ADDRESS"); exists {
+		addr = val
+	}
+
+	// not okay here
+	if val := os.Getenv("LISTEN_PORT"); len(val) > 0 {
+		n, err := strconv.ParseUint(val, 10, 16)
+		if err == nil {
+			port = int(n)
+		}
+	}
+
+	return addr, port
+}
+
+func getSource() string {
+	source := defaultSource
+
+	if val := os.Getenv("SOURCE"); len(val) > 0 {
+		source = val
+	}
+
+	return source
+}
@@ -66,6 +66,12 @@ func main() {
 	currentCheckpoint, _ := util.ParseEnvVar(common.ImporterCurrentCheckpoint, false)
 	previousCheckpoint, _ := util.ParseEnvVar(common.ImporterPreviousCheckpoint, false)
 	finalCheckpoint, _ := util.ParseEnvVar(common.ImporterFinalCheckpoint, false)
+	registryDiskPath, _ := util.ParseEnvVar(common.ImporterRegistryDiskPath, false)
+	requiredDigest, _ := util.ParseEnvVar(common.ImporterRequiredDigest, false)
+	signaturePublicKey, _ := util.ParseEnvVar(common.ImporterSignaturePublicKey, false)
+	signaturePolicy, _ := util.ParseEnvVar(common.ImporterSignaturePolicy, false)
+	checksum, _ := util.ParseEnvVar(common.ImporterChecksum, false)
+	parallelism, _ := strconv.Atoi(os.Getenv(common.ImporterParallelism))
 	preallocation, err := strconv.ParseBool(os.Getenv(common.Preallocation))
 	var preallocationApplied bool
 	var dp importer.DataSourceInterface
@@ -135,7 +141,7 @@ func main() {
 		klog.V(1).Infoln("begin import process")
 		switch source {
 		case controller.SourceHTTP:
-			dp, err = importer.NewHTTPDataSource(ep, acc, sec, certDir, cdiv1.DataVolumeContentType(contentType))
+			dp, err = importer.NewHTTPDataSource(ep, acc, sec, certDir, cdiv1.DataVolumeContentType(contentType), checksum, parallelism)
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to http data source: %+v", err))
@@ -155,9 +161,14 @@ func main() {
 				os.Exit(1)
 			}
 		case controller.SourceRegistry:
-			dp = importer.NewRegistryDataSource(ep, acc, sec, certDir, insecureTLS)
+			verification := &importer.ImageVerification{
+				Digest:        requiredDigest,
+				PublicKeyFile: signaturePublicKey,
+				PolicyFile:    signaturePolicy,
+			}
+			dp = importer.NewRegistryDataSource(ep, acc, sec, certDir, insecureTLS, registryDiskPath, verification)
 		case controller.SourceS3:
-			dp, err = importer.NewS3DataSource(ep, acc, sec, certDir)
+			dp, err = importer.NewS3DataSource(ep, acc, sec, certDir, checksum)
 			if err != nil {
 				klog.Errorf("%+v", err)
 				err = util.WriteTerminationMessage(fmt.Sprintf("Unable to connect to s3 data source: %+v", err))
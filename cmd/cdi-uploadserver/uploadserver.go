@@ -21,14 +21,17 @@ package main
 
 import (
 	"flag"
+	"io/ioutil"
 	"os"
 	"strconv"
 
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/uploadserver"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 const (
@@ -64,27 +67,27 @@ func main() {
 		os.Getenv(common.UploadImageSize),
 		filesystemOverhead,
 		preallocation,
+		os.Getenv(common.UploadChecksum),
 	)
 
 	klog.Infof("Upload destination: %s", destination)
 
+	startPrometheus()
+
 	klog.Infof("Running server on %s:%d", listenAddress, listenPort)
 
 	err := server.Run()
 	if err != nil {
 		klog.Errorf("UploadServer failed: %s", err)
+		if err == importer.ErrRequiresScratchSpace {
+			os.Exit(common.ScratchSpaceNeededExitCode)
+		}
 		os.Exit(1)
 	}
 
-	// Check if cloning or uploading based on the existance of the scratch space. Clone won't have scratch space
-	clone := false
-	_, err = os.OpenFile(common.ScratchDataDir, os.O_RDONLY, 0600)
-	if err != nil {
-		// Cloning instead of uploading.
-		clone = true
-	}
+	// Uploads may run without scratch space as well, so ask the server whether a clone source sent the data
 	var message string
-	if clone {
+	if server.Cloned() {
 		message = "Clone Complete"
 	} else {
 		message = "Upload Complete"
@@ -92,6 +95,9 @@ func main() {
 	if server.PreallocationApplied() {
 		message += ", " + common.PreallocationApplied
 	}
+	if checksum := server.Checksum(); checksum != "" {
+		message += "; Checksum: " + checksum
+	}
 	err = util.WriteTerminationMessage(message)
 	if err != nil {
 		klog.Errorf("%+v", err)
@@ -100,6 +106,15 @@ func main() {
 	klog.Info("UploadServer successfully exited")
 }
 
+func startPrometheus() {
+	certsDirectory, err := ioutil.TempDir("", "certsdir")
+	if err != nil {
+		klog.Fatalf("Error %s creating temp dir", err)
+	}
+
+	prometheusutil.StartPrometheusEndpointOnPort(certsDirectory, common.UploadServerMetricsPort)
+}
+
 func getListenAddressAndPort() (string, int) {
 	addr, port := defaultListenAddress, defaultListenPort
 
@@ -112,7 +112,7 @@ const (
 	DataVolumeArchive DataVolumeContentType = "archive"
 )
 
-// DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry or an existing PVC
+// DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry, an existing PVC or a VolumeSnapshot
 type DataVolumeSource struct {
 	HTTP     *DataVolumeSourceHTTP     `json:"http,omitempty"`
 	S3       *DataVolumeSourceS3       `json:"s3,omitempty"`
@@ -122,6 +122,7 @@ type DataVolumeSource struct {
 	Blank    *DataVolumeBlankImage     `json:"blank,omitempty"`
 	Imageio  *DataVolumeSourceImageIO  `json:"imageio,omitempty"`
 	VDDK     *DataVolumeSourceVDDK     `json:"vddk,omitempty"`
+	Snapshot *DataVolumeSourceSnapshot `json:"snapshot,omitempty"`
 }
 
 // DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC
@@ -132,11 +133,22 @@ type DataVolumeSourcePVC struct {
 	Name string `json:"name"`
 }
 
+// DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot
+type DataVolumeSourceSnapshot struct {
+	// The namespace of the source VolumeSnapshot
+	Namespace string `json:"namespace"`
+	// The name of the source VolumeSnapshot
+	Name string `json:"name"`
+}
+
 // DataVolumeBlankImage provides the parameters to create a new raw blank image for the PVC
 type DataVolumeBlankImage struct{}
 
 // DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source
 type DataVolumeSourceUpload struct {
+	// Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source
@@ -148,6 +160,9 @@ type DataVolumeSourceS3 struct {
 	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
 	// +optional
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source
@@ -158,6 +173,25 @@ type DataVolumeSourceRegistry struct {
 	SecretRef string `json:"secretRef,omitempty"`
 	//CertConfigMap provides a reference to the Registry certs
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	//DiskPath is the path of the disk image file within the container image (e.g. disk/data.qcow2), defaults to the single file under disk/
+	// +optional
+	DiskPath string `json:"diskPath,omitempty"`
+	//Digest is the manifest digest the image is required to have (e.g. sha256:...), the import fails on mismatch
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	//SignatureVerification provides the policy the image signature has to satisfy before it is imported
+	// +optional
+	SignatureVerification *RegistrySignatureVerification `json:"signatureVerification,omitempty"`
+}
+
+// RegistrySignatureVerification defines how the signature of a registry image is verified, exactly one of the fields has to be set
+type RegistrySignatureVerification struct {
+	//PublicKeyConfigMap is the name of a ConfigMap holding a PEM encoded cosign public key under the "cosign.pub" key
+	// +optional
+	PublicKeyConfigMap string `json:"publicKeyConfigMap,omitempty"`
+	//PolicyConfigMap is the name of a ConfigMap holding a containers/image signature policy under the "policy.json" key
+	// +optional
+	PolicyConfigMap string `json:"policyConfigMap,omitempty"`
 }
 
 // DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs
@@ -170,6 +204,9 @@ type DataVolumeSourceHTTP struct {
 	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
 	// +optional
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // DataVolumeSourceImageIO provides the parameters to create a Data Volume from an imageio source
@@ -222,6 +259,23 @@ type DataVolumeStatus struct {
 	// RestartCount is the number of times the pod populating the DataVolume has restarted
 	RestartCount int32                 `json:"restartCount,omitempty"`
 	Conditions   []DataVolumeCondition `json:"conditions,omitempty" optional:"true"`
+	// Transfer is the detailed progress reported by the pod populating the DataVolume
+	// +optional
+	Transfer *DataVolumeTransferStatus `json:"transfer,omitempty"`
+}
+
+// DataVolumeTransferStatus is the detailed progress of the transfer populating the DataVolume
+type DataVolumeTransferStatus struct {
+	// Phase is the processing phase of the pod populating the DataVolume
+	Phase string `json:"phase,omitempty"`
+	// BytesTransferred is the number of bytes read from the source
+	BytesTransferred int64 `json:"bytesTransferred,omitempty"`
+	// TotalBytes is the number of bytes to read from the source, if known
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// Throughput is the number of bytes read from the source per second
+	Throughput int64 `json:"throughput,omitempty"`
+	// EstimatedSecondsRemaining is the time needed to read the remaining bytes at the current throughput
+	EstimatedSecondsRemaining int64 `json:"estimatedSecondsRemaining,omitempty"`
 }
 
 //DataVolumeList provides the needed parameters to do request a list of Data Volumes from the system
@@ -280,6 +334,9 @@ const (
 	// SmartClonePVCInProgress represents a data volume with a current phase of SmartClonePVCInProgress
 	SmartClonePVCInProgress DataVolumePhase = "SmartClonePVCInProgress"
 
+	// CSICloneInProgress represents a data volume with a current phase of CSICloneInProgress
+	CSICloneInProgress DataVolumePhase = "CSICloneInProgress"
+
 	// ExpansionInProgress is the state when a PVC is expanded
 	ExpansionInProgress DataVolumePhase = "ExpansionInProgress"
 
@@ -337,6 +394,9 @@ type StorageProfile struct {
 type StorageProfileSpec struct {
 	// ClaimPropertySets is a provided set of properties applicable to PVC
 	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// Probe enables the detection of the storage capabilities by provisioning test PVCs
+	// +optional
+	Probe *StorageProbe `json:"probe,omitempty"`
 }
 
 //StorageProfileStatus provides the most recently observed status of the StorageProfile
@@ -347,8 +407,66 @@ type StorageProfileStatus struct {
 	Provisioner *string `json:"provisioner,omitempty"`
 	// ClaimPropertySets computed from the spec and detected in the system
 	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// Probe is the result of the last probe of the storage class
+	// +optional
+	Probe *StorageProbeStatus `json:"probe,omitempty"`
+}
+
+// StorageProbe configures when the storage class is probed
+type StorageProbe struct {
+	// Policy is when the storage class is probed, Never, Once or Periodic. Defaults to Never
+	// +kubebuilder:validation:Enum=Never;Once;Periodic
+	// +optional
+	Policy StorageProbePolicy `json:"policy,omitempty"`
+	// Interval is the time between the probes of the Periodic policy. Defaults to 24h
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// StorageProbePolicy defines when the storage class is probed
+type StorageProbePolicy string
+
+const (
+	// StorageProbeNever does not probe the storage class
+	StorageProbeNever StorageProbePolicy = "Never"
+	// StorageProbeOnce probes the storage class if it was never probed
+	StorageProbeOnce StorageProbePolicy = "Once"
+	// StorageProbePeriodic probes the storage class again when the probe interval passed
+	StorageProbePeriodic StorageProbePolicy = "Periodic"
+)
+
+// StorageProbeStatus is the result of probing a storage class with test PVCs
+type StorageProbeStatus struct {
+	// Phase is the phase of the probe, InProgress, Succeeded or Failed
+	Phase StorageProbePhase `json:"phase,omitempty"`
+	// StartTime is the time the probe started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the probe completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ClaimPropertySets are the volume and access modes the storage class provisioned, ordered by preference
+	ClaimPropertySets []ClaimPropertySet `json:"claimPropertySets,omitempty"`
+	// SnapshotClass is the VolumeSnapshotClass of the storage class driver
+	SnapshotClass *string `json:"snapshotClass,omitempty"`
+	// Clone is true if the storage class provisioned a PVC with a PVC data source
+	Clone bool `json:"clone,omitempty"`
+	// Expansion is true if the storage class allows volume expansion
+	Expansion bool `json:"expansion,omitempty"`
+	// Message is a human readable description of the probe result
+	Message string `json:"message,omitempty"`
 }
 
+// StorageProbePhase is the phase of a storage class probe
+type StorageProbePhase string
+
+const (
+	// StorageProbeInProgress means the test PVCs are being provisioned
+	StorageProbeInProgress StorageProbePhase = "InProgress"
+	// StorageProbeSucceeded means the capabilities were detected
+	StorageProbeSucceeded StorageProbePhase = "Succeeded"
+	// StorageProbeFailed means no test PVC was provisioned
+	StorageProbeFailed StorageProbePhase = "Failed"
+)
+
 // ClaimPropertySet is a set of properties applicable to PVC
 type ClaimPropertySet struct {
 	// AccessModes contains the desired access modes the volume should have.
@@ -416,6 +534,11 @@ type DataSourceCondition struct {
 // DataSourceConditionType is the string representation of known condition types
 type DataSourceConditionType string
 
+const (
+	// DataSourceReady is the condition that indicates if the data source is ready to be consumed
+	DataSourceReady DataSourceConditionType = "Ready"
+)
+
 // DataSourceList provides the needed parameters to do request a list of Data Sources from the system
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DataSourceList struct {
@@ -443,12 +566,20 @@ type DataImportCron struct {
 type DataImportCronSpec struct {
 	// Source specifies where to poll disk images from
 	Source DataImportCronSource `json:"source"`
+	// Template specifies template for the DVs to be created
+	Template DataVolume `json:"template"`
 	// Schedule specifies in cron format when and how often to look for new imports
 	Schedule string `json:"schedule"`
 	// GarbageCollect specifies whether old PVCs should be cleaned up after a new PVC is imported.
 	// Options are currently "Never" and "Outdated", defaults to "Never".
 	// +optional
 	GarbageCollect *DataImportCronGarbageCollect `json:"garbageCollect,omitempty"`
+	// ImportsToKeep specifies the number of latest imports to keep when garbage collection is "Outdated", defaults to 3.
+	// +optional
+	ImportsToKeep *int32 `json:"importsToKeep,omitempty"`
+	// ImportsMaxAge specifies that imports newer than this duration are kept as well when garbage collection is "Outdated".
+	// +optional
+	ImportsMaxAge *metav1.Duration `json:"importsMaxAge,omitempty"`
 	// ManagedDataSource specifies the name of the corresponding DataSource this cron will manage.
 	// DataSource has to be in the same namespace.
 	ManagedDataSource string `json:"managedDataSource"`
@@ -493,6 +624,13 @@ type DataImportCronCondition struct {
 // DataImportCronConditionType is the string representation of known condition types
 type DataImportCronConditionType string
 
+const (
+	// DataImportCronProgressing is the condition that indicates import is progressing
+	DataImportCronProgressing DataImportCronConditionType = "Progressing"
+	// DataImportCronUpToDate is the condition that indicates latest import is up to date
+	DataImportCronUpToDate DataImportCronConditionType = "UpToDate"
+)
+
 // DataImportCronList provides the needed parameters to do request a list of DataImportCrons from the system
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DataImportCronList struct {
@@ -640,6 +778,19 @@ type FilesystemOverhead struct {
 	StorageClass map[string]Percent `json:"storageClass,omitempty"`
 }
 
+//ProvisionerCapabilities defines the recommended claim properties of the storage classes of a provisioner
+type ProvisionerCapabilities struct {
+	// Provisioner is the provisioner of the storage classes the rule applies to
+	Provisioner string `json:"provisioner"`
+	// Parameters the storage class must have for the rule to apply, e.g. the parameter picking the backend of a driver serving several kinds of storage
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// AccessMode is the recommended access mode of the claims
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode"`
+	// VolumeMode is the recommended volume mode of the claims
+	VolumeMode corev1.PersistentVolumeMode `json:"volumeMode"`
+}
+
 //CDIConfigSpec defines specification for user configuration
 type CDIConfigSpec struct {
 	// Override the URL used when uploading to a DataVolume
@@ -659,6 +810,14 @@ type CDIConfigSpec struct {
 	Preallocation *bool `json:"preallocation,omitempty"`
 	// InsecureRegistries is a list of TLS disabled registries
 	InsecureRegistries []string `json:"insecureRegistries,omitempty"`
+	// ImportParallelism is the number of concurrent range requests used to download http imports, when the server supports range requests. Defaults to 1, at most 16, it can be overridden per DataVolume with the cdi.kubevirt.io/storage.import.parallelism annotation
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	ImportParallelism *int32 `json:"importParallelism,omitempty"`
+	// StorageCapabilities are provisioner rules extending the storage capabilities CDI knows for well known provisioners. The first rule matching a storage class is used, before the rules built into CDI
+	// +optional
+	StorageCapabilities []ProvisionerCapabilities `json:"storageCapabilities,omitempty"`
 }
 
 //CDIConfigStatus provides the most recently observed status of the CDI Config resource
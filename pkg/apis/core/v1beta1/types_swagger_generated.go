@@ -46,7 +46,7 @@ func (DataVolumeCheckpoint) SwaggerDoc() map[string]string {
 
 func (DataVolumeSource) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry or an existing PVC",
+		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, Registry, an existing PVC or a VolumeSnapshot",
 	}
 }
 
@@ -58,6 +58,14 @@ func (DataVolumeSourcePVC) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DataVolumeSourceSnapshot) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot",
+		"namespace": "The namespace of the source VolumeSnapshot",
+		"name":      "The name of the source VolumeSnapshot",
+	}
+}
+
 func (DataVolumeBlankImage) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "DataVolumeBlankImage provides the parameters to create a new raw blank image for the PVC",
@@ -66,7 +74,8 @@ func (DataVolumeBlankImage) SwaggerDoc() map[string]string {
 
 func (DataVolumeSourceUpload) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source",
+		"":         "DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source",
+		"checksum": "Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5\n+optional",
 	}
 }
 
@@ -76,15 +85,27 @@ func (DataVolumeSourceS3) SwaggerDoc() map[string]string {
 		"url":           "URL is the url of the S3 source",
 		"secretRef":     "SecretRef provides the secret reference needed to access the S3 source",
 		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"checksum":      "Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5\n+optional",
 	}
 }
 
 func (DataVolumeSourceRegistry) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
-		"url":           "URL is the url of the Docker registry source",
-		"secretRef":     "SecretRef provides the secret reference needed to access the Registry source",
-		"certConfigMap": "CertConfigMap provides a reference to the Registry certs",
+		"":                      "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
+		"url":                   "URL is the url of the Docker registry source",
+		"secretRef":             "SecretRef provides the secret reference needed to access the Registry source",
+		"certConfigMap":         "CertConfigMap provides a reference to the Registry certs",
+		"diskPath":              "DiskPath is the path of the disk image file within the container image (e.g. disk/data.qcow2), defaults to the single file under disk/\n+optional",
+		"digest":                "Digest is the manifest digest the image is required to have (e.g. sha256:...), the import fails on mismatch\n+optional",
+		"signatureVerification": "SignatureVerification provides the policy the image signature has to satisfy before it is imported\n+optional",
+	}
+}
+
+func (RegistrySignatureVerification) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "RegistrySignatureVerification defines how the signature of a registry image is verified, exactly one of the fields has to be set",
+		"publicKeyConfigMap": "PublicKeyConfigMap is the name of a ConfigMap holding a PEM encoded cosign public key under the \"cosign.pub\" key\n+optional",
+		"policyConfigMap":    "PolicyConfigMap is the name of a ConfigMap holding a containers/image signature policy under the \"policy.json\" key\n+optional",
 	}
 }
 
@@ -94,6 +115,7 @@ func (DataVolumeSourceHTTP) SwaggerDoc() map[string]string {
 		"url":           "URL is the URL of the http(s) endpoint",
 		"secretRef":     "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded\n+optional",
 		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"checksum":      "Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5\n+optional",
 	}
 }
 
@@ -132,6 +154,18 @@ func (DataVolumeStatus) SwaggerDoc() map[string]string {
 		"":             "DataVolumeStatus contains the current status of the DataVolume",
 		"phase":        "Phase is the current phase of the data volume",
 		"restartCount": "RestartCount is the number of times the pod populating the DataVolume has restarted",
+		"transfer":     "Transfer is the detailed progress reported by the pod populating the DataVolume\n+optional",
+	}
+}
+
+func (DataVolumeTransferStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                          "DataVolumeTransferStatus is the detailed progress of the transfer populating the DataVolume",
+		"phase":                     "Phase is the processing phase of the pod populating the DataVolume",
+		"bytesTransferred":          "BytesTransferred is the number of bytes read from the source",
+		"totalBytes":                "TotalBytes is the number of bytes to read from the source, if known",
+		"throughput":                "Throughput is the number of bytes read from the source per second",
+		"estimatedSecondsRemaining": "EstimatedSecondsRemaining is the time needed to read the remaining bytes at the current throughput",
 	}
 }
 
@@ -158,6 +192,7 @@ func (StorageProfileSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                  "StorageProfileSpec defines specification for StorageProfile",
 		"claimPropertySets": "ClaimPropertySets is a provided set of properties applicable to PVC",
+		"probe":             "Probe enables the detection of the storage capabilities by provisioning test PVCs\n+optional",
 	}
 }
 
@@ -167,6 +202,29 @@ func (StorageProfileStatus) SwaggerDoc() map[string]string {
 		"storageClass":      "The StorageClass name for which capabilities are defined",
 		"provisioner":       "The Storage class provisioner plugin name",
 		"claimPropertySets": "ClaimPropertySets computed from the spec and detected in the system",
+		"probe":             "Probe is the result of the last probe of the storage class\n+optional",
+	}
+}
+
+func (StorageProbe) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "StorageProbe configures when the storage class is probed",
+		"policy":   "Policy is when the storage class is probed, Never, Once or Periodic. Defaults to Never\n+kubebuilder:validation:Enum=Never;Once;Periodic\n+optional",
+		"interval": "Interval is the time between the probes of the Periodic policy. Defaults to 24h\n+optional",
+	}
+}
+
+func (StorageProbeStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "StorageProbeStatus is the result of probing a storage class with test PVCs",
+		"phase":             "Phase is the phase of the probe, InProgress, Succeeded or Failed",
+		"startTime":         "StartTime is the time the probe started",
+		"completionTime":    "CompletionTime is the time the probe completed",
+		"claimPropertySets": "ClaimPropertySets are the volume and access modes the storage class provisioned, ordered by preference",
+		"snapshotClass":     "SnapshotClass is the VolumeSnapshotClass of the storage class driver",
+		"clone":             "Clone is true if the storage class provisioned a PVC with a PVC data source",
+		"expansion":         "Expansion is true if the storage class allows volume expansion",
+		"message":           "Message is a human readable description of the probe result",
 	}
 }
 
@@ -235,8 +293,11 @@ func (DataImportCronSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                  "DataImportCronSpec defines specification for DataImportCron",
 		"source":            "Source specifies where to poll disk images from",
+		"template":          "Template specifies template for the DVs to be created",
 		"schedule":          "Schedule specifies in cron format when and how often to look for new imports",
 		"garbageCollect":    "GarbageCollect specifies whether old PVCs should be cleaned up after a new PVC is imported.\nOptions are currently \"Never\" and \"Outdated\", defaults to \"Never\".\n+optional",
+		"importsToKeep":     "ImportsToKeep specifies the number of latest imports to keep when garbage collection is \"Outdated\", defaults to 3.\n+optional",
+		"importsMaxAge":     "ImportsMaxAge specifies that imports newer than this duration are kept as well when garbage collection is \"Outdated\".\n+optional",
 		"managedDataSource": "ManagedDataSource specifies the name of the corresponding DataSource this cron will manage.\nDataSource has to be in the same namespace.",
 	}
 }
@@ -332,6 +393,16 @@ func (FilesystemOverhead) SwaggerDoc() map[string]string {
 	}
 }
 
+func (ProvisionerCapabilities) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "ProvisionerCapabilities defines the recommended claim properties of the storage classes of a provisioner",
+		"provisioner": "Provisioner is the provisioner of the storage classes the rule applies to",
+		"parameters":  "Parameters the storage class must have for the rule to apply, e.g. the parameter picking the backend of a driver serving several kinds of storage\n+optional",
+		"accessMode":  "AccessMode is the recommended access mode of the claims",
+		"volumeMode":  "VolumeMode is the recommended volume mode of the claims",
+	}
+}
+
 func (CDIConfigSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                         "CDIConfigSpec defines specification for user configuration",
@@ -343,6 +414,8 @@ func (CDIConfigSpec) SwaggerDoc() map[string]string {
 		"filesystemOverhead":       "FilesystemOverhead describes the space reserved for overhead when using Filesystem volumes. A value is between 0 and 1, if not defined it is 0.055 (5.5% overhead)",
 		"preallocation":            "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
 		"insecureRegistries":       "InsecureRegistries is a list of TLS disabled registries",
+		"importParallelism":        "ImportParallelism is the number of concurrent range requests used to download http imports, when the server supports range requests. Defaults to 1, at most 16, it can be overridden per DataVolume with the cdi.kubevirt.io/storage.import.parallelism annotation\n+optional",
+		"storageCapabilities":      "StorageCapabilities are provisioner rules extending the storage capabilities CDI knows for well known provisioners. The first rule matching a storage class is used, before the rules built into CDI\n+optional",
 	}
 }
 
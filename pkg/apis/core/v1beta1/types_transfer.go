@@ -92,6 +92,9 @@ const (
 
 	// ObjectTransferError is the (terminal) error transfer phase
 	ObjectTransferError ObjectTransferPhase = "Error"
+
+	// ObjectTransferRollingBack is the phase of a transfer that is deleted before it completed, the source is restored
+	ObjectTransferRollingBack ObjectTransferPhase = "RollingBack"
 )
 
 // ObjectTransferConditionType is the type of ObjectTransferCondition
@@ -184,6 +184,18 @@ func (in *CDIConfigSpec) DeepCopyInto(out *CDIConfigSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImportParallelism != nil {
+		in, out := &in.ImportParallelism, &out.ImportParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StorageCapabilities != nil {
+		in, out := &in.StorageCapabilities, &out.StorageCapabilities
+		*out = make([]ProvisionerCapabilities, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -463,7 +475,7 @@ func (in *DataImportCronSource) DeepCopyInto(out *DataImportCronSource) {
 	if in.Registry != nil {
 		in, out := &in.Registry, &out.Registry
 		*out = new(DataVolumeSourceRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -482,11 +494,22 @@ func (in *DataImportCronSource) DeepCopy() *DataImportCronSource {
 func (in *DataImportCronSpec) DeepCopyInto(out *DataImportCronSpec) {
 	*out = *in
 	in.Source.DeepCopyInto(&out.Source)
+	in.Template.DeepCopyInto(&out.Template)
 	if in.GarbageCollect != nil {
 		in, out := &in.GarbageCollect, &out.GarbageCollect
 		*out = new(DataImportCronGarbageCollect)
 		**out = **in
 	}
+	if in.ImportsToKeep != nil {
+		in, out := &in.ImportsToKeep, &out.ImportsToKeep
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImportsMaxAge != nil {
+		in, out := &in.ImportsMaxAge, &out.ImportsMaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -803,7 +826,7 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 	if in.Registry != nil {
 		in, out := &in.Registry, &out.Registry
 		*out = new(DataVolumeSourceRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.PVC != nil {
 		in, out := &in.PVC, &out.PVC
@@ -830,6 +853,11 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 		*out = new(DataVolumeSourceVDDK)
 		**out = **in
 	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(DataVolumeSourceSnapshot)
+		**out = **in
+	}
 	return
 }
 
@@ -915,6 +943,11 @@ func (in *DataVolumeSourceRef) DeepCopy() *DataVolumeSourceRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceRegistry) DeepCopyInto(out *DataVolumeSourceRegistry) {
 	*out = *in
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(RegistrySignatureVerification)
+		**out = **in
+	}
 	return
 }
 
@@ -944,6 +977,22 @@ func (in *DataVolumeSourceS3) DeepCopy() *DataVolumeSourceS3 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSnapshot) DeepCopyInto(out *DataVolumeSourceSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSnapshot.
+func (in *DataVolumeSourceSnapshot) DeepCopy() *DataVolumeSourceSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceUpload) DeepCopyInto(out *DataVolumeSourceUpload) {
 	*out = *in
@@ -1032,6 +1081,11 @@ func (in *DataVolumeStatus) DeepCopyInto(out *DataVolumeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Transfer != nil {
+		in, out := &in.Transfer, &out.Transfer
+		*out = new(DataVolumeTransferStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -1045,6 +1099,22 @@ func (in *DataVolumeStatus) DeepCopy() *DataVolumeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeTransferStatus) DeepCopyInto(out *DataVolumeTransferStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeTransferStatus.
+func (in *DataVolumeTransferStatus) DeepCopy() *DataVolumeTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilesystemOverhead) DeepCopyInto(out *FilesystemOverhead) {
 	*out = *in
@@ -1236,6 +1306,102 @@ func (in *ObjectTransferStatus) DeepCopy() *ObjectTransferStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionerCapabilities) DeepCopyInto(out *ProvisionerCapabilities) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionerCapabilities.
+func (in *ProvisionerCapabilities) DeepCopy() *ProvisionerCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionerCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistrySignatureVerification) DeepCopyInto(out *RegistrySignatureVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistrySignatureVerification.
+func (in *RegistrySignatureVerification) DeepCopy() *RegistrySignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistrySignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProbe) DeepCopyInto(out *StorageProbe) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProbe.
+func (in *StorageProbe) DeepCopy() *StorageProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProbeStatus) DeepCopyInto(out *StorageProbeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClaimPropertySets != nil {
+		in, out := &in.ClaimPropertySets, &out.ClaimPropertySets
+		*out = make([]ClaimPropertySet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotClass != nil {
+		in, out := &in.SnapshotClass, &out.SnapshotClass
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProbeStatus.
+func (in *StorageProbeStatus) DeepCopy() *StorageProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageProfile) DeepCopyInto(out *StorageProfile) {
 	*out = *in
@@ -1307,6 +1473,11 @@ func (in *StorageProfileSpec) DeepCopyInto(out *StorageProfileSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(StorageProbe)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1340,6 +1511,11 @@ func (in *StorageProfileStatus) DeepCopyInto(out *StorageProfileStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(StorageProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
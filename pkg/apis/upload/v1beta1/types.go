@@ -55,3 +55,39 @@ type UploadTokenRequestList struct {
 	// Items contains a list of UploadTokenRequests
 	Items []UploadTokenRequest `json:"items"`
 }
+
+// DownloadTokenRequest is the CR used to initiate a CDI download
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DownloadTokenRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains the parameters of the request
+	Spec DownloadTokenRequestSpec `json:"spec"`
+
+	// Status contains the status of the request
+	Status DownloadTokenRequestStatus `json:"status"`
+}
+
+// DownloadTokenRequestSpec defines the parameters of the token request
+type DownloadTokenRequestSpec struct {
+	// PvcName is the name of the PVC to download from
+	PvcName string `json:"pvcName"`
+}
+
+// DownloadTokenRequestStatus stores the status of a token request
+type DownloadTokenRequestStatus struct {
+	// Token is a JWT token to be inserted in "Authentication Bearer header"
+	Token string `json:"token,omitempty"`
+}
+
+// DownloadTokenRequestList contains a list of DownloadTokenRequests
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DownloadTokenRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items contains a list of DownloadTokenRequests
+	Items []DownloadTokenRequest `json:"items"`
+}
@@ -30,3 +30,32 @@ func (UploadTokenRequestList) SwaggerDoc() map[string]string {
 		"items": "Items contains a list of UploadTokenRequests",
 	}
 }
+
+func (DownloadTokenRequest) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "DownloadTokenRequest is the CR used to initiate a CDI download\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"spec":   "Spec contains the parameters of the request",
+		"status": "Status contains the status of the request",
+	}
+}
+
+func (DownloadTokenRequestSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "DownloadTokenRequestSpec defines the parameters of the token request",
+		"pvcName": "PvcName is the name of the PVC to download from",
+	}
+}
+
+func (DownloadTokenRequestStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DownloadTokenRequestStatus stores the status of a token request",
+		"token": "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+	}
+}
+
+func (DownloadTokenRequestList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DownloadTokenRequestList contains a list of DownloadTokenRequests\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items contains a list of DownloadTokenRequests",
+	}
+}
@@ -38,7 +38,10 @@ import (
 	restful "github.com/emicklei/go-restful"
 	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
@@ -68,6 +71,8 @@ const (
 	objectTransferValidatePath = "/objecttransfer-validate"
 
 	healthzPath = "/healthz"
+
+	metricsPath = "/metrics"
 )
 
 var uploadTokenVersions = []string{"v1beta1", "v1alpha1"}
@@ -178,6 +183,9 @@ func NewCdiAPIServer(bindAddress string,
 		return nil, errors.Errorf("failed to create ObjectTransfer validating webhook: %s", err)
 	}
 
+	// clone authorization metrics of the webhooks, only served to clients allowed to get the metrics path
+	app.container.ServeMux.Handle(metricsPath, &metricsHandler{client: app.client, handler: promhttp.Handler()})
+
 	return app, nil
 }
 
@@ -321,6 +329,78 @@ func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.
 
 }
 
+func (app *cdiAPIApp) downloadHandler(request *restful.Request, response *restful.Response) {
+	allowed, reason, err := app.authorizer.Authorize(request)
+
+	if err != nil {
+		klog.Error(err)
+		response.WriteHeader(http.StatusInternalServerError)
+		return
+	} else if !allowed {
+		klog.Infof("Rejected Request: %s", reason)
+		response.WriteErrorString(http.StatusUnauthorized, reason)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	defer request.Request.Body.Close()
+	body, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	downloadToken := &cdiuploadv1.DownloadTokenRequest{}
+	err = json.Unmarshal(body, downloadToken)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	if err = app.requestDownloadServer(namespace, downloadToken.Spec.PvcName); err != nil {
+		klog.Error(err)
+		if k8serrors.IsNotFound(err) {
+			response.WriteError(http.StatusNotFound, err)
+			return
+		}
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tokenData := &token.Payload{
+		Operation: token.OperationDownload,
+		Name:      downloadToken.Spec.PvcName,
+		Namespace: namespace,
+		Resource: metav1.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "persistentvolumeclaims",
+		},
+	}
+
+	token, err := app.tokenGenerator.Generate(tokenData)
+	if err != nil {
+		klog.Error(err)
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	downloadToken.Status.Token = token
+	response.WriteAsJson(downloadToken)
+}
+
+// requestDownloadServer annotates the PVC as a download source, so the download controller starts a download server
+// for it. The annotation holds the time of the latest request, a download server that stopped after being idle is
+// started again when a token was requested after it stopped.
+func (app *cdiAPIApp) requestDownloadServer(namespace, pvcName string) error {
+	requested := time.Now().UTC().Format(time.RFC3339)
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, common.AnnDownloadRequest, requested)
+	_, err := app.client.CoreV1().PersistentVolumeClaims(namespace).Patch(context.TODO(), pvcName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
 func uploadTokenAPIGroup() metav1.APIGroup {
 	apiGroup := metav1.APIGroup{
 		Name: uploadTokenGroup,
@@ -350,9 +430,14 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 	objExample := reflect.ValueOf(objPointer).Elem().Interface()
 	objKind := "UploadTokenRequest"
 	resource := "uploadtokenrequests"
+	downloadObjPointer := &cdiuploadv1.DownloadTokenRequest{}
+	downloadObjExample := reflect.ValueOf(downloadObjPointer).Elem().Interface()
+	downloadObjKind := "DownloadTokenRequest"
+	downloadResource := "downloadtokenrequests"
 
 	groupPath := fmt.Sprintf("/apis/%s", uploadTokenGroup)
 	createPath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", resource)
+	downloadCreatePath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", downloadResource)
 
 	app.container = restful.NewContainer()
 
@@ -379,6 +464,18 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 			Returns(http.StatusUnauthorized, "Unauthorized", "").
 			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
 
+		uploadTokenWs.Route(uploadTokenWs.POST(downloadCreatePath).
+			Produces("application/json").
+			Consumes("application/json").
+			Operation("createNamespaced"+downloadObjKind+"-"+v).
+			To(app.downloadHandler).Reads(downloadObjExample).Writes(downloadObjExample).
+			Doc("Create a DownloadTokenRequest object.").
+			Returns(http.StatusOK, "OK", downloadObjExample).
+			Returns(http.StatusCreated, "Created", downloadObjExample).
+			Returns(http.StatusAccepted, "Accepted", downloadObjExample).
+			Returns(http.StatusUnauthorized, "Unauthorized", "").
+			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
+
 		uploadTokenWs.Route(uploadTokenWs.GET("/").
 			Produces("application/json").Writes(metav1.APIResourceList{}).
 			To(func(request *restful.Request, response *restful.Response) {
@@ -396,6 +493,16 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 					Verbs:        []string{"create"},
 					ShortNames:   []string{"utr", "utrs"},
 				})
+				list.APIResources = append(list.APIResources, metav1.APIResource{
+					Name:         "downloadtokenrequests",
+					SingularName: "downloadtokenrequest",
+					Namespaced:   true,
+					Group:        uploadTokenGroup,
+					Version:      uploadTokenVersion,
+					Kind:         "DownloadTokenRequest",
+					Verbs:        []string{"create"},
+					ShortNames:   []string{"dtr", "dtrs"},
+				})
 				response.WriteAsJson(list)
 			}).
 			Operation("getAPIResources-"+v).
@@ -20,6 +20,7 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
@@ -27,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -41,7 +43,9 @@ import (
 	core "k8s.io/client-go/testing"
 
 	cdiuploadv1 "kubevirt.io/containerized-data-importer/pkg/apis/upload/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/keys/keystest"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
 type testAuthorizer struct {
@@ -240,6 +244,16 @@ var _ = Describe("API server tests", func() {
 					Verbs:        []string{"create"},
 					ShortNames:   []string{"utr", "utrs"},
 				},
+				{
+					Name:         "downloadtokenrequests",
+					SingularName: "downloadtokenrequest",
+					Namespaced:   true,
+					Group:        "upload.cdi.kubevirt.io",
+					Version:      version,
+					Kind:         "DownloadTokenRequest",
+					Verbs:        []string{"create"},
+					ShortNames:   []string{"dtr", "dtrs"},
+				},
 			},
 		}
 
@@ -406,3 +420,77 @@ var _ = Describe("API server tests", func() {
 			true),
 	)
 })
+
+var _ = Describe("Download token tests", func() {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	request := &cdiuploadv1.DownloadTokenRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-token",
+			Namespace: "default",
+		},
+		Spec: cdiuploadv1.DownloadTokenRequestSpec{
+			PvcName: "test-pvc",
+		},
+	}
+
+	serializedRequest, err := json.Marshal(request)
+	if err != nil {
+		panic(err)
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pvc",
+			Namespace: "default",
+		},
+	}
+
+	table.DescribeTable("Get download token", func(authorizer CdiAPIAuthorizer, objects []runtime.Object, expectedStatus int, checkToken bool) {
+		client := k8sfake.NewSimpleClientset(objects...)
+		app := &cdiAPIApp{client: client,
+			privateSigningKey: signingKey,
+			authorizer:        authorizer,
+			tokenGenerator:    newUploadTokenGenerator(signingKey)}
+		app.composeUploadTokenAPI()
+
+		req, err := http.NewRequest("POST",
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/downloadtokenrequests",
+			bytes.NewReader(serializedRequest))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		app.container.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(expectedStatus))
+
+		if checkToken {
+			downloadTokenRequest := &cdiuploadv1.DownloadTokenRequest{}
+			err := json.Unmarshal(rr.Body.Bytes(), &downloadTokenRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(downloadTokenRequest.Status.Token).ToNot(BeEmpty())
+
+			validator := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, 0)
+			payload, err := validator.Validate(downloadTokenRequest.Status.Token)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(payload.Operation).To(Equal(token.OperationDownload))
+			Expect(payload.Name).To(Equal("test-pvc"))
+			Expect(payload.Namespace).To(Equal("default"))
+
+			pvc, err := client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "test-pvc", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations).To(HaveKey(common.AnnDownloadRequest))
+			_, err = time.Parse(time.RFC3339, pvc.Annotations[common.AnnDownloadRequest])
+			Expect(err).ToNot(HaveOccurred())
+		}
+	},
+		table.Entry("authoriser error", &testAuthorizer{allowed: false, reason: "", err: fmt.Errorf("Error")}, nil, http.StatusInternalServerError, false),
+		table.Entry("authoriser not allowed", &testAuthorizer{allowed: false, reason: "bad person", err: nil}, nil, http.StatusUnauthorized, false),
+		table.Entry("pvc does not exist", &testAuthorizer{allowed: true}, nil, http.StatusNotFound, false),
+		table.Entry("download allowed", &testAuthorizer{allowed: true}, []runtime.Object{pvc}, http.StatusOK, true),
+	)
+})
@@ -116,7 +116,7 @@ func (a *authorizor) generateAccessReview(req *restful.Request) (*authorization.
 		return nil, fmt.Errorf("unknown api group %s", group)
 	}
 
-	if resource != "uploadtokenrequests" {
+	if resource != "uploadtokenrequests" && resource != "downloadtokenrequests" {
 		return nil, fmt.Errorf("unknown resource type %s", resource)
 	}
 
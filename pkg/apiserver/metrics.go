@@ -0,0 +1,119 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// metricsHandler serves the metrics to clients that may get the metrics path. The client authenticates with a bearer
+// token, like the service account token Prometheus scrapes with.
+type metricsHandler struct {
+	client  kubernetes.Interface
+	handler http.Handler
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok, err := h.authenticate(r)
+	if err != nil {
+		klog.Errorf("Error authenticating metrics request: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		http.Error(w, "request is not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	allowed, reason, err := h.authorize(userInfo)
+	if err != nil {
+		klog.Errorf("Error authorizing metrics request: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		http.Error(w, reason, http.StatusForbidden)
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *metricsHandler) authenticate(r *http.Request) (*authentication.UserInfo, bool, error) {
+	auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], "bearer") || auth[1] == "" {
+		return nil, false, nil
+	}
+
+	tr := &authentication.TokenReview{
+		Spec: authentication.TokenReviewSpec{
+			Token: auth[1],
+		},
+	}
+
+	result, err := h.client.AuthenticationV1().TokenReviews().Create(context.TODO(), tr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !result.Status.Authenticated {
+		return nil, false, nil
+	}
+
+	return &result.Status.User, true, nil
+}
+
+func (h *metricsHandler) authorize(userInfo *authentication.UserInfo) (bool, string, error) {
+	extra := make(map[string]authorization.ExtraValue)
+	for k, v := range userInfo.Extra {
+		extra[k] = authorization.ExtraValue(v)
+	}
+
+	sar := &authorization.SubjectAccessReview{
+		Spec: authorization.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			UID:    userInfo.UID,
+			Extra:  extra,
+			NonResourceAttributes: &authorization.NonResourceAttributes{
+				Path: metricsPath,
+				Verb: "get",
+			},
+		},
+	}
+
+	result, err := h.client.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+
+	allowed, reason := isAllowed(result)
+	return allowed, reason, nil
+}
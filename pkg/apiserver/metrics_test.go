@@ -0,0 +1,79 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Metrics handler tests", func() {
+	table.DescribeTable("Get metrics", func(header string, authenticated, allowed bool, expectedStatus int) {
+		client := k8sfake.NewSimpleClientset()
+		client.PrependReactor("create", "tokenreviews", func(action core.Action) (bool, runtime.Object, error) {
+			tr := action.(core.CreateAction).GetObject().(*authentication.TokenReview)
+			Expect(tr.Spec.Token).To(Equal("token"))
+			tr.Status.Authenticated = authenticated
+			tr.Status.User = authentication.UserInfo{Username: "system:serviceaccount:monitoring:prometheus"}
+			return true, tr, nil
+		})
+		client.PrependReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+			sar := action.(core.CreateAction).GetObject().(*authorization.SubjectAccessReview)
+			Expect(sar.Spec.User).To(Equal("system:serviceaccount:monitoring:prometheus"))
+			Expect(sar.Spec.NonResourceAttributes.Path).To(Equal("/metrics"))
+			Expect(sar.Spec.NonResourceAttributes.Verb).To(Equal("get"))
+			sar.Status.Allowed = allowed
+			return true, sar, nil
+		})
+
+		h := &metricsHandler{
+			client: client,
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		Expect(err).ToNot(HaveOccurred())
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(expectedStatus))
+	},
+		table.Entry("succeed with an allowed token", "Bearer token", true, true, http.StatusOK),
+		table.Entry("fail without a token", "", true, true, http.StatusUnauthorized),
+		table.Entry("fail with another auth scheme", "Basic token", true, true, http.StatusUnauthorized),
+		table.Entry("fail with an invalid token", "Bearer token", false, true, http.StatusUnauthorized),
+		table.Entry("fail when not allowed", "Bearer token", true, false, http.StatusForbidden),
+	)
+})
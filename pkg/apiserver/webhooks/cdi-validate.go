@@ -27,11 +27,14 @@ import (
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/storagecapabilities"
 )
 
 const uninstallErrorMsg = "Rejecting the uninstall request, since there are still DataVolumes present. Either delete all DataVolumes or change the uninstall strategy before uninstalling CDI."
@@ -48,6 +51,10 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admission
 		return toAdmissionResponseError(fmt.Errorf("unexpected resource: %s", ar.Request.Resource.Resource))
 	}
 
+	if ar.Request.Operation == admissionv1.Create || ar.Request.Operation == admissionv1.Update {
+		return validateCDIConfig(ar)
+	}
+
 	if ar.Request.Operation != admissionv1.Delete {
 		klog.V(3).Infof("Got unexpected operation type %s", ar.Request.Operation)
 		return allowedAdmissionResponse()
@@ -77,6 +84,50 @@ func (wh *cdiValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admission
 	return allowedAdmissionResponse()
 }
 
+// validateCDIConfig rejects a configuration the CDI controllers cannot use. An update is only validated if it changes
+// the storage capabilities, so a CDI created before the validation can still be updated.
+func validateCDIConfig(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	if len(ar.Request.Object.Raw) == 0 {
+		return allowedAdmissionResponse()
+	}
+
+	cdi := &cdiv1.CDI{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, cdi); err != nil {
+		return toAdmissionResponseError(err)
+	}
+	rules := getStorageCapabilities(cdi)
+	if len(rules) == 0 {
+		return allowedAdmissionResponse()
+	}
+
+	if ar.Request.Operation == admissionv1.Update && len(ar.Request.OldObject.Raw) > 0 {
+		oldCDI := &cdiv1.CDI{}
+		if err := json.Unmarshal(ar.Request.OldObject.Raw, oldCDI); err != nil {
+			return toAdmissionResponseError(err)
+		}
+		if apiequality.Semantic.DeepEqual(getStorageCapabilities(oldCDI), rules) {
+			return allowedAdmissionResponse()
+		}
+	}
+
+	if err := storagecapabilities.ValidateRules(rules); err != nil {
+		return toRejectedAdmissionResponse([]metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid storage capabilities: %v", err),
+			Field:   k8sfield.NewPath("spec", "config", "storageCapabilities").String(),
+		}})
+	}
+
+	return allowedAdmissionResponse()
+}
+
+func getStorageCapabilities(cdi *cdiv1.CDI) []cdiv1.ProvisionerCapabilities {
+	if cdi.Spec.Config == nil {
+		return nil
+	}
+	return cdi.Spec.Config.StorageCapabilities
+}
+
 func (wh *cdiValidatingWebhook) getResource(ar admissionv1.AdmissionReview) (*cdiv1.CDI, error) {
 	var cdi *cdiv1.CDI
 
@@ -28,6 +28,7 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -250,6 +251,83 @@ var _ = Describe("CDI Delete Webhook", func() {
 	})
 })
 
+var _ = Describe("CDI Config Webhook", func() {
+	newStorageCapabilitiesReview := func(op admissionv1.Operation, rules ...cdiv1.ProvisionerCapabilities) *admissionv1.AdmissionReview {
+		cdi := &cdiv1.CDI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cdi",
+			},
+			Spec: cdiv1.CDISpec{
+				Config: &cdiv1.CDIConfigSpec{
+					StorageCapabilities: rules,
+				},
+			},
+		}
+
+		bytes, _ := json.Marshal(cdi)
+
+		return &admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				Operation: op,
+				Resource: metav1.GroupVersionResource{
+					Group:    cdiv1.SchemeGroupVersion.Group,
+					Version:  cdiv1.SchemeGroupVersion.Version,
+					Resource: "cdis",
+				},
+				Object: runtime.RawExtension{
+					Raw: bytes,
+				},
+			},
+		}
+	}
+
+	sharedRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "csi.example.com",
+		Parameters:  map[string]string{"shared": "true"},
+		AccessMode:  corev1.ReadWriteMany,
+		VolumeMode:  corev1.PersistentVolumeFilesystem,
+	}
+	defaultRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "csi.example.com",
+		AccessMode:  corev1.ReadWriteOnce,
+		VolumeMode:  corev1.PersistentVolumeBlock,
+	}
+
+	DescribeTable("should accept valid storage capabilities", func(op admissionv1.Operation) {
+		resp := validateCDIs(newStorageCapabilitiesReview(op, sharedRule, defaultRule))
+		Expect(resp.Allowed).To(BeTrue())
+	},
+		Entry("CREATE", admissionv1.Create),
+		Entry("UPDATE", admissionv1.Update),
+	)
+
+	DescribeTable("should reject invalid storage capabilities", func(rule cdiv1.ProvisionerCapabilities, message string) {
+		resp := validateCDIs(newStorageCapabilitiesReview(admissionv1.Update, defaultRule, rule))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring(message))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.config.storageCapabilities"))
+	},
+		Entry("without provisioner", cdiv1.ProvisionerCapabilities{AccessMode: corev1.ReadWriteOnce, VolumeMode: corev1.PersistentVolumeBlock}, "rule 1: provisioner is missing"),
+		Entry("with an unknown access mode", cdiv1.ProvisionerCapabilities{Provisioner: "csi.example.com", Parameters: map[string]string{"shared": "true"}, AccessMode: "ReadWriteSometimes", VolumeMode: corev1.PersistentVolumeBlock}, "rule 1: unsupported accessMode"),
+		Entry("without volume mode", cdiv1.ProvisionerCapabilities{Provisioner: "csi.example.com", Parameters: map[string]string{"shared": "true"}, AccessMode: corev1.ReadWriteMany}, "rule 1: unsupported volumeMode"),
+		Entry("shadowed by a previous rule", cdiv1.ProvisionerCapabilities{Provisioner: "csi.example.com", AccessMode: corev1.ReadWriteMany, VolumeMode: corev1.PersistentVolumeFilesystem}, "rule 1: duplicates rule 0"),
+	)
+
+	DescribeTable("should only validate updates that change the storage capabilities", func(oldRules []cdiv1.ProvisionerCapabilities, expectAllowed bool) {
+		invalidRule := cdiv1.ProvisionerCapabilities{AccessMode: corev1.ReadWriteOnce, VolumeMode: corev1.PersistentVolumeBlock}
+		ar := newStorageCapabilitiesReview(admissionv1.Update, defaultRule, invalidRule)
+		old := newStorageCapabilitiesReview(admissionv1.Update, oldRules...)
+		ar.Request.OldObject = old.Request.Object
+
+		resp := validateCDIs(ar)
+		Expect(resp.Allowed).To(Equal(expectAllowed))
+	},
+		Entry("accept unchanged invalid rules", []cdiv1.ProvisionerCapabilities{defaultRule, {AccessMode: corev1.ReadWriteOnce, VolumeMode: corev1.PersistentVolumeBlock}}, true),
+		Entry("reject changed invalid rules", []cdiv1.ProvisionerCapabilities{defaultRule}, false),
+		Entry("reject new invalid rules", nil, false),
+	)
+})
+
 func newDataVolumeWithName(name string) *cdiv1.DataVolume {
 	return &cdiv1.DataVolume{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,134 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	authentication "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// CloneAuthorized is the event reason of an allowed cross namespace clone
+	CloneAuthorized = "CloneAuthorized"
+	// CloneDenied is the event reason of a denied cross namespace clone
+	CloneDenied = "CloneDenied"
+
+	cloneResultAllowed = "allowed"
+	cloneResultDenied  = "denied"
+
+	cloneAuditPrefix = "clone.cdi.kubevirt.io/"
+)
+
+var cloneAuthorizations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "clone_authorizations_total",
+		Help: "The number of cross namespace clone authorization decisions",
+	},
+	[]string{"source_namespace", "target_namespace", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(cloneAuthorizations)
+}
+
+// cloneDecision is the outcome of the authorization of a DataVolume cloning from another namespace
+type cloneDecision struct {
+	userInfo        authentication.UserInfo
+	resource        string
+	sourceNamespace string
+	sourceName      string
+	targetNamespace string
+	targetName      string
+	allowed         bool
+	reason          string
+	tokenID         string
+	// dryRun is set for an admission that is not persisted
+	dryRun bool
+}
+
+func (d *cloneDecision) result() string {
+	if d.allowed {
+		return cloneResultAllowed
+	}
+	return cloneResultDenied
+}
+
+// cloneAuditor records who cloned what between namespaces, so data movement between tenants can be audited
+type cloneAuditor struct {
+	recorder record.EventRecorder
+}
+
+func newCloneAuditor(client kubernetes.Interface) *cloneAuditor {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	return &cloneAuditor{
+		recorder: broadcaster.NewRecorder(scheme, corev1.EventSource{Component: "cdi-apiserver"}),
+	}
+}
+
+// audit emits an event on the target DataVolume and counts the decision, clones within a namespace and dry runs are
+// not audited
+func (a *cloneAuditor) audit(dataVolume *cdiv1.DataVolume, d *cloneDecision) {
+	if a == nil || d.dryRun || d.sourceNamespace == d.targetNamespace {
+		return
+	}
+
+	cloneAuthorizations.WithLabelValues(d.sourceNamespace, d.targetNamespace, d.result()).Inc()
+
+	if d.allowed && d.reason == "" {
+		d.reason = "User has permission in clone source namespace"
+	}
+
+	klog.Infof("Clone of %s %s/%s to %s/%s by user %s (groups %v) %s: %s, token %q",
+		d.resource, d.sourceNamespace, d.sourceName, d.targetNamespace, d.targetName,
+		d.userInfo.Username, d.userInfo.Groups, d.result(), d.reason, d.tokenID)
+
+	// the DataVolume may not have a namespace or name yet when it is created
+	target := dataVolume.DeepCopy()
+	target.Namespace, target.Name = d.targetNamespace, d.targetName
+
+	annotations := map[string]string{
+		cloneAuditPrefix + "user":     d.userInfo.Username,
+		cloneAuditPrefix + "groups":   strings.Join(d.userInfo.Groups, ","),
+		cloneAuditPrefix + "resource": d.resource,
+		cloneAuditPrefix + "source":   d.sourceNamespace + "/" + d.sourceName,
+		cloneAuditPrefix + "target":   d.targetNamespace + "/" + d.targetName,
+		cloneAuditPrefix + "result":   d.result(),
+		cloneAuditPrefix + "reason":   d.reason,
+		cloneAuditPrefix + "tokenID":  d.tokenID,
+	}
+
+	if d.allowed {
+		a.recorder.AnnotatedEventf(target, annotations, corev1.EventTypeNormal, CloneAuthorized,
+			"User %s allowed to clone %s %s/%s, token %s", d.userInfo.Username, d.resource, d.sourceNamespace, d.sourceName, d.tokenID)
+	} else {
+		a.recorder.AnnotatedEventf(target, annotations, corev1.EventTypeWarning, CloneDenied,
+			"User %s denied to clone %s %s/%s: %s", d.userInfo.Username, d.resource, d.sourceNamespace, d.sourceName, d.reason)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package webhooks
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	dto "github.com/prometheus/client_model/go"
+	authentication "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Clone authorization audit", func() {
+	var (
+		events  chan *corev1.Event
+		auditor *cloneAuditor
+	)
+
+	BeforeEach(func() {
+		events = make(chan *corev1.Event, 10)
+		client := fakeclient.NewSimpleClientset()
+		// the fake clientset rejects events created without a namespace, as the event sink does
+		client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			event := action.(k8stesting.CreateAction).GetObject().(*corev1.Event)
+			events <- event
+			return true, event, nil
+		})
+		auditor = newCloneAuditor(client)
+	})
+
+	cloneAuthorizationCount := func(sourceNamespace, targetNamespace, result string) float64 {
+		metric := &dto.Metric{}
+		err := cloneAuthorizations.WithLabelValues(sourceNamespace, targetNamespace, result).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		return metric.GetCounter().GetValue()
+	}
+
+	DescribeTable("should record a cross namespace clone decision", func(allowed bool, result, reason, eventType, eventReason, tokenID string) {
+		before := cloneAuthorizationCount("source", "target", result)
+		dataVolume := newPVCDataVolume("", "source", "test")
+		auditor.audit(dataVolume, &cloneDecision{
+			userInfo:        authentication.UserInfo{Username: "tenant", Groups: []string{"system:authenticated"}},
+			resource:        "persistentvolumeclaims",
+			sourceNamespace: "source",
+			sourceName:      "test",
+			targetNamespace: "target",
+			targetName:      "testDV",
+			allowed:         allowed,
+			reason:          reason,
+			tokenID:         tokenID,
+		})
+
+		var event *corev1.Event
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Namespace).To(Equal("target"))
+		Expect(event.Type).To(Equal(eventType))
+		Expect(event.Reason).To(Equal(eventReason))
+		Expect(event.InvolvedObject.Name).To(Equal("testDV"))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"user", "tenant"))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"groups", "system:authenticated"))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"source", "source/test"))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"target", "target/testDV"))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"tokenID", tokenID))
+		Expect(event.Annotations).To(HaveKeyWithValue(cloneAuditPrefix+"result", result))
+		Expect(cloneAuthorizationCount("source", "target", result)).To(Equal(before + 1))
+	},
+		Entry("when allowed", true, cloneResultAllowed, "", corev1.EventTypeNormal, CloneAuthorized, "1234"),
+		Entry("when denied", false, cloneResultDenied, "no permission", corev1.EventTypeWarning, CloneDenied, ""),
+	)
+
+	It("should not record a clone within a namespace", func() {
+		before := cloneAuthorizationCount("target", "target", cloneResultAllowed)
+		auditor.audit(newPVCDataVolume("testDV", "target", "test"), &cloneDecision{
+			resource:        "persistentvolumeclaims",
+			sourceNamespace: "target",
+			sourceName:      "test",
+			targetNamespace: "target",
+			targetName:      "testDV",
+			allowed:         true,
+		})
+		Expect(cloneAuthorizationCount("target", "target", cloneResultAllowed)).To(Equal(before))
+		Consistently(events).ShouldNot(Receive())
+	})
+
+	It("should not record a dry run", func() {
+		before := cloneAuthorizationCount("source", "target", cloneResultAllowed)
+		auditor.audit(newPVCDataVolume("testDV", "target", "test"), &cloneDecision{
+			resource:        "persistentvolumeclaims",
+			sourceNamespace: "source",
+			sourceName:      "test",
+			targetNamespace: "target",
+			targetName:      "testDV",
+			allowed:         true,
+			dryRun:          true,
+		})
+		Expect(cloneAuthorizationCount("source", "target", cloneResultAllowed)).To(Equal(before))
+		Consistently(events).ShouldNot(Receive())
+	})
+})
@@ -23,9 +23,11 @@ import (
 	"context"
 	"encoding/json"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	admissionv1 "k8s.io/api/admission/v1"
 	authv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -42,6 +44,7 @@ type dataVolumeMutatingWebhook struct {
 	cdiClient      cdiclient.Interface
 	tokenGenerator token.Generator
 	proxy          clone.SubjectAccessReviewsProxy
+	auditor        *cloneAuditor
 }
 
 type sarProxy struct {
@@ -54,6 +57,12 @@ var (
 		Version:  "v1",
 		Resource: "persistentvolumeclaims",
 	}
+
+	snapshotTokenResource = metav1.GroupVersionResource{
+		Group:    snapshotv1.GroupName,
+		Version:  snapshotv1.SchemeGroupVersion.Version,
+		Resource: "volumesnapshots",
+	}
 )
 
 func (p *sarProxy) Create(sar *authv1.SubjectAccessReview) (*authv1.SubjectAccessReview, error) {
@@ -63,6 +72,7 @@ func (p *sarProxy) Create(sar *authv1.SubjectAccessReview) (*authv1.SubjectAcces
 func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	var dataVolume, oldDataVolume cdiv1.DataVolume
 	var pvcSource *cdiv1.DataVolumeSourcePVC
+	var snapshotSource *cdiv1.DataVolumeSourceSnapshot
 
 	klog.V(3).Infof("Got AdmissionReview %+v", ar)
 
@@ -76,6 +86,7 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 
 	if dataVolume.Spec.Source != nil {
 		pvcSource = dataVolume.Spec.Source.PVC
+		snapshotSource = dataVolume.Spec.Source.Snapshot
 	} else if dataVolume.Spec.SourceRef != nil && dataVolume.Spec.SourceRef.Kind == cdiv1.DataVolumeDataSource {
 		ns := dataVolume.Namespace
 		if dataVolume.Spec.SourceRef.Namespace != nil && *dataVolume.Spec.SourceRef.Namespace != "" {
@@ -97,12 +108,25 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		targetName = ar.Request.Name
 	}
 
-	if pvcSource == nil {
+	if pvcSource == nil && snapshotSource == nil {
 		klog.V(3).Infof("DataVolume %s/%s not cloning", targetNamespace, targetName)
 		return allowedAdmissionResponse()
 	}
 
-	sourceNamespace, sourceName := pvcSource.Namespace, pvcSource.Name
+	// a snapshot is authorized the same way as a PVC, the token is issued for the snapshot
+	canClone := clone.CanUserClonePVC
+	sourceField := k8sfield.NewPath("spec", "source", "PVC", "namespace")
+	resource := tokenResource
+	var sourceNamespace, sourceName string
+	if pvcSource != nil {
+		sourceNamespace, sourceName = pvcSource.Namespace, pvcSource.Name
+	} else {
+		sourceNamespace, sourceName = snapshotSource.Namespace, snapshotSource.Name
+		canClone = clone.CanUserCloneSnapshot
+		sourceField = k8sfield.NewPath("spec", "source", "snapshot", "namespace")
+		resource = snapshotTokenResource
+	}
+
 	if sourceNamespace == "" {
 		sourceNamespace = targetNamespace
 	}
@@ -119,27 +143,41 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		}
 	}
 
-	ok, reason, err := clone.CanUserClonePVC(wh.proxy, sourceNamespace, sourceName, targetNamespace, ar.Request.UserInfo)
+	ok, reason, err := canClone(wh.proxy, sourceNamespace, sourceName, targetNamespace, ar.Request.UserInfo)
 	if err != nil {
 		return toAdmissionResponseError(err)
 	}
 
+	decision := &cloneDecision{
+		userInfo:        ar.Request.UserInfo,
+		resource:        resource.Resource,
+		sourceNamespace: sourceNamespace,
+		sourceName:      sourceName,
+		targetNamespace: targetNamespace,
+		targetName:      targetName,
+		allowed:         ok,
+		reason:          reason,
+		dryRun:          ar.Request.DryRun != nil && *ar.Request.DryRun,
+	}
+
 	if !ok {
+		wh.auditor.audit(&dataVolume, decision)
 		causes := []metav1.StatusCause{
 			{
 				Type:    metav1.CauseTypeFieldValueInvalid,
 				Message: reason,
-				Field:   k8sfield.NewPath("spec", "source", "PVC", "namespace").String(),
+				Field:   sourceField.String(),
 			},
 		}
 		return toRejectedAdmissionResponse(causes)
 	}
 
 	tokenData := &token.Payload{
+		ID:        string(uuid.NewUUID()),
 		Operation: token.OperationClone,
 		Name:      sourceName,
 		Namespace: sourceNamespace,
-		Resource:  tokenResource,
+		Resource:  resource,
 		Params: map[string]string{
 			"targetNamespace": targetNamespace,
 			"targetName":      targetName,
@@ -151,6 +189,9 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		return toAdmissionResponseError(err)
 	}
 
+	decision.tokenID = tokenData.ID
+	wh.auditor.audit(&dataVolume, decision)
+
 	modifiedDataVolume := dataVolume.DeepCopy()
 	if modifiedDataVolume.Annotations == nil {
 		modifiedDataVolume.Annotations = make(map[string]string)
@@ -24,8 +24,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/opencontainers/go-digest"
 	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -38,7 +42,9 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/controller"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 type dataVolumeValidatingWebhook struct {
@@ -72,6 +78,25 @@ func validateDataVolumeName(name string) []metav1.StatusCause {
 	return causes
 }
 
+// validateImportParallelism checks the annotation overriding the number of concurrent http range requests, unless it
+// did not change on update.
+func validateImportParallelism(dv, oldDV *cdiv1.DataVolume) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	value, ok := dv.Annotations[controller.AnnImportParallelism]
+	if !ok || (oldDV != nil && oldDV.Annotations[controller.AnnImportParallelism] == value) {
+		return causes
+	}
+	parallelism, err := strconv.Atoi(value)
+	if err != nil || parallelism < 1 || parallelism > common.MaxImportParallelism {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be an integer between 1 and %d", controller.AnnImportParallelism, common.MaxImportParallelism),
+			Field:   k8sfield.NewPath("metadata").Child("annotations").Key(controller.AnnImportParallelism).String(),
+		})
+	}
+	return causes
+}
+
 func validateContentTypes(sourcePVC *v1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec) (bool, cdiv1.DataVolumeContentType, cdiv1.DataVolumeContentType) {
 	sourceContentType := cdiv1.DataVolumeContentType(controller.GetContentType(sourcePVC))
 	targetContentType := spec.ContentType
@@ -219,6 +244,21 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		}
 	}
 
+	if spec.Source.HTTP != nil || spec.Source.S3 != nil || spec.Source.Upload != nil {
+		var cause *metav1.StatusCause
+		if spec.Source.HTTP != nil {
+			cause = validateChecksum(spec.Source.HTTP.Checksum, field.Child("source", "HTTP", "checksum"))
+		} else if spec.Source.S3 != nil {
+			cause = validateChecksum(spec.Source.S3.Checksum, field.Child("source", "S3", "checksum"))
+		} else {
+			cause = validateChecksum(spec.Source.Upload.Checksum, field.Child("source", "Upload", "checksum"))
+		}
+		if cause != nil {
+			causes = append(causes, *cause)
+			return causes
+		}
+	}
+
 	// Make sure contentType is either empty (kubevirt), or kubevirt or archive
 	if spec.ContentType != "" && string(spec.ContentType) != string(cdiv1.DataVolumeKubeVirt) && string(spec.ContentType) != string(cdiv1.DataVolumeArchive) {
 		sourceType = field.Child("contentType").String()
@@ -250,6 +290,14 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		return causes
 	}
 
+	if spec.Source.Registry != nil {
+		cause := validateDataVolumeSourceRegistry(spec.Source.Registry, field.Child("source", "Registry"))
+		if cause != nil {
+			causes = append(causes, *cause)
+			return causes
+		}
+	}
+
 	if spec.Source.Imageio != nil {
 		if spec.Source.Imageio.SecretRef == "" || spec.Source.Imageio.CertConfigMap == "" || spec.Source.Imageio.DiskID == "" {
 			causes = append(causes, metav1.StatusCause{
@@ -289,6 +337,17 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		}
 	}
 
+	if spec.Source.Snapshot != nil {
+		if spec.Source.Snapshot.Namespace == "" || spec.Source.Snapshot.Name == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s source snapshot is not valid", field.Child("source", "snapshot").String()),
+				Field:   field.Child("source", "snapshot").String(),
+			})
+			return causes
+		}
+	}
+
 	return causes
 }
 
@@ -377,6 +436,51 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSourcePVC(PVC *cdiv1.Da
 	return nil
 }
 
+func validateChecksum(checksum string, field *k8sfield.Path) *metav1.StatusCause {
+	if checksum == "" {
+		return nil
+	}
+	if _, _, err := util.ParseChecksum(checksum); err != nil {
+		return &metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid checksum: %v", err),
+			Field:   field.String(),
+		}
+	}
+	return nil
+}
+
+func validateDataVolumeSourceRegistry(registry *cdiv1.DataVolumeSourceRegistry, field *k8sfield.Path) *metav1.StatusCause {
+	if diskPath := registry.DiskPath; diskPath != "" {
+		if path.IsAbs(diskPath) || path.Clean(diskPath) != diskPath || diskPath == "." || diskPath == ".." || strings.HasPrefix(diskPath, "../") {
+			return &metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid diskPath %q, must be a clean relative path to a file in the image", diskPath),
+				Field:   field.Child("diskPath").String(),
+			}
+		}
+	}
+	if registry.Digest != "" {
+		if _, err := digest.Parse(registry.Digest); err != nil {
+			return &metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Invalid digest %q: %v", registry.Digest, err),
+				Field:   field.Child("digest").String(),
+			}
+		}
+	}
+	if verification := registry.SignatureVerification; verification != nil {
+		if (verification.PublicKeyConfigMap == "") == (verification.PolicyConfigMap == "") {
+			return &metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "signatureVerification requires exactly one of publicKeyConfigMap or policyConfigMap",
+				Field:   field.Child("signatureVerification").String(),
+			}
+		}
+	}
+	return nil
+}
+
 func validateStorageSize(resources v1.ResourceRequirements, field *k8sfield.Path, name string) (*metav1.StatusCause, bool) {
 	if pvcSize, ok := resources.Requests["storage"]; ok {
 		if pvcSize.IsZero() || pvcSize.Value() < 0 {
@@ -412,9 +516,10 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 		return toAdmissionResponseError(err)
 	}
 
+	var oldDV *cdiv1.DataVolume
 	if ar.Request.Operation == admissionv1.Update {
-		oldDV := cdiv1.DataVolume{}
-		err = json.Unmarshal(ar.Request.OldObject.Raw, &oldDV)
+		oldDV = &cdiv1.DataVolume{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, oldDV)
 		if err != nil {
 			return toAdmissionResponseError(err)
 		}
@@ -446,7 +551,7 @@ func (wh *dataVolumeValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *ad
 		}
 	}
 
-	causes := validateDataVolumeName(dv.Name)
+	causes := append(validateDataVolumeName(dv.Name), validateImportParallelism(&dv, oldDV)...)
 	if len(causes) > 0 {
 		klog.Infof("rejected DataVolume admission")
 		return toRejectedAdmissionResponse(causes)
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -40,6 +41,7 @@ import (
 	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/controller"
 )
 
 var (
@@ -74,6 +76,69 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(true))
 		})
 
+		It("should accept DataVolume with Registry source pinned by digest and verified by public key", func() {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.Digest = "sha256:" + strings.Repeat("a", 64)
+			dataVolume.Spec.Source.Registry.SignatureVerification = &cdiv1.RegistrySignatureVerification{PublicKeyConfigMap: "cosign-key"}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		It("should accept DataVolume with Registry source selecting a disk file", func() {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.DiskPath = "disk/data.qcow2"
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		DescribeTable("should reject DataVolume with Registry source and an invalid disk path", func(diskPath string) {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.DiskPath = diskPath
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		},
+			Entry("absolute", "/disk/data.qcow2"),
+			Entry("directory", "disk/"),
+			Entry("current directory", "."),
+			Entry("outside of the image", "../data.qcow2"),
+			Entry("not clean", "disk/../disk/data.qcow2"),
+		)
+
+		It("should reject DataVolume with Registry source and an invalid digest", func() {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.Digest = "sha256:1234"
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		})
+
+		It("should accept DataVolume with HTTP source and a checksum", func() {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Spec.Source.HTTP.Checksum = "sha256:" + strings.Repeat("a", 64)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		DescribeTable("should reject DataVolume with an invalid checksum", func(source cdiv1.DataVolumeSource) {
+			pvc := newHTTPDataVolume("testDV", "http://www.example.com").Spec.PVC
+			dataVolume := newDataVolume("testDV", source, pvc)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		},
+			Entry("HTTP source with an unknown algorithm", cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "http://www.example.com", Checksum: "sha1:" + strings.Repeat("a", 40)}}),
+			Entry("S3 source with a short value", cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://www.example.com", Checksum: "md5:1234"}}),
+			Entry("upload source without an algorithm", cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{Checksum: strings.Repeat("a", 64)}}),
+		)
+
+		DescribeTable("should reject DataVolume with Registry source and signature verification", func(verification *cdiv1.RegistrySignatureVerification) {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			dataVolume.Spec.Source.Registry.SignatureVerification = verification
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		},
+			Entry("without a ConfigMap", &cdiv1.RegistrySignatureVerification{}),
+			Entry("with both ConfigMaps", &cdiv1.RegistrySignatureVerification{PublicKeyConfigMap: "cosign-key", PolicyConfigMap: "policy"}),
+		)
+
 		It("should accept DataVolume with PVC source on create", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			pvc := &corev1.PersistentVolumeClaim{
@@ -121,6 +186,21 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(false))
 		})
 
+		It("should accept DataVolume with snapshot source on create", func() {
+			dataVolume := newSnapshotDataVolume("testDV", "testNamespace", "test")
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
+		DescribeTable("should reject DataVolume with incomplete snapshot source", func(namespace, name string) {
+			dataVolume := newSnapshotDataVolume("testDV", namespace, name)
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(false))
+		},
+			Entry("without namespace", "", "test"),
+			Entry("without name", "testNamespace", ""),
+		)
+
 		It("should reject DataVolume with name length greater than 253 characters", func() {
 			longName := "the-name-length-of-this-datavolume-is-greater-then-253-characters" +
 				"123456789-123456789-123456789-123456789-123456789-123456789-123456789-123456789-123456789-123456789-" +
@@ -132,6 +212,20 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(false))
 		})
 
+		DescribeTable("should validate the import parallelism annotation on create", func(value string, expectedAllowed bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			dataVolume.Annotations = map[string]string{controller.AnnImportParallelism: value}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expectedAllowed))
+		},
+			Entry("accept 1", "1", true),
+			Entry("accept the maximum", "16", true),
+			Entry("reject 0", "0", false),
+			Entry("reject a negative value", "-2", false),
+			Entry("reject more than the maximum", "17", false),
+			Entry("reject a non integer", "many", false),
+		)
+
 		It("should reject DataVolume source with invalid URL on create", func() {
 			dataVolume := newHTTPDataVolume("testDV", "invalidurl")
 			resp := validateDataVolumeCreate(dataVolume)
@@ -253,6 +347,36 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(Equal(true))
 		})
 
+		It("should accept an update keeping an invalid import parallelism annotation", func() {
+			newDataVolume := newHTTPDataVolume("testDV", "http://www.example.com")
+			newDataVolume.Annotations = map[string]string{controller.AnnImportParallelism: "100", "foo": "bar"}
+			newBytes, _ := json.Marshal(&newDataVolume)
+
+			oldDataVolume := newDataVolume.DeepCopy()
+			oldDataVolume.Annotations = map[string]string{controller.AnnImportParallelism: "100"}
+			oldBytes, _ := json.Marshal(oldDataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: newBytes,
+					},
+					OldObject: runtime.RawExtension{
+						Raw: oldBytes,
+					},
+				},
+			}
+
+			resp := validateAdmissionReview(ar)
+			Expect(resp.Allowed).To(Equal(true))
+		})
+
 		It("should reject DataVolume spec PVC size update", func() {
 			blankSource := cdiv1.DataVolumeSource{
 				Blank: &cdiv1.DataVolumeBlankImage{},
@@ -520,6 +644,17 @@ func newPVCDataVolume(name, pvcNamespace, pvcName string) *cdiv1.DataVolume {
 	return newDataVolume(name, pvcSource, pvc)
 }
 
+func newSnapshotDataVolume(name, snapshotNamespace, snapshotName string) *cdiv1.DataVolume {
+	snapshotSource := cdiv1.DataVolumeSource{
+		Snapshot: &cdiv1.DataVolumeSourceSnapshot{
+			Namespace: snapshotNamespace,
+			Name:      snapshotName,
+		},
+	}
+	pvc := newPVCSpec(pvcSizeDefault)
+	return newDataVolume(name, snapshotSource, pvc)
+}
+
 func newDataVolumeWithEmptyPVCSpec(name, url string) *cdiv1.DataVolume {
 
 	httpSource := cdiv1.DataVolumeSource{
@@ -58,7 +58,7 @@ func NewDataVolumeValidatingWebhook(k8sClient kubernetes.Interface, cdiClient cd
 // NewDataVolumeMutatingWebhook creates a new DataVolumeMutation webhook
 func NewDataVolumeMutatingWebhook(k8sClient kubernetes.Interface, cdiClient cdiclient.Interface, key *rsa.PrivateKey) http.Handler {
 	generator := newCloneTokenGenerator(key)
-	return newAdmissionHandler(&dataVolumeMutatingWebhook{k8sClient: k8sClient, cdiClient: cdiClient, tokenGenerator: generator, proxy: &sarProxy{client: k8sClient}})
+	return newAdmissionHandler(&dataVolumeMutatingWebhook{k8sClient: k8sClient, cdiClient: cdiClient, tokenGenerator: generator, proxy: &sarProxy{client: k8sClient}, auditor: newCloneAuditor(k8sClient)})
 }
 
 // NewCDIValidatingWebhook creates a new CDI validating webhook
@@ -68,7 +68,12 @@ func NewCDIValidatingWebhook(client cdiclient.Interface) http.Handler {
 
 // NewObjectTransferValidatingWebhook creates a new ObjectTransfer validating webhook
 func NewObjectTransferValidatingWebhook(k8sClient kubernetes.Interface, cdiClient cdiclient.Interface) http.Handler {
-	return newAdmissionHandler(&objectTransferValidatingWebhook{k8sClient: k8sClient, cdiClient: cdiClient})
+	return newAdmissionHandler(&objectTransferValidatingWebhook{
+		k8sClient: k8sClient,
+		cdiClient: cdiClient,
+		snapshots: &restSnapshotProxy{client: k8sClient.CoreV1().RESTClient()},
+		proxy:     &sarProxy{client: k8sClient},
+	})
 }
 
 func newCloneTokenGenerator(key *rsa.PrivateKey) token.Generator {
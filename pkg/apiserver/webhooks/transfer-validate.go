@@ -24,20 +24,43 @@ import (
 	"encoding/json"
 	"fmt"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	admissionv1 "k8s.io/api/admission/v1"
+	authentication "k8s.io/api/authentication/v1"
+	authorization "k8s.io/api/authorization/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/clone"
 )
 
 type objectTransferValidatingWebhook struct {
 	k8sClient kubernetes.Interface
 	cdiClient cdiclient.Interface
+	snapshots snapshotProxy
+	proxy     clone.SubjectAccessReviewsProxy
+}
+
+// snapshotProxy gets VolumeSnapshots, there is no typed client for them
+type snapshotProxy interface {
+	Get(namespace, name string) error
+}
+
+type restSnapshotProxy struct {
+	client rest.Interface
+}
+
+func (p *restSnapshotProxy) Get(namespace, name string) error {
+	return p.client.Get().
+		AbsPath("/apis", snapshotv1.GroupName, snapshotv1.SchemeGroupVersion.Version, "namespaces", namespace, "volumesnapshots", name).
+		Do(context.TODO()).
+		Error()
 }
 
 func (wh *objectTransferValidatingWebhook) Admit(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
@@ -95,6 +118,20 @@ func (wh *objectTransferValidatingWebhook) Admit(ar admissionv1.AdmissionReview)
 		_, err = wh.cdiClient.CdiV1beta1().DataVolumes(ns).Get(context.TODO(), name, metav1.GetOptions{})
 	case "PersistentVolumeClaim":
 		_, err = wh.k8sClient.CoreV1().PersistentVolumeClaims(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	case "VolumeSnapshot":
+		if err := wh.validateSource(obj, ar.Request.UserInfo, snapshotv1.GroupName, "volumesnapshots", wh.snapshots.Get); err != nil {
+			return toAdmissionResponseError(err)
+		}
+		err = wh.snapshots.Get(ns, name)
+	case "Secret":
+		getSecret := func(namespace, name string) error {
+			_, err := wh.k8sClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			return err
+		}
+		if err := wh.validateSource(obj, ar.Request.UserInfo, "", "secrets", getSecret); err != nil {
+			return toAdmissionResponseError(err)
+		}
+		err = getSecret(ns, name)
 	default:
 		return toAdmissionResponseError(fmt.Errorf("Unsupported kind %q", obj.Spec.Source.Kind))
 	}
@@ -109,3 +146,49 @@ func (wh *objectTransferValidatingWebhook) Admit(ar admissionv1.AdmissionReview)
 
 	return allowedAdmissionResponse()
 }
+
+// validateSource checks that the source of the transfer exists and that the requester may read it, so a transfer can
+// not be used to move an object the requester has no access to
+func (wh *objectTransferValidatingWebhook) validateSource(obj *cdiv1.ObjectTransfer, userInfo authentication.UserInfo, group, resource string, get func(namespace, name string) error) error {
+	namespace, name := obj.Spec.Source.Namespace, obj.Spec.Source.Name
+	if err := get(namespace, name); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("ObjectTransfer source \"%s/%s\" does not exist", namespace, name)
+		}
+		return err
+	}
+
+	var extra map[string]authorization.ExtraValue
+	if len(userInfo.Extra) > 0 {
+		extra = make(map[string]authorization.ExtraValue)
+		for k, v := range userInfo.Extra {
+			extra[k] = authorization.ExtraValue(v)
+		}
+	}
+
+	sar := &authorization.SubjectAccessReview{
+		Spec: authorization.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorization.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     group,
+				Resource:  resource,
+				Name:      name,
+			},
+		},
+	}
+
+	response, err := wh.proxy.Create(sar)
+	if err != nil {
+		return err
+	}
+
+	if !response.Status.Allowed {
+		return fmt.Errorf("User %s has insufficient permissions to read ObjectTransfer source \"%s/%s\"", userInfo.Username, namespace, name)
+	}
+
+	return nil
+}
@@ -27,10 +27,16 @@ import (
 	. "github.com/onsi/gomega"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	k8sclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
@@ -233,6 +239,95 @@ var _ = Describe("ObjectTransfer webhook", func() {
 			resp := validateObjectTransfers(ar, nil, nil)
 			Expect(resp.Allowed).To(BeTrue())
 		})
+
+		DescribeTable("Should validate VolumeSnapshot and Secret transfers", func(kind string, sourceExists, targetExists, authorized bool, expectedMessage string) {
+			ot := &cdiv1.ObjectTransfer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ot",
+				},
+				Spec: cdiv1.ObjectTransferSpec{
+					Source: cdiv1.TransferSource{
+						Kind:      kind,
+						Name:      "source",
+						Namespace: "ns",
+					},
+					Target: cdiv1.TransferTarget{
+						Namespace: &[]string{"foo"}[0],
+					},
+				},
+			}
+
+			bytes, _ := json.Marshal(ot)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdiv1.SchemeGroupVersion.Group,
+						Version:  cdiv1.SchemeGroupVersion.Version,
+						Resource: "objecttransfers",
+					},
+					Object: runtime.RawExtension{
+						Raw: bytes,
+					},
+					UserInfo: authenticationv1.UserInfo{
+						Username: "user",
+					},
+				},
+			}
+
+			var existing []types.NamespacedName
+			if sourceExists {
+				existing = append(existing, types.NamespacedName{Namespace: "ns", Name: "source"})
+			}
+			if targetExists {
+				existing = append(existing, types.NamespacedName{Namespace: "foo", Name: "source"})
+			}
+
+			var k8sObjects []runtime.Object
+			snapshots := fakeSnapshotProxy{}
+			for _, nn := range existing {
+				if kind == "Secret" {
+					k8sObjects = append(k8sObjects, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name}})
+				} else {
+					snapshots[nn] = true
+				}
+			}
+
+			k8sClient := k8sclient.NewSimpleClientset(k8sObjects...)
+			k8sClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				Expect(sar.Spec.User).To(Equal("user"))
+				Expect(sar.Spec.ResourceAttributes.Verb).To(Equal("get"))
+				Expect(sar.Spec.ResourceAttributes.Namespace).To(Equal("ns"))
+				Expect(sar.Spec.ResourceAttributes.Name).To(Equal("source"))
+				sar.Status.Allowed = authorized
+				return true, sar, nil
+			})
+			wh := newAdmissionHandler(&objectTransferValidatingWebhook{
+				k8sClient: k8sClient,
+				cdiClient: cdiclient.NewSimpleClientset(),
+				snapshots: snapshots,
+				proxy:     &sarProxy{client: k8sClient},
+			})
+
+			resp := serve(ar, wh)
+			if expectedMessage == "" {
+				Expect(resp.Allowed).To(BeTrue())
+			} else {
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+			Entry("VolumeSnapshot", "VolumeSnapshot", true, false, true, ""),
+			Entry("Secret", "Secret", true, false, true, ""),
+			Entry("VolumeSnapshot without source", "VolumeSnapshot", false, false, true, "source \"ns/source\" does not exist"),
+			Entry("Secret without source", "Secret", false, false, true, "source \"ns/source\" does not exist"),
+			Entry("VolumeSnapshot with existing target", "VolumeSnapshot", true, true, true, "target \"foo/source\" already exists"),
+			Entry("Secret with existing target", "Secret", true, true, true, "target \"foo/source\" already exists"),
+			Entry("VolumeSnapshot the user can not read", "VolumeSnapshot", true, false, false, "insufficient permissions"),
+			Entry("Secret the user can not read", "Secret", true, false, false, "insufficient permissions"),
+		)
 	})
 
 	Describe("UPDATE tests", func() {
@@ -333,3 +428,12 @@ func validateObjectTransfers(ar *admissionv1.AdmissionReview, k8sObjects, cdiObj
 	wh := NewObjectTransferValidatingWebhook(k8sClient, cdiClient)
 	return serve(ar, wh)
 }
+
+type fakeSnapshotProxy map[types.NamespacedName]bool
+
+func (p fakeSnapshotProxy) Get(namespace, name string) error {
+	if !p[types.NamespacedName{Namespace: namespace, Name: name}] {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: "snapshot.storage.k8s.io", Resource: "volumesnapshots"}, name)
+	}
+	return nil
+}
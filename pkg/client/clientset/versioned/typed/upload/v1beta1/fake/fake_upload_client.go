@@ -28,6 +28,10 @@ type FakeUploadV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeUploadV1beta1) DownloadTokenRequests(namespace string) v1beta1.DownloadTokenRequestInterface {
+	return &FakeDownloadTokenRequests{c, namespace}
+}
+
 func (c *FakeUploadV1beta1) UploadTokenRequests(namespace string) v1beta1.UploadTokenRequestInterface {
 	return &FakeUploadTokenRequests{c, namespace}
 }
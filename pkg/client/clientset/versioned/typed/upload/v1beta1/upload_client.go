@@ -26,6 +26,7 @@ import (
 
 type UploadV1beta1Interface interface {
 	RESTClient() rest.Interface
+	DownloadTokenRequestsGetter
 	UploadTokenRequestsGetter
 }
 
@@ -34,6 +35,10 @@ type UploadV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *UploadV1beta1Client) DownloadTokenRequests(namespace string) DownloadTokenRequestInterface {
+	return newDownloadTokenRequests(c, namespace)
+}
+
 func (c *UploadV1beta1Client) UploadTokenRequests(namespace string) UploadTokenRequestInterface {
 	return newUploadTokenRequests(c, namespace)
 }
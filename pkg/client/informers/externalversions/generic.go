@@ -84,6 +84,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Upload().V1alpha1().UploadTokenRequests().Informer()}, nil
 
 		// Group=upload.cdi.kubevirt.io, Version=v1beta1
+	case uploadv1beta1.SchemeGroupVersion.WithResource("downloadtokenrequests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Upload().V1beta1().DownloadTokenRequests().Informer()}, nil
 	case uploadv1beta1.SchemeGroupVersion.WithResource("uploadtokenrequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Upload().V1beta1().UploadTokenRequests().Informer()}, nil
 
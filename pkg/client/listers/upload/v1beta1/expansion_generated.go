@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1beta1
 
+// DownloadTokenRequestListerExpansion allows custom methods to be added to
+// DownloadTokenRequestLister.
+type DownloadTokenRequestListerExpansion interface{}
+
+// DownloadTokenRequestNamespaceListerExpansion allows custom methods to be added to
+// DownloadTokenRequestNamespaceLister.
+type DownloadTokenRequestNamespaceListerExpansion interface{}
+
 // UploadTokenRequestListerExpansion allows custom methods to be added to
 // UploadTokenRequestLister.
 type UploadTokenRequestListerExpansion interface{}
@@ -41,6 +41,20 @@ func CanUserClonePVC(client SubjectAccessReviewsProxy, sourceNamespace, pvcName,
 		return true, "", nil
 	}
 
+	return sendSubjectAccessReviews(client, sourceNamespace, getResourceAttributes(sourceNamespace, pvcName), newUserSarSpec(userInfo))
+}
+
+// CanUserCloneSnapshot checks if a user has "appropriate" permission to clone from the given VolumeSnapshot
+func CanUserCloneSnapshot(client SubjectAccessReviewsProxy, sourceNamespace, snapshotName, targetNamespace string,
+	userInfo authentication.UserInfo) (bool, string, error) {
+	if sourceNamespace == targetNamespace {
+		return true, "", nil
+	}
+
+	return sendSubjectAccessReviews(client, sourceNamespace, getSnapshotResourceAttributes(sourceNamespace, snapshotName), newUserSarSpec(userInfo))
+}
+
+func newUserSarSpec(userInfo authentication.UserInfo) authorization.SubjectAccessReviewSpec {
 	var newExtra map[string]authorization.ExtraValue
 	if len(userInfo.Extra) > 0 {
 		newExtra = make(map[string]authorization.ExtraValue)
@@ -49,13 +63,11 @@ func CanUserClonePVC(client SubjectAccessReviewsProxy, sourceNamespace, pvcName,
 		}
 	}
 
-	sarSpec := authorization.SubjectAccessReviewSpec{
+	return authorization.SubjectAccessReviewSpec{
 		User:   userInfo.Username,
 		Groups: userInfo.Groups,
 		Extra:  newExtra,
 	}
-
-	return sendSubjectAccessReviews(client, sourceNamespace, pvcName, sarSpec)
 }
 
 // CanServiceAccountClonePVC checks if a ServiceAccount has "appropriate" permission to clone from the given PVC
@@ -64,9 +76,22 @@ func CanServiceAccountClonePVC(client SubjectAccessReviewsProxy, pvcNamespace, p
 		return true, "", nil
 	}
 
+	return sendSubjectAccessReviews(client, pvcNamespace, getResourceAttributes(pvcNamespace, pvcName), newServiceAccountSarSpec(saNamespace, saName))
+}
+
+// CanServiceAccountCloneSnapshot checks if a ServiceAccount has "appropriate" permission to clone from the given VolumeSnapshot
+func CanServiceAccountCloneSnapshot(client SubjectAccessReviewsProxy, snapshotNamespace, snapshotName, saNamespace, saName string) (bool, string, error) {
+	if snapshotNamespace == saNamespace {
+		return true, "", nil
+	}
+
+	return sendSubjectAccessReviews(client, snapshotNamespace, getSnapshotResourceAttributes(snapshotNamespace, snapshotName), newServiceAccountSarSpec(saNamespace, saName))
+}
+
+func newServiceAccountSarSpec(saNamespace, saName string) authorization.SubjectAccessReviewSpec {
 	user := fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName)
 
-	sarSpec := authorization.SubjectAccessReviewSpec{
+	return authorization.SubjectAccessReviewSpec{
 		User: user,
 		Groups: []string{
 			"system:serviceaccounts",
@@ -74,14 +99,12 @@ func CanServiceAccountClonePVC(client SubjectAccessReviewsProxy, pvcNamespace, p
 			"system:authenticated",
 		},
 	}
-
-	return sendSubjectAccessReviews(client, pvcNamespace, pvcName, sarSpec)
 }
 
-func sendSubjectAccessReviews(client SubjectAccessReviewsProxy, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
+func sendSubjectAccessReviews(client SubjectAccessReviewsProxy, namespace string, attributes []authorization.ResourceAttributes, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
 	allowed := false
 
-	for _, ra := range getResourceAttributes(namespace, name) {
+	for _, ra := range attributes {
 		sar := &authorization.SubjectAccessReview{
 			Spec: sarSpec,
 		}
@@ -127,3 +150,23 @@ func getResourceAttributes(namespace, name string) []authorization.ResourceAttri
 		},
 	}
 }
+
+// getSnapshotResourceAttributes returns the permissions that allow cloning from a snapshot, anyone who can create PVCs
+// in the namespace of the snapshot can restore it and read the data
+func getSnapshotResourceAttributes(namespace, name string) []authorization.ResourceAttributes {
+	return []authorization.ResourceAttributes{
+		{
+			Namespace:   namespace,
+			Verb:        "create",
+			Group:       cdiv1.SchemeGroupVersion.Group,
+			Resource:    "datavolumes",
+			Subresource: cdiv1.DataVolumeCloneSourceSubresource,
+			Name:        name,
+		},
+		{
+			Namespace: namespace,
+			Verb:      "create",
+			Resource:  "persistentvolumeclaims",
+		},
+	}
+}
@@ -59,6 +59,12 @@ const (
 	ImportProxyConfigMapKey = "ca.pem"
 	// ImporterProxyCertDir is where the configmap containing proxy certs will be mounted
 	ImporterProxyCertDir = "/proxycerts/"
+	// ImporterSignatureDir is where the configmap containing the image signature verification policy will be mounted
+	ImporterSignatureDir = "/signature"
+	// SignaturePublicKeyConfigMapKey provides the key name of the cosign public key in the signature verification ConfigMap
+	SignaturePublicKeyConfigMapKey = "cosign.pub"
+	// SignaturePolicyConfigMapKey provides the key name of the signature policy in the signature verification ConfigMap
+	SignaturePolicyConfigMapKey = "policy.json"
 
 	// PullPolicy provides a constant to capture our env variable "PULL_POLICY" (only used by cmd/cdi-controller/controller.go)
 	PullPolicy = "PULL_POLICY"
@@ -102,6 +108,20 @@ const (
 	ImportProxyNoProxy = "NO_PROXY"
 	// ImporterProxyCertDirVar provides a constant to capture our env variable "IMPORTER_CERT_DIR"
 	ImporterProxyCertDirVar = "IMPORTER_PROXY_CERT_DIR"
+	// ImporterRegistryDiskPath provides a constant to capture our env variable "IMPORTER_REGISTRY_DISK_PATH"
+	ImporterRegistryDiskPath = "IMPORTER_REGISTRY_DISK_PATH"
+	// ImporterRequiredDigest provides a constant to capture our env variable "IMPORTER_REQUIRED_DIGEST"
+	ImporterRequiredDigest = "IMPORTER_REQUIRED_DIGEST"
+	// ImporterSignaturePublicKey provides a constant to capture our env variable "IMPORTER_SIGNATURE_PUBLIC_KEY"
+	ImporterSignaturePublicKey = "IMPORTER_SIGNATURE_PUBLIC_KEY"
+	// ImporterSignaturePolicy provides a constant to capture our env variable "IMPORTER_SIGNATURE_POLICY"
+	ImporterSignaturePolicy = "IMPORTER_SIGNATURE_POLICY"
+	// ImporterChecksum provides a constant to capture our env variable "IMPORTER_CHECKSUM"
+	ImporterChecksum = "IMPORTER_CHECKSUM"
+	// ImporterParallelism provides a constant to capture our env variable "IMPORTER_PARALLELISM"
+	ImporterParallelism = "IMPORTER_PARALLELISM"
+	// MaxImportParallelism is the maximum number of concurrent range requests an importer uses for an http download
+	MaxImportParallelism = 16
 
 	// CloningLabelValue provides a constant to use as a label value for pod affinity (controller pkg only)
 	CloningLabelValue = "host-assisted-cloning"
@@ -122,6 +142,9 @@ const (
 	// SmartClonerCDILabel is the label applied to resources created by the smart-clone controller
 	SmartClonerCDILabel = "cdi-smart-clone"
 
+	// StorageProbeLabel is the label applied to the PVCs the storage profile controller probes a storage class with
+	StorageProbeLabel = "cdi.kubevirt.io/storageProbe"
+
 	// UploadServerCDILabel is the label applied to upload server resources
 	UploadServerCDILabel = "cdi-upload-server"
 	// UploadServerPodname is name of the upload server pod container
@@ -130,8 +153,22 @@ const (
 	UploadServerDataDir = ImporterDataDir
 	// UploadServerServiceLabel is the label selector for upload server services
 	UploadServerServiceLabel = "service"
+	// UploadServerMetricsPort is the port upload server pods serve their metrics and progress on, the upload itself
+	// is served on 8443
+	UploadServerMetricsPort = 8444
+	// DownloadServerCDILabel is the label applied to download server resources
+	DownloadServerCDILabel = "cdi-download-server"
+	// DownloadServerPodname is name of the download server pod container
+	DownloadServerPodname = DownloadServerCDILabel
+	// DownloadServerScratchDir is where the download server writes converted images before serving them
+	DownloadServerScratchDir = "/scratch"
+	// AnnDownloadRequest marks that a PVC should be made available for download, the cdi-apiserver sets it when a
+	// DownloadTokenRequest is created for the PVC
+	AnnDownloadRequest = "cdi.kubevirt.io/storage.download.source"
 	// UploadImageSize provides a constant to capture our env variable "UPLOAD_IMAGE_SIZE"
 	UploadImageSize = "UPLOAD_IMAGE_SIZE"
+	// UploadChecksum provides a constant to capture our env variable "UPLOAD_CHECKSUM"
+	UploadChecksum = "UPLOAD_CHECKSUM"
 
 	// FilesystemOverheadVar provides a constant to capture our env variable "FILESYSTEM_OVERHEAD"
 	FilesystemOverheadVar = "FILESYSTEM_OVERHEAD"
@@ -144,6 +181,17 @@ const (
 	// OwnerUID provides the UID of the owner entity (either PVC or DV)
 	OwnerUID = "OWNER_UID"
 
+	// ProgressServerKey provides a constant to capture our env variable "PROGRESS_SERVER_KEY"
+	ProgressServerKey = "PROGRESS_SERVER_KEY"
+	// ProgressServerCert provides a constant to capture our env variable "PROGRESS_SERVER_CERT"
+	ProgressServerCert = "PROGRESS_SERVER_CERT"
+	// ProgressClientCA provides a constant to capture our env variable "PROGRESS_CLIENT_CA"
+	ProgressClientCA = "PROGRESS_CLIENT_CA"
+	// ProgressClientName is the TLS name worker pods accept progress requests from
+	ProgressClientName = "client.progress.cdi.kubevirt.io"
+	// ProgressPath is the path worker pods serve their progress report on
+	ProgressPath = "/progress"
+
 	// KeyAccess provides a constant to the accessKeyId label using in controller pkg and transport_test.go
 	KeyAccess = "accessKeyId"
 	// KeySecret provides a constant to the secretKey label using in controller pkg and transport_test.go
@@ -188,6 +236,21 @@ const (
 	// BlockdeviceClone is the content type when cloning a block device
 	BlockdeviceClone = "blockdevice-clone"
 
+	// BlockdeviceExtentsClone is the content type when cloning a block device as sparse extents
+	BlockdeviceExtentsClone = "blockdevice-extents-clone"
+
+	// FilesystemExtentsClone is the content type when cloning the disk image of a filesystem as sparse extents
+	FilesystemExtentsClone = "filesystem-extents-clone"
+
+	// CloneStreamsHeader is the header holding the number of concurrent streams of an extents clone
+	CloneStreamsHeader = "x-cdi-clone-streams"
+
+	// CloneSizeHeader is the header holding the size of the source of an extents clone
+	CloneSizeHeader = "x-cdi-clone-size"
+
+	// CloneAttemptHeader is the header holding the ID of the cloner run the streams of an extents clone belong to
+	CloneAttemptHeader = "x-cdi-clone-attempt"
+
 	// UploadPathSync is the path to POST CDI uploads
 	UploadPathSync = "/v1beta1/upload"
 
@@ -200,6 +263,24 @@ const (
 	// UploadFormAsync is the path to POST CDI uploads as form data in async mode
 	UploadFormAsync = "/v1beta1/upload-form-async"
 
+	// UploadPathResumable is the path to PATCH CDI uploads in chunks and HEAD the committed offset
+	UploadPathResumable = "/v1beta1/upload-resumable"
+
+	// UploadOffsetHeader is the header holding the offset of a chunk of a resumable upload, and the committed offset in responses
+	UploadOffsetHeader = "Upload-Offset"
+
+	// UploadLengthHeader is the header holding the total size of a resumable upload
+	UploadLengthHeader = "Upload-Length"
+
+	// DownloadPathRaw is the path to GET a PVC's disk image in raw format
+	DownloadPathRaw = "/v1beta1/download/raw"
+
+	// DownloadPathQcow2 is the path to GET a PVC's disk image as compressed qcow2
+	DownloadPathQcow2 = "/v1beta1/download/qcow2"
+
+	// DownloadPathTar is the path to GET a tar archive of a filesystem PVC's contents
+	DownloadPathTar = "/v1beta1/download/tar"
+
 	// PreallocationApplied is a string inserted into importer's/uploader's exit message
 	PreallocationApplied = "Preallocation applied"
 )
@@ -207,9 +288,16 @@ const (
 // ProxyPaths are all supported paths
 var ProxyPaths = append(
 	append(SyncUploadPaths, AsyncUploadPaths...),
-	append(SyncUploadFormPaths, AsyncUploadFormPaths...)...,
+	append(append(SyncUploadFormPaths, AsyncUploadFormPaths...), ResumableUploadPaths...)...,
 )
 
+// DownloadPaths are paths to GET the contents of a PVC
+var DownloadPaths = []string{
+	DownloadPathRaw,
+	DownloadPathQcow2,
+	DownloadPathTar,
+}
+
 // SyncUploadPaths are paths to POST CDI uploads
 var SyncUploadPaths = []string{
 	UploadPathSync,
@@ -234,6 +322,11 @@ var AsyncUploadFormPaths = []string{
 	"/v1alpha1/upload-form-async",
 }
 
+// ResumableUploadPaths are paths to PATCH CDI uploads in chunks
+var ResumableUploadPaths = []string{
+	UploadPathResumable,
+}
+
 // ErrConnectionRefused checks whether the error is "connection refused"
 func ErrConnectionRefused(err error) bool {
 	return strings.Contains(err.Error(), "connection refused")
@@ -44,6 +44,8 @@ const (
 	AnnCloneOf = "k8s.io/CloneOf"
 	// AnnCloneToken is the annotation containing the clone token
 	AnnCloneToken = "cdi.kubevirt.io/storage.clone.token"
+	// AnnCloneAuthorization is the annotation recording the identity of the clone token that authorized the clone
+	AnnCloneAuthorization = "cdi.kubevirt.io/cloneAuthorization"
 
 	//CloneUniqueID is used as a special label to be used when we search for the pod
 	CloneUniqueID = "cdi.kubevirt.io/storage.clone.cloneUniqeId"
@@ -81,6 +83,9 @@ type CloneReconciler struct {
 	recorder            record.EventRecorder
 	clientCertGenerator generator.CertGenerator
 	serverCAFetcher     fetcher.CertBundleFetcher
+	// serverCertGenerator and clientCAFetcher secure the progress endpoint of the source pods
+	serverCertGenerator generator.CertGenerator
+	clientCAFetcher     fetcher.CertBundleFetcher
 	log                 logr.Logger
 	tokenValidator      token.Validator
 	image               string
@@ -95,6 +100,8 @@ func NewCloneController(mgr manager.Manager,
 	verbose string,
 	clientCertGenerator generator.CertGenerator,
 	serverCAFetcher fetcher.CertBundleFetcher,
+	serverCertGenerator generator.CertGenerator,
+	clientCAFetcher fetcher.CertBundleFetcher,
 	apiServerKey *rsa.PublicKey) (controller.Controller, error) {
 	reconciler := &CloneReconciler{
 		client:              mgr.GetClient(),
@@ -107,6 +114,8 @@ func NewCloneController(mgr manager.Manager,
 		recorder:            mgr.GetEventRecorderFor("clone-controller"),
 		clientCertGenerator: clientCertGenerator,
 		serverCAFetcher:     serverCAFetcher,
+		serverCertGenerator: serverCertGenerator,
+		clientCAFetcher:     clientCAFetcher,
 	}
 	cloneController, err := controller.New("clone-controller", mgr, controller.Options{
 		Reconciler: reconciler,
@@ -470,7 +479,13 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy, clientName str
 		sourceVolumeMode = corev1.PersistentVolumeFilesystem
 	}
 
+	progressTLS, err := newProgressServerTLS(r.serverCertGenerator, r.clientCAFetcher, sourcePvcNamespace, pvc.Annotations[AnnCloneSourcePod])
+	if err != nil {
+		return nil, err
+	}
+
 	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, sourcePvcName, sourcePvcNamespace, ownerKey, clientKey, clientCert, serverCABundle, pvc, podResourceRequirements, workloadNodePlacement)
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, progressTLS.envVars()...)
 
 	if err := r.client.Create(context.TODO(), pod); err != nil {
 		return nil, errors.Wrap(err, "source pod API create errored")
@@ -659,6 +674,16 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 		}
 	}
 
+	// A filesystem cloned to a block device only sends the disk image
+	targetVolumeMode := "filesystem"
+	if getVolumeMode(targetPvc) == corev1.PersistentVolumeBlock {
+		targetVolumeMode = "block"
+	}
+	addVars = append(addVars, corev1.EnvVar{
+		Name:  "TARGET_VOLUME_MODE",
+		Value: targetVolumeMode,
+	})
+
 	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, addVars...)
 	SetPodPvcAnnotations(pod, targetPvc)
 	return pod
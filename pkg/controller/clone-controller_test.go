@@ -40,6 +40,8 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/token"
@@ -66,6 +68,7 @@ func (cg *fakeCertGenerator) MakeServerCert(namespace, service string, duration
 
 type FakeValidator struct {
 	match     string
+	ID        string
 	Operation token.Operation
 	Name      string
 	Namespace string
@@ -149,6 +152,15 @@ var _ = Describe("Clone controller reconcile loop", func() {
 		Expect(HasFinalizer(testPvc, cloneSourcePodFinalizer)).To(BeTrue())
 	})
 
+	DescribeTable("Should pass the target volume mode to the source pod", func(sourceVolumeMode corev1.PersistentVolumeMode, targetPvc *corev1.PersistentVolumeClaim, expected string) {
+		pod := MakeCloneSourcePodSpec(sourceVolumeMode, "image", "Always", "source", "default", "default/target",
+			[]byte("key"), []byte("cert"), []byte("ca"), targetPvc, nil, &sdkapi.NodePlacement{})
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "TARGET_VOLUME_MODE", Value: expected}))
+	},
+		Entry("filesystem to block", corev1.PersistentVolumeFilesystem, createCloneBlockPvc("default", "source", "default", "target", nil, nil), "block"),
+		Entry("block to filesystem", corev1.PersistentVolumeBlock, createClonePvc("default", "source", "default", "target", nil, nil), "filesystem"),
+	)
+
 	DescribeTable("Should NOT create new source pod if source PVC is in use", func(podFunc func(*corev1.PersistentVolumeClaim) *corev1.Pod) {
 		testPvc := createPvc("testPvc1", "default", map[string]string{
 			AnnCloneRequest:     "default/source",
@@ -664,6 +676,58 @@ var _ = Describe("TokenValidation", func() {
 		Entry("fail on bad targetNamespace", badTargetNamespace, false),
 		Entry("fail on bad missing parameters", missingParams, false),
 	)
+
+	snapshotTokenData := func(name string) *token.Payload {
+		p := goodTokenData()
+		p.Name = name
+		p.Resource.Resource = "volumesnapshots"
+		return p
+	}
+
+	DescribeTable("should validate a snapshot token for the PVC restored from the snapshot", func(p *token.Payload, mutateFn func(*corev1.PersistentVolumeClaim), expectedSuccess bool) {
+		tokenString, err := g.Generate(p)
+		Expect(err).ToNot(HaveOccurred())
+
+		restored := source.DeepCopy()
+		restored.Name = "cdi-snapshot-dv-uid"
+		restored.Annotations = map[string]string{annOwnedByDataVolume: "targetns/dv"}
+		restored.Spec.DataSource = &corev1.TypedLocalObjectReference{Kind: "VolumeSnapshot", Name: "snapshot"}
+		mutateFn(restored)
+		target := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "target",
+				Namespace: "targetns",
+				Annotations: map[string]string{
+					AnnCloneToken: tokenString,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&cdiv1.DataVolume{ObjectMeta: metav1.ObjectMeta{Name: "dv", UID: "dv-uid"}},
+						cdiv1.SchemeGroupVersion.WithKind("DataVolume")),
+				},
+			},
+		}
+		err = validateCloneTokenPVC(v, restored, target)
+		if expectedSuccess {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+		Entry("succeed", snapshotTokenData("snapshot"), func(*corev1.PersistentVolumeClaim) {}, true),
+		Entry("fail on other snapshot", snapshotTokenData("other"), func(*corev1.PersistentVolumeClaim) {}, false),
+		Entry("fail on PVC not restored from a snapshot", snapshotTokenData("snapshot"), func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Spec.DataSource = nil
+		}, false),
+		Entry("fail on restore PVC of another DataVolume", snapshotTokenData("snapshot"), func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Name = "cdi-snapshot-other-uid"
+		}, false),
+		Entry("fail on restore PVC owned by another DataVolume", snapshotTokenData("snapshot"), func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Annotations[annOwnedByDataVolume] = "targetns/other"
+		}, false),
+		Entry("fail on restore PVC without owner", snapshotTokenData("snapshot"), func(pvc *corev1.PersistentVolumeClaim) {
+			pvc.Annotations = nil
+		}, false),
+	)
 })
 
 func createCloneReconciler(objects ...runtime.Object) *CloneReconciler {
@@ -713,10 +777,12 @@ func (v *FakeValidator) Validate(value string) (*token.Payload, error) {
 	if value != v.match {
 		return nil, fmt.Errorf("Token does not match expected")
 	}
-	resource := metav1.GroupVersionResource{
-		Resource: "persistentvolumeclaims",
+	resource := v.Resource
+	if resource.Resource == "" {
+		resource.Resource = "persistentvolumeclaims"
 	}
 	return &token.Payload{
+		ID:        v.ID,
 		Name:      v.Name,
 		Namespace: v.Namespace,
 		Operation: token.OperationClone,
@@ -0,0 +1,632 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	imagetypes "github.com/containers/image/v5/types"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// AnnSourceDesiredDigest is the digest of the registry image the DataImportCron should import
+	AnnSourceDesiredDigest = AnnAPIGroup + "/storage.import.sourceDesiredDigest"
+	// LabelDataImportCronName is the label set on DataVolumes and DataSources managed by a DataImportCron
+	LabelDataImportCronName = AnnAPIGroup + "/dataImportCron"
+
+	// DataImportCronInvalidSchedule provides a const to indicate the cron schedule cannot be parsed
+	DataImportCronInvalidSchedule = "InvalidSchedule"
+	// DataImportCronInvalidSource provides a const to indicate the cron source is not supported
+	DataImportCronInvalidSource = "InvalidSource"
+	// DataImportCronPollFailed provides a const to indicate polling the registry has failed
+	DataImportCronPollFailed = "PollFailed"
+	// DataImportCronNewDigest provides a const to indicate a new image digest was found
+	DataImportCronNewDigest = "NewDigest"
+	// DataImportCronImportScheduled provides a const to indicate a new import was scheduled
+	DataImportCronImportScheduled = "ImportScheduled"
+	// DataImportCronImportSucceeded provides a const to indicate the latest import has succeeded
+	DataImportCronImportSucceeded = "ImportSucceeded"
+	// DataImportCronImportFailed provides a const to indicate the latest import has failed
+	DataImportCronImportFailed = "ImportFailed"
+	// DataImportCronNoDigest provides a const to indicate no digest has been resolved yet
+	DataImportCronNoDigest = "NoDigest"
+	// DataImportCronGarbageCollected provides a const to indicate an outdated import was deleted
+	DataImportCronGarbageCollected = "GarbageCollected"
+
+	// MessageDataImportCronInvalidSchedule provides a const to form the invalid schedule message
+	MessageDataImportCronInvalidSchedule = "Invalid schedule %q: %v"
+	// MessageDataImportCronInvalidSource provides a const to form the invalid source message
+	MessageDataImportCronInvalidSource = "Only registry sources with a docker:// URL are supported"
+	// MessageDataImportCronPollFailed provides a const to form the poll failed message
+	MessageDataImportCronPollFailed = "Unable to poll %s: %v"
+	// MessageDataImportCronNewDigest provides a const to form the new digest message
+	MessageDataImportCronNewDigest = "Found new digest %s for %s"
+	// MessageDataImportCronImportScheduled provides a const to form the import scheduled message
+	MessageDataImportCronImportScheduled = "Import of %s into DataVolume %s scheduled"
+	// MessageDataImportCronImportInProgress provides a const to form the import in progress message
+	MessageDataImportCronImportInProgress = "Import into DataVolume %s in progress"
+	// MessageDataImportCronImportSucceeded provides a const to form the import succeeded message
+	MessageDataImportCronImportSucceeded = "Successfully imported into PVC %s"
+	// MessageDataImportCronImportFailed provides a const to form the import failed message
+	MessageDataImportCronImportFailed = "Import into DataVolume %s failed"
+	// MessageDataImportCronNoDigest provides a const to form the no digest message
+	MessageDataImportCronNoDigest = "No image digest has been resolved yet"
+	// MessageDataImportCronGarbageCollected provides a const to form the garbage collected message
+	MessageDataImportCronGarbageCollected = "Deleted outdated DataVolume %s and its PVC"
+
+	// registrySecretClusterRole is the role the controller needs in the namespace of a DataImportCron to read its registry Secret
+	registrySecretClusterRole = "cdi.kubevirt.io:registry-secret-reader"
+
+	registryURLPrefix = "docker://"
+	digestShortLength = 12
+	// defaultImportsToKeep is the number of latest imports kept when ImportsToKeep is not set
+	defaultImportsToKeep = 3
+)
+
+// imageDigestGetter returns the digest of the image manifest referenced by a docker:// URL
+type imageDigestGetter func(ctx context.Context, url string, sys *imagetypes.SystemContext) (string, error)
+
+// DataImportCronReconciler members
+type DataImportCronReconciler struct {
+	client client.Client
+	// use this for getting any resources not in the install namespace or cluster scope
+	uncachedClient client.Client
+	recorder       record.EventRecorder
+	scheme         *runtime.Scheme
+	log            logr.Logger
+	getImageDigest imageDigestGetter
+}
+
+// Reconcile the reconcile.Reconciler implementation for the DataImportCronReconciler object.
+func (r *DataImportCronReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataImportCron", req.NamespacedName)
+	log.V(3).Info("reconciling DataImportCron")
+
+	dataImportCron := &cdiv1.DataImportCron{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, dataImportCron); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if dataImportCron.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	return r.reconcileDataImportCron(dataImportCron, log)
+}
+
+func (r *DataImportCronReconciler) reconcileDataImportCron(dataImportCron *cdiv1.DataImportCron, log logr.Logger) (reconcile.Result, error) {
+	prevDataImportCron := dataImportCron.DeepCopy()
+
+	schedule, err := cron.ParseStandard(dataImportCron.Spec.Schedule)
+	if err != nil {
+		msg := fmt.Sprintf(MessageDataImportCronInvalidSchedule, dataImportCron.Spec.Schedule, err)
+		r.recorder.Event(dataImportCron, corev1.EventTypeWarning, DataImportCronInvalidSchedule, msg)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, msg, DataImportCronInvalidSchedule)
+		// Nothing to do until the spec is fixed
+		return reconcile.Result{}, r.updateDataImportCron(prevDataImportCron, dataImportCron)
+	}
+	registry := dataImportCron.Spec.Source.Registry
+	if registry == nil || !strings.HasPrefix(registry.URL, registryURLPrefix) {
+		r.recorder.Event(dataImportCron, corev1.EventTypeWarning, DataImportCronInvalidSource, MessageDataImportCronInvalidSource)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, MessageDataImportCronInvalidSource, DataImportCronInvalidSource)
+		return reconcile.Result{}, r.updateDataImportCron(prevDataImportCron, dataImportCron)
+	}
+
+	now := time.Now()
+	nextPoll := now
+	if lastPoll := dataImportCron.Status.LastExecutionTimestamp; lastPoll != nil {
+		nextPoll = schedule.Next(lastPoll.Time)
+	}
+	if !now.Before(nextPoll) {
+		if err := r.pollRegistry(dataImportCron, log); err != nil {
+			msg := fmt.Sprintf(MessageDataImportCronPollFailed, registry.URL, err)
+			r.recorder.Event(dataImportCron, corev1.EventTypeWarning, DataImportCronPollFailed, msg)
+			updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, msg, DataImportCronPollFailed)
+			if updateErr := r.updateDataImportCron(prevDataImportCron, dataImportCron); updateErr != nil {
+				log.Error(updateErr, "Unable to update DataImportCron")
+			}
+			return reconcile.Result{}, err
+		}
+		lastPoll := metav1.NewTime(now)
+		dataImportCron.Status.LastExecutionTimestamp = &lastPoll
+		nextPoll = schedule.Next(now)
+	}
+
+	if err := r.reconcileImport(dataImportCron, log); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.updateDataImportCron(prevDataImportCron, dataImportCron); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.garbageCollectOldImports(dataImportCron, log); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.V(3).Info("next registry poll", "time", nextPoll)
+	return reconcile.Result{RequeueAfter: nextPoll.Sub(now)}, nil
+}
+
+// pollRegistry resolves the current digest of the source image and records it if it changed
+func (r *DataImportCronReconciler) pollRegistry(dataImportCron *cdiv1.DataImportCron, log logr.Logger) error {
+	registry := dataImportCron.Spec.Source.Registry
+
+	sys, cleanup, err := r.buildSystemContext(dataImportCron)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	digest, err := r.getImageDigest(context.TODO(), registry.URL, sys)
+	if err != nil {
+		return err
+	}
+	if digest == dataImportCron.Annotations[AnnSourceDesiredDigest] {
+		log.V(3).Info("digest unchanged", "digest", digest)
+		return nil
+	}
+
+	log.Info("found new digest", "digest", digest)
+	addAnnotation(dataImportCron, AnnSourceDesiredDigest, digest)
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronNewDigest, MessageDataImportCronNewDigest, digest, registry.URL)
+	return nil
+}
+
+// reconcileImport makes sure a DataVolume exists for the desired digest and follows its progress
+func (r *DataImportCronReconciler) reconcileImport(dataImportCron *cdiv1.DataImportCron, log logr.Logger) error {
+	digest := dataImportCron.Annotations[AnnSourceDesiredDigest]
+	if digest == "" {
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, MessageDataImportCronNoDigest, DataImportCronNoDigest)
+		return nil
+	}
+	dvName := getDataImportCronDataVolumeName(dataImportCron, digest)
+
+	if lastImported := dataImportCron.Status.LastImportedPVC; lastImported != nil && lastImported.Name == dvName {
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, "", "")
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionTrue, "", DataImportCronImportSucceeded)
+		return r.updateDataSource(dataImportCron, log)
+	}
+
+	dataVolume := &cdiv1.DataVolume{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: dvName}, dataVolume); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		dataVolume, err = newDataImportCronDataVolume(dataImportCron, dvName, digest)
+		if err != nil {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), dataVolume); err != nil {
+			return err
+		}
+		log.Info("created DataVolume", "name", dvName)
+		r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronImportScheduled, MessageDataImportCronImportScheduled, digest, dvName)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionTrue, fmt.Sprintf(MessageDataImportCronImportInProgress, dvName), string(cdiv1.ImportScheduled))
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, fmt.Sprintf(MessageDataImportCronImportInProgress, dvName), string(cdiv1.ImportScheduled))
+		return nil
+	}
+
+	switch dataVolume.Status.Phase {
+	case cdiv1.Succeeded:
+		importTime := metav1.Now()
+		dataImportCron.Status.LastImportedPVC = &cdiv1.DataVolumeSourcePVC{Namespace: dataVolume.Namespace, Name: dataVolume.Name}
+		dataImportCron.Status.LastImportTimestamp = &importTime
+		r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronImportSucceeded, MessageDataImportCronImportSucceeded, dvName)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, "", "")
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionTrue, "", DataImportCronImportSucceeded)
+		return r.updateDataSource(dataImportCron, log)
+	case cdiv1.Failed:
+		msg := fmt.Sprintf(MessageDataImportCronImportFailed, dvName)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionFalse, msg, DataImportCronImportFailed)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, msg, DataImportCronImportFailed)
+	default:
+		msg := fmt.Sprintf(MessageDataImportCronImportInProgress, dvName)
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronProgressing, corev1.ConditionTrue, msg, string(dataVolume.Status.Phase))
+		updateDataImportCronCondition(dataImportCron, cdiv1.DataImportCronUpToDate, corev1.ConditionFalse, msg, string(dataVolume.Status.Phase))
+	}
+	return nil
+}
+
+// updateDataSource points the managed DataSource at the last imported PVC, creating it if needed
+func (r *DataImportCronReconciler) updateDataSource(dataImportCron *cdiv1.DataImportCron, log logr.Logger) error {
+	dataSourceName := dataImportCron.Spec.ManagedDataSource
+	lastImported := dataImportCron.Status.LastImportedPVC
+	if dataSourceName == "" || lastImported == nil {
+		return nil
+	}
+
+	dataSource := &cdiv1.DataSource{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: dataSourceName}, dataSource); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		dataSource = &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dataSourceName,
+				Namespace: dataImportCron.Namespace,
+				Labels: map[string]string{
+					LabelDataImportCronName: dataImportCron.Name,
+				},
+			},
+			Spec: cdiv1.DataSourceSpec{
+				Source: cdiv1.DataSourceSource{
+					PVC: lastImported.DeepCopy(),
+				},
+			},
+		}
+		log.Info("creating DataSource", "name", dataSourceName)
+		return r.client.Create(context.TODO(), dataSource)
+	}
+
+	if dataSource.Spec.Source.PVC != nil && *dataSource.Spec.Source.PVC == *lastImported &&
+		dataSource.Labels[LabelDataImportCronName] == dataImportCron.Name {
+		return nil
+	}
+	dataSource.Spec.Source.PVC = lastImported.DeepCopy()
+	if dataSource.Labels == nil {
+		dataSource.Labels = make(map[string]string)
+	}
+	dataSource.Labels[LabelDataImportCronName] = dataImportCron.Name
+	log.Info("updating DataSource", "name", dataSourceName, "pvc", lastImported.Name)
+	return r.client.Update(context.TODO(), dataSource)
+}
+
+// garbageCollectOldImports deletes the outdated imports which are not kept by the DataImportCron retention policy
+func (r *DataImportCronReconciler) garbageCollectOldImports(dataImportCron *cdiv1.DataImportCron, log logr.Logger) error {
+	if dataImportCron.Spec.GarbageCollect == nil || *dataImportCron.Spec.GarbageCollect != cdiv1.DataImportCronGarbageCollectOutdated {
+		return nil
+	}
+
+	dvList := &cdiv1.DataVolumeList{}
+	if err := r.client.List(context.TODO(), dvList, client.InNamespace(dataImportCron.Namespace), client.MatchingLabels{LabelDataImportCronName: dataImportCron.Name}); err != nil {
+		return err
+	}
+	dataVolumes := dvList.Items
+	// Newest first
+	sort.Slice(dataVolumes, func(i, j int) bool {
+		return dataVolumes[j].CreationTimestamp.Before(&dataVolumes[i].CreationTimestamp)
+	})
+
+	// Never collect the current import, nor the one being imported
+	keep := sets.NewString()
+	if digest := dataImportCron.Annotations[AnnSourceDesiredDigest]; digest != "" {
+		keep.Insert(getDataImportCronDataVolumeName(dataImportCron, digest))
+	}
+	if lastImported := dataImportCron.Status.LastImportedPVC; lastImported != nil {
+		keep.Insert(lastImported.Name)
+	}
+	importsToKeep := defaultImportsToKeep
+	if dataImportCron.Spec.ImportsToKeep != nil {
+		importsToKeep = int(*dataImportCron.Spec.ImportsToKeep)
+	}
+	maxAge := dataImportCron.Spec.ImportsMaxAge
+
+	now := time.Now()
+	for i := range dataVolumes {
+		dataVolume := &dataVolumes[i]
+		if keep.Has(dataVolume.Name) || i < importsToKeep {
+			continue
+		}
+		if maxAge != nil && now.Sub(dataVolume.CreationTimestamp.Time) < maxAge.Duration {
+			continue
+		}
+		if err := r.deleteOutdatedImport(dataImportCron, dataVolume, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteOutdatedImport deletes the DataVolume, and so its PVC, unless the PVC is still referenced or in use
+func (r *DataImportCronReconciler) deleteOutdatedImport(dataImportCron *cdiv1.DataImportCron, dataVolume *cdiv1.DataVolume, log logr.Logger) error {
+	pvcName := GetDataVolumeClaimName(dataVolume)
+	referenced, err := isPvcReferencedByDataSource(r.client, dataVolume.Namespace, pvcName)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		log.V(3).Info("outdated PVC is referenced by a DataSource, not collecting", "name", pvcName)
+		return nil
+	}
+	pods, err := GetPodsUsingPVCs(r.client, dataVolume.Namespace, sets.NewString(pvcName), false)
+	if err != nil {
+		return err
+	}
+	if len(pods) > 0 {
+		log.V(3).Info("outdated PVC is in use, not collecting", "name", pvcName, "pods", len(pods))
+		return nil
+	}
+
+	log.Info("deleting outdated import", "name", dataVolume.Name)
+	if err := r.client.Delete(context.TODO(), dataVolume); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	r.recorder.Eventf(dataImportCron, corev1.EventTypeNormal, DataImportCronGarbageCollected, MessageDataImportCronGarbageCollected, dataVolume.Name)
+	return nil
+}
+
+func (r *DataImportCronReconciler) updateDataImportCron(prevDataImportCron, dataImportCron *cdiv1.DataImportCron) error {
+	if reflect.DeepEqual(prevDataImportCron, dataImportCron) {
+		return nil
+	}
+	return r.client.Update(context.TODO(), dataImportCron)
+}
+
+// buildSystemContext returns the image system context used to access the registry, and a cleanup func
+func (r *DataImportCronReconciler) buildSystemContext(dataImportCron *cdiv1.DataImportCron) (*imagetypes.SystemContext, func(), error) {
+	registry := dataImportCron.Spec.Source.Registry
+	sys := &imagetypes.SystemContext{}
+	cleanup := func() {}
+
+	if registry.SecretRef != "" {
+		secret := &corev1.Secret{}
+		if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Namespace: dataImportCron.Namespace, Name: registry.SecretRef}, secret); err != nil {
+			if k8serrors.IsForbidden(err) {
+				// The controller may not read Secrets cluster wide, the role has to be bound in the DataImportCron namespace
+				return nil, cleanup, errors.Errorf("unable to get secret %s, bind the %s ClusterRole to the CDI service account in namespace %s",
+					registry.SecretRef, registrySecretClusterRole, dataImportCron.Namespace)
+			}
+			return nil, cleanup, errors.Wrapf(err, "unable to get secret %s", registry.SecretRef)
+		}
+		accessKey, secretKey := string(secret.Data[common.KeyAccess]), string(secret.Data[common.KeySecret])
+		if accessKey != "" && secretKey != "" {
+			sys.DockerAuthConfig = &imagetypes.DockerAuthConfig{Username: accessKey, Password: secretKey}
+		}
+	}
+
+	if registry.CertConfigMap != "" {
+		certDir, err := r.writeCertConfigMap(dataImportCron.Namespace, registry.CertConfigMap)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() { os.RemoveAll(certDir) }
+		sys.DockerCertPath = certDir
+	}
+
+	insecure, err := r.isInsecureRegistry(registry.URL)
+	if err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	if insecure {
+		sys.DockerInsecureSkipTLSVerify = imagetypes.NewOptionalBool(true)
+	}
+
+	return sys, cleanup, nil
+}
+
+func (r *DataImportCronReconciler) writeCertConfigMap(namespace, name string) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.uncachedClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, configMap); err != nil {
+		return "", errors.Wrapf(err, "unable to get configmap %s", name)
+	}
+	certDir, err := ioutil.TempDir("", "dataimportcron-certs")
+	if err != nil {
+		return "", err
+	}
+	for file, data := range configMap.Data {
+		if err := ioutil.WriteFile(filepath.Join(certDir, file), []byte(data), 0600); err != nil {
+			os.RemoveAll(certDir)
+			return "", err
+		}
+	}
+	return certDir, nil
+}
+
+func (r *DataImportCronReconciler) isInsecureRegistry(url string) (bool, error) {
+	cdiConfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	host := strings.SplitN(strings.TrimPrefix(url, registryURLPrefix), "/", 2)[0]
+	for _, value := range cdiConfig.Spec.InsecureRegistries {
+		if value == host {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getRegistryImageDigest fetches the image manifest from the registry and returns its digest
+func getRegistryImageDigest(ctx context.Context, url string, sys *imagetypes.SystemContext) (string, error) {
+	ref, err := docker.ParseReference(strings.TrimPrefix(url, "docker:"))
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse image")
+	}
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not create image reference")
+	}
+	defer src.Close()
+
+	rawManifest, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get image manifest")
+	}
+	digest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute manifest digest")
+	}
+	return digest.String(), nil
+}
+
+// getDataImportCronDataVolumeName returns the name of the DataVolume importing the given digest
+func getDataImportCronDataVolumeName(dataImportCron *cdiv1.DataImportCron, digest string) string {
+	digestHex := digest[strings.Index(digest, ":")+1:]
+	if len(digestHex) > digestShortLength {
+		digestHex = digestHex[:digestShortLength]
+	}
+	return naming.GetResourceName(dataImportCron.Name, digestHex)
+}
+
+// getPinnedRegistryURL returns the registry URL with the tag replaced by the given digest
+func getPinnedRegistryURL(url, digest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(url, registryURLPrefix))
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse image")
+	}
+	return registryURLPrefix + reference.TrimNamed(named).String() + "@" + digest, nil
+}
+
+func newDataImportCronDataVolume(dataImportCron *cdiv1.DataImportCron, name, digest string) (*cdiv1.DataVolume, error) {
+	url, err := getPinnedRegistryURL(dataImportCron.Spec.Source.Registry.URL, digest)
+	if err != nil {
+		return nil, err
+	}
+	dataVolume := dataImportCron.Spec.Template.DeepCopy()
+	dataVolume.ObjectMeta = metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   dataImportCron.Namespace,
+		Labels:      dataVolume.Labels,
+		Annotations: dataVolume.Annotations,
+	}
+	if dataVolume.Labels == nil {
+		dataVolume.Labels = make(map[string]string)
+	}
+	dataVolume.Labels[LabelDataImportCronName] = dataImportCron.Name
+	registry := dataImportCron.Spec.Source.Registry.DeepCopy()
+	registry.URL = url
+	registry.Digest = digest
+	dataVolume.Spec.Source = &cdiv1.DataVolumeSource{Registry: registry}
+	dataVolume.Spec.SourceRef = nil
+	dataVolume.Status = cdiv1.DataVolumeStatus{}
+	return dataVolume, nil
+}
+
+func findDataImportCronConditionByType(conditionType cdiv1.DataImportCronConditionType, conditions []cdiv1.DataImportCronCondition) *cdiv1.DataImportCronCondition {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func updateDataImportCronCondition(dataImportCron *cdiv1.DataImportCron, conditionType cdiv1.DataImportCronConditionType, status corev1.ConditionStatus, message, reason string) {
+	condition := findDataImportCronConditionByType(conditionType, dataImportCron.Status.Conditions)
+	if condition == nil {
+		dataImportCron.Status.Conditions = append(dataImportCron.Status.Conditions, cdiv1.DataImportCronCondition{
+			Type: conditionType,
+		})
+		condition = findDataImportCronConditionByType(conditionType, dataImportCron.Status.Conditions)
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+		condition.Message = message
+		condition.Reason = reason
+		condition.LastHeartbeatTime = condition.LastTransitionTime
+	} else if condition.Message != message || condition.Reason != reason {
+		condition.Message = message
+		condition.Reason = reason
+		condition.LastHeartbeatTime = metav1.Now()
+	}
+	condition.Status = status
+}
+
+// NewDataImportCronController creates a new instance of the DataImportCron controller
+func NewDataImportCronController(mgr manager.Manager, log logr.Logger) (controller.Controller, error) {
+	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reconciler := &DataImportCronReconciler{
+		client:         mgr.GetClient(),
+		uncachedClient: uncachedClient,
+		recorder:       mgr.GetEventRecorderFor("dataimportcron-controller"),
+		scheme:         mgr.GetScheme(),
+		log:            log.WithName("dataimportcron-controller"),
+		getImageDigest: getRegistryImageDigest,
+	}
+
+	dataImportCronController, err := controller.New(
+		"dataimportcron-controller",
+		mgr,
+		controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDataImportCronControllerWatches(mgr, dataImportCronController); err != nil {
+		return nil, err
+	}
+
+	log.Info("Initialized DataImportCron controller")
+	return dataImportCronController, nil
+}
+
+func addDataImportCronControllerWatches(mgr manager.Manager, c controller.Controller) error {
+	if err := cdiv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataImportCron{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Map DataVolumes and DataSources managed by a DataImportCron back to it
+	mapToDataImportCron := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		cronName, ok := obj.GetLabels()[LabelDataImportCronName]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: cronName}}}
+	})
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataVolume{}}, mapToDataImportCron); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataSource{}}, mapToDataImportCron); err != nil {
+		return err
+	}
+	return nil
+}
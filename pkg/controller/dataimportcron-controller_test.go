@@ -0,0 +1,487 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	imagetypes "github.com/containers/image/v5/types"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var (
+	dicLog = logf.Log.WithName("dataimportcron-controller-test")
+)
+
+const (
+	testCronName       = "test-cron"
+	testDataSourceName = "test-datasource"
+	testRegistryURL    = "docker://quay.io/kubevirt/fedora-cloud-container-disk-demo:latest"
+	testDigest         = "sha256:68b44fc891f3fae6703d4b74bcc9b5f24df8d23f12e642805d1420cbe7a4be70"
+	testDigest2        = "sha256:12345fc891f3fae6703d4b74bcc9b5f24df8d23f12e642805d1420cbe7a4be70"
+)
+
+var _ = Describe("All DataImportCron Tests", func() {
+	var _ = Describe("DataImportCron controller reconcile loop", func() {
+		var (
+			reconciler *DataImportCronReconciler
+			digest     string
+		)
+		cronKey := types.NamespacedName{Name: testCronName, Namespace: metav1.NamespaceDefault}
+		cronReq := reconcile.Request{NamespacedName: cronKey}
+
+		BeforeEach(func() {
+			digest = testDigest
+		})
+
+		AfterEach(func() {
+			if reconciler != nil {
+				close(reconciler.recorder.(*record.FakeRecorder).Events)
+				reconciler = nil
+			}
+		})
+
+		getCron := func() *cdiv1.DataImportCron {
+			cron := &cdiv1.DataImportCron{}
+			err := reconciler.client.Get(context.TODO(), cronKey, cron)
+			Expect(err).ToNot(HaveOccurred())
+			return cron
+		}
+
+		getDataVolume := func(digest string) *cdiv1.DataVolume {
+			dv := &cdiv1.DataVolume{}
+			dvKey := types.NamespacedName{Name: getDataImportCronDataVolumeName(getCron(), digest), Namespace: metav1.NamespaceDefault}
+			err := reconciler.client.Get(context.TODO(), dvKey, dv)
+			Expect(err).ToNot(HaveOccurred())
+			return dv
+		}
+
+		verifyConditions := func(progressing, upToDate corev1.ConditionStatus) {
+			cron := getCron()
+			condition := findDataImportCronConditionByType(cdiv1.DataImportCronProgressing, cron.Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(progressing))
+			condition = findDataImportCronConditionByType(cdiv1.DataImportCronUpToDate, cron.Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(upToDate))
+		}
+
+		It("Should return nil if no DataImportCron can be found", func() {
+			reconciler = createDataImportCronReconciler(&digest)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should report invalid schedule and not requeue", func() {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Spec.Schedule = "not a schedule"
+			reconciler = createDataImportCronReconciler(&digest, cron)
+			res, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+
+			condition := findDataImportCronConditionByType(cdiv1.DataImportCronProgressing, getCron().Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(DataImportCronInvalidSchedule))
+			Expect(getCron().Status.LastExecutionTimestamp).To(BeNil())
+		})
+
+		It("Should create a DataVolume pinned to the digest and requeue for the next poll", func() {
+			reconciler = createDataImportCronReconciler(&digest, createDataImportCron(testCronName, testRegistryURL))
+			res, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(res.RequeueAfter).To(BeNumerically("<=", time.Minute))
+
+			cron := getCron()
+			Expect(cron.Annotations[AnnSourceDesiredDigest]).To(Equal(testDigest))
+			Expect(cron.Status.LastExecutionTimestamp).ToNot(BeNil())
+			dv := getDataVolume(testDigest)
+			Expect(dv.Labels[LabelDataImportCronName]).To(Equal(testCronName))
+			Expect(dv.Spec.Source.Registry.URL).To(Equal("docker://quay.io/kubevirt/fedora-cloud-container-disk-demo@" + testDigest))
+			Expect(dv.Spec.Source.Registry.Digest).To(Equal(testDigest))
+			Expect(dv.Spec.PVC).ToNot(BeNil())
+			verifyConditions(corev1.ConditionTrue, corev1.ConditionFalse)
+		})
+
+		It("Should not poll the registry before the next scheduled time", func() {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			lastPoll := metav1.Now()
+			cron.Status.LastExecutionTimestamp = &lastPoll
+			reconciler = createDataImportCronReconciler(&digest, cron)
+			reconciler.getImageDigest = func(context.Context, string, *imagetypes.SystemContext) (string, error) {
+				return "", fmt.Errorf("should not poll")
+			}
+			res, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(getCron().Annotations[AnnSourceDesiredDigest]).To(BeEmpty())
+		})
+
+		It("Should update DataSource when the import succeeds, and import again only on digest change", func() {
+			reconciler = createDataImportCronReconciler(&digest, createDataImportCron(testCronName, testRegistryURL))
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			dv := getDataVolume(testDigest)
+			dv.Status.Phase = cdiv1.Succeeded
+			err = reconciler.client.Update(context.TODO(), dv)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			cron := getCron()
+			Expect(cron.Status.LastImportedPVC).ToNot(BeNil())
+			Expect(cron.Status.LastImportedPVC.Name).To(Equal(dv.Name))
+			Expect(cron.Status.LastImportTimestamp).ToNot(BeNil())
+			verifyConditions(corev1.ConditionFalse, corev1.ConditionTrue)
+
+			dataSource := &cdiv1.DataSource{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testDataSourceName, Namespace: metav1.NamespaceDefault}, dataSource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataSource.Spec.Source.PVC).ToNot(BeNil())
+			Expect(*dataSource.Spec.Source.PVC).To(Equal(*cron.Status.LastImportedPVC))
+			Expect(dataSource.Labels[LabelDataImportCronName]).To(Equal(testCronName))
+
+			// Same digest on the next poll, nothing new should be imported
+			cron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			err = reconciler.client.Update(context.TODO(), cron)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			dvList := &cdiv1.DataVolumeList{}
+			err = reconciler.client.List(context.TODO(), dvList)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvList.Items).To(HaveLen(1))
+
+			// New digest, a new DataVolume should be created
+			digest = testDigest2
+			cron = getCron()
+			cron.Status.LastExecutionTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			err = reconciler.client.Update(context.TODO(), cron)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCron().Annotations[AnnSourceDesiredDigest]).To(Equal(testDigest2))
+			getDataVolume(testDigest2)
+			verifyConditions(corev1.ConditionTrue, corev1.ConditionFalse)
+		})
+
+		It("Should repoint an existing DataSource at the newest PVC", func() {
+			dataSource := &cdiv1.DataSource{
+				ObjectMeta: metav1.ObjectMeta{Name: testDataSourceName, Namespace: metav1.NamespaceDefault},
+				Spec: cdiv1.DataSourceSpec{
+					Source: cdiv1.DataSourceSource{
+						PVC: &cdiv1.DataVolumeSourcePVC{Namespace: metav1.NamespaceDefault, Name: "old-pvc"},
+					},
+				},
+			}
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Annotations = map[string]string{AnnSourceDesiredDigest: testDigest}
+			dv, err := newDataImportCronDataVolume(cron, getDataImportCronDataVolumeName(cron, testDigest), testDigest)
+			Expect(err).ToNot(HaveOccurred())
+			dv.Status.Phase = cdiv1.Succeeded
+			lastPoll := metav1.Now()
+			cron.Status.LastExecutionTimestamp = &lastPoll
+			reconciler = createDataImportCronReconciler(&digest, cron, dv, dataSource)
+
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testDataSourceName, Namespace: metav1.NamespaceDefault}, dataSource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataSource.Spec.Source.PVC.Name).To(Equal(dv.Name))
+		})
+
+		It("Should report a failed import", func() {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Annotations = map[string]string{AnnSourceDesiredDigest: testDigest}
+			dv, err := newDataImportCronDataVolume(cron, getDataImportCronDataVolumeName(cron, testDigest), testDigest)
+			Expect(err).ToNot(HaveOccurred())
+			dv.Status.Phase = cdiv1.Failed
+			lastPoll := metav1.Now()
+			cron.Status.LastExecutionTimestamp = &lastPoll
+			reconciler = createDataImportCronReconciler(&digest, cron, dv)
+
+			_, err = reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyConditions(corev1.ConditionFalse, corev1.ConditionFalse)
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testDataSourceName, Namespace: metav1.NamespaceDefault}, &cdiv1.DataSource{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should return an error and report when the registry poll fails", func() {
+			reconciler = createDataImportCronReconciler(&digest, createDataImportCron(testCronName, testRegistryURL))
+			reconciler.getImageDigest = func(context.Context, string, *imagetypes.SystemContext) (string, error) {
+				return "", fmt.Errorf("registry unavailable")
+			}
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).To(HaveOccurred())
+			condition := findDataImportCronConditionByType(cdiv1.DataImportCronProgressing, getCron().Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(DataImportCronPollFailed))
+		})
+
+		It("Should pass registry credentials and insecure flag to the digest getter", func() {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Spec.Source.Registry.SecretRef = "registry-secret"
+			config := createCDIConfig("config")
+			config.Spec.InsecureRegistries = []string{"quay.io"}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry-secret", Namespace: metav1.NamespaceDefault},
+				Data: map[string][]byte{
+					common.KeyAccess: []byte("user"),
+					common.KeySecret: []byte("password"),
+				},
+			}
+			reconciler = createDataImportCronReconciler(&digest, cron, config, secret)
+			var sysCtx *imagetypes.SystemContext
+			reconciler.getImageDigest = func(_ context.Context, _ string, sys *imagetypes.SystemContext) (string, error) {
+				sysCtx = sys
+				return testDigest, nil
+			}
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sysCtx).ToNot(BeNil())
+			Expect(sysCtx.DockerAuthConfig).ToNot(BeNil())
+			Expect(sysCtx.DockerAuthConfig.Username).To(Equal("user"))
+			Expect(sysCtx.DockerAuthConfig.Password).To(Equal("password"))
+			Expect(sysCtx.DockerInsecureSkipTLSVerify).To(Equal(imagetypes.OptionalBoolTrue))
+		})
+		It("Should report the missing role when the registry secret can not be read", func() {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Spec.Source.Registry.SecretRef = "registry-secret"
+			reconciler = createDataImportCronReconciler(&digest, cron)
+			reconciler.uncachedClient = &forbiddenSecretClient{Client: reconciler.uncachedClient}
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(registrySecretClusterRole))
+			condition := findDataImportCronConditionByType(cdiv1.DataImportCronProgressing, getCron().Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(DataImportCronPollFailed))
+			Expect(condition.Message).To(ContainSubstring(registrySecretClusterRole))
+		})
+	})
+
+	var _ = Describe("DataImportCron garbage collection", func() {
+		var (
+			reconciler *DataImportCronReconciler
+			digest     = testDigest
+		)
+		cronReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: testCronName, Namespace: metav1.NamespaceDefault}}
+
+		AfterEach(func() {
+			if reconciler != nil {
+				close(reconciler.recorder.(*record.FakeRecorder).Events)
+				reconciler = nil
+			}
+		})
+
+		createCronWithImports := func(garbageCollect cdiv1.DataImportCronGarbageCollect) (*cdiv1.DataImportCron, []runtime.Object) {
+			cron := createDataImportCron(testCronName, testRegistryURL)
+			cron.Spec.GarbageCollect = &garbageCollect
+			cron.Annotations = map[string]string{AnnSourceDesiredDigest: testDigest}
+			lastPoll := metav1.Now()
+			cron.Status.LastExecutionTimestamp = &lastPoll
+			current := createDataImportCronDataVolume(cron, getDataImportCronDataVolumeName(cron, testDigest), time.Minute)
+			current.Status.Phase = cdiv1.Succeeded
+			cron.Status.LastImportedPVC = &cdiv1.DataVolumeSourcePVC{Namespace: current.Namespace, Name: current.Name}
+			objs := []runtime.Object{cron, current}
+			for i := 1; i <= 3; i++ {
+				objs = append(objs, createDataImportCronDataVolume(cron, fmt.Sprintf("old-import-%d", i), time.Duration(i)*time.Hour))
+			}
+			return cron, objs
+		}
+
+		dataVolumeExists := func(name string) bool {
+			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metav1.NamespaceDefault}, &cdiv1.DataVolume{})
+			if k8serrors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).ToNot(HaveOccurred())
+			return true
+		}
+
+		It("Should not collect anything if garbage collection is disabled", func() {
+			_, objs := createCronWithImports(cdiv1.DataImportCronGarbageCollectNever)
+			reconciler = createDataImportCronReconciler(&digest, objs...)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			for i := 1; i <= 3; i++ {
+				Expect(dataVolumeExists(fmt.Sprintf("old-import-%d", i))).To(BeTrue())
+			}
+		})
+
+		It("Should keep the latest imports by default", func() {
+			_, objs := createCronWithImports(cdiv1.DataImportCronGarbageCollectOutdated)
+			reconciler = createDataImportCronReconciler(&digest, objs...)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataVolumeExists("old-import-1")).To(BeTrue())
+			Expect(dataVolumeExists("old-import-2")).To(BeTrue())
+			Expect(dataVolumeExists("old-import-3")).To(BeFalse())
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(DataImportCronGarbageCollected)))
+		})
+
+		It("Should keep the imports newer than max age", func() {
+			cron, objs := createCronWithImports(cdiv1.DataImportCronGarbageCollectOutdated)
+			importsToKeep := int32(1)
+			cron.Spec.ImportsToKeep = &importsToKeep
+			cron.Spec.ImportsMaxAge = &metav1.Duration{Duration: 90 * time.Minute}
+			reconciler = createDataImportCronReconciler(&digest, objs...)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataVolumeExists(cron.Status.LastImportedPVC.Name)).To(BeTrue())
+			Expect(dataVolumeExists("old-import-1")).To(BeTrue())
+			Expect(dataVolumeExists("old-import-2")).To(BeFalse())
+			Expect(dataVolumeExists("old-import-3")).To(BeFalse())
+		})
+
+		It("Should not collect imports referenced by a DataSource or used by a pod", func() {
+			cron, objs := createCronWithImports(cdiv1.DataImportCronGarbageCollectOutdated)
+			importsToKeep := int32(0)
+			cron.Spec.ImportsToKeep = &importsToKeep
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: metav1.NamespaceDefault},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "disk",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "old-import-2"},
+							},
+						},
+					},
+				},
+			}
+			objs = append(objs, createDataSource("pinned", "old-import-1"), pod)
+			reconciler = createDataImportCronReconciler(&digest, objs...)
+			_, err := reconciler.Reconcile(context.TODO(), cronReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataVolumeExists(cron.Status.LastImportedPVC.Name)).To(BeTrue())
+			Expect(dataVolumeExists("old-import-1")).To(BeTrue())
+			Expect(dataVolumeExists("old-import-2")).To(BeTrue())
+			Expect(dataVolumeExists("old-import-3")).To(BeFalse())
+		})
+	})
+
+	table.DescribeTable("getPinnedRegistryURL", func(url, expected string) {
+		pinned, err := getPinnedRegistryURL(url, testDigest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pinned).To(Equal(expected))
+	},
+		table.Entry("with tag", "docker://quay.io/kubevirt/cirros:latest", "docker://quay.io/kubevirt/cirros@"+testDigest),
+		table.Entry("without tag", "docker://quay.io/kubevirt/cirros", "docker://quay.io/kubevirt/cirros@"+testDigest),
+		table.Entry("with registry port", "docker://myregistry:5000/cirros:v1", "docker://myregistry:5000/cirros@"+testDigest),
+		table.Entry("with existing digest", "docker://quay.io/kubevirt/cirros@"+testDigest2, "docker://quay.io/kubevirt/cirros@"+testDigest),
+	)
+})
+
+func createDataImportCronReconciler(digest *string, objects ...runtime.Object) *DataImportCronReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	// Register operator types with the runtime scheme.
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	rec := record.NewFakeRecorder(10)
+	r := &DataImportCronReconciler{
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            dicLog,
+		recorder:       rec,
+		getImageDigest: func(context.Context, string, *imagetypes.SystemContext) (string, error) {
+			return *digest, nil
+		},
+	}
+	return r
+}
+
+// forbiddenSecretClient denies access to Secrets like an API server without the registry secret role bound
+type forbiddenSecretClient struct {
+	client.Client
+}
+
+func (c *forbiddenSecretClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		return k8serrors.NewForbidden(corev1.Resource("secrets"), key.Name, fmt.Errorf("access denied"))
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func createDataImportCron(name, url string) *cdiv1.DataImportCron {
+	garbageCollect := cdiv1.DataImportCronGarbageCollectNever
+	return &cdiv1.DataImportCron{
+		TypeMeta: metav1.TypeMeta{APIVersion: cdiv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: cdiv1.DataImportCronSpec{
+			Source: cdiv1.DataImportCronSource{
+				Registry: &cdiv1.DataVolumeSourceRegistry{
+					URL: url,
+				},
+			},
+			Template: cdiv1.DataVolume{
+				Spec: cdiv1.DataVolumeSpec{
+					PVC: &corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("5Gi"),
+							},
+						},
+					},
+				},
+			},
+			Schedule:          "* * * * *",
+			GarbageCollect:    &garbageCollect,
+			ManagedDataSource: testDataSourceName,
+		},
+	}
+}
+
+func createDataImportCronDataVolume(cron *cdiv1.DataImportCron, name string, age time.Duration) *cdiv1.DataVolume {
+	dv, err := newDataImportCronDataVolume(cron, name, testDigest)
+	Expect(err).ToNot(HaveOccurred())
+	dv.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	return dv
+}
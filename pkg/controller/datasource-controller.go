@@ -0,0 +1,276 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	// DataSourceSourceReady provides a const to indicate the DataSource source is ready to be used
+	DataSourceSourceReady = "Ready"
+	// DataSourceNoSource provides a const to indicate the DataSource has no source
+	DataSourceNoSource = "NoSource"
+	// DataSourceNotFound provides a const to indicate the DataSource source does not exist
+	DataSourceNotFound = "NotFound"
+	// DataSourceNotPopulated provides a const to indicate the DataSource source is not populated yet
+	DataSourceNotPopulated = "NotPopulated"
+	// DataSourceMarkedForDeletion provides a const to indicate the DataSource source is being deleted
+	DataSourceMarkedForDeletion = "MarkedForDeletion"
+
+	// MessageDataSourceReady provides a const to form the DataSource ready message
+	MessageDataSourceReady = "DataSource is ready to be consumed"
+	// MessageDataSourceNoSource provides a const to form the no source message
+	MessageDataSourceNoSource = "No source PVC set"
+	// MessageDataSourceNotFound provides a const to form the source not found message
+	MessageDataSourceNotFound = "Source PVC %s/%s not found"
+	// MessageDataSourceNotPopulated provides a const to form the source not populated message
+	MessageDataSourceNotPopulated = "Source PVC %s/%s is not populated yet"
+	// MessageDataSourceMarkedForDeletion provides a const to form the source marked for deletion message
+	MessageDataSourceMarkedForDeletion = "Source PVC %s/%s is marked for deletion"
+
+	dataSourcePvcField = "spec.source.pvc"
+)
+
+// DataSourceReconciler members
+type DataSourceReconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+}
+
+// Reconcile the reconcile.Reconciler implementation for the DataSourceReconciler object.
+func (r *DataSourceReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataSource", req.NamespacedName)
+	log.V(3).Info("reconciling DataSource")
+
+	dataSource := &cdiv1.DataSource{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, dataSource); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if dataSource.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.reconcileDataSource(dataSource, log)
+}
+
+func (r *DataSourceReconciler) reconcileDataSource(dataSource *cdiv1.DataSource, log logr.Logger) error {
+	prevDataSource := dataSource.DeepCopy()
+
+	sourcePvc := dataSource.Spec.Source.PVC
+	if sourcePvc == nil {
+		updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionFalse, MessageDataSourceNoSource, DataSourceNoSource)
+		return r.updateDataSource(prevDataSource, dataSource, log)
+	}
+
+	namespace := getDataSourcePvcNamespace(dataSource)
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: sourcePvc.Name}, pvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		msg := fmt.Sprintf(MessageDataSourceNotFound, namespace, sourcePvc.Name)
+		updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionFalse, msg, DataSourceNotFound)
+		return r.updateDataSource(prevDataSource, dataSource, log)
+	}
+
+	if pvc.DeletionTimestamp != nil {
+		msg := fmt.Sprintf(MessageDataSourceMarkedForDeletion, namespace, sourcePvc.Name)
+		updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionFalse, msg, DataSourceMarkedForDeletion)
+		return r.updateDataSource(prevDataSource, dataSource, log)
+	}
+
+	populated, err := IsPopulated(pvc, r.client)
+	if err != nil {
+		return err
+	}
+	if !populated {
+		msg := fmt.Sprintf(MessageDataSourceNotPopulated, namespace, sourcePvc.Name)
+		updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionFalse, msg, DataSourceNotPopulated)
+		return r.updateDataSource(prevDataSource, dataSource, log)
+	}
+
+	updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionTrue, "", DataSourceSourceReady)
+	return r.updateDataSource(prevDataSource, dataSource, log)
+}
+
+func (r *DataSourceReconciler) updateDataSource(prevDataSource, dataSource *cdiv1.DataSource, log logr.Logger) error {
+	if reflect.DeepEqual(prevDataSource, dataSource) {
+		return nil
+	}
+	prevReady := isDataSourceReady(prevDataSource)
+	if ready := isDataSourceReady(dataSource); ready != prevReady {
+		log.Info("DataSource readiness changed", "ready", ready)
+		if ready {
+			r.recorder.Event(dataSource, corev1.EventTypeNormal, DataSourceSourceReady, MessageDataSourceReady)
+		}
+	}
+	return r.client.Update(context.TODO(), dataSource)
+}
+
+// getDataSourcePvcNamespace returns the namespace of the DataSource source PVC, defaulting to the DataSource namespace
+func getDataSourcePvcNamespace(dataSource *cdiv1.DataSource) string {
+	if ns := dataSource.Spec.Source.PVC.Namespace; ns != "" {
+		return ns
+	}
+	return dataSource.Namespace
+}
+
+// isPvcReferencedByDataSource returns true if any DataSource points at the given PVC
+func isPvcReferencedByDataSource(c client.Client, namespace, name string) (bool, error) {
+	var dataSources cdiv1.DataSourceList
+	value := types.NamespacedName{Namespace: namespace, Name: name}.String()
+	if err := c.List(context.TODO(), &dataSources, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(dataSourcePvcField, value),
+	}); err != nil {
+		return false, err
+	}
+	for i := range dataSources.Items {
+		dataSource := &dataSources.Items[i]
+		if dataSource.Spec.Source.PVC != nil && dataSource.Spec.Source.PVC.Name == name && getDataSourcePvcNamespace(dataSource) == namespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isDataSourceReady returns true if the DataSource Ready condition is true
+func isDataSourceReady(dataSource *cdiv1.DataSource) bool {
+	condition := findDataSourceConditionByType(cdiv1.DataSourceReady, dataSource.Status.Conditions)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func findDataSourceConditionByType(conditionType cdiv1.DataSourceConditionType, conditions []cdiv1.DataSourceCondition) *cdiv1.DataSourceCondition {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func updateDataSourceCondition(dataSource *cdiv1.DataSource, conditionType cdiv1.DataSourceConditionType, status corev1.ConditionStatus, message, reason string) {
+	condition := findDataSourceConditionByType(conditionType, dataSource.Status.Conditions)
+	if condition == nil {
+		dataSource.Status.Conditions = append(dataSource.Status.Conditions, cdiv1.DataSourceCondition{
+			Type: conditionType,
+		})
+		condition = findDataSourceConditionByType(conditionType, dataSource.Status.Conditions)
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+		condition.Message = message
+		condition.Reason = reason
+		condition.LastHeartbeatTime = condition.LastTransitionTime
+	} else if condition.Message != message || condition.Reason != reason {
+		condition.Message = message
+		condition.Reason = reason
+		condition.LastHeartbeatTime = metav1.Now()
+	}
+	condition.Status = status
+}
+
+// NewDataSourceController creates a new instance of the DataSource controller
+func NewDataSourceController(mgr manager.Manager, log logr.Logger) (controller.Controller, error) {
+	reconciler := &DataSourceReconciler{
+		client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("datasource-controller"),
+		scheme:   mgr.GetScheme(),
+		log:      log.WithName("datasource-controller"),
+	}
+
+	dataSourceController, err := controller.New(
+		"datasource-controller",
+		mgr,
+		controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDataSourceControllerWatches(mgr, dataSourceController); err != nil {
+		return nil, err
+	}
+
+	log.Info("Initialized DataSource controller")
+	return dataSourceController, nil
+}
+
+func addDataSourceControllerWatches(mgr manager.Manager, c controller.Controller) error {
+	if err := cdiv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataSource{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &cdiv1.DataSource{}, dataSourcePvcField, func(obj client.Object) []string {
+		dataSource := obj.(*cdiv1.DataSource)
+		if dataSource.Spec.Source.PVC == nil {
+			return nil
+		}
+		return []string{types.NamespacedName{Namespace: getDataSourcePvcNamespace(dataSource), Name: dataSource.Spec.Source.PVC.Name}.String()}
+	}); err != nil {
+		return err
+	}
+
+	// Both the PVC and the DataVolume populating it affect readiness, and they share the same name
+	mapToDataSource := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		var dataSources cdiv1.DataSourceList
+		value := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+		if err := mgr.GetClient().List(context.TODO(), &dataSources, &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(dataSourcePvcField, value),
+		}); err != nil {
+			return nil
+		}
+		var result []reconcile.Request
+		for _, dataSource := range dataSources.Items {
+			result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dataSource.Namespace, Name: dataSource.Name}})
+		}
+		return result
+	})
+	if err := c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, mapToDataSource); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &cdiv1.DataVolume{}}, mapToDataSource); err != nil {
+		return err
+	}
+	return nil
+}
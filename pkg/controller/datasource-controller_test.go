@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var (
+	dsLog = logf.Log.WithName("datasource-controller-test")
+)
+
+var _ = Describe("All DataSource Tests", func() {
+	var _ = Describe("DataSource controller reconcile loop", func() {
+		var (
+			reconciler *DataSourceReconciler
+		)
+		dsKey := types.NamespacedName{Name: testDataSourceName, Namespace: metav1.NamespaceDefault}
+		dsReq := reconcile.Request{NamespacedName: dsKey}
+
+		AfterEach(func() {
+			if reconciler != nil {
+				close(reconciler.recorder.(*record.FakeRecorder).Events)
+				reconciler = nil
+			}
+		})
+
+		verifyReady := func(status corev1.ConditionStatus, reason string) {
+			dataSource := &cdiv1.DataSource{}
+			err := reconciler.client.Get(context.TODO(), dsKey, dataSource)
+			Expect(err).ToNot(HaveOccurred())
+			condition := findDataSourceConditionByType(cdiv1.DataSourceReady, dataSource.Status.Conditions)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(status))
+			Expect(condition.Reason).To(Equal(reason))
+		}
+
+		It("Should return nil if no DataSource can be found", func() {
+			reconciler = createDataSourceReconciler()
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should not be ready if no source is set", func() {
+			dataSource := createDataSource(testDataSourceName, "")
+			dataSource.Spec.Source.PVC = nil
+			reconciler = createDataSourceReconciler(dataSource)
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionFalse, DataSourceNoSource)
+		})
+
+		It("Should not be ready if the source PVC does not exist", func() {
+			reconciler = createDataSourceReconciler(createDataSource(testDataSourceName, "test-pvc"))
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionFalse, DataSourceNotFound)
+		})
+
+		It("Should be ready if the source PVC exists and is not owned by a DataVolume", func() {
+			pvc := createPvc("test-pvc", metav1.NamespaceDefault, nil, nil)
+			reconciler = createDataSourceReconciler(createDataSource(testDataSourceName, "test-pvc"), pvc)
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionTrue, DataSourceSourceReady)
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(MessageDataSourceReady)))
+		})
+
+		It("Should become ready only once the DataVolume populating the PVC succeeds", func() {
+			dv := newImportDataVolume("test-pvc")
+			dv.Status.Phase = cdiv1.ImportInProgress
+			pvc := createPvc("test-pvc", metav1.NamespaceDefault, nil, nil)
+			pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+			reconciler = createDataSourceReconciler(createDataSource(testDataSourceName, "test-pvc"), pvc, dv)
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionFalse, DataSourceNotPopulated)
+
+			dv.Status.Phase = cdiv1.Succeeded
+			err = reconciler.client.Update(context.TODO(), dv)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionTrue, DataSourceSourceReady)
+		})
+
+		It("Should look for the source PVC in the DataSource namespace if none is set", func() {
+			pvc := createPvc("test-pvc", "other-ns", nil, nil)
+			dataSource := createDataSource(testDataSourceName, "test-pvc")
+			dataSource.Spec.Source.PVC.Namespace = ""
+			reconciler = createDataSourceReconciler(dataSource, pvc)
+			_, err := reconciler.Reconcile(context.TODO(), dsReq)
+			Expect(err).ToNot(HaveOccurred())
+			verifyReady(corev1.ConditionFalse, DataSourceNotFound)
+		})
+	})
+})
+
+func createDataSourceReconciler(objects ...runtime.Object) *DataSourceReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	// Register operator types with the runtime scheme.
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	rec := record.NewFakeRecorder(10)
+	r := &DataSourceReconciler{
+		client:   cl,
+		scheme:   s,
+		log:      dsLog,
+		recorder: rec,
+	}
+	return r
+}
+
+func createDataSource(name, pvcName string) *cdiv1.DataSource {
+	return &cdiv1.DataSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: cdiv1.DataSourceSpec{
+			Source: cdiv1.DataSourceSource{
+				PVC: &cdiv1.DataVolumeSourcePVC{
+					Namespace: metav1.NamespaceDefault,
+					Name:      pvcName,
+				},
+			},
+		},
+	}
+}
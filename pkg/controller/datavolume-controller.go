@@ -19,12 +19,8 @@ package controller
 import (
 	"context"
 	"crypto/rsa"
-	"crypto/tls"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +36,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -52,6 +49,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -59,6 +57,8 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
 )
 
 const (
@@ -98,6 +98,12 @@ const (
 	SnapshotForSmartCloneCreated = "SnapshotForSmartCloneCreated"
 	// SmartClonePVCInProgress provides a const to indicate snapshot creation for smart-clone is in progress
 	SmartClonePVCInProgress = "SmartClonePVCInProgress"
+	// CSICloneInProgress provides a const to indicate csi volume clone is in progress
+	CSICloneInProgress = "CSICloneInProgress"
+	// SnapshotRestoreInProgress provides a const to indicate the restore of a source snapshot is in progress
+	SnapshotRestoreInProgress = "SnapshotRestoreInProgress"
+	// DataSourceNotReady provides a const to indicate the DataSource referenced by sourceRef is not ready
+	DataSourceNotReady = "DataSourceNotReady"
 	// SmartCloneSourceInUse provides a const to indicate a smart clone is being delayed becasuse the source is in use
 	SmartCloneSourceInUse = "SmartCloneSourceInUse"
 	// CloneFailed provides a const to indicate clone has failed
@@ -144,6 +150,12 @@ const (
 	MessageSmartCloneInProgress = "Creating snapshot for smart-clone is in progress (for pvc %s/%s)"
 	// MessageSmartClonePVCInProgress provides a const to form snapshot for smart-clone is in progress message
 	MessageSmartClonePVCInProgress = "Creating PVC for smart-clone is in progress (for pvc %s/%s)"
+	// MessageCsiCloneInProgress provides a const to form csi volume clone is in progress message
+	MessageCsiCloneInProgress = "CSI volume clone is in progress (for pvc %s/%s)"
+	// MessageSnapshotRestoreInProgress provides a const to form the snapshot restore is in progress message
+	MessageSnapshotRestoreInProgress = "Restoring snapshot %s/%s is in progress"
+	// MessageDataSourceNotReady provides a const to form the DataSource not ready message
+	MessageDataSourceNotReady = "Waiting for DataSource %s/%s to be ready"
 	// MessageUploadScheduled provides a const to form upload is scheduled message
 	MessageUploadScheduled = "Upload into %s scheduled"
 	// MessageUploadReady provides a const to form upload is ready message
@@ -160,6 +172,10 @@ const (
 	NamespaceTransferInProgress = "NamespaceTransferInProgress"
 	// MessageNamespaceTransferInProgress is a const for reporting target transfer
 	MessageNamespaceTransferInProgress = "Transferring PersistentVolumeClaim for DataVolume %s/%s"
+	// ClaimPropertySetSelected is const representing the choice of a StorageProfile claim property set
+	ClaimPropertySetSelected = "ClaimPropertySetSelected"
+	// MessageClaimPropertySetSelected is a const for reporting the claim property set the PVC is created with
+	MessageClaimPropertySetSelected = "Using claimPropertySet %d of StorageProfile %s with volumeMode %s and accessModes %v"
 
 	annOwnedByDataVolume = "cdi.kubevirt.io/ownedByDataVolume"
 
@@ -169,7 +185,16 @@ const (
 
 	annReadyForTransfer = "cdi.kubevirt.io/readyForTransfer"
 
+	dataVolumeSourceRefField = "spec.sourceRef"
+
 	annCloneType = "cdi.kubevirt.io/cloneType"
+	// cloneTypeSnapshot is the clone type of a smart clone, done by restoring a snapshot of the source
+	cloneTypeSnapshot = "snapshot"
+	// cloneTypeCsiClone is the clone type of a clone done by the CSI driver
+	cloneTypeCsiClone = "csi-clone"
+
+	// AnnCSICloneRequest annotation associates object with CSI Clone Request
+	AnnCSICloneRequest = "cdi.kubevirt.io/CSICloneRequest"
 )
 
 type cloneStrategy int
@@ -182,8 +207,6 @@ const (
 	CsiClone
 )
 
-var httpClient *http.Client
-
 // DataVolumeEvent reoresents event
 type DataVolumeEvent struct {
 	eventType string
@@ -202,6 +225,7 @@ type DatavolumeReconciler struct {
 	image          string
 	pullPolicy     string
 	tokenValidator token.Validator
+	progressClient *progressClient
 }
 
 func hasAnnOwnedByDataVolume(obj metav1.Object) bool {
@@ -250,6 +274,8 @@ func NewDatavolumeController(
 	log logr.Logger,
 	image, pullPolicy string,
 	apiServerKey *rsa.PublicKey,
+	clientCertGenerator generator.CertGenerator,
+	serverCAFetcher fetcher.CertBundleFetcher,
 ) (controller.Controller, error) {
 	client := mgr.GetClient()
 	reconciler := &DatavolumeReconciler{
@@ -262,6 +288,10 @@ func NewDatavolumeController(
 		image:          image,
 		pullPolicy:     pullPolicy,
 		tokenValidator: newCloneTokenValidator(apiServerKey),
+		progressClient: newProgressClient(clientCertGenerator, serverCAFetcher),
+	}
+	if err := metrics.Registry.Register(newDataVolumeCollector(client)); err != nil {
+		return nil, err
 	}
 	datavolumeController, err := controller.New("datavolume-controller", mgr, controller.Options{
 		Reconciler: reconciler,
@@ -297,6 +327,9 @@ func addDatavolumeControllerWatches(mgr manager.Manager, datavolumeController co
 	}); err != nil {
 		return err
 	}
+	if err := addDataSourceRefWatch(mgr, datavolumeController); err != nil {
+		return err
+	}
 	for _, k := range []client.Object{&corev1.PersistentVolumeClaim{}, &corev1.Pod{}, &cdiv1.ObjectTransfer{}} {
 		if err := datavolumeController.Watch(&source.Kind{Type: k}, handler.EnqueueRequestsFromMapFunc(
 			func(obj client.Object) []reconcile.Request {
@@ -324,6 +357,36 @@ func addDatavolumeControllerWatches(mgr manager.Manager, datavolumeController co
 	return nil
 }
 
+// addDataSourceRefWatch requeues the DataVolumes referencing a DataSource whenever it changes
+func addDataSourceRefWatch(mgr manager.Manager, datavolumeController controller.Controller) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &cdiv1.DataVolume{}, dataVolumeSourceRefField, func(obj client.Object) []string {
+		dv := obj.(*cdiv1.DataVolume)
+		if dv.Spec.SourceRef == nil || dv.Spec.SourceRef.Kind != cdiv1.DataVolumeDataSource {
+			return nil
+		}
+		return []string{types.NamespacedName{Namespace: getSourceRefNamespace(dv), Name: dv.Spec.SourceRef.Name}.String()}
+	}); err != nil {
+		return err
+	}
+
+	return datavolumeController.Watch(&source.Kind{Type: &cdiv1.DataSource{}}, handler.EnqueueRequestsFromMapFunc(
+		func(obj client.Object) []reconcile.Request {
+			var dataVolumes cdiv1.DataVolumeList
+			value := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+			if err := mgr.GetClient().List(context.TODO(), &dataVolumes, &client.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector(dataVolumeSourceRefField, value),
+			}); err != nil {
+				return nil
+			}
+			var result []reconcile.Request
+			for _, dv := range dataVolumes.Items {
+				result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dv.Namespace, Name: dv.Name}})
+			}
+			return result
+		}),
+	)
+}
+
 // Reconcile the reconcile loop for the data volumes.
 func (r *DatavolumeReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
 	log := r.log.WithValues("Datavolume", req.NamespacedName)
@@ -341,13 +404,19 @@ func (r *DatavolumeReconciler) Reconcile(_ context.Context, req reconcile.Reques
 
 	if datavolume.DeletionTimestamp != nil {
 		log.Info("Datavolume marked for deletion, cleaning up")
+		if datavolume.Spec.Source != nil && datavolume.Spec.Source.Snapshot != nil {
+			if err := r.cleanupSnapshotClone(log, datavolume); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
 		if err := r.cleanupTransfer(log, datavolume, transferName); err != nil {
 			return reconcile.Result{}, err
 		}
 		return reconcile.Result{}, nil
 	}
 
-	if err := r.populateSourceIfSourceRef(datavolume); err != nil {
+	dataSourceReady, err := r.populateSourceIfSourceRef(datavolume)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
@@ -384,12 +453,25 @@ func (r *DatavolumeReconciler) Reconcile(_ context.Context, req reconcile.Reques
 		}
 	}
 
-	pvcSpec, err := RenderPvcSpec(r.client, r.recorder, r.log, datavolume)
+	if !dataSourceReady && (!pvcExists || datavolume.Spec.Source == nil) {
+		// The DataSource watch requeues the DataVolume once the source is ready
+		return reconcile.Result{}, r.updateDataSourceNotReadyStatus(datavolume)
+	}
+
+	pvcSpec, claimPropertySetIndex, err := renderPvcSpec(r.client, r.recorder, r.log, datavolume)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	cloneStrategy, err := r.selectCloneStrategy(datavolume, pvcSpec)
+	if err := r.reportClaimPropertySet(datavolume, claimPropertySetIndex); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if datavolume.Spec.Source.Snapshot != nil {
+		return r.reconcileSnapshotClone(log, datavolume, pvc, pvcExists, pvcSpec)
+	}
+
+	cloneStrategy, err := r.selectCloneStrategy(datavolume, pvcSpec, pvcExists)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -400,8 +482,7 @@ func (r *DatavolumeReconciler) Reconcile(_ context.Context, req reconcile.Reques
 			return r.reconcileSmartClonePvc(log, datavolume, pvcSpec, transferName, snapshotClassName)
 		}
 		if cloneStrategy == CsiClone {
-			// Right not a NO-OP so falls back to Host Assisted - implementation in progress
-			r.log.V(3).Info("CSI-Clone is available")
+			return r.reconcileCsiClonePvc(log, datavolume, pvcSpec, transferName)
 		}
 
 		newPvc, err := r.createPvcForDatavolume(log, datavolume, pvcSpec)
@@ -433,14 +514,14 @@ func (r *DatavolumeReconciler) Reconcile(_ context.Context, req reconcile.Reques
 		return r.reconcileSmartCloneForExistingPvc(log, datavolume, pvc, pvcSpec, transferName)
 	}
 	if cloneStrategy == CsiClone {
-		// Right not a NO-OP so falls back to Host Assisted - implementation in progress
+		return r.reconcileCsiCloneForExistingPvc(log, datavolume, pvc, pvcSpec, transferName)
 	}
 	// Finally, we update the status block of the DataVolume resource to reflect the
 	// current state of the world
 	return r.reconcileDataVolumeStatus(datavolume, pvc)
 }
 
-func (r *DatavolumeReconciler) isCSIClonePossible(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec) (bool, error) {
+func (r *DatavolumeReconciler) isCSIClonePossible(dataVolume *cdiv1.DataVolume, targetStorageSpec *corev1.PersistentVolumeClaimSpec, targetPvcExists bool) (bool, error) {
 	log := r.log.WithName("CSIClonePossible").V(3)
 
 	sourcePvcSpec := dataVolume.Spec.Source.PVC
@@ -493,6 +574,19 @@ func (r *DatavolumeReconciler) isCSIClonePossible(dataVolume *cdiv1.DataVolume,
 		return false, nil
 	}
 
+	csiDriverExists, err := r.storageClassCSIDriverExists(targetStorageClass)
+	if err != nil {
+		return false, err
+	}
+	if !csiDriverExists {
+		log.Info("No CSIDriver for the storage class provisioner, falling back to host assisted clone", "provisioner", targetStorageClass.Provisioner)
+		// Warn once when falling back, not on every reconcile of the host assisted clone
+		if !targetPvcExists {
+			r.recorder.Eventf(dataVolume, corev1.EventTypeWarning, ErrUnableToClone, "CSI clone configured, but no CSIDriver available for %s", targetStorageClass.Name)
+		}
+		return false, nil
+	}
+
 	srcStorageClass := &storagev1.StorageClass{}
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *targetPvcStorageClassName}, srcStorageClass); err != nil {
 		log.Info("Unable to retrieve storage class", "storage class", *targetPvcStorageClassName)
@@ -510,7 +604,20 @@ func (r *DatavolumeReconciler) isCSIClonePossible(dataVolume *cdiv1.DataVolume,
 	return true, nil
 }
 
-func (r *DatavolumeReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec) (cloneStrategy, error) {
+// storageClassCSIDriverExists returns true if the provisioner of the storage class is a CSI driver, only CSI drivers
+// can populate a volume from a PVC data source
+func (r *DatavolumeReconciler) storageClassCSIDriverExists(storageClass *storagev1.StorageClass) (bool, error) {
+	csiDriver := &storagev1.CSIDriver{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: storageClass.Provisioner}, csiDriver); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *DatavolumeReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec, pvcExists bool) (cloneStrategy, error) {
 	if datavolume.Spec.Source.PVC == nil {
 		return NoClone, nil
 	}
@@ -528,7 +635,7 @@ func (r *DatavolumeReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume,
 	}
 
 	if preferredCloneStrategy != nil && *preferredCloneStrategy == cdiv1.CloneStrategyCsiClone {
-		csiClonePossible, err := r.isCSIClonePossible(datavolume, pvcSpec)
+		csiClonePossible, err := r.isCSIClonePossible(datavolume, pvcSpec, pvcExists)
 		if err != nil {
 			return NoClone, err
 		}
@@ -560,7 +667,7 @@ func (r *DatavolumeReconciler) selectCloneStrategy(datavolume *cdiv1.DataVolume,
 
 func (r *DatavolumeReconciler) createPvcForDatavolume(log logr.Logger, datavolume *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec) (*corev1.PersistentVolumeClaim, error) {
 	log.Info("Creating PVC for datavolume")
-	newPvc, err := r.newPersistentVolumeClaim(datavolume, pvcSpec)
+	newPvc, err := r.newPersistentVolumeClaim(datavolume, pvcSpec, datavolume.Namespace, datavolume.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -634,6 +741,10 @@ func (r *DatavolumeReconciler) reconcileSmartClonePvc(log logr.Logger, datavolum
 	if err := setAnnOwnedByDataVolume(newSnapshot, datavolume); err != nil {
 		return reconcile.Result{}, err
 	}
+	// the PVC restored from the snapshot records the clone authorization
+	if token, ok := datavolume.Annotations[AnnCloneToken]; ok {
+		r.setCloneAuthorization(newSnapshot.Annotations, token)
+	}
 
 	nn := client.ObjectKeyFromObject(newSnapshot)
 	if err := r.client.Get(context.TODO(), nn, newSnapshot.DeepCopy()); err != nil {
@@ -697,6 +808,185 @@ func (r *DatavolumeReconciler) reconcileSmartCloneForExistingPvc(log logr.Logger
 	return reconcile.Result{}, r.updateSmartCloneStatusPhase(cdiv1.Succeeded, datavolume, pvc)
 }
 
+func (r *DatavolumeReconciler) reconcileCsiClonePvc(log logr.Logger, datavolume *cdiv1.DataVolume, pvcSpec *corev1.PersistentVolumeClaimSpec, transferName string) (reconcile.Result, error) {
+	pvcName := datavolume.Name
+	if isCrossNamespaceClone(datavolume) {
+		pvcName = transferName
+		initialized, err := r.initTransfer(log, datavolume, pvcName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// get reconciled again v soon
+		if !initialized {
+			return reconcile.Result{},
+				r.updateCsiCloneStatusPhase(cdiv1.CloneScheduled, datavolume, nil)
+		}
+	}
+
+	if datavolume.Status.Phase == cdiv1.NamespaceTransferInProgress {
+		return reconcile.Result{}, nil
+	}
+
+	sourcePvc, err := r.findSourcePvc(datavolume)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The volume is cloned in the namespace of the source, a PVC data source can't be in another namespace
+	clonePvc := &corev1.PersistentVolumeClaim{}
+	nn := types.NamespacedName{Namespace: sourcePvc.Namespace, Name: pvcName}
+	if err := r.client.Get(context.TODO(), nn, clonePvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+
+		return r.createCsiClonePvc(log, datavolume, sourcePvc, pvcSpec, pvcName)
+	}
+
+	// Only a cross namespace clone gets here, the PVC is transferred once it is cloned and expanded
+	populated, err := r.markCsiClonePopulated(clonePvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !populated {
+		return reconcile.Result{},
+			r.updateCsiCloneStatusPhase(cdiv1.CSICloneInProgress, datavolume, nil)
+	}
+
+	done, err := r.expand(log, datavolume, clonePvc, pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !done {
+		return reconcile.Result{},
+			r.updateCsiCloneStatusPhase(cdiv1.ExpansionInProgress, datavolume, nil)
+	}
+
+	// trigger transfer and next reconcile should have pvcExists == true
+	clonePvc.Annotations[annReadyForTransfer] = "true"
+	clonePvc.Annotations[AnnPopulatedFor] = datavolume.Name
+	if err := r.client.Update(context.TODO(), clonePvc); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{},
+		r.updateCsiCloneStatusPhase(cdiv1.NamespaceTransferInProgress, datavolume, nil)
+}
+
+func (r *DatavolumeReconciler) createCsiClonePvc(log logr.Logger, datavolume *cdiv1.DataVolume, sourcePvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec, pvcName string) (reconcile.Result, error) {
+	inUse, err := r.sourceInUse(datavolume)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	populated, err := r.isSourcePVCPopulated(datavolume)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if inUse || !populated {
+		return reconcile.Result{Requeue: true},
+			r.updateCsiCloneStatusPhase(cdiv1.CloneScheduled, datavolume, nil)
+	}
+
+	newPvc, err := r.newCsiClonePvc(datavolume, sourcePvc, pvcSpec, pvcName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Creating PVC for CSI clone", "namespace", newPvc.Namespace, "name", newPvc.Name)
+	if err := r.client.Create(context.TODO(), newPvc); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{},
+		r.updateCsiCloneStatusPhase(cdiv1.CSICloneInProgress, datavolume, newPvc)
+}
+
+func (r *DatavolumeReconciler) reconcileCsiCloneForExistingPvc(log logr.Logger, datavolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec, transferName string) (reconcile.Result, error) {
+	if isCrossNamespaceClone(datavolume) && datavolume.Status.Phase == cdiv1.Succeeded {
+		if err := r.cleanupTransfer(log, datavolume, transferName); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// done, done
+		return reconcile.Result{}, nil
+	}
+
+	populated, err := r.markCsiClonePopulated(pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !populated {
+		return reconcile.Result{},
+			r.updateCsiCloneStatusPhase(cdiv1.CSICloneInProgress, datavolume, pvc)
+	}
+
+	// expand for non-namespace case
+	done, err := r.expand(log, datavolume, pvc, pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !done {
+		return reconcile.Result{},
+			r.updateCsiCloneStatusPhase(cdiv1.ExpansionInProgress, datavolume, pvc)
+	}
+
+	// done
+	return reconcile.Result{}, r.updateCsiCloneStatusPhase(cdiv1.Succeeded, datavolume, pvc)
+}
+
+// markCsiClonePopulated marks the clone complete once the provisioner has bound the PVC, the volume is populated
+// from the data source when it is provisioned
+func (r *DatavolumeReconciler) markCsiClonePopulated(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Annotations[AnnCloneOf] == "true" {
+		return true, nil
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return false, nil
+	}
+
+	pvc.Annotations[AnnCloneOf] = "true"
+	if err := r.client.Update(context.TODO(), pvc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newCsiClonePvc creates the PVC populated by the CSI driver from the source PVC. The PVC requests the size of the
+// source, a larger target is expanded once the clone is bound.
+func (r *DatavolumeReconciler) newCsiClonePvc(dataVolume *cdiv1.DataVolume, sourcePvc *corev1.PersistentVolumeClaim, targetPvcSpec *corev1.PersistentVolumeClaimSpec, pvcName string) (*corev1.PersistentVolumeClaim, error) {
+	sourceSize, ok := sourcePvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		return nil, errors.Errorf("source PVC %s/%s has no capacity", sourcePvc.Namespace, sourcePvc.Name)
+	}
+
+	pvcSpec := targetPvcSpec.DeepCopy()
+	pvcSpec.DataSource = &corev1.TypedLocalObjectReference{
+		Kind: "PersistentVolumeClaim",
+		Name: sourcePvc.Name,
+	}
+	if pvcSpec.Resources.Requests == nil {
+		pvcSpec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvcSpec.Resources.Requests[corev1.ResourceStorage] = sourceSize
+
+	pvc, err := r.newPersistentVolumeClaim(dataVolume, pvcSpec, sourcePvc.Namespace, pvcName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The clone controller must not start a host assisted clone of this PVC
+	delete(pvc.Annotations, AnnCloneRequest)
+	delete(pvc.Annotations, AnnCloneToken)
+	pvc.Annotations[AnnCSICloneRequest] = "true"
+
+	return pvc, nil
+}
+
 func (r *DatavolumeReconciler) getVddkAnnotations(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) (bool, error) {
 	var dataVolumeCopy = dataVolume.DeepCopy()
 	if vddkHost := pvc.Annotations[AnnVddkHostConnection]; vddkHost != "" {
@@ -972,6 +1262,23 @@ func (r *DatavolumeReconciler) cleanupTransfer(log logr.Logger, dv *cdiv1.DataVo
 
 	log.Info("Doing cleanup")
 
+	// an unfinished transfer rolls back when deleted, the PVCs are deleted once the PV is given back to them
+	ot := &cdiv1.ObjectTransfer{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, ot); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		if ot.DeletionTimestamp == nil {
+			if err := r.client.Delete(context.TODO(), ot); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return err
+				}
+			}
+		}
+		return fmt.Errorf("waiting for ObjectTransfer %s to delete", name)
+	}
+
 	if dv.DeletionTimestamp != nil && dv.Status.Phase != cdiv1.Succeeded {
 		// delete all potential PVCs that may not have owner refs
 		namespaces := []string{dv.Namespace}
@@ -1014,22 +1321,6 @@ func (r *DatavolumeReconciler) cleanupTransfer(log logr.Logger, dv *cdiv1.DataVo
 		}
 	}
 
-	ot := &cdiv1.ObjectTransfer{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, ot); err != nil {
-		if !k8serrors.IsNotFound(err) {
-			return err
-		}
-	} else {
-		if ot.DeletionTimestamp == nil {
-			if err := r.client.Delete(context.TODO(), ot); err != nil {
-				if !k8serrors.IsNotFound(err) {
-					return err
-				}
-			}
-		}
-		return fmt.Errorf("waiting for ObjectTransfer %s to delete", name)
-	}
-
 	RemoveFinalizer(dv, crossNamespaceFinalizer)
 	if err := r.updateDataVolume(dv); dv != nil {
 		return err
@@ -1222,7 +1513,7 @@ func getStorageVolumeMode(c client.Client, dataVolume *cdiv1.DataVolume, storage
 		if dataVolume.Spec.Storage.VolumeMode != nil {
 			return dataVolume.Spec.Storage.VolumeMode, nil
 		}
-		volumeMode, err := getDefaultVolumeMode(c, storageClass)
+		volumeMode, err := getDefaultVolumeMode(c, storageClass, dataVolume)
 		if err != nil {
 			return nil, err
 		}
@@ -1240,6 +1531,8 @@ func (r *DatavolumeReconciler) reconcileProgressUpdate(datavolume *cdiv1.DataVol
 
 	if datavolume.Spec.Source.PVC != nil {
 		podNamespace = datavolume.Spec.Source.PVC.Namespace
+	} else if datavolume.Spec.Source.Snapshot != nil {
+		podNamespace = getSnapshotNamespace(datavolume)
 	} else {
 		podNamespace = datavolume.Namespace
 	}
@@ -1251,7 +1544,7 @@ func (r *DatavolumeReconciler) reconcileProgressUpdate(datavolume *cdiv1.DataVol
 	}
 	pod, err := r.getPodFromPvc(podNamespace, pvcUID)
 	if err == nil {
-		if err := updateProgressUsingPod(datavolume, pod); err != nil {
+		if err := r.updateProgressUsingPod(datavolume, pod); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
@@ -1399,7 +1692,7 @@ func (r *DatavolumeReconciler) getCloneStrategy(dataVolume *cdiv1.DataVolume) (*
 	}
 
 	// do check storageProfile and apply the preferences
-	strategy, err := r.getPreferredCloneStrategyForStorageClass(storageClass)
+	strategy, err := r.getPreferredCloneStrategyForStorageClass(storageClass, sourcePvc)
 	if err != nil {
 		return nil, err
 	}
@@ -1530,22 +1823,42 @@ func (r *DatavolumeReconciler) updateImportStatusPhase(pvc *corev1.PersistentVol
 }
 
 func (r *DatavolumeReconciler) updateSmartCloneStatusPhase(phase cdiv1.DataVolumePhase, dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	return r.updateStorageCloneStatusPhase(phase, dataVolume, pvc, cloneTypeSnapshot)
+}
+
+func (r *DatavolumeReconciler) updateCsiCloneStatusPhase(phase cdiv1.DataVolumePhase, dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	return r.updateStorageCloneStatusPhase(phase, dataVolume, pvc, cloneTypeCsiClone)
+}
+
+// updateStorageCloneStatusPhase updates the phase of a clone done by the storage, cloneType is recorded on the DataVolume
+func (r *DatavolumeReconciler) updateStorageCloneStatusPhase(phase cdiv1.DataVolumePhase, dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, cloneType string) error {
 	var dataVolumeCopy = dataVolume.DeepCopy()
 	var event DataVolumeEvent
 
 	curPhase := dataVolumeCopy.Status.Phase
+	sourceNamespace, sourceName := getCloneSource(dataVolume)
 
 	switch phase {
 	case cdiv1.CloneScheduled:
 		dataVolumeCopy.Status.Phase = cdiv1.CloneScheduled
 		event.eventType = corev1.EventTypeNormal
 		event.reason = CloneScheduled
-		event.message = fmt.Sprintf(MessageCloneScheduled, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, dataVolume.Namespace, dataVolume.Name)
+		event.message = fmt.Sprintf(MessageCloneScheduled, sourceNamespace, sourceName, dataVolume.Namespace, dataVolume.Name)
 	case cdiv1.SnapshotForSmartCloneInProgress:
 		dataVolumeCopy.Status.Phase = cdiv1.SnapshotForSmartCloneInProgress
 		event.eventType = corev1.EventTypeNormal
 		event.reason = SnapshotForSmartCloneInProgress
-		event.message = fmt.Sprintf(MessageSmartCloneInProgress, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name)
+		event.message = fmt.Sprintf(MessageSmartCloneInProgress, sourceNamespace, sourceName)
+	case cdiv1.CSICloneInProgress:
+		dataVolumeCopy.Status.Phase = cdiv1.CSICloneInProgress
+		event.eventType = corev1.EventTypeNormal
+		event.reason = CSICloneInProgress
+		event.message = fmt.Sprintf(MessageCsiCloneInProgress, sourceNamespace, sourceName)
+	case cdiv1.SmartClonePVCInProgress:
+		dataVolumeCopy.Status.Phase = cdiv1.SmartClonePVCInProgress
+		event.eventType = corev1.EventTypeNormal
+		event.reason = SnapshotRestoreInProgress
+		event.message = fmt.Sprintf(MessageSnapshotRestoreInProgress, sourceNamespace, sourceName)
 	case cdiv1.ExpansionInProgress:
 		dataVolumeCopy.Status.Phase = cdiv1.ExpansionInProgress
 		event.eventType = corev1.EventTypeNormal
@@ -1560,12 +1873,12 @@ func (r *DatavolumeReconciler) updateSmartCloneStatusPhase(phase cdiv1.DataVolum
 		dataVolumeCopy.Status.Phase = cdiv1.Succeeded
 		event.eventType = corev1.EventTypeNormal
 		event.reason = CloneSucceeded
-		event.message = fmt.Sprintf(MessageCloneSucceeded, dataVolume.Spec.Source.PVC.Namespace, dataVolume.Spec.Source.PVC.Name, dataVolume.Namespace, dataVolume.Name)
+		event.message = fmt.Sprintf(MessageCloneSucceeded, sourceNamespace, sourceName, dataVolume.Namespace, dataVolume.Name)
 	}
 
 	r.updateConditions(dataVolumeCopy, pvc)
 
-	addAnnotation(dataVolumeCopy, annCloneType, "snapshot")
+	addAnnotation(dataVolumeCopy, annCloneType, cloneType)
 
 	return r.emitEvent(dataVolume, dataVolumeCopy, curPhase, dataVolume.Status.Conditions, &event)
 }
@@ -1575,30 +1888,31 @@ func (r *DatavolumeReconciler) updateCloneStatusPhase(pvc *corev1.PersistentVolu
 	if !ok {
 		return
 	}
+	sourceNamespace, sourceName := getCloneSource(dataVolumeCopy)
 	switch phase {
 	case string(corev1.PodPending):
 		// TODO: Use a more generic Scheduled, like maybe TransferScheduled.
 		dataVolumeCopy.Status.Phase = cdiv1.CloneScheduled
 		event.eventType = corev1.EventTypeNormal
 		event.reason = CloneScheduled
-		event.message = fmt.Sprintf(MessageCloneScheduled, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+		event.message = fmt.Sprintf(MessageCloneScheduled, sourceNamespace, sourceName, pvc.Namespace, pvc.Name)
 	case string(corev1.PodRunning):
 		// TODO: Use a more generic In Progess, like maybe TransferInProgress.
 		dataVolumeCopy.Status.Phase = cdiv1.CloneInProgress
 		event.eventType = corev1.EventTypeNormal
 		event.reason = CloneInProgress
-		event.message = fmt.Sprintf(MessageCloneInProgress, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+		event.message = fmt.Sprintf(MessageCloneInProgress, sourceNamespace, sourceName, pvc.Namespace, pvc.Name)
 	case string(corev1.PodFailed):
 		dataVolumeCopy.Status.Phase = cdiv1.Failed
 		event.eventType = corev1.EventTypeWarning
 		event.reason = CloneFailed
-		event.message = fmt.Sprintf(MessageCloneFailed, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+		event.message = fmt.Sprintf(MessageCloneFailed, sourceNamespace, sourceName, pvc.Namespace, pvc.Name)
 	case string(corev1.PodSucceeded):
 		dataVolumeCopy.Status.Phase = cdiv1.Succeeded
 		dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress("100.0%")
 		event.eventType = corev1.EventTypeNormal
 		event.reason = CloneSucceeded
-		event.message = fmt.Sprintf(MessageCloneSucceeded, dataVolumeCopy.Spec.Source.PVC.Namespace, dataVolumeCopy.Spec.Source.PVC.Name, pvc.Namespace, pvc.Name)
+		event.message = fmt.Sprintf(MessageCloneSucceeded, sourceNamespace, sourceName, pvc.Namespace, pvc.Name)
 	}
 }
 
@@ -1768,7 +2082,7 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 		}
 	}
 
-	if dataVolumeCopy.Spec.Source.PVC != nil {
+	if dataVolumeCopy.Spec.Source.PVC != nil || dataVolumeCopy.Spec.Source.Snapshot != nil {
 		// XXX should probably be is status
 		addAnnotation(dataVolumeCopy, annCloneType, "network")
 	}
@@ -1779,6 +2093,40 @@ func (r *DatavolumeReconciler) reconcileDataVolumeStatus(dataVolume *cdiv1.DataV
 	return result, r.emitEvent(dataVolume, dataVolumeCopy, curPhase, currentCond, &event)
 }
 
+// updateDataSourceNotReadyStatus keeps the DataVolume Pending while the DataSource referenced by sourceRef is not ready,
+// the event is only emitted when the DataVolume starts waiting for the DataSource
+func (r *DatavolumeReconciler) updateDataSourceNotReadyStatus(dataVolume *cdiv1.DataVolume) error {
+	dataVolumeCopy := dataVolume.DeepCopy()
+	curPhase := dataVolumeCopy.Status.Phase
+	message := fmt.Sprintf(MessageDataSourceNotReady, getSourceRefNamespace(dataVolume), dataVolume.Spec.SourceRef.Name)
+
+	ready := findConditionByType(cdiv1.DataVolumeReady, dataVolumeCopy.Status.Conditions)
+	waiting := ready != nil && ready.Reason == DataSourceNotReady && ready.Message == message
+
+	dataVolumeCopy.Status.Phase = cdiv1.Pending
+	dataVolumeCopy.Status.Conditions = updateReadyCondition(dataVolumeCopy.Status.Conditions, corev1.ConditionFalse, message, DataSourceNotReady)
+	if reflect.DeepEqual(dataVolume, dataVolumeCopy) {
+		return nil
+	}
+
+	now := time.Now()
+	phaseChanged := curPhase != dataVolumeCopy.Status.Phase
+	if phaseChanged {
+		setPhaseTransitionTime(dataVolumeCopy, now)
+	}
+	if err := r.updateDataVolume(dataVolumeCopy); err != nil {
+		r.log.Error(err, "Unable to update datavolume", "name", dataVolumeCopy.Name)
+		return err
+	}
+	if phaseChanged {
+		observePhaseDuration(dataVolumeCopy, curPhase, getPhaseTransitionTime(dataVolume), now)
+	}
+	if !waiting {
+		r.recorder.Event(dataVolumeCopy, corev1.EventTypeWarning, DataSourceNotReady, message)
+	}
+	return nil
+}
+
 func (r *DatavolumeReconciler) updateConditions(dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) {
 	var anno map[string]string
 
@@ -1832,20 +2180,32 @@ func (r *DatavolumeReconciler) emitFailureConditionEvent(dataVolume *cdiv1.DataV
 		//Bound, not ready, and not running
 		if curRunning.Message != "" && orgRunning.Message != curRunning.Message {
 			r.recorder.Event(dataVolume, corev1.EventTypeWarning, curRunning.Reason, curRunning.Message)
+			countDataVolumeFailure(dataVolume, curRunning.Reason)
 		}
 	}
 }
 
 func (r *DatavolumeReconciler) emitEvent(dataVolume *cdiv1.DataVolume, dataVolumeCopy *cdiv1.DataVolume, curPhase cdiv1.DataVolumePhase, originalCond []cdiv1.DataVolumeCondition, event *DataVolumeEvent) error {
+	phaseChanged := curPhase != dataVolumeCopy.Status.Phase
+	now := time.Now()
+	if phaseChanged {
+		setPhaseTransitionTime(dataVolumeCopy, now)
+	}
 	// Only update the object if something actually changed in the status.
 	if !reflect.DeepEqual(dataVolume, dataVolumeCopy) {
 		if err := r.updateDataVolume(dataVolumeCopy); err != nil {
 			r.log.Error(err, "Unable to update datavolume", "name", dataVolumeCopy.Name)
 			return err
 		}
+		if phaseChanged {
+			observePhaseDuration(dataVolumeCopy, curPhase, getPhaseTransitionTime(dataVolume), now)
+		}
 		// Emit the event only when the status change happens, not every time
-		if event.eventType != "" && curPhase != dataVolumeCopy.Status.Phase {
+		if event.eventType != "" && phaseChanged {
 			r.recorder.Event(dataVolumeCopy, event.eventType, event.reason, event.message)
+			if event.eventType == corev1.EventTypeWarning {
+				countDataVolumeFailure(dataVolumeCopy, event.reason)
+			}
 		}
 		r.emitConditionEvent(dataVolumeCopy, originalCond)
 	}
@@ -1887,38 +2247,39 @@ func (r *DatavolumeReconciler) addOwnerRef(pvc *corev1.PersistentVolumeClaim, dv
 	return r.client.Update(context.TODO(), pvc)
 }
 
-func updateProgressUsingPod(dataVolumeCopy *cdiv1.DataVolume, pod *corev1.Pod) error {
-	httpClient := buildHTTPClient()
-	// Example value: import_progress{ownerUID="b856691e-1038-11e9-a5ab-525500d15501"} 13.45
-	var importRegExp = regexp.MustCompile("progress\\{ownerUID\\=\"" + string(dataVolumeCopy.UID) + "\"\\} (\\d{1,3}\\.?\\d*)")
-
+// updateProgressUsingPod reads the progress report of the pod populating the DataVolume
+func (r *DatavolumeReconciler) updateProgressUsingPod(dataVolumeCopy *cdiv1.DataVolume, pod *corev1.Pod) error {
 	port, err := getPodMetricsPort(pod)
-	if err == nil && pod.Status.PodIP != "" {
-		url := fmt.Sprintf("https://%s:%d/metrics", pod.Status.PodIP, port)
-		resp, err := httpClient.Get(url)
-		if err != nil {
-			if common.ErrConnectionRefused(err) {
-				return nil
-			}
-			return err
-		}
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
+	if pod.Status.PodIP == "" || r.progressClient == nil {
+		return nil
+	}
 
-		match := importRegExp.FindStringSubmatch(string(body))
-		if match == nil {
-			// No match
+	report, err := r.progressClient.getProgressReport(pod, port)
+	if err != nil {
+		if common.ErrConnectionRefused(err) {
 			return nil
 		}
-		if f, err := strconv.ParseFloat(match[1], 64); err == nil {
-			dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress(fmt.Sprintf("%.2f%%", f))
-		}
+		return err
+	}
+	if report == nil || (report.OwnerUID != "" && report.OwnerUID != string(dataVolumeCopy.UID)) {
 		return nil
 	}
-	return err
+
+	// the transfer phase is reported before the transferred bytes are known
+	if report.OwnerUID != "" {
+		dataVolumeCopy.Status.Progress = cdiv1.DataVolumeProgress(fmt.Sprintf("%.2f%%", report.Progress))
+	}
+	dataVolumeCopy.Status.Transfer = &cdiv1.DataVolumeTransferStatus{
+		Phase:                     report.Phase,
+		BytesTransferred:          int64(report.BytesTransferred),
+		TotalBytes:                int64(report.TotalBytes),
+		Throughput:                int64(report.Throughput),
+		EstimatedSecondsRemaining: report.EstimatedSecondsRemaining,
+	}
+	return nil
 }
 
 func getPodMetricsPort(pod *corev1.Pod) (int, error) {
@@ -1932,34 +2293,11 @@ func getPodMetricsPort(pod *corev1.Pod) (int, error) {
 	return 0, errors.New("Metrics port not found in pod")
 }
 
-// buildHTTPClient generates an http client that accepts any certificate, since we are using
-// it to get prometheus data it doesn't matter if someone can intercept the data. Once we have
-// a mechanism to properly sign the server, we can update this method to get a proper client.
-func buildHTTPClient() *http.Client {
-	if httpClient == nil {
-		defaultTransport := http.DefaultTransport.(*http.Transport)
-		// Create new Transport that ignores self-signed SSL
-		tr := &http.Transport{
-			Proxy:                 defaultTransport.Proxy,
-			DialContext:           defaultTransport.DialContext,
-			MaxIdleConns:          defaultTransport.MaxIdleConns,
-			IdleConnTimeout:       defaultTransport.IdleConnTimeout,
-			ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
-			TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
-			TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-		}
-		httpClient = &http.Client{
-			Transport: tr,
-		}
-	}
-	return httpClient
-}
-
 // newPersistentVolumeClaim creates a new PVC the DataVolume resource.
 // It also sets the appropriate OwnerReferences on the resource
 // which allows handleObject to discover the DataVolume resource
 // that 'owns' it.
-func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVolume, targetPvcSpec *corev1.PersistentVolumeClaimSpec) (*corev1.PersistentVolumeClaim, error) {
+func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVolume, targetPvcSpec *corev1.PersistentVolumeClaimSpec, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
 	labels := map[string]string{
 		"app": "containerized-data-importer",
 	}
@@ -1968,6 +2306,7 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 	for k, v := range dataVolume.ObjectMeta.Annotations {
 		annotations[k] = v
 	}
+	delete(annotations, annPhaseTransitionTime)
 
 	annotations[AnnPodRestarts] = "0"
 	if dataVolume.Spec.Source.HTTP != nil {
@@ -1984,6 +2323,9 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		if dataVolume.Spec.Source.HTTP.CertConfigMap != "" {
 			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.HTTP.CertConfigMap
 		}
+		if dataVolume.Spec.Source.HTTP.Checksum != "" {
+			annotations[AnnChecksum] = dataVolume.Spec.Source.HTTP.Checksum
+		}
 	} else if dataVolume.Spec.Source.S3 != nil {
 		annotations[AnnEndpoint] = dataVolume.Spec.Source.S3.URL
 		annotations[AnnSource] = SourceS3
@@ -1993,6 +2335,9 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		if dataVolume.Spec.Source.S3.CertConfigMap != "" {
 			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.S3.CertConfigMap
 		}
+		if dataVolume.Spec.Source.S3.Checksum != "" {
+			annotations[AnnChecksum] = dataVolume.Spec.Source.S3.Checksum
+		}
 	} else if dataVolume.Spec.Source.Registry != nil {
 		annotations[AnnSource] = SourceRegistry
 		annotations[AnnEndpoint] = dataVolume.Spec.Source.Registry.URL
@@ -2003,6 +2348,20 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		if dataVolume.Spec.Source.Registry.CertConfigMap != "" {
 			annotations[AnnCertConfigMap] = dataVolume.Spec.Source.Registry.CertConfigMap
 		}
+		if dataVolume.Spec.Source.Registry.DiskPath != "" {
+			annotations[AnnRegistryDiskPath] = dataVolume.Spec.Source.Registry.DiskPath
+		}
+		if dataVolume.Spec.Source.Registry.Digest != "" {
+			annotations[AnnRequiredDigest] = dataVolume.Spec.Source.Registry.Digest
+		}
+		if verification := dataVolume.Spec.Source.Registry.SignatureVerification; verification != nil {
+			if verification.PublicKeyConfigMap != "" {
+				annotations[AnnSignaturePublicKeyConfigMap] = verification.PublicKeyConfigMap
+			}
+			if verification.PolicyConfigMap != "" {
+				annotations[AnnSignaturePolicyConfigMap] = verification.PolicyConfigMap
+			}
+		}
 	} else if dataVolume.Spec.Source.PVC != nil {
 		sourceNamespace := dataVolume.Spec.Source.PVC.Namespace
 		if sourceNamespace == "" {
@@ -2014,8 +2373,21 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 		}
 		annotations[AnnCloneToken] = token
 		annotations[AnnCloneRequest] = sourceNamespace + "/" + dataVolume.Spec.Source.PVC.Name
+		r.setCloneAuthorization(annotations, token)
+	} else if dataVolume.Spec.Source.Snapshot != nil {
+		// the target is cloned from the PVC the snapshot is restored to, unless it is restored directly
+		token, ok := dataVolume.Annotations[AnnCloneToken]
+		if !ok {
+			return nil, errors.Errorf("no clone token")
+		}
+		annotations[AnnCloneToken] = token
+		annotations[AnnCloneRequest] = getSnapshotNamespace(dataVolume) + "/" + snapshotRestorePvcName(dataVolume)
+		r.setCloneAuthorization(annotations, token)
 	} else if dataVolume.Spec.Source.Upload != nil {
 		annotations[AnnUploadRequest] = ""
+		if dataVolume.Spec.Source.Upload.Checksum != "" {
+			annotations[AnnChecksum] = dataVolume.Spec.Source.Upload.Checksum
+		}
 	} else if dataVolume.Spec.Source.Blank != nil {
 		annotations[AnnSource] = SourceNone
 		annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
@@ -2042,8 +2414,8 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace:   dataVolume.Namespace,
-			Name:        dataVolume.Name,
+			Namespace:   namespace,
+			Name:        name,
 			Labels:      labels,
 			Annotations: annotations,
 		},
@@ -2068,26 +2440,39 @@ func (r *DatavolumeReconciler) newPersistentVolumeClaim(dataVolume *cdiv1.DataVo
 	return pvc, nil
 }
 
-// If sourceRef is set, populate spec.Source with data from the DataSource
-func (r *DatavolumeReconciler) populateSourceIfSourceRef(dv *cdiv1.DataVolume) error {
+// populateSourceIfSourceRef sets the DataVolume source from its sourceRef, and returns false if the referenced DataSource is not ready
+func (r *DatavolumeReconciler) populateSourceIfSourceRef(dv *cdiv1.DataVolume) (bool, error) {
 	if dv.Spec.SourceRef == nil {
-		return nil
+		return true, nil
 	}
 	if dv.Spec.SourceRef.Kind != cdiv1.DataVolumeDataSource {
-		return errors.Errorf("Unsupported sourceRef kind %s, currently only %s is supported", dv.Spec.SourceRef.Kind, cdiv1.DataVolumeDataSource)
-	}
-	ns := dv.Namespace
-	if dv.Spec.SourceRef.Namespace != nil && *dv.Spec.SourceRef.Namespace != "" {
-		ns = *dv.Spec.SourceRef.Namespace
+		return false, errors.Errorf("Unsupported sourceRef kind %s, currently only %s is supported", dv.Spec.SourceRef.Kind, cdiv1.DataVolumeDataSource)
 	}
 	dataSource := &cdiv1.DataSource{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dv.Spec.SourceRef.Name, Namespace: ns}, dataSource); err != nil {
-		return err
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dv.Spec.SourceRef.Name, Namespace: getSourceRefNamespace(dv)}, dataSource); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
 	}
 	dv.Spec.Source = &cdiv1.DataVolumeSource{
 		PVC: dataSource.Spec.Source.PVC,
 	}
-	return nil
+	return isDataSourceReady(dataSource), nil
+}
+
+// setCloneAuthorization records the clone token identity, a clone is not failed because it can't be recorded
+func (r *DatavolumeReconciler) setCloneAuthorization(annotations map[string]string, token string) {
+	if err := setCloneAuthorization(r.tokenValidator, annotations, token); err != nil {
+		r.log.V(1).Info("Unable to record clone authorization", "error", err)
+	}
+}
+
+func getSourceRefNamespace(dv *cdiv1.DataVolume) string {
+	if dv.Spec.SourceRef.Namespace != nil && *dv.Spec.SourceRef.Namespace != "" {
+		return *dv.Spec.SourceRef.Namespace
+	}
+	return dv.Namespace
 }
 
 // Whenever the controller updates a DV, we must make sure to nil out spec.source when spec.sourceRef is set
@@ -2100,24 +2485,31 @@ func (r *DatavolumeReconciler) updateDataVolume(dv *cdiv1.DataVolume) error {
 
 // RenderPvcSpec creates a new PVC Spec based on either the dv.spec.pvc or dv.spec.storage section
 func RenderPvcSpec(client client.Client, recorder record.EventRecorder, log logr.Logger, dv *cdiv1.DataVolume) (*corev1.PersistentVolumeClaimSpec, error) {
+	pvcSpec, _, err := renderPvcSpec(client, recorder, log, dv)
+	return pvcSpec, err
+}
+
+// renderPvcSpec is RenderPvcSpec that also returns the index of the StorageProfile claim property set the spec is
+// completed with, -1 if no set is used
+func renderPvcSpec(client client.Client, recorder record.EventRecorder, log logr.Logger, dv *cdiv1.DataVolume) (*corev1.PersistentVolumeClaimSpec, int, error) {
 	if dv.Spec.PVC != nil {
-		return dv.Spec.PVC, nil
+		return dv.Spec.PVC, -1, nil
 	}
 
 	if dv.Spec.Storage != nil {
 		return pvcFromStorage(client, recorder, log, dv)
 	}
 
-	return nil, errors.Errorf("datavolume one of {pvc, storage} field is required")
+	return nil, -1, errors.Errorf("datavolume one of {pvc, storage} field is required")
 }
 
-func pvcFromStorage(client client.Client, recorder record.EventRecorder, log logr.Logger, dv *cdiv1.DataVolume) (*corev1.PersistentVolumeClaimSpec, error) {
+func pvcFromStorage(client client.Client, recorder record.EventRecorder, log logr.Logger, dv *cdiv1.DataVolume) (*corev1.PersistentVolumeClaimSpec, int, error) {
 	storage := dv.Spec.Storage
 	pvcSpec := copyStorageAsPvc(log, storage)
 
 	storageClass, err := GetStorageClassByName(client, storage.StorageClassName)
 	if err != nil {
-		return nil, err
+		return nil, -1, err
 	}
 
 	if storageClass == nil {
@@ -2125,41 +2517,50 @@ func pvcFromStorage(client client.Client, recorder record.EventRecorder, log log
 		if len(pvcSpec.AccessModes) == 0 {
 			log.V(1).Info("Cannot set accessMode for new pvc", "namespace", dv.Namespace, "name", dv.Name)
 			recorder.Eventf(dv, corev1.EventTypeWarning, ErrClaimNotValid, "DataVolume.storage spec is missing accessMode and no storageClass to choose profile")
-			return nil, errors.Errorf("DataVolume spec is missing accessMode")
+			return nil, -1, errors.Errorf("DataVolume spec is missing accessMode")
 		}
 
-		return pvcSpec, nil
+		return pvcSpec, -1, nil
 	}
 
-	// given storageClass we can apply defaults if needed
-	if len(pvcSpec.AccessModes) == 0 {
-		accessModes, err := getDefaultAccessModes(client, storageClass)
-		if err != nil {
-			log.V(1).Info("Cannot set accessMode for new pvc", "namespace", dv.Namespace, "name", dv.Name)
-			recorder.Eventf(dv, corev1.EventTypeWarning, ErrClaimNotValid,
-				fmt.Sprintf("DataVolume.storage spec is missing accessMode and cannot get access mode from StorageProfile %s", getName(storageClass)))
-			return nil, err
+	// given storageClass we can apply defaults if needed, from the preferred claim property set compatible with the DV
+	index := -1
+	if len(pvcSpec.AccessModes) == 0 || pvcSpec.VolumeMode == nil || *pvcSpec.VolumeMode == "" {
+		var claimPropertySet *cdiv1.ClaimPropertySet
+		claimPropertySet, index, err = getClaimPropertySet(client, storageClass, pvcSpec, dv)
+		if err == nil && claimPropertySet == nil && len(pvcSpec.AccessModes) == 0 {
+			// no accessMode configured on storageProfile
+			err = errors.Errorf("no accessMode defined on StorageProfile for %s StorageClass", storageClass.Name)
 		}
-		pvcSpec.AccessModes = append(pvcSpec.AccessModes, accessModes...)
-	}
-	if pvcSpec.VolumeMode == nil || *pvcSpec.VolumeMode == "" {
-		volumeMode, err := getDefaultVolumeMode(client, storageClass)
 		if err != nil {
-			return nil, err
+			if len(pvcSpec.AccessModes) == 0 {
+				log.V(1).Info("Cannot set accessMode for new pvc", "namespace", dv.Namespace, "name", dv.Name)
+				recorder.Eventf(dv, corev1.EventTypeWarning, ErrClaimNotValid,
+					fmt.Sprintf("DataVolume.storage spec is missing accessMode and cannot get access mode from StorageProfile %s", getName(storageClass)))
+			}
+			return nil, -1, err
+		}
+
+		if claimPropertySet != nil {
+			if len(pvcSpec.AccessModes) == 0 {
+				pvcSpec.AccessModes = append(pvcSpec.AccessModes, claimPropertySet.AccessModes...)
+			}
+			if pvcSpec.VolumeMode == nil || *pvcSpec.VolumeMode == "" {
+				pvcSpec.VolumeMode = claimPropertySet.VolumeMode
+			}
 		}
-		pvcSpec.VolumeMode = volumeMode
 	}
 
 	requestedVolumeSize, err := volumeSize(client, storage, pvcSpec.VolumeMode)
 	if err != nil {
-		return nil, err
+		return nil, -1, err
 	}
 	if pvcSpec.Resources.Requests == nil {
 		pvcSpec.Resources.Requests = corev1.ResourceList{}
 	}
 	pvcSpec.Resources.Requests[corev1.ResourceStorage] = *requestedVolumeSize
 
-	return pvcSpec, nil
+	return pvcSpec, index, nil
 }
 
 func copyStorageAsPvc(log logr.Logger, storage *cdiv1.StorageSpec) *corev1.PersistentVolumeClaimSpec {
@@ -2207,7 +2608,7 @@ func getName(storageClass *storagev1.StorageClass) string {
 	return ""
 }
 
-func (r *DatavolumeReconciler) getPreferredCloneStrategyForStorageClass(storageClass *storagev1.StorageClass) (*cdiv1.CDICloneStrategy, error) {
+func (r *DatavolumeReconciler) getPreferredCloneStrategyForStorageClass(storageClass *storagev1.StorageClass, sourcePvc *corev1.PersistentVolumeClaim) (*cdiv1.CDICloneStrategy, error) {
 	if storageClass == nil {
 		// fallback to defaults
 		return nil, nil
@@ -2218,53 +2619,156 @@ func (r *DatavolumeReconciler) getPreferredCloneStrategyForStorageClass(storageC
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get StorageProfile")
 	}
-	if len(storageProfile.Status.ClaimPropertySets) > 0 {
-		cloneStrategy := storageProfile.Status.ClaimPropertySets[0].CloneStrategy
-		return cloneStrategy, nil
+	claimPropertySets := storageProfile.Status.ClaimPropertySets
+	if len(claimPropertySets) == 0 {
+		return nil, nil
+	}
+	// the strategy of the set matching the modes of the source, or of the preferred set
+	if index := selectClaimPropertySet(claimPropertySets, &sourcePvc.Spec, ""); index >= 0 {
+		return claimPropertySets[index].CloneStrategy, nil
 	}
-
-	return nil, nil
+	return claimPropertySets[0].CloneStrategy, nil
 }
 
-func getDefaultVolumeMode(c client.Client, storageClass *storagev1.StorageClass) (*corev1.PersistentVolumeMode, error) {
+func getDefaultVolumeMode(c client.Client, storageClass *storagev1.StorageClass, dv *cdiv1.DataVolume) (*corev1.PersistentVolumeMode, error) {
 	if storageClass == nil {
 		// fallback to k8s defaults
 		return nil, nil
 	}
 
-	storageProfile := &cdiv1.StorageProfile{}
-	err := c.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile)
+	requested := &corev1.PersistentVolumeClaimSpec{AccessModes: dv.Spec.Storage.AccessModes}
+	claimPropertySet, _, err := getClaimPropertySet(c, storageClass, requested, dv)
 	if err != nil {
-		return nil, errors.Wrap(err, "cannot get StorageProfile")
+		return nil, err
 	}
-	if len(storageProfile.Status.ClaimPropertySets) > 0 {
-		volumeMode := storageProfile.Status.ClaimPropertySets[0].VolumeMode
-		return volumeMode, nil
+	if claimPropertySet != nil {
+		return claimPropertySet.VolumeMode, nil
 	}
 
 	// since volumeMode is optional - > gracefully fallback to k8s defaults,
 	return nil, nil
 }
 
-func getDefaultAccessModes(c client.Client, storageClass *storagev1.StorageClass) ([]corev1.PersistentVolumeAccessMode, error) {
-	if storageClass == nil {
-		return nil, errors.Errorf("no accessMode defined on DV, no StorageProfile ")
+// getClaimPropertySet returns the preferred claim property set of the StorageProfile of the storage class that is
+// compatible with the requested modes and the DataVolume, and its index. A nil set is returned if no set is compatible.
+func getClaimPropertySet(c client.Client, storageClass *storagev1.StorageClass, requested *corev1.PersistentVolumeClaimSpec, dv *cdiv1.DataVolume) (*cdiv1.ClaimPropertySet, int, error) {
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile); err != nil {
+		return nil, -1, errors.Wrap(err, "cannot get StorageProfile")
 	}
 
-	storageProfile := &cdiv1.StorageProfile{}
-	err := c.Get(context.TODO(), types.NamespacedName{Name: storageClass.Name}, storageProfile)
+	index := selectClaimPropertySet(storageProfile.Status.ClaimPropertySets, requested, dv.Spec.ContentType)
+	if index < 0 {
+		return nil, -1, nil
+	}
+	return &storageProfile.Status.ClaimPropertySets[index], index, nil
+}
+
+// selectClaimPropertySet returns the index of the first ranked claim property set compatible with the requested modes
+// and the content type, -1 if no set is compatible. For VM disks this overrides the ranking of the StorageProfile: a
+// compatible Block ReadWriteMany set is chosen even if other sets are ranked before it, so the VM can be live migrated.
+// A DataVolume that sets its volumeMode and accessModes, or a StorageProfile without such a set, is not affected.
+func selectClaimPropertySet(claimPropertySets []cdiv1.ClaimPropertySet, requested *corev1.PersistentVolumeClaimSpec, contentType cdiv1.DataVolumeContentType) int {
+	selected := -1
+	for i := range claimPropertySets {
+		claimPropertySet := &claimPropertySets[i]
+		if !isClaimPropertySetCompatible(claimPropertySet, requested, contentType) {
+			continue
+		}
+		if contentType == cdiv1.DataVolumeArchive {
+			return i
+		}
+		if isLiveMigratable(claimPropertySet, requested) {
+			return i
+		}
+		if selected < 0 {
+			selected = i
+		}
+	}
+	return selected
+}
+
+// isClaimPropertySetCompatible returns true if the claim property set provides the modes missing from the request
+// without conflicting with the requested ones
+func isClaimPropertySetCompatible(claimPropertySet *cdiv1.ClaimPropertySet, requested *corev1.PersistentVolumeClaimSpec, contentType cdiv1.DataVolumeContentType) bool {
+	requestedVolumeMode := requested.VolumeMode
+	if requestedVolumeMode != nil && *requestedVolumeMode == "" {
+		requestedVolumeMode = nil
+	}
+	if requestedVolumeMode != nil && claimPropertySet.VolumeMode != nil && *requestedVolumeMode != *claimPropertySet.VolumeMode {
+		return false
+	}
+	if len(requested.AccessModes) == 0 && len(claimPropertySet.AccessModes) == 0 {
+		return false
+	}
+	for _, accessMode := range requested.AccessModes {
+		if len(claimPropertySet.AccessModes) > 0 && !supportsAccessMode(claimPropertySet.AccessModes, accessMode) {
+			return false
+		}
+	}
+
+	volumeMode := requestedVolumeMode
+	if volumeMode == nil {
+		volumeMode = claimPropertySet.VolumeMode
+	}
+	// archives are extracted to a filesystem
+	return contentType != cdiv1.DataVolumeArchive || resolveVolumeMode(volumeMode) == corev1.PersistentVolumeFilesystem
+}
+
+// supportsAccessMode returns true if the access modes include the access mode, a volume that can be written by many
+// nodes can be used with any access mode
+func supportsAccessMode(accessModes []corev1.PersistentVolumeAccessMode, accessMode corev1.PersistentVolumeAccessMode) bool {
+	for _, mode := range accessModes {
+		if mode == accessMode || mode == corev1.ReadWriteMany {
+			return true
+		}
+	}
+	return false
+}
+
+func isLiveMigratable(claimPropertySet *cdiv1.ClaimPropertySet, requested *corev1.PersistentVolumeClaimSpec) bool {
+	volumeMode := requested.VolumeMode
+	if volumeMode == nil || *volumeMode == "" {
+		volumeMode = claimPropertySet.VolumeMode
+	}
+	accessModes := requested.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = claimPropertySet.AccessModes
+	}
+	return volumeMode != nil && *volumeMode == corev1.PersistentVolumeBlock &&
+		len(accessModes) > 0 && accessModes[0] == corev1.ReadWriteMany
+}
+
+// reportClaimPropertySet records the index of the claim property set the PVC of the DataVolume is rendered with, the
+// event is emitted when the choice changes
+func (r *DatavolumeReconciler) reportClaimPropertySet(dv *cdiv1.DataVolume, index int) error {
+	value := strconv.Itoa(index)
+	if index < 0 || dv.Annotations[AnnClaimPropertySet] == value {
+		return nil
+	}
+
+	storageClass, err := GetStorageClassByName(r.client, dv.Spec.Storage.StorageClassName)
 	if err != nil {
-		return nil, errors.Wrap(err, "no accessMode defined on DV, cannot get StorageProfile")
+		return err
 	}
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: getName(storageClass)}, storageProfile); err != nil {
+		return errors.Wrap(err, "cannot get StorageProfile")
+	}
+	if index >= len(storageProfile.Status.ClaimPropertySets) {
+		return nil
+	}
+	claimPropertySet := storageProfile.Status.ClaimPropertySets[index]
 
-	if len(storageProfile.Status.ClaimPropertySets) > 0 &&
-		len(storageProfile.Status.ClaimPropertySets[0].AccessModes) > 0 {
-		accessModes := storageProfile.Status.ClaimPropertySets[0].AccessModes
-		return accessModes, nil
+	addAnnotation(dv, AnnClaimPropertySet, value)
+	volumeMode := "default"
+	if claimPropertySet.VolumeMode != nil {
+		volumeMode = string(*claimPropertySet.VolumeMode)
 	}
+	r.recorder.Eventf(dv, corev1.EventTypeNormal, ClaimPropertySetSelected, MessageClaimPropertySetSelected,
+		index, storageProfile.Name, volumeMode, claimPropertySet.AccessModes)
 
-	// no accessMode configured on storageProfile
-	return nil, errors.Errorf("no accessMode defined on StorageProfile for %s StorageClass", storageClass.Name)
+	return nil
 }
 
 // GetRequiredSpace calculates space required taking file system overhead into account
@@ -18,6 +18,9 @@ package controller
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -48,6 +51,11 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 var (
@@ -90,6 +98,99 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.Name).To(Equal("test-dv"))
 		})
 
+		It("Should pass the registry image verification requirements to the PVC", func() {
+			dv := newImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				Registry: &cdiv1.DataVolumeSourceRegistry{
+					URL:                   "docker://registry:5000/test",
+					Digest:                "sha256:" + strings.Repeat("a", 64),
+					SignatureVerification: &cdiv1.RegistrySignatureVerification{PublicKeyConfigMap: "cosign-key"},
+				},
+			}
+			reconciler = createDatavolumeReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnSource]).To(Equal(SourceRegistry))
+			Expect(pvc.Annotations[AnnRequiredDigest]).To(Equal("sha256:" + strings.Repeat("a", 64)))
+			Expect(pvc.Annotations[AnnSignaturePublicKeyConfigMap]).To(Equal("cosign-key"))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnSignaturePolicyConfigMap))
+		})
+
+		DescribeTable("Should pass the expected checksum to the PVC", func(source *cdiv1.DataVolumeSource) {
+			dv := newImportDataVolume("test-dv")
+			dv.Spec.Source = source
+			reconciler = createDatavolumeReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnChecksum]).To(Equal("sha512:" + strings.Repeat("c", 128)))
+		},
+			Entry("http", &cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "http://example.com/disk.img", Checksum: "sha512:" + strings.Repeat("c", 128)}}),
+			Entry("s3", &cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://example.com/bucket/disk.img", Checksum: "sha512:" + strings.Repeat("c", 128)}}),
+			Entry("upload", &cdiv1.DataVolumeSource{Upload: &cdiv1.DataVolumeSourceUpload{Checksum: "sha512:" + strings.Repeat("c", 128)}}),
+		)
+
+		It("Should pass the registry disk path to the PVC", func() {
+			dv := newImportDataVolume("test-dv")
+			dv.Spec.Source = &cdiv1.DataVolumeSource{
+				Registry: &cdiv1.DataVolumeSourceRegistry{
+					URL:      "docker://registry:5000/test",
+					DiskPath: "disk/data.qcow2",
+				},
+			}
+			reconciler = createDatavolumeReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnRegistryDiskPath]).To(Equal("disk/data.qcow2"))
+		})
+
+		It("Should wait for the DataSource referenced by sourceRef to be ready", func() {
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.Source = nil
+			dv.Spec.SourceRef = &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: testDataSourceName}
+			dataSource := createDataSource(testDataSourceName, "test")
+			updateDataSourceCondition(dataSource, cdiv1.DataSourceReady, corev1.ConditionFalse, "", DataSourceNotPopulated)
+			reconciler = createDatavolumeReconciler(dv, dataSource)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(DataSourceNotReady)))
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.Pending))
+			ready := findConditionByType(cdiv1.DataVolumeReady, dv.Status.Conditions)
+			Expect(ready).ToNot(BeNil())
+			Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(DataSourceNotReady))
+
+			By("Reconciling again while the DataSource is still not ready")
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).ToNot(Receive())
+		})
+
+		It("Should wait for the DataSource referenced by sourceRef to exist", func() {
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.Source = nil
+			dv.Spec.SourceRef = &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: testDataSourceName}
+			reconciler = createDatavolumeReconciler(dv)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(DataSourceNotReady)))
+		})
+
 		It("Should set params on a PVC from import DV.PVC", func() {
 			volumeBlock := corev1.PersistentVolumeBlock
 			importDataVolume := newImportDataVolume("test-dv")
@@ -229,6 +330,115 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(pvc.Spec.Resources.Requests.Storage().Value()).To(Equal(expectedSize.Value()))
 		})
 
+		Context("with ranked claimPropertySets", func() {
+			scName := "testStorageClass"
+			block := corev1.PersistentVolumeBlock
+			filesystem := corev1.PersistentVolumeFilesystem
+			blockRWX := cdiv1.ClaimPropertySet{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: &block}
+			filesystemRWO := cdiv1.ClaimPropertySet{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: &filesystem}
+
+			newDataVolume := func(volumeMode *corev1.PersistentVolumeMode) *cdiv1.DataVolume {
+				importDataVolume := newImportDataVolumeWithPvc("test-dv", nil)
+				importDataVolume.Spec.Storage = &cdiv1.StorageSpec{
+					StorageClassName: &scName,
+					VolumeMode:       volumeMode,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("1G"),
+						},
+					},
+				}
+				return importDataVolume
+			}
+
+			reconcileDataVolume := func(dv *cdiv1.DataVolume, claimPropertySets ...cdiv1.ClaimPropertySet) (*corev1.PersistentVolumeClaim, []string) {
+				reconciler = createDatavolumeReconciler(createStorageClass(scName, nil),
+					createStorageProfileWithClaimPropertySets(scName, claimPropertySets...), dv)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				pvc := &corev1.PersistentVolumeClaim{}
+				err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+
+				events := []string{}
+				for len(reconciler.recorder.(*record.FakeRecorder).Events) > 0 {
+					events = append(events, <-reconciler.recorder.(*record.FakeRecorder).Events)
+				}
+				return pvc, events
+			}
+
+			It("Should use the first claimPropertySet compatible with the DV volumeMode and report it", func() {
+				pvc, events := reconcileDataVolume(newDataVolume(&filesystem), blockRWX, filesystemRWO)
+				Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+				Expect(*pvc.Spec.VolumeMode).To(Equal(corev1.PersistentVolumeFilesystem))
+				Expect(pvc.Annotations[AnnClaimPropertySet]).To(Equal("1"))
+				Expect(events).To(ContainElement(ContainSubstring(ClaimPropertySetSelected)))
+
+				dv := &cdiv1.DataVolume{}
+				err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dv.Annotations[AnnClaimPropertySet]).To(Equal("1"))
+			})
+
+			It("Should report the claimPropertySet once", func() {
+				_, events := reconcileDataVolume(newDataVolume(&filesystem), blockRWX, filesystemRWO)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				for len(reconciler.recorder.(*record.FakeRecorder).Events) > 0 {
+					events = append(events, <-reconciler.recorder.(*record.FakeRecorder).Events)
+				}
+				count := 0
+				for _, event := range events {
+					if strings.Contains(event, ClaimPropertySetSelected) {
+						count++
+					}
+				}
+				Expect(count).To(Equal(1))
+			})
+
+			It("Should not change the DV when rendering its PVC spec", func() {
+				dv := newDataVolume(&filesystem)
+				reconciler = createDatavolumeReconciler(createStorageClass(scName, nil),
+					createStorageProfileWithClaimPropertySets(scName, blockRWX, filesystemRWO), dv)
+				pvcSpec, err := RenderPvcSpec(reconciler.client, reconciler.recorder, reconciler.log, dv)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pvcSpec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+				Expect(dv.Annotations).ToNot(HaveKey(AnnClaimPropertySet))
+				Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+			})
+
+			It("Should prefer a Block ReadWriteMany claimPropertySet for VM disks", func() {
+				pvc, _ := reconcileDataVolume(newDataVolume(nil), filesystemRWO, blockRWX)
+				Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+				Expect(*pvc.Spec.VolumeMode).To(Equal(corev1.PersistentVolumeBlock))
+				Expect(pvc.Annotations[AnnClaimPropertySet]).To(Equal("1"))
+			})
+
+			It("Should use a Filesystem claimPropertySet for archives", func() {
+				dv := newDataVolume(nil)
+				dv.Spec.ContentType = cdiv1.DataVolumeArchive
+				pvc, _ := reconcileDataVolume(dv, blockRWX, filesystemRWO)
+				Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+				Expect(*pvc.Spec.VolumeMode).To(Equal(corev1.PersistentVolumeFilesystem))
+			})
+
+			It("Should not report a claimPropertySet if the DV sets the accessModes and volumeMode", func() {
+				dv := newDataVolume(&block)
+				dv.Spec.Storage.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+				pvc, events := reconcileDataVolume(dv, blockRWX, filesystemRWO)
+				Expect(pvc.Annotations).ToNot(HaveKey(AnnClaimPropertySet))
+				Expect(events).ToNot(ContainElement(ContainSubstring(ClaimPropertySetSelected)))
+			})
+
+			It("Should fail if no claimPropertySet is compatible with the DV", func() {
+				reconciler = createDatavolumeReconciler(createStorageClass(scName, nil),
+					createStorageProfileWithClaimPropertySets(scName, blockRWX), newDataVolume(&filesystem))
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).To(HaveOccurred())
+				Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(ErrClaimNotValid)))
+			})
+		})
+
 		It("Should pass annotation from DV to created a PVC on a DV", func() {
 			dv := newImportDataVolume("test-dv")
 			dv.SetAnnotations(make(map[string]string))
@@ -379,6 +589,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dv.Status.Phase).To(Equal(cdiv1.SnapshotForSmartCloneInProgress))
+			Expect(dv.Annotations[annCloneType]).To(Equal(cloneTypeSnapshot))
 		})
 
 		It("Should do nothing when smart clone with namespace transfer and not target found", func() {
@@ -448,6 +659,237 @@ var _ = Describe("All DataVolume Tests", func() {
 			}),
 		)
 
+		It("Should create a PVC cloned by the CSI driver if cloning and the CSI clone strategy is preferred", func() {
+			dv := newCloneDataVolume("test-dv")
+			scName := "testsc"
+			sc := createStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "csi-plugin")
+			csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+			sp := createStorageProfileWithCloneStrategy(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, corev1.PersistentVolumeFilesystem, &csiClone)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("2G")
+			pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			sc.AllowVolumeExpansion = &[]bool{true}[0]
+			csiDriver := &storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "csi-plugin"}}
+			reconciler := createDatavolumeReconciler(sc, sp, dv, pvc, csiDriver)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the PVC is cloned from the source with the size of the source")
+			clonePvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, clonePvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clonePvc.Spec.DataSource).To(Equal(&corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "test"}))
+			Expect(clonePvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1G")))
+			Expect(clonePvc.Annotations[AnnCSICloneRequest]).To(Equal("true"))
+			Expect(clonePvc.Annotations).ToNot(HaveKey(AnnCloneRequest))
+
+			By("Verifying that phase is now CSI clone in progress")
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.CSICloneInProgress))
+			Expect(dv.Annotations[annCloneType]).To(Equal(cloneTypeCsiClone))
+		})
+
+		It("Should fall back to host assisted clone if the provisioner is not a CSI driver", func() {
+			dv := newCloneDataVolume("test-dv")
+			scName := "testsc"
+			sc := createStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "csi-plugin")
+			csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+			sp := createStorageProfileWithCloneStrategy(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, corev1.PersistentVolumeFilesystem, &csiClone)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler := createDatavolumeReconciler(sc, sp, dv, pvc)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			clonePvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, clonePvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clonePvc.Spec.DataSource).To(BeNil())
+			Expect(clonePvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+
+			By("Verifying the fallback is reported once")
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+			warnings := 0
+			for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+				if strings.Contains(event, "no CSIDriver available") {
+					warnings++
+				}
+			}
+			Expect(warnings).To(Equal(1))
+		})
+
+		DescribeTable("Should follow the PVC cloned by the CSI driver", func(pvcPhase corev1.PersistentVolumeClaimPhase, capacity string, expectedPhase cdiv1.DataVolumePhase) {
+			dv := newCloneDataVolume("test-dv")
+			scName := "testsc"
+			sc := createStorageClassWithProvisioner(scName, map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "csi-plugin")
+			sc.AllowVolumeExpansion = &[]bool{true}[0]
+			csiClone := cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)
+			sp := createStorageProfileWithCloneStrategy(scName, []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}, corev1.PersistentVolumeFilesystem, &csiClone)
+
+			dv.Spec.PVC.StorageClassName = &scName
+			pvc := createPvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			clonePvc := createPvcInStorageClass("test-dv", metav1.NamespaceDefault, &scName, map[string]string{AnnCSICloneRequest: "true"}, nil, pvcPhase)
+			clonePvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(dv, cdiv1.SchemeGroupVersion.WithKind("DataVolume"))}
+			clonePvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse(capacity)
+			csiDriver := &storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "csi-plugin"}}
+			reconciler := createDatavolumeReconciler(sc, sp, dv, pvc, clonePvc, csiDriver)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(expectedPhase))
+
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, clonePvc)
+			Expect(err).ToNot(HaveOccurred())
+			if pvcPhase == corev1.ClaimBound {
+				Expect(clonePvc.Annotations[AnnCloneOf]).To(Equal("true"))
+			} else {
+				Expect(clonePvc.Annotations).ToNot(HaveKey(AnnCloneOf))
+			}
+		},
+			Entry("in progress until bound", corev1.ClaimPending, "1G", cdiv1.CSICloneInProgress),
+			Entry("expanding when smaller than requested", corev1.ClaimBound, "500M", cdiv1.ExpansionInProgress),
+			Entry("succeeded when bound", corev1.ClaimBound, "1G", cdiv1.Succeeded),
+		)
+
+		It("Should restore the snapshot directly if the storage class belongs to the snapshot driver", func() {
+			dv := newSnapshotCloneDataVolume("test-dv", metav1.NamespaceDefault)
+			sc := createStorageClassWithProvisioner("testsc", map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "csi-plugin")
+			snapshot, content := createReadySnapshot("snapshot", metav1.NamespaceDefault, "csi-plugin", "1G")
+			reconciler := createDatavolumeReconciler(sc, dv, snapshot, content)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the PVC is restored from the snapshot")
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Spec.DataSource.Kind).To(Equal("VolumeSnapshot"))
+			Expect(pvc.Spec.DataSource.Name).To(Equal("snapshot"))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneRequest))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneToken))
+
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).To(Equal(cdiv1.SmartClonePVCInProgress))
+			Expect(dv.Annotations[annCloneType]).To(Equal("snapshot-restore"))
+		})
+
+		It("Should wait for the snapshot to be ready", func() {
+			dv := newSnapshotCloneDataVolume("test-dv", metav1.NamespaceDefault)
+			sc := createStorageClassWithProvisioner("testsc", map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "csi-plugin")
+			snapshot, content := createReadySnapshot("snapshot", metav1.NamespaceDefault, "csi-plugin", "1G")
+			snapshot.Status.ReadyToUse = &[]bool{false}[0]
+			reconciler := createDatavolumeReconciler(sc, dv, snapshot, content)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should restore the snapshot to a temporary PVC and clone it if the snapshot is in another namespace", func() {
+			dv := newSnapshotCloneDataVolume("test-dv", "source-ns")
+			sc := createStorageClassWithProvisioner("testsc", map[string]string{
+				AnnDefaultStorageClass: "true",
+			}, "other-plugin")
+			snapshotSc := createStorageClassWithProvisioner("snapshotsc", nil, "csi-plugin")
+			snapshot, content := createReadySnapshot("snapshot", "source-ns", "csi-plugin", "1G")
+			reconciler := createDatavolumeReconciler(sc, snapshotSc, dv, snapshot, content)
+			reconciler.tokenValidator = &FakeValidator{
+				match:     "foobar",
+				ID:        "1234",
+				Name:      "snapshot",
+				Namespace: "source-ns",
+				Resource:  metav1.GroupVersionResource{Resource: "volumesnapshots"},
+				Params: map[string]string{
+					"targetNamespace": metav1.NamespaceDefault,
+					"targetName":      "test-dv",
+				},
+			}
+
+			By("Verifying the finalizer is added first")
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Finalizers).To(ContainElement(crossNamespaceFinalizer))
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the snapshot is restored with a storage class of the snapshot driver")
+			restorePvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: snapshotRestorePvcName(dv), Namespace: "source-ns"}, restorePvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(restorePvc.Spec.DataSource.Name).To(Equal("snapshot"))
+			Expect(*restorePvc.Spec.StorageClassName).To(Equal("snapshotsc"))
+			Expect(restorePvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1G")))
+			Expect(isOwnedByDataVolume(restorePvc, dv)).To(BeTrue())
+
+			By("Verifying the target is cloned from the temporary PVC")
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Spec.DataSource).To(BeNil())
+			Expect(pvc.Annotations[AnnCloneRequest]).To(Equal("source-ns/" + snapshotRestorePvcName(dv)))
+			Expect(pvc.Annotations[AnnCloneToken]).To(Equal("foobar"))
+
+			By("Verifying the clone authorization is recorded on the target")
+			identity := &cloneTokenIdentity{}
+			err = json.Unmarshal([]byte(pvc.Annotations[AnnCloneAuthorization]), identity)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity.TokenID).To(Equal("1234"))
+			Expect(identity.Resource).To(Equal("volumesnapshots"))
+			Expect(identity.SourceNamespace).To(Equal("source-ns"))
+			Expect(identity.SourceName).To(Equal("snapshot"))
+			Expect(identity.TargetNamespace).To(Equal(metav1.NamespaceDefault))
+			Expect(identity.TargetName).To(Equal("test-dv"))
+		})
+
+		It("Should delete the temporary PVC when the DataVolume is deleted", func() {
+			dv := newSnapshotCloneDataVolume("test-dv", "source-ns")
+			dv.Finalizers = []string{crossNamespaceFinalizer}
+			dv.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			restorePvc := createPvcInStorageClass(snapshotRestorePvcName(dv), "source-ns", nil, nil, nil, corev1.ClaimBound)
+			Expect(setAnnOwnedByDataVolume(restorePvc, dv)).To(Succeed())
+			reconciler := createDatavolumeReconciler(dv, restorePvc)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: restorePvc.Name, Namespace: "source-ns"}, restorePvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			dv = &cdiv1.DataVolume{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			if err == nil {
+				Expect(dv.Finalizers).ToNot(ContainElement(crossNamespaceFinalizer))
+			} else {
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
 		It("Should set multistage migration annotations on a newly created PVC", func() {
 			dv := newImportDataVolume("test-dv")
 			dv.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{
@@ -1300,20 +1742,77 @@ var _ = Describe("All DataVolume Tests", func() {
 
 	var _ = Describe("Update Progress from pod", func() {
 		var (
-			pvc *corev1.PersistentVolumeClaim
-			pod *corev1.Pod
-			dv  *cdiv1.DataVolume
+			pvc        *corev1.PersistentVolumeClaim
+			pod        *corev1.Pod
+			dv         *cdiv1.DataVolume
+			reconciler *DatavolumeReconciler
+			serverCA   *triple.KeyPair
+			clientCA   *triple.KeyPair
 		)
 
 		BeforeEach(func() {
+			var err error
 			pvc = createPvc("test", metav1.NamespaceDefault, nil, nil)
 			pod = createImporterTestPod(pvc, "test", nil)
 			dv = newImportDataVolume("test")
+
+			serverCA, err = triple.NewCA("server")
+			Expect(err).ToNot(HaveOccurred())
+			clientCA, err = triple.NewCA("client")
+			Expect(err).ToNot(HaveOccurred())
+			clientCertGenerator := &generator.FetchCertGenerator{
+				Fetcher: &fetcher.MemCertFetcher{
+					Cert: cert.EncodeCertPEM(clientCA.Cert),
+					Key:  cert.EncodePrivateKeyPEM(clientCA.Key),
+				},
+			}
+			serverCAFetcher := &fetcher.MemCertBundleFetcher{Bundle: cert.EncodeCertPEM(serverCA.Cert)}
+			reconciler = &DatavolumeReconciler{progressClient: newProgressClient(clientCertGenerator, serverCAFetcher)}
 		})
 
+		// startProgressServer serves the report the way worker pods do, only to clients with a certificate signed by
+		// the client CA
+		startProgressServer := func(report *prometheusutil.ProgressReport) *httptest.Server {
+			serverCertGenerator := &generator.FetchCertGenerator{
+				Fetcher: &fetcher.MemCertFetcher{
+					Cert: cert.EncodeCertPEM(serverCA.Cert),
+					Key:  cert.EncodePrivateKeyPEM(serverCA.Key),
+				},
+			}
+			serverTLS, err := newProgressServerTLS(serverCertGenerator, &fetcher.MemCertBundleFetcher{Bundle: cert.EncodeCertPEM(clientCA.Cert)}, pod.Namespace, pod.Name)
+			Expect(err).ToNot(HaveOccurred())
+			serverCert, err := tls.X509KeyPair(serverTLS.cert, serverTLS.key)
+			Expect(err).ToNot(HaveOccurred())
+			clientCAs := x509.NewCertPool()
+			Expect(clientCAs.AppendCertsFromPEM(serverTLS.clientCA)).To(BeTrue())
+
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != common.ProgressPath || len(r.TLS.VerifiedChains) == 0 ||
+					r.TLS.VerifiedChains[0][0].Subject.CommonName != common.ProgressClientName {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				Expect(json.NewEncoder(w).Encode(report)).To(Succeed())
+			}))
+			ts.TLS = &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientCAs:    clientCAs,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}
+			ts.StartTLS()
+
+			ep, err := url.Parse(ts.URL)
+			Expect(err).ToNot(HaveOccurred())
+			port, err := strconv.Atoi(ep.Port())
+			Expect(err).ToNot(HaveOccurred())
+			pod.Spec.Containers[0].Ports[0].ContainerPort = int32(port)
+			pod.Status.PodIP = ep.Hostname()
+			return ts
+		}
+
 		It("Should return error, if no metrics port in pod", func() {
 			pod.Spec.Containers[0].Ports = nil
-			err := updateProgressUsingPod(dv, pod)
+			err := reconciler.updateProgressUsingPod(dv, pod)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Metrics port not found in pod"))
 		})
@@ -1321,34 +1820,72 @@ var _ = Describe("All DataVolume Tests", func() {
 		It("Should not error, if no endpoint exists", func() {
 			pod.Spec.Containers[0].Ports[0].ContainerPort = 12345
 			pod.Status.PodIP = "127.0.0.1"
-			err := updateProgressUsingPod(dv, pod)
+			err := reconciler.updateProgressUsingPod(dv, pod)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
-		It("Should properly update progress if http endpoint returns matching data", func() {
+		It("Should not read the progress without a progress client", func() {
+			dv.Status.Progress = cdiv1.DataVolumeProgress("2.3%")
+			reconciler.progressClient = nil
+			pod.Status.PodIP = "127.0.0.1"
+			err := reconciler.updateProgressUsingPod(dv, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Progress).To(BeEquivalentTo("2.3%"))
+			Expect(dv.Status.Transfer).To(BeNil())
+		})
+
+		It("Should properly update progress if endpoint returns matching report", func() {
 			dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
-			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.Write([]byte(fmt.Sprintf("import_progress{ownerUID=\"%v\"} 13.45", dv.GetUID())))
-				w.WriteHeader(200)
-			}))
+			ts := startProgressServer(&prometheusutil.ProgressReport{
+				OwnerUID:                  string(dv.GetUID()),
+				Phase:                     "TransferDataFile",
+				BytesTransferred:          1345,
+				TotalBytes:                10000,
+				Progress:                  13.45,
+				Throughput:                100,
+				EstimatedSecondsRemaining: 86,
+			})
 			defer ts.Close()
-			ep, err := url.Parse(ts.URL)
-			Expect(err).ToNot(HaveOccurred())
-			port, err := strconv.Atoi(ep.Port())
-			Expect(err).ToNot(HaveOccurred())
-			pod.Spec.Containers[0].Ports[0].ContainerPort = int32(port)
-			pod.Status.PodIP = ep.Hostname()
-			err = updateProgressUsingPod(dv, pod)
+			err := reconciler.updateProgressUsingPod(dv, pod)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dv.Status.Progress).To(BeEquivalentTo("13.45%"))
+			Expect(dv.Status.Transfer).To(Equal(&cdiv1.DataVolumeTransferStatus{
+				Phase:                     "TransferDataFile",
+				BytesTransferred:          1345,
+				TotalBytes:                10000,
+				Throughput:                100,
+				EstimatedSecondsRemaining: 86,
+			}))
+		})
+
+		It("Should only update the transfer phase if no bytes are reported yet", func() {
+			dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
+			dv.Status.Progress = cdiv1.DataVolumeProgress("N/A")
+			ts := startProgressServer(&prometheusutil.ProgressReport{Phase: "Convert"})
+			defer ts.Close()
+			err := reconciler.updateProgressUsingPod(dv, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Progress).To(BeEquivalentTo("N/A"))
+			Expect(dv.Status.Transfer).To(Equal(&cdiv1.DataVolumeTransferStatus{Phase: "Convert"}))
 		})
 
-		It("Should not change update progress if http endpoint returns no matching data", func() {
+		It("Should not change update progress if endpoint returns no matching report", func() {
 			dv.SetUID("b856691e-1038-11e9-a5ab-525500d15501")
 			dv.Status.Progress = cdiv1.DataVolumeProgress("2.3%")
+			ts := startProgressServer(&prometheusutil.ProgressReport{
+				OwnerUID: "b856691e-1038-11e9-a5ab-55500d15501",
+				Progress: 13.45,
+			})
+			defer ts.Close()
+			err := reconciler.updateProgressUsingPod(dv, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Progress).To(BeEquivalentTo("2.3%"))
+			Expect(dv.Status.Transfer).To(BeNil())
+		})
+
+		It("Should fail if the pod is not signed by the server CA", func() {
 			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.Write([]byte(fmt.Sprintf("import_progress{ownerUID=\"%v\"} 13.45", "b856691e-1038-11e9-a5ab-55500d15501")))
-				w.WriteHeader(200)
+				w.Write([]byte(`{"progress": 13.45}`))
 			}))
 			defer ts.Close()
 			ep, err := url.Parse(ts.URL)
@@ -1357,9 +1894,9 @@ var _ = Describe("All DataVolume Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 			pod.Spec.Containers[0].Ports[0].ContainerPort = int32(port)
 			pod.Status.PodIP = ep.Hostname()
-			err = updateProgressUsingPod(dv, pod)
-			Expect(err).ToNot(HaveOccurred())
-			Expect(dv.Status.Progress).To(BeEquivalentTo("2.3%"))
+			err = reconciler.updateProgressUsingPod(dv, pod)
+			Expect(err).To(HaveOccurred())
+			Expect(dv.Status.Transfer).To(BeNil())
 		})
 	})
 })
@@ -1569,6 +2106,46 @@ func newCloneDataVolumeWithPVCNS(name string, pvcNamespace string) *cdiv1.DataVo
 	}
 }
 
+func newSnapshotCloneDataVolume(name, snapshotNamespace string) *cdiv1.DataVolume {
+	dv := newCloneDataVolume(name)
+	dv.Spec.Source = &cdiv1.DataVolumeSource{
+		Snapshot: &cdiv1.DataVolumeSourceSnapshot{
+			Name:      "snapshot",
+			Namespace: snapshotNamespace,
+		},
+	}
+	return dv
+}
+
+func createReadySnapshot(name, namespace, driver, restoreSize string) (*snapshotv1.VolumeSnapshot, *snapshotv1.VolumeSnapshotContent) {
+	contentName := "content-" + name
+	size := resource.MustParse(restoreSize)
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: &snapshotv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &contentName,
+			ReadyToUse:                     &[]bool{true}[0],
+			RestoreSize:                    &size,
+		},
+	}
+	content := &snapshotv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: contentName,
+		},
+		Spec: snapshotv1.VolumeSnapshotContentSpec{
+			Driver: driver,
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Namespace: namespace,
+				Name:      name,
+			},
+		},
+	}
+	return snapshot, content
+}
+
 func newUploadDataVolume(name string) *cdiv1.DataVolume {
 	return &cdiv1.DataVolume{
 		TypeMeta: metav1.TypeMeta{APIVersion: cdiv1.SchemeGroupVersion.String()},
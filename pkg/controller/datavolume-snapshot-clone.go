@@ -0,0 +1,409 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// A DataVolume with a snapshot source is restored directly when the target storage class belongs to the driver of the
+// snapshot. Otherwise the snapshot is restored to a temporary PVC in the namespace of the snapshot, which is the
+// source of a host assisted clone.
+const snapshotCloneType = "snapshot-restore"
+
+// getCloneSource returns the namespace and name of the PVC or snapshot a DataVolume is cloned from
+func getCloneSource(dv *cdiv1.DataVolume) (string, string) {
+	if dv.Spec.Source == nil {
+		return "", ""
+	}
+	if dv.Spec.Source.Snapshot != nil {
+		return getSnapshotNamespace(dv), dv.Spec.Source.Snapshot.Name
+	}
+	if dv.Spec.Source.PVC != nil {
+		return dv.Spec.Source.PVC.Namespace, dv.Spec.Source.PVC.Name
+	}
+	return "", ""
+}
+
+func getSnapshotNamespace(dv *cdiv1.DataVolume) string {
+	if dv.Spec.Source.Snapshot.Namespace != "" {
+		return dv.Spec.Source.Snapshot.Namespace
+	}
+	return dv.Namespace
+}
+
+// snapshotRestorePvcName is the name of the temporary PVC the snapshot is restored to for a host assisted clone
+func snapshotRestorePvcName(dv *cdiv1.DataVolume) string {
+	return fmt.Sprintf("cdi-snapshot-%s", dv.UID)
+}
+
+func isSnapshotRestorePvc(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Spec.DataSource != nil && pvc.Spec.DataSource.Kind == "VolumeSnapshot"
+}
+
+// isSnapshotRestorePvcOf returns true if pvc is the snapshot restore PVC of the DataVolume that owns target
+func isSnapshotRestorePvcOf(pvc, target *corev1.PersistentVolumeClaim) bool {
+	owner := metav1.GetControllerOf(target)
+	if owner == nil || owner.Kind != "DataVolume" || !isSnapshotRestorePvc(pvc) {
+		return false
+	}
+
+	dv := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: target.Namespace,
+			Name:      owner.Name,
+			UID:       owner.UID,
+		},
+	}
+	namespace, name, err := getAnnOwnedByDataVolume(pvc)
+	return err == nil && namespace == dv.Namespace && name == dv.Name && pvc.Name == snapshotRestorePvcName(dv)
+}
+
+// isSnapshotReady returns true once the snapshot is bound to its content and can be restored
+func isSnapshotReady(snapshot *snapshotv1.VolumeSnapshot) bool {
+	status := snapshot.Status
+	return status != nil &&
+		status.ReadyToUse != nil && *status.ReadyToUse &&
+		status.BoundVolumeSnapshotContentName != nil &&
+		status.RestoreSize != nil
+}
+
+func (r *DatavolumeReconciler) updateSnapshotCloneStatusPhase(phase cdiv1.DataVolumePhase, dataVolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) error {
+	return r.updateStorageCloneStatusPhase(phase, dataVolume, pvc, snapshotCloneType)
+}
+
+func (r *DatavolumeReconciler) reconcileSnapshotClone(log logr.Logger, datavolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcExists bool, pvcSpec *corev1.PersistentVolumeClaimSpec) (reconcile.Result, error) {
+	if pvcExists {
+		if isSnapshotRestorePvc(pvc) {
+			return r.reconcileSnapshotRestoreForExistingPvc(log, datavolume, pvc, pvcSpec)
+		}
+
+		if datavolume.Status.Phase == cdiv1.Succeeded {
+			return reconcile.Result{}, r.cleanupSnapshotClone(log, datavolume)
+		}
+
+		return r.reconcileDataVolumeStatus(datavolume, pvc)
+	}
+
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	nn := types.NamespacedName{Namespace: getSnapshotNamespace(datavolume), Name: datavolume.Spec.Source.Snapshot.Name}
+	if err := r.client.Get(context.TODO(), nn, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.recorder.Eventf(datavolume, corev1.EventTypeWarning, ErrUnableToClone, "Source snapshot %s/%s not found", nn.Namespace, nn.Name)
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !isSnapshotReady(snapshot) {
+		// snapshots of other owners are not watched
+		return reconcile.Result{Requeue: true},
+			r.updateSnapshotCloneStatusPhase(cdiv1.CloneScheduled, datavolume, nil)
+	}
+
+	content := &snapshotv1.VolumeSnapshotContent{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: *snapshot.Status.BoundVolumeSnapshotContentName}, content); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	possible, err := r.isSnapshotRestorePossible(datavolume, snapshot, content, pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !possible {
+		return r.reconcileSnapshotHostAssistedClone(log, datavolume, snapshot, content, pvcSpec)
+	}
+
+	restoreSpec, err := newSnapshotRestoreSpec(snapshot, pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	newPvc, err := r.newPersistentVolumeClaim(datavolume, restoreSpec, datavolume.Namespace, datavolume.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The clone controller must not start a host assisted clone of this PVC
+	delete(newPvc.Annotations, AnnCloneRequest)
+	delete(newPvc.Annotations, AnnCloneToken)
+
+	log.Info("Restoring snapshot to PVC", "snapshot", snapshot.Name)
+	if err := r.client.Create(context.TODO(), newPvc); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{},
+		r.updateSnapshotCloneStatusPhase(cdiv1.SmartClonePVCInProgress, datavolume, newPvc)
+}
+
+func (r *DatavolumeReconciler) reconcileSnapshotRestoreForExistingPvc(log logr.Logger, datavolume *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec) (reconcile.Result, error) {
+	populated, err := r.markCsiClonePopulated(pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !populated {
+		return reconcile.Result{},
+			r.updateSnapshotCloneStatusPhase(cdiv1.SmartClonePVCInProgress, datavolume, pvc)
+	}
+
+	done, err := r.expand(log, datavolume, pvc, pvcSpec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !done {
+		return reconcile.Result{},
+			r.updateSnapshotCloneStatusPhase(cdiv1.ExpansionInProgress, datavolume, pvc)
+	}
+
+	return reconcile.Result{}, r.updateSnapshotCloneStatusPhase(cdiv1.Succeeded, datavolume, pvc)
+}
+
+// isSnapshotRestorePossible returns true if the target can be restored from the snapshot, a snapshot can only be
+// restored in its own namespace by its own driver
+func (r *DatavolumeReconciler) isSnapshotRestorePossible(dataVolume *cdiv1.DataVolume, snapshot *snapshotv1.VolumeSnapshot, content *snapshotv1.VolumeSnapshotContent, targetStorageSpec *corev1.PersistentVolumeClaimSpec) (bool, error) {
+	log := r.log.WithName("SnapshotRestorePossible").V(3)
+
+	if snapshot.Namespace != dataVolume.Namespace {
+		log.Info("Snapshot is in another namespace, falling back to host assisted clone")
+		return false, nil
+	}
+
+	targetStorageClass, err := GetStorageClassByName(r.client, targetStorageSpec.StorageClassName)
+	if err != nil {
+		return false, err
+	}
+	if targetStorageClass == nil {
+		log.Info("Target PVC's Storage Class not found")
+		return false, nil
+	}
+
+	if targetStorageClass.Provisioner != content.Spec.Driver {
+		log.Info("Target storage class does not belong to the snapshot driver, falling back to host assisted clone",
+			"provisioner", targetStorageClass.Provisioner, "driver", content.Spec.Driver)
+		return false, nil
+	}
+
+	sourcePvc, err := r.getSnapshotSourcePvc(snapshot)
+	if err != nil {
+		return false, err
+	}
+	if sourcePvc != nil {
+		targetVolumeMode, err := getStorageVolumeMode(r.client, dataVolume, targetStorageClass)
+		if err != nil {
+			return false, err
+		}
+		if resolveVolumeMode(sourcePvc.Spec.VolumeMode) != resolveVolumeMode(targetVolumeMode) {
+			log.Info("Snapshot source and target PVC have different volumeModes, falling back to host assisted clone")
+			return false, nil
+		}
+	}
+
+	targetRequest, hasTargetRequest := targetStorageSpec.Resources.Requests[corev1.ResourceStorage]
+	allowExpansion := targetStorageClass.AllowVolumeExpansion != nil && *targetStorageClass.AllowVolumeExpansion
+	if !hasTargetRequest || (snapshot.Status.RestoreSize.Cmp(targetRequest) < 0 && !allowExpansion) {
+		log.Info("Snapshot is smaller than the target and the storage class does not allow expansion, falling back to host assisted clone")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// getSnapshotSourcePvc returns the PVC the snapshot was taken from, or nil if it was deleted since
+func (r *DatavolumeReconciler) getSnapshotSourcePvc(snapshot *snapshotv1.VolumeSnapshot) (*corev1.PersistentVolumeClaim, error) {
+	if snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+		return nil, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	nn := types.NamespacedName{Namespace: snapshot.Namespace, Name: *snapshot.Spec.Source.PersistentVolumeClaimName}
+	if err := r.client.Get(context.TODO(), nn, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pvc, nil
+}
+
+func (r *DatavolumeReconciler) reconcileSnapshotHostAssistedClone(log logr.Logger, datavolume *cdiv1.DataVolume, snapshot *snapshotv1.VolumeSnapshot, content *snapshotv1.VolumeSnapshotContent, pvcSpec *corev1.PersistentVolumeClaimSpec) (reconcile.Result, error) {
+	if !HasFinalizer(datavolume, crossNamespaceFinalizer) {
+		// the temporary PVC is deleted with the DataVolume, get reconciled again once the finalizer is added
+		AddFinalizer(datavolume, crossNamespaceFinalizer)
+		return reconcile.Result{}, r.updateDataVolume(datavolume)
+	}
+
+	restorePvc := &corev1.PersistentVolumeClaim{}
+	nn := types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshotRestorePvcName(datavolume)}
+	if err := r.client.Get(context.TODO(), nn, restorePvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+
+		// the token is checked before anything is created in the namespace of the snapshot
+		if err := validateCloneTokenDV(r.tokenValidator, datavolume); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		restorePvc, err = r.newSnapshotRestorePvc(datavolume, snapshot, content)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		log.Info("Restoring snapshot for host assisted clone", "namespace", restorePvc.Namespace, "name", restorePvc.Name)
+		if err := r.client.Create(context.TODO(), restorePvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	newPvc, err := r.createPvcForDatavolume(log, datavolume, pvcSpec)
+	if err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	return r.reconcileDataVolumeStatus(datavolume, newPvc)
+}
+
+// newSnapshotRestorePvc creates the temporary PVC the snapshot is restored to. The storage class and volume mode of
+// the PVC the snapshot was taken from are used if it still exists, otherwise a storage class of the snapshot driver.
+func (r *DatavolumeReconciler) newSnapshotRestorePvc(dataVolume *cdiv1.DataVolume, snapshot *snapshotv1.VolumeSnapshot, content *snapshotv1.VolumeSnapshotContent) (*corev1.PersistentVolumeClaim, error) {
+	restoreSpec := &corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+	}
+
+	sourcePvc, err := r.getSnapshotSourcePvc(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if sourcePvc != nil && sourcePvc.Spec.StorageClassName != nil {
+		restoreSpec.StorageClassName = sourcePvc.Spec.StorageClassName
+		restoreSpec.VolumeMode = sourcePvc.Spec.VolumeMode
+		restoreSpec.AccessModes = sourcePvc.Spec.AccessModes
+	} else {
+		storageClass, err := r.findStorageClassForDriver(content.Spec.Driver)
+		if err != nil {
+			return nil, err
+		}
+		if storageClass == nil {
+			return nil, errors.Errorf("no storage class for snapshot driver %s", content.Spec.Driver)
+		}
+		restoreSpec.StorageClassName = &storageClass.Name
+	}
+
+	pvcSpec, err := newSnapshotRestoreSpec(snapshot, restoreSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: snapshot.Namespace,
+			Name:      snapshotRestorePvcName(dataVolume),
+			Labels: map[string]string{
+				common.CDILabelKey: common.CDILabelValue,
+			},
+		},
+		Spec: *pvcSpec,
+	}
+	if err := setAnnOwnedByDataVolume(pvc, dataVolume); err != nil {
+		return nil, err
+	}
+
+	return pvc, nil
+}
+
+// findStorageClassForDriver returns the default storage class if it is provisioned by driver, otherwise the first
+// storage class of driver by name
+func (r *DatavolumeReconciler) findStorageClassForDriver(driver string) (*storagev1.StorageClass, error) {
+	storageClasses := &storagev1.StorageClassList{}
+	if err := r.client.List(context.TODO(), storageClasses); err != nil {
+		return nil, err
+	}
+
+	var candidates []storagev1.StorageClass
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Provisioner != driver {
+			continue
+		}
+		if storageClass.Annotations[AnnDefaultStorageClass] == "true" {
+			return &storageClass, nil
+		}
+		candidates = append(candidates, storageClass)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	return &candidates[0], nil
+}
+
+// cleanupSnapshotClone deletes the PVC the snapshot was restored to for a host assisted clone
+func (r *DatavolumeReconciler) cleanupSnapshotClone(log logr.Logger, dv *cdiv1.DataVolume) error {
+	if !HasFinalizer(dv, crossNamespaceFinalizer) {
+		return nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	nn := types.NamespacedName{Namespace: getSnapshotNamespace(dv), Name: snapshotRestorePvcName(dv)}
+	if err := r.client.Get(context.TODO(), nn, pvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+	} else if isOwnedByDataVolume(pvc, dv) && pvc.DeletionTimestamp == nil {
+		log.Info("Deleting PVC restored from snapshot", "namespace", pvc.Namespace, "name", pvc.Name)
+		if err := r.client.Delete(context.TODO(), pvc); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	RemoveFinalizer(dv, crossNamespaceFinalizer)
+	return r.updateDataVolume(dv)
+}
+
+// isOwnedByDataVolume returns true if obj was created for dv in another namespace
+func isOwnedByDataVolume(obj client.Object, dv *cdiv1.DataVolume) bool {
+	if !hasAnnOwnedByDataVolume(obj) {
+		return false
+	}
+	namespace, name, err := getAnnOwnedByDataVolume(obj)
+	return err == nil && namespace == dv.Namespace && name == dv.Name
+}
@@ -0,0 +1,550 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// AnnDownloadPodReady tells whether the download pod of a PVC is ready to serve requests
+	AnnDownloadPodReady = "cdi.kubevirt.io/storage.download.podReady"
+
+	annCreatedByDownload = "cdi.kubevirt.io/storage.createdByDownloadController"
+
+	downloadServerCommand = "/usr/bin/cdi-downloadserver"
+
+	// DownloadSourceInUse is reason for event created when a download pvc is in use
+	DownloadSourceInUse = "DownloadSourceInUse"
+)
+
+// DownloadReconciler members
+type DownloadReconciler struct {
+	client              client.Client
+	recorder            record.EventRecorder
+	scheme              *runtime.Scheme
+	log                 logr.Logger
+	image               string
+	verbose             string
+	pullPolicy          string
+	serverCertGenerator generator.CertGenerator
+	clientCAFetcher     fetcher.CertBundleFetcher
+}
+
+// DownloadPodArgs are the parameters required to create a download pod
+type DownloadPodArgs struct {
+	Name                            string
+	PVC                             *corev1.PersistentVolumeClaim
+	ServerCert, ServerKey, ClientCA []byte
+}
+
+// Reconcile the reconcile loop for PVCs annotated for download.
+func (r *DownloadReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("PVC", req.NamespacedName)
+	log.V(1).Info("reconciling Download PVCs")
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	_, isDownload := pvc.Annotations[common.AnnDownloadRequest]
+	// force cleanup if PVC pending delete or the download annotation was removed
+	if !isDownload || !isBound(pvc, log) || pvc.DeletionTimestamp != nil {
+		log.V(1).Info("not doing anything with PVC",
+			"isDownload", isDownload,
+			"deletionTimeStamp set?", pvc.DeletionTimestamp != nil)
+		if err := r.cleanup(pvc); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	log.Info("Calling Download reconcile PVC")
+	return r.reconcilePVC(log, pvc)
+}
+
+func (r *DownloadReconciler) reconcilePVC(log logr.Logger, pvc *corev1.PersistentVolumeClaim) (reconcile.Result, error) {
+	pvcCopy := pvc.DeepCopy()
+	podName := createDownloadResourceName(pvc.Name)
+
+	pod, err := r.findDownloadPodForPvc(pvc, podName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if pod != nil && pod.Status.Phase == corev1.PodSucceeded {
+		return reconcile.Result{}, r.handleStoppedDownloadPod(log, pvc, pod)
+	}
+
+	if pod == nil {
+		// Readers are fine, the download pod mounts the PVC read only as well
+		podsUsingPVC, err := GetPodsUsingPVCs(r.client, pvc.Namespace, sets.NewString(pvc.Name), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if len(podsUsingPVC) > 0 {
+			for _, pod := range podsUsingPVC {
+				log.V(1).Info("can't create download pod, pvc in use by other pod", "pod", pod.Name)
+				r.recorder.Eventf(pvc, corev1.EventTypeWarning, DownloadSourceInUse,
+					"pod %s/%s using PersistentVolumeClaim %s", pod.Namespace, pod.Name, pvc.Name)
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		pod, err = r.createDownloadPodForPvc(pvc, podName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	svcName := naming.GetServiceNameFromResourceName(pod.Name)
+	if _, err = r.getOrCreateDownloadService(pvc, svcName); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	pvcCopy.Annotations[AnnDownloadPodReady] = strconv.FormatBool(isPodReady(pod))
+
+	if !reflect.DeepEqual(pvc, pvcCopy) {
+		if err := r.client.Update(context.TODO(), pvcCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// handleStoppedDownloadPod ends the download once the download server stopped after being idle, removing the download
+// request lets the cleanup release the PVC. If a download token was requested after the pod stopped, the pod is deleted
+// instead so a new one is started.
+func (r *DownloadReconciler) handleStoppedDownloadPod(log logr.Logger, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) error {
+	if isDownloadRequestedAfter(pvc, getDownloadPodFinishTime(pod)) {
+		log.V(1).Info("download requested after the download pod stopped, restarting it")
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "error deleting download pod")
+		}
+		return nil
+	}
+
+	log.V(1).Info("download pod stopped, removing the download request")
+	pvcCopy := pvc.DeepCopy()
+	delete(pvcCopy.Annotations, common.AnnDownloadRequest)
+	return r.client.Update(context.TODO(), pvcCopy)
+}
+
+// isDownloadRequestedAfter returns true if the latest download request of the PVC is after t, a request annotation
+// without a time was added by hand and is never newer
+func isDownloadRequestedAfter(pvc *corev1.PersistentVolumeClaim, t time.Time) bool {
+	requested, err := time.Parse(time.RFC3339, pvc.Annotations[common.AnnDownloadRequest])
+	if err != nil {
+		return false
+	}
+	return requested.After(t)
+}
+
+// getDownloadPodFinishTime returns when the download server exited, or when the pod was created if that is unknown
+func getDownloadPodFinishTime(pod *corev1.Pod) time.Time {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return status.State.Terminated.FinishedAt.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+func (r *DownloadReconciler) cleanup(pvc *corev1.PersistentVolumeClaim) error {
+	resourceName := createDownloadResourceName(pvc.Name)
+	svcName := naming.GetServiceNameFromResourceName(resourceName)
+
+	service := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: svcName, Namespace: pvc.Namespace}, service); IgnoreNotFound(err) != nil {
+		return err
+	} else if err == nil && metav1.IsControlledBy(service, pvc) && service.DeletionTimestamp == nil {
+		if err := r.client.Delete(context.TODO(), service); IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "error deleting download service")
+		}
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: pvc.Namespace}, pod); IgnoreNotFound(err) != nil {
+		return err
+	} else if err == nil && metav1.IsControlledBy(pod, pvc) && pod.DeletionTimestamp == nil {
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "error deleting download pod")
+		}
+	}
+
+	if _, ok := pvc.Annotations[AnnDownloadPodReady]; ok && pvc.DeletionTimestamp == nil {
+		pvcCopy := pvc.DeepCopy()
+		delete(pvcCopy.Annotations, AnnDownloadPodReady)
+		if err := r.client.Update(context.TODO(), pvcCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DownloadReconciler) findDownloadPodForPvc(pvc *corev1.PersistentVolumeClaim, podName string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: pvc.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "error getting download pod %s/%s", pvc.Namespace, podName)
+		}
+		return nil, nil
+	}
+
+	if !metav1.IsControlledBy(pod, pvc) {
+		return nil, errors.Errorf("%s pod not controlled by pvc %s", podName, pvc.Name)
+	}
+
+	return pod, nil
+}
+
+func (r *DownloadReconciler) createDownloadPodForPvc(pvc *corev1.PersistentVolumeClaim, podName string) (*corev1.Pod, error) {
+	serverCert, serverKey, err := r.serverCertGenerator.MakeServerCert(pvc.Namespace, naming.GetServiceNameFromResourceName(podName), uploadServerCertDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCA, err := r.clientCAFetcher.BundleBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	podResourceRequirements, err := GetDefaultPodResourceRequirements(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	workloadNodePlacement, err := GetWorkloadNodePlacement(r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	args := DownloadPodArgs{
+		Name:       podName,
+		PVC:        pvc,
+		ServerCert: serverCert,
+		ServerKey:  serverKey,
+		ClientCA:   clientCA,
+	}
+
+	r.log.V(3).Info("Creating download pod")
+	pod := r.makeDownloadPodSpec(args, podResourceRequirements, workloadNodePlacement)
+	if err := r.client.Create(context.TODO(), pod); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, errors.Wrap(err, "download pod API create errored")
+		}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: pvc.Namespace}, pod); err != nil {
+			return nil, errors.Wrap(err, "download pod should exist but couldn't retrieve it")
+		}
+	}
+
+	r.log.V(1).Info("download pod created\n", "Namespace", pod.Namespace, "Name", pod.Name, "Image name", r.image)
+	return pod, nil
+}
+
+func (r *DownloadReconciler) getOrCreateDownloadService(pvc *corev1.PersistentVolumeClaim, name string) (*corev1.Service, error) {
+	service := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: pvc.Namespace}, service); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "error getting download service")
+		}
+		service = r.makeDownloadServiceSpec(name, pvc)
+		if err := r.client.Create(context.TODO(), service); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return nil, errors.Wrap(err, "download service API create errored")
+		}
+		r.log.V(1).Info("download service created\n", "Namespace", service.Namespace, "Name", service.Name)
+	}
+
+	if !metav1.IsControlledBy(service, pvc) {
+		return nil, errors.Errorf("%s service not controlled by pvc %s", name, pvc.Name)
+	}
+
+	return service, nil
+}
+
+// makeDownloadServiceSpec creates download service manifest
+func (r *DownloadReconciler) makeDownloadServiceSpec(name string, pvc *corev1.PersistentVolumeClaim) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pvc.Namespace,
+			Annotations: map[string]string{
+				annCreatedByDownload: "yes",
+			},
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.DownloadServerCDILabel,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				MakePVCOwnerReference(pvc),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Protocol: "TCP",
+					Port:     443,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 8443,
+					},
+				},
+			},
+			Selector: map[string]string{
+				common.UploadServerServiceLabel: name,
+			},
+		},
+	}
+}
+
+func (r *DownloadReconciler) makeDownloadPodSpec(args DownloadPodArgs, resourceRequirements *corev1.ResourceRequirements, workloadNodePlacement *sdkapi.NodePlacement) *corev1.Pod {
+	serviceName := naming.GetServiceNameFromResourceName(args.Name)
+	scratchSize := getDownloadScratchSize(args.PVC)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      args.Name,
+			Namespace: args.PVC.Namespace,
+			Annotations: map[string]string{
+				annCreatedByDownload: "yes",
+			},
+			Labels: map[string]string{
+				common.CDILabelKey:              common.CDILabelValue,
+				common.CDIComponentLabel:        common.DownloadServerCDILabel,
+				common.UploadServerServiceLabel: serviceName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				MakePVCOwnerReference(args.PVC),
+			},
+		},
+		Spec: corev1.PodSpec{
+			// Run as root like the upload and clone pods, the owner of a block device or of an image written by another
+			// pod is not changed by an fsGroup
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser: &[]int64{0}[0],
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            common.DownloadServerPodname,
+					Image:           r.image,
+					ImagePullPolicy: corev1.PullPolicy(r.pullPolicy),
+					Command:         []string{downloadServerCommand, "-alsologtostderr"},
+					Args:            []string{"-v=" + r.verbose},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "TLS_KEY",
+							Value: string(args.ServerKey),
+						},
+						{
+							Name:  "TLS_CERT",
+							Value: string(args.ServerCert),
+						},
+						{
+							Name:  "CLIENT_CERT",
+							Value: string(args.ClientCA),
+						},
+						{
+							Name:  "CLIENT_NAME",
+							Value: uploadServerClientName,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: "/healthz",
+								Port: intstr.IntOrString{
+									Type:   intstr.Int,
+									IntVal: 8080,
+								},
+							},
+						},
+						InitialDelaySeconds: 2,
+						PeriodSeconds:       5,
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      ScratchVolName,
+							MountPath: common.DownloadServerScratchDir,
+						},
+					},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+			Volumes: []corev1.Volume{
+				{
+					Name: DataVolName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: args.PVC.Name,
+							ReadOnly:  true,
+						},
+					},
+				},
+				{
+					Name: ScratchVolName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{
+							SizeLimit: &scratchSize,
+						},
+					},
+				},
+			},
+			NodeSelector:      workloadNodePlacement.NodeSelector,
+			Tolerations:       workloadNodePlacement.Tolerations,
+			Affinity:          workloadNodePlacement.Affinity,
+			PriorityClassName: getPriorityClass(args.PVC),
+		},
+	}
+
+	if resourceRequirements != nil {
+		pod.Spec.Containers[0].Resources = *resourceRequirements.DeepCopy()
+	}
+	// The qcow2 conversion is written to the scratch space, reserve room for it on the node
+	if pod.Spec.Containers[0].Resources.Requests == nil {
+		pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{}
+	}
+	pod.Spec.Containers[0].Resources.Requests[corev1.ResourceEphemeralStorage] = scratchSize
+
+	if getVolumeMode(args.PVC) == corev1.PersistentVolumeBlock {
+		pod.Spec.Containers[0].VolumeDevices = []corev1.VolumeDevice{
+			{
+				Name:       DataVolName,
+				DevicePath: common.WriteBlockPath,
+			},
+		}
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "SOURCE",
+			Value: common.WriteBlockPath,
+		})
+	} else {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      DataVolName,
+			MountPath: common.UploadServerDataDir,
+			ReadOnly:  true,
+		})
+	}
+
+	SetPodPvcAnnotations(pod, args.PVC)
+	return pod
+}
+
+// getDownloadScratchSize returns the size of the scratch space of the download pod, the converted image is never larger
+// than the PVC.
+func getDownloadScratchSize(pvc *corev1.PersistentVolumeClaim) resource.Quantity {
+	if size, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return size
+	}
+	return pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
+// NewDownloadController creates a new instance of the download controller.
+func NewDownloadController(mgr manager.Manager, log logr.Logger, downloadImage, pullPolicy, verbose string, serverCertGenerator generator.CertGenerator, clientCAFetcher fetcher.CertBundleFetcher) (controller.Controller, error) {
+	reconciler := &DownloadReconciler{
+		client:              mgr.GetClient(),
+		scheme:              mgr.GetScheme(),
+		log:                 log.WithName("download-controller"),
+		image:               downloadImage,
+		verbose:             verbose,
+		pullPolicy:          pullPolicy,
+		recorder:            mgr.GetEventRecorderFor("download-controller"),
+		serverCertGenerator: serverCertGenerator,
+		clientCAFetcher:     clientCAFetcher,
+	}
+	downloadController, err := controller.New("download-controller", mgr, controller.Options{
+		Reconciler: reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addDownloadControllerWatches(downloadController); err != nil {
+		return nil, err
+	}
+
+	return downloadController, nil
+}
+
+func addDownloadControllerWatches(downloadController controller.Controller) error {
+	if err := downloadController.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := downloadController.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &corev1.PersistentVolumeClaim{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
+	if err := downloadController.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &corev1.PersistentVolumeClaim{},
+		IsController: true,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createDownloadResourceName returns the name given to download resources
+func createDownloadResourceName(name string) string {
+	return naming.GetResourceName("cdi-download", name)
+}
+
+// DownloadPossibleForPVC is called by the upload proxy to see whether a PVC may be downloaded
+func DownloadPossibleForPVC(pvc *corev1.PersistentVolumeClaim) error {
+	if _, ok := pvc.Annotations[common.AnnDownloadRequest]; !ok {
+		return errors.Errorf("PVC %s is not a download source", pvc.Name)
+	}
+	return nil
+}
+
+// GetDownloadServerURL returns the url the proxy should get from for a particular pvc
+func GetDownloadServerURL(namespace, pvc, downloadPath string) string {
+	serviceName := naming.GetServiceNameFromResourceName(createDownloadResourceName(pvc))
+	return fmt.Sprintf("https://%s.%s.svc%s", serviceName, namespace, downloadPath)
+}
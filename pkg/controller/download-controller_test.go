@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+)
+
+var (
+	downloadLog = logf.Log.WithName("download-controller-test")
+)
+
+var _ = Describe("Download controller reconcile loop", func() {
+	pvcKey := types.NamespacedName{Name: "testPvc1", Namespace: "default"}
+	resourceKey := types.NamespacedName{Name: "cdi-download-testPvc1", Namespace: "default"}
+
+	listPods := func(reconciler *DownloadReconciler) []corev1.Pod {
+		podList := &corev1.PodList{}
+		err := reconciler.client.List(context.TODO(), podList, &client.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return podList.Items
+	}
+
+	It("Should return nil and not create a pod, if pvc can not be found", func() {
+		reconciler := createDownloadReconciler()
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listPods(reconciler)).To(BeEmpty())
+	})
+
+	It("Should not create a pod, if the download annotation does not exist", func() {
+		reconciler := createDownloadReconciler(createPvc("testPvc1", "default", map[string]string{}, nil))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listPods(reconciler)).To(BeEmpty())
+	})
+
+	It("Should not create a pod, if the pvc is not bound", func() {
+		reconciler := createDownloadReconciler(createPendingPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listPods(reconciler)).To(BeEmpty())
+	})
+
+	It("Should requeue and not create a pod if the pvc is written by another pod", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil)
+		reconciler := createDownloadReconciler(pvc, podUsingPVC(pvc, false))
+		result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(listPods(reconciler)).To(HaveLen(1))
+		By("Checking events recorded")
+		close(reconciler.recorder.(*record.FakeRecorder).Events)
+		found := false
+		for event := range reconciler.recorder.(*record.FakeRecorder).Events {
+			if strings.Contains(event, DownloadSourceInUse) {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("Should create a read only pod and a service for a filesystem pvc", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil)
+		reconciler := createDownloadReconciler(pvc, podUsingPVC(pvc, true))
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), resourceKey, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Command[0]).To(Equal(downloadServerCommand))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+		Expect(pod.Spec.Volumes[1].EmptyDir).ToNot(BeNil())
+		scratchSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		Expect(pod.Spec.Volumes[1].EmptyDir.SizeLimit.Cmp(scratchSize)).To(Equal(0))
+		Expect(pod.Spec.Containers[0].Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(scratchSize))
+		Expect(*pod.Spec.SecurityContext.RunAsUser).To(Equal(int64(0)))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      DataVolName,
+			MountPath: common.UploadServerDataDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      ScratchVolName,
+			MountPath: common.DownloadServerScratchDir,
+		}))
+		Expect(pod.Labels[common.CDIComponentLabel]).To(Equal(common.DownloadServerCDILabel))
+
+		service := &corev1.Service{}
+		err = reconciler.client.Get(context.TODO(), resourceKey, service)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(service.Spec.Selector[common.UploadServerServiceLabel]).To(Equal(pod.Labels[common.UploadServerServiceLabel]))
+
+		updatedPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), pvcKey, updatedPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedPvc.Annotations[AnnDownloadPodReady]).To(Equal("false"))
+	})
+
+	It("Should expose the block device for a block pvc", func() {
+		pvc := createBlockPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil)
+		reconciler := createDownloadReconciler(pvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), resourceKey, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeDevices).To(Equal([]corev1.VolumeDevice{
+			{
+				Name:       DataVolName,
+				DevicePath: common.WriteBlockPath,
+			},
+		}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "SOURCE", Value: common.WriteBlockPath}))
+	})
+
+	It("Should delete the pod and service when the download annotation is removed", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil)
+		reconciler := createDownloadReconciler(pvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listPods(reconciler)).To(HaveLen(1))
+
+		err = reconciler.client.Get(context.TODO(), pvcKey, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		delete(pvc.Annotations, common.AnnDownloadRequest)
+		err = reconciler.client.Update(context.TODO(), pvc)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listPods(reconciler)).To(BeEmpty())
+		err = reconciler.client.Get(context.TODO(), resourceKey, &corev1.Service{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		updatedPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), pvcKey, updatedPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedPvc.Annotations).ToNot(HaveKey(AnnDownloadPodReady))
+	})
+
+	table.DescribeTable("Should handle a download pod that stopped after being idle", func(requestedAfterStop, expectRequest bool) {
+		finished := time.Now().Add(-time.Minute)
+		requested := finished.Add(-time.Minute)
+		if requestedAfterStop {
+			requested = finished.Add(time.Minute)
+		}
+		pvc := createPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: requested.UTC().Format(time.RFC3339)}, nil)
+		reconciler := createDownloadReconciler(pvc)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		pod := &corev1.Pod{}
+		err = reconciler.client.Get(context.TODO(), resourceKey, pod)
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(finished)},
+				},
+			},
+		}
+		err = reconciler.client.Update(context.TODO(), pod)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: pvcKey})
+		Expect(err).ToNot(HaveOccurred())
+		updatedPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), pvcKey, updatedPvc)
+		Expect(err).ToNot(HaveOccurred())
+		if expectRequest {
+			Expect(updatedPvc.Annotations).To(HaveKey(common.AnnDownloadRequest))
+			Expect(listPods(reconciler)).To(BeEmpty())
+		} else {
+			Expect(updatedPvc.Annotations).ToNot(HaveKey(common.AnnDownloadRequest))
+		}
+	},
+		table.Entry("by removing the download request", false, false),
+		table.Entry("by restarting the pod when a token was requested after it stopped", true, true),
+	)
+})
+
+var _ = Describe("Download helpers", func() {
+	It("Should only allow download of annotated pvcs", func() {
+		Expect(DownloadPossibleForPVC(createPvc("testPvc1", "default", map[string]string{common.AnnDownloadRequest: ""}, nil))).To(Succeed())
+		Expect(DownloadPossibleForPVC(createPvc("testPvc1", "default", map[string]string{}, nil))).ToNot(Succeed())
+	})
+
+	It("Should return the url of the download service", func() {
+		url := GetDownloadServerURL("default", "testPvc1", common.DownloadPathRaw)
+		Expect(url).To(Equal("https://cdi-download-testPvc1.default.svc/v1beta1/download/raw"))
+	})
+})
+
+func createDownloadReconciler(objects ...runtime.Object) *DownloadReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+	objs = append(objs, MakeEmptyCDICR())
+	cdiConfig := MakeEmptyCDIConfigSpec(common.ConfigName)
+	cdiConfig.Status = cdiv1.CDIConfigStatus{
+		DefaultPodResourceRequirements: createDefaultPodResourceRequirements("", "", "", ""),
+	}
+	objs = append(objs, cdiConfig)
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+
+	return &DownloadReconciler{
+		client:              fake.NewFakeClientWithScheme(s, objs...),
+		scheme:              s,
+		log:                 downloadLog,
+		serverCertGenerator: &fakeCertGenerator{},
+		clientCAFetcher:     &fetcher.MemCertBundleFetcher{Bundle: []byte("baz")},
+		recorder:            record.NewFakeRecorder(10),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"time"
@@ -30,6 +31,8 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
 	"kubevirt.io/containerized-data-importer/pkg/util/naming"
 )
 
@@ -75,6 +78,16 @@ const (
 	AnnThumbprint = AnnAPIGroup + "/storage.import.vddk.thumbprint"
 	// AnnPreallocationApplied provides a const for PVC preallocation annotation
 	AnnPreallocationApplied = AnnAPIGroup + "/storage.preallocation"
+	// AnnRegistryDiskPath provides a const for our PVC registry disk image file path annotation
+	AnnRegistryDiskPath = AnnAPIGroup + "/storage.import.registryDiskPath"
+	// AnnRequiredDigest provides a const for our PVC required registry image digest annotation
+	AnnRequiredDigest = AnnAPIGroup + "/storage.import.requiredDigest"
+	// AnnSignaturePublicKeyConfigMap provides a const for our PVC signature public key configmap annotation
+	AnnSignaturePublicKeyConfigMap = AnnAPIGroup + "/storage.import.signaturePublicKeyConfigMap"
+	// AnnSignaturePolicyConfigMap provides a const for our PVC signature policy configmap annotation
+	AnnSignaturePolicyConfigMap = AnnAPIGroup + "/storage.import.signaturePolicyConfigMap"
+	// AnnImportParallelism provides a const for our PVC/DV annotation overriding the number of concurrent http range requests
+	AnnImportParallelism = AnnAPIGroup + "/storage.import.parallelism"
 
 	//LabelImportPvc is a pod label used to find the import pod that was created by the relevant PVC
 	LabelImportPvc = AnnAPIGroup + "/storage.import.importPvcName"
@@ -105,6 +118,9 @@ type ImportReconciler struct {
 	pullPolicy         string
 	filesystemOverhead string
 	featureGates       featuregates.FeatureGates
+	// serverCertGenerator and clientCAFetcher secure the progress endpoint of the importer pods
+	serverCertGenerator generator.CertGenerator
+	clientCAFetcher     fetcher.CertBundleFetcher
 }
 
 type importPodEnvVar struct {
@@ -128,10 +144,17 @@ type importPodEnvVar struct {
 	httpsProxy         string
 	noProxy            string
 	certConfigMapProxy string
+	registryDiskPath   string
+	requiredDigest     string
+	publicKeyConfigMap string
+	policyConfigMap    string
+	checksum           string
+	parallelism        int32
+	progressTLS        *progressServerTLS
 }
 
 // NewImportController creates a new instance of the import controller.
-func NewImportController(mgr manager.Manager, log logr.Logger, importerImage, pullPolicy, verbose string) (controller.Controller, error) {
+func NewImportController(mgr manager.Manager, log logr.Logger, importerImage, pullPolicy, verbose string, serverCertGenerator generator.CertGenerator, clientCAFetcher fetcher.CertBundleFetcher) (controller.Controller, error) {
 	uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
 		Scheme: mgr.GetScheme(),
 		Mapper: mgr.GetRESTMapper(),
@@ -147,6 +170,9 @@ func NewImportController(mgr manager.Manager, log logr.Logger, importerImage, pu
 		pullPolicy:     pullPolicy,
 		recorder:       mgr.GetEventRecorderFor("import-controller"),
 		featureGates:   featuregates.NewFeatureGates(client),
+
+		serverCertGenerator: serverCertGenerator,
+		clientCAFetcher:     clientCAFetcher,
 	}
 	importController, err := controller.New("import-controller", mgr, controller.Options{
 		Reconciler: reconciler,
@@ -504,6 +530,12 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		podEnvVar.previousCheckpoint = getValueFromAnnotation(pvc, AnnPreviousCheckpoint)
 		podEnvVar.currentCheckpoint = getValueFromAnnotation(pvc, AnnCurrentCheckpoint)
 		podEnvVar.finalCheckpoint = getValueFromAnnotation(pvc, AnnFinalCheckpoint)
+		podEnvVar.registryDiskPath = getValueFromAnnotation(pvc, AnnRegistryDiskPath)
+		podEnvVar.requiredDigest = getValueFromAnnotation(pvc, AnnRequiredDigest)
+		podEnvVar.publicKeyConfigMap = getValueFromAnnotation(pvc, AnnSignaturePublicKeyConfigMap)
+		podEnvVar.policyConfigMap = getValueFromAnnotation(pvc, AnnSignaturePolicyConfigMap)
+		podEnvVar.checksum = getValueFromAnnotation(pvc, AnnChecksum)
+		podEnvVar.parallelism = r.getImportParallelism(pvc, cdiConfig)
 
 		var field string
 		if field, err = GetImportProxyConfig(cdiConfig, common.ImportProxyHTTP); err != nil {
@@ -539,6 +571,11 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 	if err != nil {
 		return nil, err
 	}
+
+	podEnvVar.progressTLS, err = newProgressServerTLS(r.serverCertGenerator, r.clientCAFetcher, pvc.Namespace, pvc.Annotations[AnnImportPod])
+	if err != nil {
+		return nil, err
+	}
 	return podEnvVar, nil
 }
 
@@ -953,6 +990,27 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 		pod.Spec.Volumes = append(pod.Spec.Volumes, createProxyConfigMapVolume(CertVolName, podEnvVar.certConfigMapProxy))
 	}
 
+	if signatureConfigMap := getSignatureConfigMap(podEnvVar); signatureConfigMap != "" {
+		vm := corev1.VolumeMount{
+			Name:      SignatureVolName,
+			MountPath: common.ImporterSignatureDir,
+		}
+
+		vol := corev1.Volume{
+			Name: SignatureVolName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: signatureConfigMap,
+					},
+				},
+			},
+		}
+
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, vm)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, vol)
+	}
+
 	if podEnvVar.contentType == string(cdiv1.DataVolumeKubeVirt) {
 		// Set the fsGroup on the security context to the QemuSubGid
 		if pod.Spec.SecurityContext == nil {
@@ -965,6 +1023,14 @@ func makeImporterPodSpec(namespace, image, verbose, pullPolicy string, podEnvVar
 	return pod
 }
 
+// getSignatureConfigMap returns the name of the configmap holding the image signature verification policy, if any
+func getSignatureConfigMap(podEnvVar *importPodEnvVar) string {
+	if podEnvVar.publicKeyConfigMap != "" {
+		return podEnvVar.publicKeyConfigMap
+	}
+	return podEnvVar.policyConfigMap
+}
+
 func createProxyConfigMapVolume(certVolName, objRef string) corev1.Volume {
 	return corev1.Volume{
 		Name: CertVolName,
@@ -1101,5 +1167,64 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 			Value: common.ImporterProxyCertDir,
 		})
 	}
+	if podEnvVar.registryDiskPath != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRegistryDiskPath,
+			Value: podEnvVar.registryDiskPath,
+		})
+	}
+	if podEnvVar.requiredDigest != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterRequiredDigest,
+			Value: podEnvVar.requiredDigest,
+		})
+	}
+	if podEnvVar.publicKeyConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignaturePublicKey,
+			Value: filepath.Join(common.ImporterSignatureDir, common.SignaturePublicKeyConfigMapKey),
+		})
+	} else if podEnvVar.policyConfigMap != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignaturePolicy,
+			Value: filepath.Join(common.ImporterSignatureDir, common.SignaturePolicyConfigMapKey),
+		})
+	}
+	if podEnvVar.checksum != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterChecksum,
+			Value: podEnvVar.checksum,
+		})
+	}
+	if podEnvVar.parallelism > 1 {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterParallelism,
+			Value: strconv.Itoa(int(podEnvVar.parallelism)),
+		})
+	}
+	env = append(env, podEnvVar.progressTLS.envVars()...)
 	return env
 }
+
+// getImportParallelism returns the number of concurrent range requests the importer may use for http downloads. The
+// PVC annotation, copied from the DataVolume, takes precedence over the CDIConfig default. The result is limited to
+// common.MaxImportParallelism.
+func (r *ImportReconciler) getImportParallelism(pvc *corev1.PersistentVolumeClaim, cdiConfig *cdiv1.CDIConfig) int32 {
+	if value, ok := pvc.Annotations[AnnImportParallelism]; ok {
+		if parallelism, err := strconv.ParseInt(value, 10, 32); err == nil && parallelism > 0 {
+			return limitImportParallelism(int32(parallelism))
+		}
+		r.log.V(1).Info("Ignoring invalid import parallelism annotation", "value", value, "PVC", pvc.Name)
+	}
+	if cdiConfig.Spec.ImportParallelism != nil && *cdiConfig.Spec.ImportParallelism > 0 {
+		return limitImportParallelism(*cdiConfig.Spec.ImportParallelism)
+	}
+	return 1
+}
+
+func limitImportParallelism(parallelism int32) int32 {
+	if parallelism > common.MaxImportParallelism {
+		return common.MaxImportParallelism
+	}
+	return parallelism
+}
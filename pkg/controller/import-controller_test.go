@@ -37,6 +37,7 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -671,6 +672,59 @@ var _ = Describe("Update PVC from POD", func() {
 		Expect(resPvc.GetAnnotations()[AnnVddkVersion]).To(Equal("1.0.0"))
 	})
 
+	It("Should copy the resolved image digest to annotations on PVC", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnSource: SourceRegistry}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+							Message:  "Import Complete; Image digest: sha256:" + strings.Repeat("a", 64),
+							Reason:   "Completed",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnImageDigest]).To(Equal("sha256:" + strings.Repeat("a", 64)))
+	})
+
+	It("Should copy the computed checksum to annotations on PVC", func() {
+		pvc := createPvcInStorageClass("testPvc1", "default", &testStorageClass, map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodRunning), AnnSource: SourceHTTP}, nil, corev1.ClaimBound)
+		pod := createImporterTestPod(pvc, "testPvc1", nil)
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+							Message:  "Import Complete, " + common.PreallocationApplied + "; Checksum: md5:" + strings.Repeat("b", 32),
+							Reason:   "Completed",
+						},
+					},
+				},
+			},
+		}
+		reconciler = createImportReconciler(pvc, pod)
+		err := reconciler.updatePvcFromPod(pvc, pod, reconciler.log)
+		Expect(err).ToNot(HaveOccurred())
+		resPvc := &corev1.PersistentVolumeClaim{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, resPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resPvc.GetAnnotations()[AnnComputedChecksum]).To(Equal("md5:" + strings.Repeat("b", 32)))
+		Expect(resPvc.GetAnnotations()[AnnPreallocationApplied]).To(Equal("true"))
+	})
+
 })
 
 var _ = Describe("Create Importer Pod", func() {
@@ -730,9 +784,113 @@ var _ = Describe("Create Importer Pod", func() {
 	)
 })
 
+var _ = Describe("Create Importer Pod with signature verification", func() {
+	It("should mount the signature verification ConfigMap", func() {
+		pvc := createPvc("testPvc1", "default", map[string]string{AnnEndpoint: testEndPoint, AnnPodPhase: string(corev1.PodPending), AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar := &importPodEnvVar{
+			source:             SourceRegistry,
+			imageSize:          "1G",
+			filesystemOverhead: "0.055",
+			publicKeyConfigMap: "cosign-key",
+		}
+		pod, err := createImporterPod(reconciler.log, reconciler.client, testImage, "5", testPullPolicy, podEnvVar, pvc, nil, nil, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: SignatureVolName, MountPath: common.ImporterSignatureDir}))
+		found := false
+		for _, vol := range pod.Spec.Volumes {
+			if vol.Name == SignatureVolName {
+				Expect(vol.ConfigMap).ToNot(BeNil())
+				Expect(vol.ConfigMap.Name).To(Equal("cosign-key"))
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+})
+
 var _ = Describe("Import test env", func() {
 	const mockUID = "1111-1111-1111-1111"
 
+	It("Should pass the registry disk path to the importer", func() {
+		testEnvVar := &importPodEnvVar{
+			source:           SourceRegistry,
+			registryDiskPath: "disk/data.qcow2",
+		}
+		Expect(makeImportEnv(testEnvVar, mockUID)).To(ContainElement(corev1.EnvVar{Name: common.ImporterRegistryDiskPath, Value: "disk/data.qcow2"}))
+	})
+
+	It("Should pass the image verification requirements to the importer", func() {
+		testEnvVar := &importPodEnvVar{
+			source:          SourceRegistry,
+			requiredDigest:  "sha256:" + strings.Repeat("a", 64),
+			policyConfigMap: "policy",
+		}
+		env := makeImportEnv(testEnvVar, mockUID)
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterRequiredDigest, Value: testEnvVar.requiredDigest}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSignaturePolicy, Value: "/signature/policy.json"}))
+		for _, envVar := range env {
+			Expect(envVar.Name).ToNot(Equal(common.ImporterSignaturePublicKey))
+		}
+	})
+
+	It("Should pass the expected checksum to the importer", func() {
+		testEnvVar := &importPodEnvVar{
+			source:   SourceHTTP,
+			checksum: "sha256:" + strings.Repeat("a", 64),
+		}
+		Expect(makeImportEnv(testEnvVar, mockUID)).To(ContainElement(corev1.EnvVar{Name: common.ImporterChecksum, Value: testEnvVar.checksum}))
+	})
+
+	It("Should pass the parallelism to the importer only when downloading concurrently", func() {
+		testEnvVar := &importPodEnvVar{
+			source:      SourceHTTP,
+			parallelism: 4,
+		}
+		Expect(makeImportEnv(testEnvVar, mockUID)).To(ContainElement(corev1.EnvVar{Name: common.ImporterParallelism, Value: "4"}))
+		testEnvVar.parallelism = 1
+		for _, envVar := range makeImportEnv(testEnvVar, mockUID) {
+			Expect(envVar.Name).ToNot(Equal(common.ImporterParallelism))
+		}
+	})
+
+	It("Should pass the progress endpoint certificates to the importer", func() {
+		progressTLS, err := newProgressServerTLS(&fakeCertGenerator{}, &fetcher.MemCertBundleFetcher{Bundle: []byte("baz")}, "default", "importer-testPvc1")
+		Expect(err).ToNot(HaveOccurred())
+		testEnvVar := &importPodEnvVar{
+			source:      SourceHTTP,
+			progressTLS: progressTLS,
+		}
+		env := makeImportEnv(testEnvVar, mockUID)
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressServerKey, Value: "bar"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressServerCert, Value: "foo"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressClientCA, Value: "baz"}))
+	})
+
+	int32Ptr := func(value int32) *int32 {
+		return &value
+	}
+
+	table.DescribeTable("Should get the import parallelism", func(annotation string, configParallelism *int32, expected int32) {
+		annotations := map[string]string{AnnEndpoint: testEndPoint}
+		if annotation != "" {
+			annotations[AnnImportParallelism] = annotation
+		}
+		pvc := createPvc("testPvc1", "default", annotations, nil)
+		cdiConfig := createCDIConfig(common.ConfigName)
+		cdiConfig.Spec.ImportParallelism = configParallelism
+		reconciler := createImportReconciler(pvc)
+		Expect(reconciler.getImportParallelism(pvc, cdiConfig)).To(Equal(expected))
+	},
+		table.Entry("default", "", nil, int32(1)),
+		table.Entry("from the CDIConfig", "", int32Ptr(8), int32(8)),
+		table.Entry("from the annotation", "2", int32Ptr(8), int32(2)),
+		table.Entry("ignoring an invalid annotation", "many", int32Ptr(8), int32(8)),
+		table.Entry("ignoring a zero annotation", "0", nil, int32(1)),
+		table.Entry("limiting the annotation", "1000", nil, int32(common.MaxImportParallelism)),
+		table.Entry("limiting the CDIConfig", "", int32Ptr(1000), int32(common.MaxImportParallelism)),
+	)
+
 	It("Should create import env", func() {
 		testEnvVar := &importPodEnvVar{
 			ep:                 "myendpoint",
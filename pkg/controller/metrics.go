@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// annPhaseTransitionTime is the time the DataVolume entered its current phase
+	annPhaseTransitionTime = "cdi.kubevirt.io/phaseTransitionTime"
+
+	// DataVolume source types of the metrics
+	metricsSourceHTTP       = "http"
+	metricsSourceS3         = "s3"
+	metricsSourceRegistry   = "registry"
+	metricsSourceImageIO    = "imageio"
+	metricsSourceVDDK       = "vddk"
+	metricsSourceUpload     = "upload"
+	metricsSourceBlank      = "blank"
+	metricsSourceSnapshot   = "snapshot"
+	metricsSourcePVCClone   = "pvc-clone"
+	metricsSourceSmartClone = "smart-clone"
+	metricsSourceCSIClone   = "csi-clone"
+)
+
+var (
+	dataVolumePhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_cdi_datavolume_phase_duration_seconds",
+			Help:    "The time DataVolumes spent in a phase",
+			Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400, 28800, 86400},
+		},
+		[]string{"phase", "source"},
+	)
+	dataVolumeFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_cdi_datavolume_failures_total",
+			Help: "The number of DataVolume failures",
+		},
+		[]string{"reason", "source"},
+	)
+
+	dataVolumesInProgressDesc = prometheus.NewDesc(
+		"kubevirt_cdi_datavolumes_in_progress",
+		"The number of DataVolumes being populated",
+		[]string{"namespace", "storage_class", "source"}, nil,
+	)
+	scratchPvcsDesc = prometheus.NewDesc(
+		"kubevirt_cdi_scratch_pvcs",
+		"The number of scratch space PVCs",
+		[]string{"namespace", "storage_class"}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(dataVolumePhaseDuration, dataVolumeFailures)
+}
+
+// getDataVolumeSourceType returns the source type label of the DataVolume metrics
+func getDataVolumeSourceType(dv *cdiv1.DataVolume) string {
+	source := dv.Spec.Source
+	if source == nil {
+		// the DataSource of a source reference is a PVC
+		return getCloneSourceType(dv)
+	}
+	switch {
+	case source.HTTP != nil:
+		return metricsSourceHTTP
+	case source.S3 != nil:
+		return metricsSourceS3
+	case source.Registry != nil:
+		return metricsSourceRegistry
+	case source.Imageio != nil:
+		return metricsSourceImageIO
+	case source.VDDK != nil:
+		return metricsSourceVDDK
+	case source.Upload != nil:
+		return metricsSourceUpload
+	case source.Blank != nil:
+		return metricsSourceBlank
+	case source.Snapshot != nil:
+		return metricsSourceSnapshot
+	case source.PVC != nil:
+		return getCloneSourceType(dv)
+	}
+	return ""
+}
+
+// getCloneSourceType returns the source of a PVC clone by the clone type recorded on the DataVolume
+func getCloneSourceType(dv *cdiv1.DataVolume) string {
+	switch dv.Annotations[annCloneType] {
+	case cloneTypeSnapshot:
+		return metricsSourceSmartClone
+	case cloneTypeCsiClone:
+		return metricsSourceCSIClone
+	}
+	return metricsSourcePVCClone
+}
+
+// getPhaseTransitionTime returns the time the DataVolume entered its current phase
+func getPhaseTransitionTime(dv *cdiv1.DataVolume) time.Time {
+	if value, ok := dv.Annotations[annPhaseTransitionTime]; ok {
+		if transitionTime, err := time.Parse(time.RFC3339, value); err == nil {
+			return transitionTime
+		}
+	}
+	return dv.CreationTimestamp.Time
+}
+
+// setPhaseTransitionTime records the time the DataVolume enters a new phase
+func setPhaseTransitionTime(dv *cdiv1.DataVolume, transitionTime time.Time) {
+	addAnnotation(dv, annPhaseTransitionTime, transitionTime.UTC().Format(time.RFC3339))
+}
+
+// observePhaseDuration records the time the DataVolume spent in the phase it is leaving
+func observePhaseDuration(dv *cdiv1.DataVolume, phase cdiv1.DataVolumePhase, since, until time.Time) {
+	if phase == cdiv1.PhaseUnset || since.IsZero() || until.Before(since) {
+		return
+	}
+	dataVolumePhaseDuration.WithLabelValues(string(phase), getDataVolumeSourceType(dv)).Observe(until.Sub(since).Seconds())
+}
+
+// countDataVolumeFailure records a failure of the DataVolume population
+func countDataVolumeFailure(dv *cdiv1.DataVolume, reason string) {
+	dataVolumeFailures.WithLabelValues(reason, getDataVolumeSourceType(dv)).Inc()
+}
+
+// isDataVolumeInProgress returns true if CDI is populating the DataVolume
+func isDataVolumeInProgress(dv *cdiv1.DataVolume) bool {
+	switch dv.Status.Phase {
+	case cdiv1.Succeeded, cdiv1.Failed, cdiv1.Paused, cdiv1.Unknown:
+		return false
+	}
+	return true
+}
+
+// isScratchPvc returns true if the PVC is the scratch space of a CDI pod
+func isScratchPvc(pvc *corev1.PersistentVolumeClaim) bool {
+	if pvc.Labels[common.CDILabelKey] != common.CDILabelValue {
+		return false
+	}
+	for _, ref := range pvc.OwnerReferences {
+		if ref.Kind == "Pod" && ref.Controller != nil && *ref.Controller {
+			return true
+		}
+	}
+	return false
+}
+
+// dataVolumeCollector counts the DataVolumes in progress and the scratch PVCs when the metrics are scraped, so the
+// gauges always match the cluster state
+type dataVolumeCollector struct {
+	client client.Client
+}
+
+func newDataVolumeCollector(client client.Client) *dataVolumeCollector {
+	return &dataVolumeCollector{client: client}
+}
+
+// Describe implements prometheus.Collector
+func (c *dataVolumeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dataVolumesInProgressDesc
+	ch <- scratchPvcsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *dataVolumeCollector) Collect(ch chan<- prometheus.Metric) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := c.client.List(context.TODO(), pvcs); err != nil {
+		klog.Errorf("Unable to list PVCs for metrics: %v", err)
+		return
+	}
+	dataVolumes := &cdiv1.DataVolumeList{}
+	if err := c.client.List(context.TODO(), dataVolumes); err != nil {
+		klog.Errorf("Unable to list DataVolumes for metrics: %v", err)
+		return
+	}
+
+	pvcStorageClasses := make(map[string]string)
+	scratchPvcs := make(map[[2]string]int)
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		storageClass := ""
+		if pvc.Spec.StorageClassName != nil {
+			storageClass = *pvc.Spec.StorageClassName
+		}
+		pvcStorageClasses[pvc.Namespace+"/"+pvc.Name] = storageClass
+		if isScratchPvc(pvc) {
+			scratchPvcs[[2]string{pvc.Namespace, storageClass}]++
+		}
+	}
+
+	inProgress := make(map[[3]string]int)
+	for i := range dataVolumes.Items {
+		dv := &dataVolumes.Items[i]
+		if !isDataVolumeInProgress(dv) {
+			continue
+		}
+		// the PVC has the storage class picked for the DataVolume, the spec may leave it to the default
+		storageClass, ok := pvcStorageClasses[dv.Namespace+"/"+GetDataVolumeClaimName(dv)]
+		if !ok {
+			storageClass = getDataVolumeStorageClass(dv)
+		}
+		inProgress[[3]string{dv.Namespace, storageClass, getDataVolumeSourceType(dv)}]++
+	}
+
+	for labels, count := range inProgress {
+		ch <- prometheus.MustNewConstMetric(dataVolumesInProgressDesc, prometheus.GaugeValue, float64(count), labels[:]...)
+	}
+	for labels, count := range scratchPvcs {
+		ch <- prometheus.MustNewConstMetric(scratchPvcsDesc, prometheus.GaugeValue, float64(count), labels[:]...)
+	}
+}
+
+func getDataVolumeStorageClass(dv *cdiv1.DataVolume) string {
+	if dv.Spec.PVC != nil && dv.Spec.PVC.StorageClassName != nil {
+		return *dv.Spec.PVC.StorageClassName
+	}
+	if dv.Spec.Storage != nil && dv.Spec.Storage.StorageClassName != nil {
+		return *dv.Spec.Storage.StorageClassName
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("DataVolume metrics", func() {
+	withCloneType := func(dv *cdiv1.DataVolume, cloneType string) *cdiv1.DataVolume {
+		addAnnotation(dv, annCloneType, cloneType)
+		return dv
+	}
+
+	withoutSource := func(dv *cdiv1.DataVolume) *cdiv1.DataVolume {
+		dv.Spec.Source = nil
+		return dv
+	}
+
+	table.DescribeTable("should report the source type", func(dv *cdiv1.DataVolume, expected string) {
+		Expect(getDataVolumeSourceType(dv)).To(Equal(expected))
+	},
+		table.Entry("of an http import", newImportDataVolume("test"), metricsSourceHTTP),
+		table.Entry("of an upload", newUploadDataVolume("test"), metricsSourceUpload),
+		table.Entry("of a blank image", newBlankImageDataVolume("test"), metricsSourceBlank),
+		table.Entry("of a host assisted clone", newCloneDataVolume("test"), metricsSourcePVCClone),
+		table.Entry("of a smart clone", withCloneType(newCloneDataVolume("test"), cloneTypeSnapshot), metricsSourceSmartClone),
+		table.Entry("of a csi clone", withCloneType(newCloneDataVolume("test"), cloneTypeCsiClone), metricsSourceCSIClone),
+		table.Entry("of a source reference", withoutSource(newCloneDataVolume("test")), metricsSourcePVCClone),
+	)
+
+	phaseDurationCount := func(phase cdiv1.DataVolumePhase, source string) uint64 {
+		metric := &dto.Metric{}
+		err := dataVolumePhaseDuration.WithLabelValues(string(phase), source).(prometheus.Histogram).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	phaseDurationSum := func(phase cdiv1.DataVolumePhase, source string) float64 {
+		metric := &dto.Metric{}
+		err := dataVolumePhaseDuration.WithLabelValues(string(phase), source).(prometheus.Histogram).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		return metric.GetHistogram().GetSampleSum()
+	}
+
+	failureCount := func(reason, source string) float64 {
+		metric := &dto.Metric{}
+		err := dataVolumeFailures.WithLabelValues(reason, source).Write(metric)
+		Expect(err).ToNot(HaveOccurred())
+		return metric.GetCounter().GetValue()
+	}
+
+	Context("on a phase change", func() {
+		var (
+			reconciler *DatavolumeReconciler
+			dv         *cdiv1.DataVolume
+		)
+
+		BeforeEach(func() {
+			dv = newImportDataVolume("test")
+			dv.Status.Phase = cdiv1.ImportScheduled
+			setPhaseTransitionTime(dv, time.Now().Add(-time.Minute))
+			reconciler = createDatavolumeReconciler(dv)
+		})
+
+		AfterEach(func() {
+			close(reconciler.recorder.(*record.FakeRecorder).Events)
+		})
+
+		It("should observe the time spent in the previous phase", func() {
+			count := phaseDurationCount(cdiv1.ImportScheduled, metricsSourceHTTP)
+			sum := phaseDurationSum(cdiv1.ImportScheduled, metricsSourceHTTP)
+			dataVolumeCopy := dv.DeepCopy()
+			dataVolumeCopy.Status.Phase = cdiv1.ImportInProgress
+			err := reconciler.emitEvent(dv, dataVolumeCopy, dv.Status.Phase, nil, &DataVolumeEvent{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phaseDurationCount(cdiv1.ImportScheduled, metricsSourceHTTP)).To(Equal(count + 1))
+			Expect(phaseDurationSum(cdiv1.ImportScheduled, metricsSourceHTTP) - sum).To(BeNumerically("~", 60, 5))
+			Expect(time.Since(getPhaseTransitionTime(dataVolumeCopy))).To(BeNumerically("<", 5*time.Second))
+		})
+
+		It("should not observe an unchanged phase", func() {
+			count := phaseDurationCount(cdiv1.ImportScheduled, metricsSourceHTTP)
+			dataVolumeCopy := dv.DeepCopy()
+			dataVolumeCopy.Status.Progress = "10.00%"
+			err := reconciler.emitEvent(dv, dataVolumeCopy, dv.Status.Phase, nil, &DataVolumeEvent{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phaseDurationCount(cdiv1.ImportScheduled, metricsSourceHTTP)).To(Equal(count))
+			Expect(dataVolumeCopy.Annotations[annPhaseTransitionTime]).To(Equal(dv.Annotations[annPhaseTransitionTime]))
+		})
+
+		It("should count a failure", func() {
+			count := failureCount(ImportFailed, metricsSourceHTTP)
+			dataVolumeCopy := dv.DeepCopy()
+			dataVolumeCopy.Status.Phase = cdiv1.Failed
+			err := reconciler.emitEvent(dv, dataVolumeCopy, dv.Status.Phase, nil, &DataVolumeEvent{
+				eventType: corev1.EventTypeWarning,
+				reason:    ImportFailed,
+				message:   "import failed",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failureCount(ImportFailed, metricsSourceHTTP)).To(Equal(count + 1))
+		})
+	})
+
+	It("should not copy the phase transition time to the PVC", func() {
+		dv := newImportDataVolume("test")
+		setPhaseTransitionTime(dv, time.Now())
+		reconciler := createDatavolumeReconciler(dv)
+		pvc, err := reconciler.newPersistentVolumeClaim(dv, dv.Spec.PVC, dv.Namespace, dv.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Annotations).ToNot(HaveKey(annPhaseTransitionTime))
+	})
+
+	It("should collect the DataVolumes in progress and the scratch PVCs", func() {
+		storageClass := "test-sc"
+		running := newImportDataVolume("running")
+		running.Status.Phase = cdiv1.ImportInProgress
+		runningPvc := createPvcInStorageClass("running", metav1.NamespaceDefault, &storageClass, nil, nil, corev1.ClaimBound)
+		pending := newUploadDataVolume("pending")
+		pending.Status.Phase = cdiv1.Pending
+		succeeded := newImportDataVolume("succeeded")
+		succeeded.Status.Phase = cdiv1.Succeeded
+
+		isController := true
+		scratchPvc := createPvcInStorageClass("running-scratch", metav1.NamespaceDefault, &storageClass, nil,
+			map[string]string{"app": "containerized-data-importer"}, corev1.ClaimBound)
+		scratchPvc.OwnerReferences = []metav1.OwnerReference{{Kind: "Pod", Name: "importer-running", Controller: &isController}}
+
+		reconciler := createDatavolumeReconciler(running, runningPvc, pending, succeeded, scratchPvc)
+		ch := make(chan prometheus.Metric, 10)
+		newDataVolumeCollector(reconciler.client).Collect(ch)
+		close(ch)
+
+		gauges := map[string]float64{}
+		for metric := range ch {
+			m := &dto.Metric{}
+			Expect(metric.Write(m)).To(Succeed())
+			labels := metric.Desc().String()
+			for _, label := range m.GetLabel() {
+				labels += "," + label.GetName() + "=" + label.GetValue()
+			}
+			gauges[labels] = m.GetGauge().GetValue()
+		}
+		Expect(gauges).To(HaveLen(3))
+		Expect(gauges).To(HaveKeyWithValue(dataVolumesInProgressDesc.String()+",namespace=default,source=http,storage_class=test-sc", float64(1)))
+		Expect(gauges).To(HaveKeyWithValue(dataVolumesInProgressDesc.String()+",namespace=default,source=upload,storage_class=", float64(1)))
+		Expect(gauges).To(HaveKeyWithValue(scratchPvcsDesc.String()+",namespace=default,storage_class=test-sc", float64(1)))
+	})
+})
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+)
+
+const (
+	// progressServerCertDuration is the lifetime of the certificate worker pods serve their progress with
+	progressServerCertDuration = 365 * 24 * time.Hour
+	// progressClientCertDuration is the lifetime of the certificate the controller reads the progress with
+	progressClientCertDuration = 24 * time.Hour
+	// progressClientRefresh is how often the client certificate and the server CA bundle are renewed, so rotated
+	// CAs are picked up
+	progressClientRefresh = time.Hour
+	// progressRequestTimeout bounds the time spent reading the progress of a pod in a reconcile
+	progressRequestTimeout = 5 * time.Second
+)
+
+// progressServerTLS is the TLS material of the endpoint a worker pod serves its progress report on
+type progressServerTLS struct {
+	key      []byte
+	cert     []byte
+	clientCA []byte
+}
+
+// newProgressServerTLS issues the serving certificate of a worker pod, the pod serves its progress report only to
+// clients with a certificate signed by the client CA
+func newProgressServerTLS(certGenerator generator.CertGenerator, clientCAFetcher fetcher.CertBundleFetcher, namespace, podName string) (*progressServerTLS, error) {
+	if certGenerator == nil || clientCAFetcher == nil {
+		return nil, nil
+	}
+
+	cert, key, err := certGenerator.MakeServerCert(namespace, podName, progressServerCertDuration)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating progress server cert")
+	}
+
+	clientCA, err := clientCAFetcher.BundleBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching progress client CA")
+	}
+
+	return &progressServerTLS{key: key, cert: cert, clientCA: clientCA}, nil
+}
+
+func (t *progressServerTLS) envVars() []corev1.EnvVar {
+	if t == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  common.ProgressServerKey,
+			Value: string(t.key),
+		},
+		{
+			Name:  common.ProgressServerCert,
+			Value: string(t.cert),
+		},
+		{
+			Name:  common.ProgressClientCA,
+			Value: string(t.clientCA),
+		},
+	}
+}
+
+// progressClient reads the progress report of worker pods, the pod is verified with the server CA bundle and the
+// client authenticates with a certificate of the progress client name
+type progressClient struct {
+	certGenerator generator.CertGenerator
+	caFetcher     fetcher.CertBundleFetcher
+
+	lock    sync.Mutex
+	cert    *tls.Certificate
+	roots   *x509.CertPool
+	renewAt time.Time
+}
+
+func newProgressClient(certGenerator generator.CertGenerator, caFetcher fetcher.CertBundleFetcher) *progressClient {
+	if certGenerator == nil || caFetcher == nil {
+		return nil
+	}
+	return &progressClient{certGenerator: certGenerator, caFetcher: caFetcher}
+}
+
+func (c *progressClient) tlsConfig(serverName string) (*tls.Config, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cert == nil || time.Now().After(c.renewAt) {
+		certBytes, keyBytes, err := c.certGenerator.MakeClientCert(common.ProgressClientName, nil, progressClientCertDuration)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating progress client cert")
+		}
+		cert, err := tls.X509KeyPair(certBytes, keyBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing progress client cert")
+		}
+
+		bundle, err := c.caFetcher.BundleBytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching progress server CA")
+		}
+		roots := x509.NewCertPool()
+		if ok := roots.AppendCertsFromPEM(bundle); !ok {
+			return nil, errors.New("invalid progress server CA")
+		}
+
+		c.cert, c.roots = &cert, roots
+		c.renewAt = time.Now().Add(progressClientRefresh)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{*c.cert},
+		RootCAs:      c.roots,
+		ServerName:   serverName,
+	}, nil
+}
+
+// getProgressReport reads the progress report of the pod, the pod serving certificate is issued for the pod name.
+// A nil report is returned if the pod does not serve a report.
+func (c *progressClient) getProgressReport(pod *corev1.Pod, port int) (*prometheusutil.ProgressReport, error) {
+	tlsConfig, err := c.tlsConfig(pod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   tlsConfig,
+			DisableKeepAlives: true,
+		},
+		Timeout: progressRequestTimeout,
+	}
+	resp, err := httpClient.Get(fmt.Sprintf("https://%s:%d%s", pod.Status.PodIP, port, common.ProgressPath))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+
+	report := &prometheusutil.ProgressReport{}
+	if err := json.NewDecoder(resp.Body).Decode(report); err != nil {
+		return nil, errors.Wrap(err, "error decoding progress report")
+	}
+	return report, nil
+}
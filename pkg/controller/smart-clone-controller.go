@@ -309,9 +309,9 @@ func (r *SmartCloneReconciler) getTargetPVC(dataVolume *cdiv1.DataVolume) (*core
 }
 
 func newPvcFromSnapshot(snapshot *snapshotv1.VolumeSnapshot, targetPvcSpec *corev1.PersistentVolumeClaimSpec) (*corev1.PersistentVolumeClaim, error) {
-	restoreSize := snapshot.Status.RestoreSize
-	if restoreSize == nil {
-		return nil, fmt.Errorf("snapshot has no RestoreSize")
+	pvcSpec, err := newSnapshotRestoreSpec(snapshot, targetPvcSpec)
+	if err != nil {
+		return nil, err
 	}
 
 	key, err := cache.MetaNamespaceKeyFunc(snapshot)
@@ -336,25 +336,13 @@ func newPvcFromSnapshot(snapshot *snapshotv1.VolumeSnapshot, targetPvcSpec *core
 				annSmartCloneSnapshot:      key,
 			},
 		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			DataSource: &corev1.TypedLocalObjectReference{
-				Name:     snapshot.Name,
-				Kind:     "VolumeSnapshot",
-				APIGroup: &snapshotv1.SchemeGroupVersion.Group,
-			},
-			VolumeMode:       targetPvcSpec.VolumeMode,
-			AccessModes:      targetPvcSpec.AccessModes,
-			StorageClassName: targetPvcSpec.StorageClassName,
-			Resources:        targetPvcSpec.Resources,
-		},
+		Spec: *pvcSpec,
 	}
 
-	if target.Spec.Resources.Requests == nil {
-		target.Spec.Resources.Requests = corev1.ResourceList{}
+	if authorization, ok := snapshot.Annotations[AnnCloneAuthorization]; ok {
+		target.Annotations[AnnCloneAuthorization] = authorization
 	}
 
-	target.Spec.Resources.Requests[corev1.ResourceStorage] = *restoreSize
-
 	ownerRef := metav1.GetControllerOf(snapshot)
 	if ownerRef != nil {
 		target.OwnerReferences = append(target.OwnerReferences, *ownerRef)
@@ -362,3 +350,31 @@ func newPvcFromSnapshot(snapshot *snapshotv1.VolumeSnapshot, targetPvcSpec *core
 
 	return target, nil
 }
+
+// newSnapshotRestoreSpec returns the spec of a PVC populated from the snapshot by the CSI driver, the PVC requests the
+// restore size of the snapshot
+func newSnapshotRestoreSpec(snapshot *snapshotv1.VolumeSnapshot, targetPvcSpec *corev1.PersistentVolumeClaimSpec) (*corev1.PersistentVolumeClaimSpec, error) {
+	if snapshot.Status == nil || snapshot.Status.RestoreSize == nil {
+		return nil, fmt.Errorf("snapshot has no RestoreSize")
+	}
+
+	pvcSpec := &corev1.PersistentVolumeClaimSpec{
+		DataSource: &corev1.TypedLocalObjectReference{
+			Name:     snapshot.Name,
+			Kind:     "VolumeSnapshot",
+			APIGroup: &snapshotv1.SchemeGroupVersion.Group,
+		},
+		VolumeMode:       targetPvcSpec.VolumeMode,
+		AccessModes:      targetPvcSpec.AccessModes,
+		StorageClassName: targetPvcSpec.StorageClassName,
+		Resources:        *targetPvcSpec.Resources.DeepCopy(),
+	}
+
+	if pvcSpec.Resources.Requests == nil {
+		pvcSpec.Resources.Requests = corev1.ResourceList{}
+	}
+
+	pvcSpec.Resources.Requests[corev1.ResourceStorage] = *snapshot.Status.RestoreSize
+
+	return pvcSpec, nil
+}
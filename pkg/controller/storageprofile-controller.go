@@ -39,6 +39,10 @@ func (r *StorageProfileReconciler) Reconcile(_ context.Context, req reconcile.Re
 
 	storageClass := &storagev1.StorageClass{}
 	if err := r.client.Get(context.TODO(), req.NamespacedName, storageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// remove the PVCs of a probe interrupted by the deletion of the storage class
+			return reconcile.Result{}, r.deleteProbePvcs(req.Name)
+		}
 		return reconcile.Result{}, err
 	}
 
@@ -56,28 +60,24 @@ func (r *StorageProfileReconciler) reconcileStorageProfile(sc *storagev1.Storage
 
 	storageProfile.Status.StorageClass = &sc.Name
 	storageProfile.Status.Provisioner = &sc.Provisioner
-	storageProfile.Status.ClaimPropertySets = storageProfile.Spec.ClaimPropertySets
 
-	var claimPropertySet *cdiv1.ClaimPropertySet
-	// right now the CDI supports only a single property set
-	if len(storageProfile.Status.ClaimPropertySets) > 0 {
-		claimPropertySet = &storageProfile.Status.ClaimPropertySets[0]
-	} else {
-		claimPropertySet = &cdiv1.ClaimPropertySet{}
+	requeueAfter, err := r.reconcileProbe(sc, storageProfile, log)
+	if err != nil {
+		log.Error(err, "Unable to probe storage class")
+		return reconcile.Result{}, err
 	}
 
-	r.reconcileAccessModes(sc, claimPropertySet)
-	r.reconcileVolumeMode(sc, claimPropertySet)
-
-	if !isClaimPropertySetEmpty(claimPropertySet) {
-		storageProfile.Status.ClaimPropertySets = []cdiv1.ClaimPropertySet{*claimPropertySet}
+	capabilities, err := r.getStorageCapabilities(sc, log)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
+	storageProfile.Status.ClaimPropertySets = r.reconcileClaimPropertySets(storageProfile, capabilities)
 
 	if err := r.updateStorageProfile(prevStorageProfile, storageProfile, log); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *StorageProfileReconciler) updateStorageProfile(prevStorageProfile runtime.Object, storageProfile *cdiv1.StorageProfile, log logr.Logger) error {
@@ -112,27 +112,80 @@ func (r *StorageProfileReconciler) getStorageProfile(sc *storagev1.StorageClass)
 	return storageProfile, prevStorageProfile, nil
 }
 
+// getStorageCapabilities returns the CDI recommendation for the storage class, the provisioner rules of the CDIConfig
+// take precedence over the rules built into CDI. Returns nil if there is no recommendation.
+func (r *StorageProfileReconciler) getStorageCapabilities(sc *storagev1.StorageClass, log logr.Logger) (*storagecapabilities.StorageCapabilities, error) {
+	var rules []cdiv1.ProvisionerCapabilities
+	cdiConfig := &cdiv1.CDIConfig{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		rules = cdiConfig.Spec.StorageCapabilities
+	}
+	// the webhook rejects invalid rules, this only protects against rules edited directly in the CDIConfig
+	if err := storagecapabilities.ValidateRules(rules); err != nil {
+		log.Error(err, "Ignoring invalid storage capabilities of CDIConfig")
+		rules = nil
+	}
+
+	capabilities, found := storagecapabilities.GetWithRules(sc, rules)
+	if !found {
+		return nil, nil
+	}
+	return &capabilities, nil
+}
+
+// reconcileClaimPropertySets returns the ranked claim property sets of the storage class. The sets of the spec are used
+// if provided, otherwise the sets detected by the probe. The properties a set does not provide are filled with the
+// detected ones and the CDI recommendation for the provisioner.
+func (r *StorageProfileReconciler) reconcileClaimPropertySets(storageProfile *cdiv1.StorageProfile, capabilities *storagecapabilities.StorageCapabilities) []cdiv1.ClaimPropertySet {
+	var claimPropertySets []cdiv1.ClaimPropertySet
+	for _, set := range storageProfile.Spec.ClaimPropertySets {
+		claimPropertySets = append(claimPropertySets, *set.DeepCopy())
+	}
+	fromSpec := len(claimPropertySets) > 0
+
+	probe := storageProfile.Status.Probe
+	if !fromSpec && isProbeCompleted(probe) {
+		for _, set := range probe.ClaimPropertySets {
+			claimPropertySets = append(claimPropertySets, *set.DeepCopy())
+		}
+	}
+	if len(claimPropertySets) == 0 {
+		claimPropertySets = []cdiv1.ClaimPropertySet{{}}
+	}
+
+	var result []cdiv1.ClaimPropertySet
+	for i := range claimPropertySets {
+		claimPropertySet := &claimPropertySets[i]
+		applyProbeResult(probe, claimPropertySet)
+		r.reconcileAccessModes(capabilities, claimPropertySet)
+		r.reconcileVolumeMode(capabilities, claimPropertySet)
+		if fromSpec || !isClaimPropertySetEmpty(claimPropertySet) {
+			result = append(result, *claimPropertySet)
+		}
+	}
+	return result
+}
+
 func isClaimPropertySetEmpty(set *cdiv1.ClaimPropertySet) bool {
 	return set == nil ||
 		(len(set.AccessModes) == 0 && set.VolumeMode == nil)
 }
 
-func (r *StorageProfileReconciler) reconcileVolumeMode(sc *storagev1.StorageClass, claimPropertySet *cdiv1.ClaimPropertySet) {
-	if claimPropertySet.VolumeMode == nil {
-		capabilities, found := storagecapabilities.Get(sc)
-		if found {
-			claimPropertySet.VolumeMode = &capabilities.VolumeMode
-		}
+func (r *StorageProfileReconciler) reconcileVolumeMode(capabilities *storagecapabilities.StorageCapabilities, claimPropertySet *cdiv1.ClaimPropertySet) {
+	if claimPropertySet.VolumeMode == nil && capabilities != nil {
+		volumeMode := capabilities.VolumeMode
+		claimPropertySet.VolumeMode = &volumeMode
 	}
 }
 
-func (r *StorageProfileReconciler) reconcileAccessModes(sc *storagev1.StorageClass, claimPropertySet *cdiv1.ClaimPropertySet) {
+func (r *StorageProfileReconciler) reconcileAccessModes(capabilities *storagecapabilities.StorageCapabilities, claimPropertySet *cdiv1.ClaimPropertySet) {
 	// reconcile accessModes
-	if len(claimPropertySet.AccessModes) == 0 {
-		capabilities, found := storagecapabilities.Get(sc)
-		if found {
-			claimPropertySet.AccessModes = []v1.PersistentVolumeAccessMode{capabilities.AccessMode}
-		}
+	if len(claimPropertySet.AccessModes) == 0 && capabilities != nil {
+		claimPropertySet.AccessModes = []v1.PersistentVolumeAccessMode{capabilities.AccessMode}
 	}
 }
 
@@ -209,5 +262,36 @@ func addStorageProfileControllerWatches(mgr manager.Manager, c controller.Contro
 	if err := c.Watch(&source.Kind{Type: &cdiv1.StorageProfile{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
 	}
+	// the provisioner rules of the CDIConfig apply to every storage class
+	if err := c.Watch(&source.Kind{Type: &cdiv1.CDIConfig{}}, handler.EnqueueRequestsFromMapFunc(
+		func(client.Object) []reconcile.Request {
+			storageClasses := &storagev1.StorageClassList{}
+			if err := mgr.GetClient().List(context.TODO(), storageClasses); err != nil {
+				log.Error(err, "Unable to list StorageClasses")
+				return nil
+			}
+			var result []reconcile.Request
+			for _, sc := range storageClasses.Items {
+				result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{Name: sc.Name}})
+			}
+			return result
+		},
+	)); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &v1.PersistentVolumeClaim{}}, handler.EnqueueRequestsFromMapFunc(
+		func(obj client.Object) []reconcile.Request {
+			pvc, ok := obj.(*v1.PersistentVolumeClaim)
+			if !ok || pvc.Spec.StorageClassName == nil {
+				return nil
+			}
+			if _, ok := pvc.Labels[common.StorageProbeLabel]; !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: *pvc.Spec.StorageClassName}}}
+		},
+	)); err != nil {
+		return err
+	}
 	return nil
 }
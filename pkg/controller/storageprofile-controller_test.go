@@ -0,0 +1,480 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	testProbeStorageClass = "probe-sc"
+	testProbeProvisioner  = "probe.csi.example.com"
+)
+
+var (
+	storageProfileLog = logf.Log.WithName("storageprofile-controller-test")
+)
+
+var _ = Describe("StorageProfile probe", func() {
+	var reconciler *StorageProfileReconciler
+	probeReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: testProbeStorageClass}}
+
+	getProfile := func() *cdiv1.StorageProfile {
+		storageProfile := &cdiv1.StorageProfile{}
+		err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testProbeStorageClass}, storageProfile)
+		Expect(err).ToNot(HaveOccurred())
+		return storageProfile
+	}
+
+	getProbePvc := func(suffix string) *v1.PersistentVolumeClaim {
+		pvc := &v1.PersistentVolumeClaim{}
+		key := types.NamespacedName{Name: getProbePvcName(testProbeStorageClass, suffix), Namespace: util.GetNamespace()}
+		err := reconciler.client.Get(context.TODO(), key, pvc)
+		Expect(err).ToNot(HaveOccurred())
+		return pvc
+	}
+
+	bindProbePvc := func(suffix string) {
+		pvc := getProbePvc(suffix)
+		pvc.Status.Phase = v1.ClaimBound
+		Expect(reconciler.client.Update(context.TODO(), pvc)).To(Succeed())
+	}
+
+	countProbePvcs := func() int {
+		pvcs := &v1.PersistentVolumeClaimList{}
+		err := reconciler.client.List(context.TODO(), pvcs, client.HasLabels{common.StorageProbeLabel})
+		Expect(err).ToNot(HaveOccurred())
+		return len(pvcs.Items)
+	}
+
+	expireProbe := func() {
+		storageProfile := getProfile()
+		storageProfile.Status.Probe.StartTime = &metav1.Time{Time: time.Now().Add(-probeTimeout - time.Minute)}
+		Expect(reconciler.client.Update(context.TODO(), storageProfile)).To(Succeed())
+	}
+
+	reconcileProfile := func() reconcile.Result {
+		result, err := reconciler.Reconcile(context.TODO(), probeReq)
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	It("Should not probe without a probe policy and remove leftover probe PVCs", func() {
+		leftover := newProbePvc(testProbeStorageClass, getProbePvcName(testProbeStorageClass, "block-rwx"), v1.PersistentVolumeBlock, v1.ReadWriteMany, "")
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), createProbeStorageProfile(""), leftover)
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(getProfile().Status.Probe).To(BeNil())
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should create a probe PVC per volume and access mode", func() {
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), createProbeStorageProfile(cdiv1.StorageProbeOnce))
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(Equal(probeRequeue))
+		Expect(countProbePvcs()).To(Equal(len(probeClaimProperties)))
+
+		pvc := getProbePvc("block-rwx")
+		Expect(*pvc.Spec.VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(pvc.Spec.AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+		Expect(*pvc.Spec.StorageClassName).To(Equal(testProbeStorageClass))
+		Expect(pvc.Labels[common.CDILabelKey]).To(Equal(common.CDILabelValue))
+		Expect(pvc.Annotations).ToNot(HaveKey(AnnSelectedNode))
+
+		probe := getProfile().Status.Probe
+		Expect(probe).ToNot(BeNil())
+		Expect(probe.Phase).To(Equal(cdiv1.StorageProbeInProgress))
+		Expect(probe.StartTime).ToNot(BeNil())
+		Expect(probe.CompletionTime).To(BeNil())
+	})
+
+	It("Should record the bound modes and clone support and remove the probe PVCs", func() {
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), createProbeStorageProfile(cdiv1.StorageProbeOnce))
+		reconcileProfile()
+		bindProbePvc("block-rwx")
+		bindProbePvc("filesystem-rwo")
+		reconcileProfile()
+
+		clone := getProbePvc(probeCloneSuffix)
+		Expect(clone.Spec.DataSource).ToNot(BeNil())
+		Expect(clone.Spec.DataSource.Name).To(Equal(getProbePvcName(testProbeStorageClass, "block-rwx")))
+		Expect(*clone.Spec.VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		bindProbePvc(probeCloneSuffix)
+
+		expireProbe()
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(countProbePvcs()).To(BeZero())
+
+		storageProfile := getProfile()
+		probe := storageProfile.Status.Probe
+		Expect(probe.Phase).To(Equal(cdiv1.StorageProbeSucceeded))
+		Expect(probe.CompletionTime).ToNot(BeNil())
+		Expect(probe.Clone).To(BeTrue())
+		Expect(probe.Expansion).To(BeTrue())
+		Expect(probe.ClaimPropertySets).To(HaveLen(2))
+		Expect(*probe.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(probe.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+		Expect(*probe.ClaimPropertySets[1].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(probe.ClaimPropertySets[1].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+
+		Expect(storageProfile.Status.ClaimPropertySets).To(HaveLen(2))
+		claimPropertySet := storageProfile.Status.ClaimPropertySets[0]
+		Expect(*claimPropertySet.VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(claimPropertySet.AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+		Expect(*claimPropertySet.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+		claimPropertySet = storageProfile.Status.ClaimPropertySets[1]
+		Expect(*claimPropertySet.VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(claimPropertySet.AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+		Expect(*claimPropertySet.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategyCsiClone)))
+	})
+
+	It("Should fail if no probe PVC is bound before the timeout", func() {
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), createProbeStorageProfile(cdiv1.StorageProbeOnce))
+		reconcileProfile()
+		expireProbe()
+		reconcileProfile()
+
+		storageProfile := getProfile()
+		Expect(storageProfile.Status.Probe.Phase).To(Equal(cdiv1.StorageProbeFailed))
+		Expect(storageProfile.Status.Probe.ClaimPropertySets).To(BeEmpty())
+		Expect(storageProfile.Status.ClaimPropertySets).To(BeEmpty())
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should provision the probe PVCs of a WaitForFirstConsumer storage class on a ready node", func() {
+		bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(&bindingMode), createProbeStorageProfile(cdiv1.StorageProbeOnce),
+			createProbeNode("cordoned", true, v1.ConditionTrue), createProbeNode("not-ready", false, v1.ConditionFalse), createProbeNode("ready", false, v1.ConditionTrue))
+		reconcileProfile()
+		Expect(getProbePvc("filesystem-rwo").Annotations[AnnSelectedNode]).To(Equal("ready"))
+	})
+
+	It("Should provision the probe PVCs of a WaitForFirstConsumer storage class on an untainted node of the allowed topologies", func() {
+		bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		sc := createProbeStorageClass(&bindingMode)
+		sc.AllowedTopologies = []v1.TopologySelectorTerm{{
+			MatchLabelExpressions: []v1.TopologySelectorLabelRequirement{
+				{Key: "topology.kubernetes.io/zone", Values: []string{"zone-a", "zone-b"}},
+			},
+		}}
+		tainted := createProbeNode("tainted", false, v1.ConditionTrue)
+		tainted.Labels = map[string]string{"topology.kubernetes.io/zone": "zone-a"}
+		tainted.Spec.Taints = []v1.Taint{{Key: "node-role.kubernetes.io/master", Effect: v1.TaintEffectNoSchedule}}
+		otherZone := createProbeNode("other-zone", false, v1.ConditionTrue)
+		otherZone.Labels = map[string]string{"topology.kubernetes.io/zone": "zone-c"}
+		inZone := createProbeNode("in-zone", false, v1.ConditionTrue)
+		inZone.Labels = map[string]string{"topology.kubernetes.io/zone": "zone-b"}
+		inZone.Spec.Taints = []v1.Taint{{Key: "example.com/prefer-not", Effect: v1.TaintEffectPreferNoSchedule}}
+
+		reconciler = createStorageProfileReconciler(sc, createProbeStorageProfile(cdiv1.StorageProbeOnce), tainted, otherZone, inZone)
+		reconcileProfile()
+		Expect(getProbePvc("filesystem-rwo").Annotations[AnnSelectedNode]).To(Equal("in-zone"))
+	})
+
+	It("Should provision the missing probe PVCs on the node of the existing ones", func() {
+		bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		node := createProbeNode("ready", false, v1.ConditionTrue)
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(&bindingMode), createProbeStorageProfile(cdiv1.StorageProbeOnce), node)
+		reconcileProfile()
+
+		// the node is not looked up again
+		Expect(reconciler.client.Delete(context.TODO(), node)).To(Succeed())
+		Expect(reconciler.client.Delete(context.TODO(), getProbePvc("block-rwx"))).To(Succeed())
+
+		reconcileProfile()
+		Expect(getProbePvc("block-rwx").Annotations[AnnSelectedNode]).To(Equal("ready"))
+		Expect(getProfile().Status.Probe.Phase).To(Equal(cdiv1.StorageProbeInProgress))
+	})
+
+	It("Should fail the probe of a WaitForFirstConsumer storage class without a ready node", func() {
+		bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(&bindingMode), createProbeStorageProfile(cdiv1.StorageProbeOnce),
+			createProbeNode("not-ready", false, v1.ConditionFalse))
+		reconcileProfile()
+		probe := getProfile().Status.Probe
+		Expect(probe.Phase).To(Equal(cdiv1.StorageProbeFailed))
+		Expect(probe.Message).To(ContainSubstring("No schedulable node"))
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should prefer the spec over the probe result", func() {
+		storageProfile := createProbeStorageProfile(cdiv1.StorageProbeOnce)
+		filesystem := v1.PersistentVolumeFilesystem
+		storageProfile.Spec.ClaimPropertySets = []cdiv1.ClaimPropertySet{{VolumeMode: &filesystem}}
+		storageProfile.Status.Probe = createCompletedProbe(time.Now())
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), storageProfile,
+			createSnapshotClass("probe-snapshot-class", nil, testProbeProvisioner))
+		reconcileProfile()
+
+		storageProfile = getProfile()
+		Expect(storageProfile.Spec.ClaimPropertySets[0].AccessModes).To(BeEmpty())
+		Expect(storageProfile.Spec.ClaimPropertySets[0].CloneStrategy).To(BeNil())
+		claimPropertySet := storageProfile.Status.ClaimPropertySets[0]
+		Expect(*claimPropertySet.VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(claimPropertySet.AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+		Expect(*claimPropertySet.CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)))
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should record the snapshot class of the storage class driver", func() {
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), createProbeStorageProfile(cdiv1.StorageProbeOnce),
+			createSnapshotClass("other-snapshot-class", nil, "other.csi.example.com"),
+			createSnapshotClass("probe-snapshot-class", nil, testProbeProvisioner))
+		reconcileProfile()
+		bindProbePvc("filesystem-rwx")
+		expireProbe()
+		reconcileProfile()
+
+		storageProfile := getProfile()
+		Expect(storageProfile.Status.Probe.SnapshotClass).To(Equal(&[]string{"probe-snapshot-class"}[0]))
+		Expect(storageProfile.Status.Probe.Clone).To(BeFalse())
+		Expect(*storageProfile.Status.ClaimPropertySets[0].CloneStrategy).To(Equal(cdiv1.CDICloneStrategy(cdiv1.CloneStrategySnapshot)))
+	})
+
+	It("Should not probe again with the Once policy", func() {
+		storageProfile := createProbeStorageProfile(cdiv1.StorageProbeOnce)
+		storageProfile.Status.Probe = createCompletedProbe(time.Now().Add(-2 * defaultProbeInterval))
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), storageProfile)
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(getProfile().Status.Probe.Phase).To(Equal(cdiv1.StorageProbeSucceeded))
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should wait for the interval of the Periodic policy", func() {
+		storageProfile := createProbeStorageProfile(cdiv1.StorageProbePeriodic)
+		storageProfile.Spec.Probe.Interval = &metav1.Duration{Duration: time.Hour}
+		storageProfile.Status.Probe = createCompletedProbe(time.Now().Add(-30 * time.Minute))
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), storageProfile)
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+		Expect(countProbePvcs()).To(BeZero())
+	})
+
+	It("Should probe again after the interval of the Periodic policy and keep the previous result meanwhile", func() {
+		storageProfile := createProbeStorageProfile(cdiv1.StorageProbePeriodic)
+		storageProfile.Status.Probe = createCompletedProbe(time.Now().Add(-2 * defaultProbeInterval))
+		reconciler = createStorageProfileReconciler(createProbeStorageClass(nil), storageProfile)
+		result := reconcileProfile()
+		Expect(result.RequeueAfter).To(Equal(probeRequeue))
+		Expect(countProbePvcs()).To(Equal(len(probeClaimProperties)))
+
+		storageProfile = getProfile()
+		Expect(storageProfile.Status.Probe.Phase).To(Equal(cdiv1.StorageProbeInProgress))
+		Expect(*storageProfile.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+	})
+
+	It("Should remove the probe PVCs of a deleted storage class", func() {
+		leftover := newProbePvc(testProbeStorageClass, getProbePvcName(testProbeStorageClass, "block-rwo"), v1.PersistentVolumeBlock, v1.ReadWriteOnce, "")
+		reconciler = createStorageProfileReconciler(leftover)
+		reconcileProfile()
+		Expect(countProbePvcs()).To(BeZero())
+	})
+})
+
+var _ = Describe("StorageProfile claim property sets", func() {
+	const hppProvisioner = "kubevirt.io/hostpath-provisioner"
+
+	reconcileStorageProfile := func(sc *storagev1.StorageClass, storageProfile *cdiv1.StorageProfile, objects ...runtime.Object) *cdiv1.StorageProfile {
+		reconciler := createStorageProfileReconciler(append(objects, sc, storageProfile)...)
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: sc.Name}})
+		Expect(err).ToNot(HaveOccurred())
+		result := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: sc.Name}, result)
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	It("Should keep all ranked sets of the spec and fill them with the provisioner defaults", func() {
+		block := v1.PersistentVolumeBlock
+		storageProfile := MakeEmptyStorageProfileSpec(testProbeStorageClass)
+		storageProfile.Spec.ClaimPropertySets = []cdiv1.ClaimPropertySet{
+			{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}, VolumeMode: &block},
+			{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}},
+		}
+		result := reconcileStorageProfile(createStorageClassWithProvisioner(testProbeStorageClass, nil, hppProvisioner), storageProfile)
+
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(2))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+		Expect(*result.Status.ClaimPropertySets[1].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(result.Status.ClaimPropertySets[1].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+		Expect(result.Spec.ClaimPropertySets[1].VolumeMode).To(BeNil())
+	})
+
+	It("Should use the provisioner defaults without spec", func() {
+		result := reconcileStorageProfile(createStorageClassWithProvisioner(testProbeStorageClass, nil, hppProvisioner), MakeEmptyStorageProfileSpec(testProbeStorageClass))
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+	})
+
+	It("Should have no sets for an unknown provisioner", func() {
+		result := reconcileStorageProfile(createStorageClassWithProvisioner(testProbeStorageClass, nil, testProbeProvisioner), MakeEmptyStorageProfileSpec(testProbeStorageClass))
+		Expect(result.Status.ClaimPropertySets).To(BeEmpty())
+	})
+
+	It("Should use the first provisioner rule of the CDIConfig matching the storage class parameters", func() {
+		sc := createStorageClassWithProvisioner(testProbeStorageClass, nil, testProbeProvisioner)
+		sc.Parameters = map[string]string{"backend": "san", "pool": "fast"}
+		cdiConfig := createCDIConfigWithStorageCapabilities(
+			cdiv1.ProvisionerCapabilities{Provisioner: testProbeProvisioner, Parameters: map[string]string{"backend": "nas"}, AccessMode: v1.ReadWriteMany, VolumeMode: v1.PersistentVolumeFilesystem},
+			cdiv1.ProvisionerCapabilities{Provisioner: testProbeProvisioner, Parameters: map[string]string{"backend": "san"}, AccessMode: v1.ReadWriteOnce, VolumeMode: v1.PersistentVolumeBlock},
+			cdiv1.ProvisionerCapabilities{Provisioner: testProbeProvisioner, AccessMode: v1.ReadWriteOnce, VolumeMode: v1.PersistentVolumeFilesystem},
+		)
+		result := reconcileStorageProfile(sc, MakeEmptyStorageProfileSpec(testProbeStorageClass), cdiConfig)
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+	})
+
+	It("Should prefer the provisioner rules of the CDIConfig over the built in ones", func() {
+		cdiConfig := createCDIConfigWithStorageCapabilities(
+			cdiv1.ProvisionerCapabilities{Provisioner: hppProvisioner, AccessMode: v1.ReadWriteMany, VolumeMode: v1.PersistentVolumeBlock},
+		)
+		result := reconcileStorageProfile(createStorageClassWithProvisioner(testProbeStorageClass, nil, hppProvisioner), MakeEmptyStorageProfileSpec(testProbeStorageClass), cdiConfig)
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+	})
+
+	It("Should ignore invalid provisioner rules of the CDIConfig", func() {
+		cdiConfig := createCDIConfigWithStorageCapabilities(
+			cdiv1.ProvisionerCapabilities{Provisioner: hppProvisioner, AccessMode: v1.ReadWriteMany},
+		)
+		result := reconcileStorageProfile(createStorageClassWithProvisioner(testProbeStorageClass, nil, hppProvisioner), MakeEmptyStorageProfileSpec(testProbeStorageClass), cdiConfig)
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeFilesystem))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}))
+	})
+
+	It("Should apply the changes of the provisioner rules of the CDIConfig", func() {
+		cdiConfig := createCDIConfigWithStorageCapabilities()
+		reconciler := createStorageProfileReconciler(createStorageClassWithProvisioner(testProbeStorageClass, nil, testProbeProvisioner), cdiConfig)
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testProbeStorageClass}}
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		result := &cdiv1.StorageProfile{}
+		err = reconciler.client.Get(context.TODO(), req.NamespacedName, result)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Status.ClaimPropertySets).To(BeEmpty())
+
+		cdiConfig.Spec.StorageCapabilities = []cdiv1.ProvisionerCapabilities{
+			{Provisioner: testProbeProvisioner, AccessMode: v1.ReadWriteMany, VolumeMode: v1.PersistentVolumeBlock},
+		}
+		err = reconciler.client.Update(context.TODO(), cdiConfig)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), req.NamespacedName, result)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Status.ClaimPropertySets).To(HaveLen(1))
+		Expect(*result.Status.ClaimPropertySets[0].VolumeMode).To(Equal(v1.PersistentVolumeBlock))
+		Expect(result.Status.ClaimPropertySets[0].AccessModes).To(Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}))
+	})
+})
+
+func createStorageProfileReconciler(objects ...runtime.Object) *StorageProfileReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+
+	// Register operator types with the runtime scheme.
+	s := scheme.Scheme
+	cdiv1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	r := &StorageProfileReconciler{
+		client:         cl,
+		uncachedClient: cl,
+		scheme:         s,
+		log:            storageProfileLog,
+	}
+	return r
+}
+
+func createProbeStorageClass(bindingMode *storagev1.VolumeBindingMode) *storagev1.StorageClass {
+	allowExpansion := true
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testProbeStorageClass,
+		},
+		Provisioner:          testProbeProvisioner,
+		VolumeBindingMode:    bindingMode,
+		AllowVolumeExpansion: &allowExpansion,
+	}
+}
+
+func createProbeStorageProfile(policy cdiv1.StorageProbePolicy) *cdiv1.StorageProfile {
+	storageProfile := MakeEmptyStorageProfileSpec(testProbeStorageClass)
+	if policy != "" {
+		storageProfile.Spec.Probe = &cdiv1.StorageProbe{Policy: policy}
+	}
+	return storageProfile
+}
+
+func createCompletedProbe(completionTime time.Time) *cdiv1.StorageProbeStatus {
+	block := v1.PersistentVolumeBlock
+	return &cdiv1.StorageProbeStatus{
+		Phase:          cdiv1.StorageProbeSucceeded,
+		StartTime:      &metav1.Time{Time: completionTime.Add(-time.Minute)},
+		CompletionTime: &metav1.Time{Time: completionTime},
+		ClaimPropertySets: []cdiv1.ClaimPropertySet{{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			VolumeMode:  &block,
+		}},
+		SnapshotClass: &[]string{"probe-snapshot-class"}[0],
+	}
+}
+
+func createProbeNode(name string, unschedulable bool, ready v1.ConditionStatus) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.NodeSpec{
+			Unschedulable: unschedulable,
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: ready},
+			},
+		},
+	}
+}
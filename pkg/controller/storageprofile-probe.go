@@ -0,0 +1,411 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// AnnSelectedNode is the annotation the scheduler sets on a PVC of a WaitForFirstConsumer storage class
+	AnnSelectedNode = "volume.kubernetes.io/selected-node"
+
+	// probePvcSize is the requested size of the probe PVCs
+	probePvcSize = "1Gi"
+	// probeTimeout is the time the probe PVCs have to bind, the modes of the PVCs not bound by then are unsupported
+	probeTimeout = 5 * time.Minute
+	// probeRequeue is how often a probe in progress is checked
+	probeRequeue = 5 * time.Second
+	// defaultProbeInterval is the time between the probes of the Periodic policy
+	defaultProbeInterval = 24 * time.Hour
+
+	probeCloneSuffix = "clone"
+)
+
+// probeClaimProperties are the volume and access modes a storage class is probed for, ordered by preference
+var probeClaimProperties = []struct {
+	suffix     string
+	volumeMode v1.PersistentVolumeMode
+	accessMode v1.PersistentVolumeAccessMode
+}{
+	{"block-rwx", v1.PersistentVolumeBlock, v1.ReadWriteMany},
+	{"filesystem-rwx", v1.PersistentVolumeFilesystem, v1.ReadWriteMany},
+	{"block-rwo", v1.PersistentVolumeBlock, v1.ReadWriteOnce},
+	{"filesystem-rwo", v1.PersistentVolumeFilesystem, v1.ReadWriteOnce},
+}
+
+// reconcileProbe probes the storage class with test PVCs according to the probe policy of the StorageProfile and
+// records the result in the status. It returns the time after which the probe has to be checked again.
+func (r *StorageProfileReconciler) reconcileProbe(sc *storagev1.StorageClass, storageProfile *cdiv1.StorageProfile, log logr.Logger) (time.Duration, error) {
+	policy, interval := getProbePolicy(storageProfile)
+	if policy == cdiv1.StorageProbeNever {
+		storageProfile.Status.Probe = nil
+		return 0, r.deleteProbePvcs(sc.Name)
+	}
+
+	now := time.Now()
+	status := storageProfile.Status.Probe
+	if status == nil || status.Phase != cdiv1.StorageProbeInProgress {
+		if next := nextProbe(policy, interval, status, now); next > 0 {
+			return next, nil
+		} else if next < 0 {
+			return 0, nil
+		}
+		// the previous result is used until the new probe completes
+		if status == nil {
+			status = &cdiv1.StorageProbeStatus{}
+		}
+		status.Phase = cdiv1.StorageProbeInProgress
+		status.StartTime = &metav1.Time{Time: now}
+		status.Message = ""
+		storageProfile.Status.Probe = status
+		log.Info("Probing storage class", "policy", policy)
+	}
+
+	pvcs, err := r.getProbePvcs(sc.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	node := ""
+	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		// all probe PVCs are provisioned on the node of the first one
+		for _, pvc := range pvcs {
+			if node = pvc.Annotations[AnnSelectedNode]; node != "" {
+				break
+			}
+		}
+		if node == "" {
+			if node, err = r.getProbeNode(sc); err != nil {
+				return 0, err
+			}
+		}
+		if node == "" {
+			r.completeProbe(sc, status, nil, false, now)
+			status.Phase = cdiv1.StorageProbeFailed
+			status.Message = "No schedulable node to provision the probe PVCs of the WaitForFirstConsumer storage class"
+			return r.finishProbe(sc, policy, interval)
+		}
+	}
+
+	done := true
+	var cloneSource *v1.PersistentVolumeClaim
+	for _, properties := range probeClaimProperties {
+		name := getProbePvcName(sc.Name, properties.suffix)
+		pvc, ok := pvcs[name]
+		if !ok {
+			pvc = newProbePvc(sc.Name, name, properties.volumeMode, properties.accessMode, node)
+			if err := r.createProbePvc(pvc, log); err != nil {
+				return 0, err
+			}
+		}
+		if pvc.Status.Phase != v1.ClaimBound {
+			done = false
+		} else if cloneSource == nil {
+			cloneSource = pvc
+		}
+	}
+
+	cloneName := getProbePvcName(sc.Name, probeCloneSuffix)
+	clone, ok := pvcs[cloneName]
+	if !ok && cloneSource != nil {
+		clone = newProbePvc(sc.Name, cloneName, *cloneSource.Spec.VolumeMode, cloneSource.Spec.AccessModes[0], cloneSource.Annotations[AnnSelectedNode])
+		clone.Spec.DataSource = &v1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: cloneSource.Name,
+		}
+		if err := r.createProbePvc(clone, log); err != nil {
+			return 0, err
+		}
+	}
+	cloned := clone != nil && clone.Status.Phase == v1.ClaimBound
+	if !cloned {
+		done = false
+	}
+
+	if !done && now.Before(status.StartTime.Add(probeTimeout)) {
+		return probeRequeue, nil
+	}
+
+	snapshotClass, err := r.getProbeSnapshotClass(sc)
+	if err != nil {
+		return 0, err
+	}
+	status.SnapshotClass = snapshotClass
+	r.completeProbe(sc, status, pvcs, cloned, now)
+	log.Info("Probed storage class", "phase", status.Phase, "message", status.Message)
+
+	return r.finishProbe(sc, policy, interval)
+}
+
+// completeProbe records the modes of the bound probe PVCs in the probe status
+func (r *StorageProfileReconciler) completeProbe(sc *storagev1.StorageClass, status *cdiv1.StorageProbeStatus, pvcs map[string]*v1.PersistentVolumeClaim, cloned bool, now time.Time) {
+	status.ClaimPropertySets = nil
+	for _, properties := range probeClaimProperties {
+		pvc, ok := pvcs[getProbePvcName(sc.Name, properties.suffix)]
+		if !ok || pvc.Status.Phase != v1.ClaimBound {
+			continue
+		}
+		volumeMode := properties.volumeMode
+		status.ClaimPropertySets = append(status.ClaimPropertySets, cdiv1.ClaimPropertySet{
+			AccessModes: []v1.PersistentVolumeAccessMode{properties.accessMode},
+			VolumeMode:  &volumeMode,
+		})
+	}
+	status.Clone = cloned
+	status.Expansion = sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+	status.CompletionTime = &metav1.Time{Time: now}
+
+	if len(status.ClaimPropertySets) == 0 {
+		status.Phase = cdiv1.StorageProbeFailed
+		status.Message = fmt.Sprintf("No probe PVC was bound within %s", probeTimeout)
+		return
+	}
+	status.Phase = cdiv1.StorageProbeSucceeded
+	status.Message = fmt.Sprintf("Provisioned %d of %d volume and access mode combinations", len(status.ClaimPropertySets), len(probeClaimProperties))
+}
+
+// finishProbe removes the probe PVCs and returns the time until the next probe
+func (r *StorageProfileReconciler) finishProbe(sc *storagev1.StorageClass, policy cdiv1.StorageProbePolicy, interval time.Duration) (time.Duration, error) {
+	if err := r.deleteProbePvcs(sc.Name); err != nil {
+		return 0, err
+	}
+	if policy == cdiv1.StorageProbePeriodic {
+		return interval, nil
+	}
+	return 0, nil
+}
+
+// isProbeCompleted returns true if a probe completed and detected supported modes
+func isProbeCompleted(status *cdiv1.StorageProbeStatus) bool {
+	return status != nil && status.CompletionTime != nil && len(status.ClaimPropertySets) > 0
+}
+
+// applyProbeResult fills the properties the claim property set does not provide with the result of the last completed
+// probe
+func applyProbeResult(status *cdiv1.StorageProbeStatus, claimPropertySet *cdiv1.ClaimPropertySet) {
+	if !isProbeCompleted(status) {
+		return
+	}
+
+	// the preferred detected set with the volume mode of the claim property set
+	detected := status.ClaimPropertySets[0]
+	for _, set := range status.ClaimPropertySets {
+		if claimPropertySet.VolumeMode == nil || (set.VolumeMode != nil && *set.VolumeMode == *claimPropertySet.VolumeMode) {
+			detected = set
+			break
+		}
+	}
+	if len(claimPropertySet.AccessModes) == 0 {
+		claimPropertySet.AccessModes = detected.AccessModes
+	}
+	if claimPropertySet.VolumeMode == nil {
+		claimPropertySet.VolumeMode = detected.VolumeMode
+	}
+	if claimPropertySet.CloneStrategy == nil {
+		var cloneStrategy cdiv1.CDICloneStrategy
+		switch {
+		case status.Clone:
+			cloneStrategy = cdiv1.CloneStrategyCsiClone
+		case status.SnapshotClass != nil:
+			cloneStrategy = cdiv1.CloneStrategySnapshot
+		default:
+			cloneStrategy = cdiv1.CloneStrategyHostAssisted
+		}
+		claimPropertySet.CloneStrategy = &cloneStrategy
+	}
+}
+
+func getProbePolicy(storageProfile *cdiv1.StorageProfile) (cdiv1.StorageProbePolicy, time.Duration) {
+	probe := storageProfile.Spec.Probe
+	if probe == nil || probe.Policy == "" {
+		return cdiv1.StorageProbeNever, 0
+	}
+	interval := defaultProbeInterval
+	if probe.Interval != nil && probe.Interval.Duration > 0 {
+		interval = probe.Interval.Duration
+	}
+	return probe.Policy, interval
+}
+
+// nextProbe returns the time until the next probe, zero if the storage class has to be probed now and a negative
+// duration if it is not probed again
+func nextProbe(policy cdiv1.StorageProbePolicy, interval time.Duration, status *cdiv1.StorageProbeStatus, now time.Time) time.Duration {
+	if status == nil || status.CompletionTime == nil {
+		return 0
+	}
+	if policy != cdiv1.StorageProbePeriodic {
+		return -1
+	}
+	if next := status.CompletionTime.Add(interval).Sub(now); next > 0 {
+		return next
+	}
+	return 0
+}
+
+func getProbePvcName(storageClassName, suffix string) string {
+	return naming.GetResourceName("cdi-probe-"+storageClassName, suffix)
+}
+
+func getProbeLabelValue(storageClassName string) string {
+	return naming.GetLabelNameFromResourceName(storageClassName)
+}
+
+func newProbePvc(storageClassName, name string, volumeMode v1.PersistentVolumeMode, accessMode v1.PersistentVolumeAccessMode, node string) *v1.PersistentVolumeClaim {
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: util.GetNamespace(),
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.StorageProbeLabel: getProbeLabelValue(storageClassName),
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{accessMode},
+			VolumeMode:  &volumeMode,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(probePvcSize),
+				},
+			},
+			StorageClassName: &storageClassName,
+		},
+	}
+	if node != "" {
+		pvc.Annotations = map[string]string{AnnSelectedNode: node}
+	}
+	return pvc
+}
+
+func (r *StorageProfileReconciler) createProbePvc(pvc *v1.PersistentVolumeClaim, log logr.Logger) error {
+	err := r.client.Create(context.TODO(), pvc)
+	switch {
+	case err == nil, k8serrors.IsAlreadyExists(err):
+		return nil
+	case k8serrors.IsInvalid(err), k8serrors.IsForbidden(err):
+		// the PVC is rejected on admission, the modes are unsupported
+		log.V(1).Info("Probe PVC rejected", "pvc", pvc.Name, "error", err.Error())
+		return nil
+	}
+	return err
+}
+
+func (r *StorageProfileReconciler) getProbePvcs(storageClassName string) (map[string]*v1.PersistentVolumeClaim, error) {
+	pvcList := &v1.PersistentVolumeClaimList{}
+	if err := r.client.List(context.TODO(), pvcList,
+		client.InNamespace(util.GetNamespace()),
+		client.MatchingLabels{common.StorageProbeLabel: getProbeLabelValue(storageClassName)}); err != nil {
+		return nil, err
+	}
+	pvcs := make(map[string]*v1.PersistentVolumeClaim)
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName == storageClassName {
+			pvcs[pvc.Name] = pvc
+		}
+	}
+	return pvcs, nil
+}
+
+func (r *StorageProfileReconciler) deleteProbePvcs(storageClassName string) error {
+	pvcs, err := r.getProbePvcs(storageClassName)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), pvc); IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getProbeNode returns a node the probe PVCs of a WaitForFirstConsumer storage class are provisioned for, the node has
+// to be ready, schedulable without tolerations and in the allowed topologies of the storage class
+func (r *StorageProfileReconciler) getProbeNode(sc *storagev1.StorageClass) (string, error) {
+	nodes := &v1.NodeList{}
+	if err := r.client.List(context.TODO(), nodes); err != nil {
+		return "", err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if isProbeNodeSchedulable(node) && isNodeInTopologies(node, sc.AllowedTopologies) {
+			return node.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func isProbeNodeSchedulable(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isNodeInTopologies returns true if the node labels match one of the topology terms, any node matches without terms
+func isNodeInTopologies(node *v1.Node, terms []v1.TopologySelectorTerm) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	for _, term := range terms {
+		if isNodeInTopology(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNodeInTopology(node *v1.Node, term v1.TopologySelectorTerm) bool {
+	for _, requirement := range term.MatchLabelExpressions {
+		value, ok := node.Labels[requirement.Key]
+		if !ok || !sets.NewString(requirement.Values...).Has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// getProbeSnapshotClass returns the VolumeSnapshotClass of the storage class driver
+func (r *StorageProfileReconciler) getProbeSnapshotClass(sc *storagev1.StorageClass) (*string, error) {
+	snapshotClasses := &snapshotv1.VolumeSnapshotClassList{}
+	if err := r.uncachedClient.List(context.TODO(), snapshotClasses); err != nil {
+		return nil, IgnoreIsNoMatchError(err)
+	}
+	for _, snapshotClass := range snapshotClasses.Items {
+		if snapshotClass.Driver == sc.Provisioner {
+			name := snapshotClass.Name
+			return &name, nil
+		}
+	}
+	return nil, nil
+}
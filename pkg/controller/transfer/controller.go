@@ -45,6 +45,9 @@ const (
 	// AnnObjectTransferName holds the name of related ObjectTransfer resource
 	AnnObjectTransferName = "cdi.kubevirt.io/objectTransferName"
 
+	// LabelObjectTransferUID holds the UID of the ObjectTransfer that created the object
+	LabelObjectTransferUID = "cdi.kubevirt.io/objectTransferUID"
+
 	objectTransferFinalizer = "cdi.kubevirt.io/objectTransfer"
 
 	defaultRequeue = 2 * time.Second
@@ -53,6 +56,8 @@ const (
 type transferHandler interface {
 	ReconcilePending(*cdiv1.ObjectTransfer) (time.Duration, error)
 	ReconcileRunning(*cdiv1.ObjectTransfer) (time.Duration, error)
+	// ReconcileRollback undoes the steps of an unfinished transfer, it returns true once the source is restored
+	ReconcileRollback(*cdiv1.ObjectTransfer) (bool, error)
 }
 
 type objectTransferHandler struct {
@@ -61,10 +66,12 @@ type objectTransferHandler struct {
 
 // ObjectTransferReconciler members
 type ObjectTransferReconciler struct {
-	Client   client.Client
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
-	Log      logr.Logger
+	Client client.Client
+	// APIReader reads objects that are not cached, like Secrets
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	Log       logr.Logger
 }
 
 func getTransferTargetName(ot *cdiv1.ObjectTransfer) string {
@@ -88,10 +95,11 @@ func NewObjectTransferController(mgr manager.Manager, log logr.Logger) (controll
 	name := "transfer-controller"
 	client := mgr.GetClient()
 	reconciler := &ObjectTransferReconciler{
-		Client:   client,
-		Scheme:   mgr.GetScheme(),
-		Log:      log.WithName(name),
-		Recorder: mgr.GetEventRecorderFor(name),
+		Client:    client,
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Log:       log.WithName(name),
+		Recorder:  mgr.GetEventRecorderFor(name),
 	}
 
 	ctrl, err := controller.New(name, mgr, controller.Options{
@@ -143,22 +151,23 @@ func (r *ObjectTransferReconciler) Reconcile(_ context.Context, req reconcile.Re
 		}
 
 		if ot.DeletionTimestamp != nil {
-			return r.reconcileCleanup(ot)
+			return r.reconcileRollback(ot, handler)
 		}
 
 		if requeue, err := handler.ReconcilePending(ot); requeue > 0 || err != nil {
 			return reconcile.Result{RequeueAfter: requeue}, err
 		}
-	case cdiv1.ObjectTransferRunning, cdiv1.ObjectTransferError:
-		if ot.DeletionTimestamp != nil && ot.Status.Phase == cdiv1.ObjectTransferError {
-			return r.reconcileCleanup(ot)
-		}
-
+	case cdiv1.ObjectTransferRunning, cdiv1.ObjectTransferError, cdiv1.ObjectTransferRollingBack:
 		handler, err := r.getHandler(ot)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
+		// An unfinished transfer that is deleted is rolled back, so neither the source nor the PV is left behind
+		if ot.DeletionTimestamp != nil || ot.Status.Phase == cdiv1.ObjectTransferRollingBack {
+			return r.reconcileRollback(ot, handler)
+		}
+
 		if requeue, err := handler.ReconcileRunning(ot); requeue > 0 || err != nil {
 			return reconcile.Result{RequeueAfter: requeue}, err
 		}
@@ -195,6 +204,40 @@ func (r *ObjectTransferReconciler) reconcileCleanup(ot *cdiv1.ObjectTransfer) (r
 	return reconcile.Result{}, nil
 }
 
+func (r *ObjectTransferReconciler) reconcileRollback(ot *cdiv1.ObjectTransfer, handler transferHandler) (reconcile.Result, error) {
+	if ot.Status.Phase != cdiv1.ObjectTransferRollingBack {
+		r.logger(ot).Info("Rolling back transfer", "phase", ot.Status.Phase)
+		ot.Status.Phase = cdiv1.ObjectTransferRollingBack
+		if err := r.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Rolling back", ""); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	done, err := handler.ReconcileRollback(ot)
+	if err != nil {
+		if err2 := r.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Rollback error", err.Error()); err2 != nil {
+			return reconcile.Result{}, err2
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !done {
+		if err := r.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Rolling back", ""); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{RequeueAfter: defaultRequeue}, nil
+	}
+
+	recordStep(ot, stepRolledBack, "")
+	if err := r.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Rolled back", ""); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.reconcileCleanup(ot)
+}
+
 func (r *ObjectTransferReconciler) getHandler(ot *cdiv1.ObjectTransfer) (transferHandler, error) {
 	switch strings.ToLower(ot.Spec.Source.Kind) {
 	case "datavolume":
@@ -209,6 +252,18 @@ func (r *ObjectTransferReconciler) getHandler(ot *cdiv1.ObjectTransfer) (transfe
 				reconciler: r,
 			},
 		}, nil
+	case "volumesnapshot":
+		return &snapshotTransferHandler{
+			objectTransferHandler: objectTransferHandler{
+				reconciler: r,
+			},
+		}, nil
+	case "secret":
+		return &secretTransferHandler{
+			objectTransferHandler: objectTransferHandler{
+				reconciler: r,
+			},
+		}, nil
 	}
 
 	return nil, fmt.Errorf("invalid kind %q", ot.Spec.Source.Kind)
@@ -337,7 +392,79 @@ func (r *ObjectTransferReconciler) setCondition(ot *cdiv1.ObjectTransfer, t cdiv
 	return updated
 }
 
+func (r *ObjectTransferReconciler) setCompleted(ot *cdiv1.ObjectTransfer) error {
+	recordStep(ot, stepComplete, "")
+	ot.Status.Phase = cdiv1.ObjectTransferComplete
+	// the intermediary state is not needed anymore, only the journal is kept
+	ot.Status.Data = map[string]string{
+		journalKey: ot.Status.Data[journalKey],
+	}
+
+	return r.setAndUpdateCompleteCondition(ot, corev1.ConditionTrue, "Transfer complete", "")
+}
+
+// createObjectTransferTarget creates the target from the saved source, labeled so a rollback can tell it was created
+// by the transfer
 func (r *ObjectTransferReconciler) createObjectTransferTarget(ot *cdiv1.ObjectTransfer, obj client.Object, mutateFn func(client.Object)) error {
+	uid, err := r.getRootTransferUID(ot)
+	if err != nil {
+		return err
+	}
+
+	if err := r.createFromSavedSource(ot, obj, getTransferTargetNamespace(ot), getTransferTargetName(ot), func(o client.Object) {
+		if o.GetLabels() == nil {
+			o.SetLabels(make(map[string]string))
+		}
+		o.GetLabels()[LabelObjectTransferUID] = string(uid)
+		if mutateFn != nil {
+			mutateFn(o)
+		}
+	}); err != nil {
+		return err
+	}
+
+	recordStep(ot, stepTargetCreated, string(obj.GetUID()))
+
+	return nil
+}
+
+// getRootTransferUID returns the UID of the transfer that started ot, the objects created by a PVC transfer of a
+// DataVolume transfer belong to the DataVolume transfer
+func (r *ObjectTransferReconciler) getRootTransferUID(ot *cdiv1.ObjectTransfer) (types.UID, error) {
+	if ot.Spec.ParentName == nil {
+		return ot.UID, nil
+	}
+
+	parent := &cdiv1.ObjectTransfer{}
+	parentExists, err := r.getResource("", *ot.Spec.ParentName, parent)
+	if err != nil {
+		return "", err
+	}
+
+	if !parentExists {
+		return ot.UID, nil
+	}
+
+	return r.getRootTransferUID(parent)
+}
+
+// isCreatedByTransfer returns true if obj was created by ot or one of its child transfers
+func isCreatedByTransfer(ot *cdiv1.ObjectTransfer, obj client.Object) bool {
+	return obj.GetLabels()[LabelObjectTransferUID] == string(ot.UID)
+}
+
+// restoreObjectTransferSource recreates the deleted source from the copy saved when the transfer started
+func (r *ObjectTransferReconciler) restoreObjectTransferSource(ot *cdiv1.ObjectTransfer, obj client.Object, mutateFn func(client.Object)) error {
+	if err := r.createFromSavedSource(ot, obj, ot.Spec.Source.Namespace, ot.Spec.Source.Name, mutateFn); err != nil {
+		return err
+	}
+
+	recordStep(ot, stepSourceRestored, string(obj.GetUID()))
+
+	return nil
+}
+
+func (r *ObjectTransferReconciler) createFromSavedSource(ot *cdiv1.ObjectTransfer, obj client.Object, namespace, name string, mutateFn func(client.Object)) error {
 	s, ok := ot.Status.Data["source"]
 	if !ok {
 		return fmt.Errorf("source spec missing")
@@ -352,8 +479,8 @@ func (r *ObjectTransferReconciler) createObjectTransferTarget(ot *cdiv1.ObjectTr
 		return err
 	}
 
-	metaObj.SetNamespace(getTransferTargetNamespace(ot))
-	metaObj.SetName(getTransferTargetName(ot))
+	metaObj.SetNamespace(namespace)
+	metaObj.SetName(name)
 	metaObj.SetGenerateName("")
 	metaObj.SetUID("")
 	metaObj.SetResourceVersion("")
@@ -363,11 +490,12 @@ func (r *ObjectTransferReconciler) createObjectTransferTarget(ot *cdiv1.ObjectTr
 	metaObj.SetDeletionTimestamp(nil)
 	metaObj.SetManagedFields(nil)
 
-	if ot.Spec.Target.Namespace != nil && *ot.Spec.Target.Namespace != ot.Spec.Source.Namespace {
+	if namespace != ot.Spec.Source.Namespace {
 		metaObj.SetOwnerReferences(nil)
 	}
 
 	delete(metaObj.GetAnnotations(), AnnObjectTransferName)
+	delete(metaObj.GetLabels(), LabelObjectTransferUID)
 
 	if mutateFn != nil {
 		mutateFn(obj)
@@ -377,6 +505,12 @@ func (r *ObjectTransferReconciler) createObjectTransferTarget(ot *cdiv1.ObjectTr
 }
 
 func (r *ObjectTransferReconciler) pendingHelper(ot *cdiv1.ObjectTransfer, obj client.Object, data map[string]string) error {
+	return r.pendingHelperWithFilter(ot, obj, nil, data)
+}
+
+// pendingHelperWithFilter is pendingHelper for a source that is not saved as it is, filter is applied to a copy of the
+// reserved source before it is saved
+func (r *ObjectTransferReconciler) pendingHelperWithFilter(ot *cdiv1.ObjectTransfer, obj client.Object, filter func(client.Object), data map[string]string) error {
 	metaObj, err := meta.Accessor(obj)
 	if err != nil {
 		return r.setCompleteConditionError(ot, err)
@@ -408,24 +542,37 @@ func (r *ObjectTransferReconciler) pendingHelper(ot *cdiv1.ObjectTransfer, obj c
 			return r.setCompleteConditionError(ot, err)
 		}
 
+		recordStep(ot, stepSourceReserved, string(metaObj.GetUID()))
+
 		if err := r.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Pending", ""); err != nil {
 			return err
 		}
 	}
 
-	bs, err := json.Marshal(obj)
+	saved := obj
+	if filter != nil {
+		saved = obj.DeepCopyObject().(client.Object)
+		filter(saved)
+	}
+
+	bs, err := json.Marshal(saved)
 	if err != nil {
 		return r.setCompleteConditionError(ot, err)
 	}
 
 	source := string(bs)
+	journal, hasJournal := ot.Status.Data[journalKey]
 	ot.Status.Data = map[string]string{
 		"source": source,
 	}
+	if hasJournal {
+		ot.Status.Data[journalKey] = journal
+	}
 
 	for k, v := range data {
 		ot.Status.Data[k] = v
 	}
+	recordStep(ot, stepSourceSaved, string(metaObj.GetUID()))
 
 	ot.Status.Phase = cdiv1.ObjectTransferRunning
 	if err := r.setCompleteConditionRunning(ot); err != nil {
@@ -435,6 +582,31 @@ func (r *ObjectTransferReconciler) pendingHelper(ot *cdiv1.ObjectTransfer, obj c
 	return nil
 }
 
+// releaseIfExists releases the source of a rollback, if it exists
+func (h *objectTransferHandler) releaseIfExists(ot *cdiv1.ObjectTransfer, source client.Object, exists bool) error {
+	if !exists {
+		return nil
+	}
+
+	return h.reconciler.releaseSource(ot, source)
+}
+
+// releaseSource removes the reservation of the source by this transfer
+func (r *ObjectTransferReconciler) releaseSource(ot *cdiv1.ObjectTransfer, obj client.Object) error {
+	if v, ok := obj.GetAnnotations()[AnnObjectTransferName]; !ok || v != ot.Name {
+		return nil
+	}
+
+	delete(obj.GetAnnotations(), AnnObjectTransferName)
+	if err := r.updateResource(ot, obj); err != nil {
+		return err
+	}
+
+	recordStep(ot, stepSourceReleased, string(obj.GetUID()))
+
+	return nil
+}
+
 func (r *ObjectTransferReconciler) hasRequiredAnnotations(ot *cdiv1.ObjectTransfer, obj metav1.Object) bool {
 	for rk, rv := range ot.Spec.Source.RequiredAnnotations {
 		if v, ok := obj.GetAnnotations()[rk]; !ok || v != rv {
@@ -2,10 +2,12 @@ package transfer_test
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 
 	. "github.com/onsi/gomega"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -52,10 +54,23 @@ func checkCompleteTrue(ot *cdiv1.ObjectTransfer) {
 	Expect(cond.LastTransitionTime.Unix()).ToNot(BeZero())
 }
 
+func checkJournal(ot *cdiv1.ObjectTransfer, steps ...string) {
+	var journal []struct {
+		Step string `json:"step"`
+	}
+	Expect(json.Unmarshal([]byte(ot.Status.Data["journal"]), &journal)).To(Succeed())
+	var recorded []string
+	for _, entry := range journal {
+		recorded = append(recorded, entry.Step)
+	}
+	Expect(recorded).To(Equal(steps))
+}
+
 func createReconciler(objects ...client.Object) *transfer.ObjectTransferReconciler {
 	s := scheme.Scheme
 	corev1.AddToScheme(s)
 	cdiv1.AddToScheme(s)
+	snapshotv1.AddToScheme(s)
 
 	var runtimeObjects []runtime.Object
 	for _, obj := range objects {
@@ -64,9 +79,10 @@ func createReconciler(objects ...client.Object) *transfer.ObjectTransferReconcil
 	cl := fake.NewFakeClientWithScheme(s, runtimeObjects...)
 
 	return &transfer.ObjectTransferReconciler{
-		Client:   cl,
-		Scheme:   s,
-		Log:      logf.Log.WithName("transfer-controller-test"),
-		Recorder: record.NewFakeRecorder(10),
+		Client:    cl,
+		APIReader: cl,
+		Scheme:    s,
+		Log:       logf.Log.WithName("transfer-controller-test"),
+		Recorder:  record.NewFakeRecorder(10),
 	}
 }
@@ -115,6 +115,7 @@ func (h *dataVolumeTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (
 		if err := h.reconciler.Client.Create(context.TODO(), pvcTransfer); err != nil {
 			return 0, h.reconciler.setCompleteConditionError(ot, err)
 		}
+		recordStep(ot, stepPVCTransferCreated, pvcTransferName)
 
 		pvcTransferExists = true
 	}
@@ -171,13 +172,160 @@ func (h *dataVolumeTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (
 		}
 	}
 
-	ot.Status.Phase = cdiv1.ObjectTransferComplete
-	ot.Status.Data = nil
-	if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionTrue, "Transfer complete", ""); err != nil {
-		return 0, err
+	return 0, h.reconciler.setCompleted(ot)
+}
+
+func (h *dataVolumeTransferHandler) ReconcileRollback(ot *cdiv1.ObjectTransfer) (bool, error) {
+	// an unfinished PVC transfer rolls back on its own, a completed one has to be reversed
+	pvcTransfer := &cdiv1.ObjectTransfer{}
+	pvcTransferExists, err := h.reconciler.getResource("", fmt.Sprintf("pvc-transfer-%s", ot.UID), pvcTransfer)
+	if err != nil {
+		return false, err
+	}
+
+	if pvcTransferExists {
+		if pvcTransfer.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), pvcTransfer); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepPVCTransferDeleted, pvcTransfer.Name)
+		}
+
+		return false, nil
+	}
+
+	reverseTransfer := &cdiv1.ObjectTransfer{}
+	reverseTransferExists, err := h.reconciler.getResource("", fmt.Sprintf("pvc-rollback-%s", ot.UID), reverseTransfer)
+	if err != nil {
+		return false, err
+	}
+
+	if reverseTransferExists && reverseTransfer.Status.Phase != cdiv1.ObjectTransferComplete {
+		return false, nil
+	}
+
+	target := &cdiv1.DataVolume{}
+	targetExists, err := h.reconciler.getTargetResource(ot, target)
+	if err != nil {
+		return false, err
+	}
+
+	if targetExists && isCreatedByTransfer(ot, target) {
+		if target.DeletionTimestamp == nil {
+			// keep the PVC, it is moved back to the source
+			pvc := &corev1.PersistentVolumeClaim{}
+			pvcExists, err := h.reconciler.getResource(target.Namespace, cdicontroller.GetDataVolumeClaimName(target), pvc)
+			if err != nil {
+				return false, err
+			}
+
+			if pvcExists {
+				if _, err := h.releasePVC(ot, target, pvc); err != nil {
+					return false, err
+				}
+			}
+
+			if err := h.reconciler.Client.Delete(context.TODO(), target); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepTargetDeleted, string(target.UID))
+		}
+
+		return false, nil
+	}
+
+	source := &cdiv1.DataVolume{}
+	sourceExists, err := h.reconciler.getSourceResource(ot, source)
+	if err != nil {
+		return false, err
+	}
+
+	pvcName, ok := ot.Status.Data["pvcName"]
+	if !ok {
+		// the transfer did not start, at most the source is reserved
+		return true, h.releaseIfExists(ot, source, sourceExists)
+	}
+
+	sourcePVC := &corev1.PersistentVolumeClaim{}
+	sourcePVCExists, err := h.reconciler.getResource(ot.Spec.Source.Namespace, pvcName, sourcePVC)
+	if err != nil {
+		return false, err
+	}
+
+	if !sourcePVCExists && !reverseTransferExists {
+		targetPVC := &corev1.PersistentVolumeClaim{}
+		targetPVCExists, err := h.reconciler.getTargetResource(ot, targetPVC)
+		if err != nil {
+			return false, err
+		}
+
+		if targetPVCExists && isCreatedByTransfer(ot, targetPVC) {
+			if err := h.createReverseTransfer(ot, pvcName); err != nil {
+				return false, err
+			}
+
+			return false, nil
+		}
+	}
+
+	if !sourceExists {
+		if sourcePVCExists && sourcePVC.Annotations[cdicontroller.AnnPopulatedFor] != ot.Spec.Source.Name {
+			// the restored DataVolume adopts its PVC again
+			if sourcePVC.Annotations == nil {
+				sourcePVC.Annotations = make(map[string]string)
+			}
+			sourcePVC.Annotations[cdicontroller.AnnPopulatedFor] = ot.Spec.Source.Name
+			// the PVC moved back by the rollback transfer is not a target anymore
+			delete(sourcePVC.Labels, LabelObjectTransferUID)
+			if err := h.reconciler.updateResource(ot, sourcePVC); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepSourcePVCReacquired, string(sourcePVC.UID))
+		}
+
+		if err := h.reconciler.restoreObjectTransferSource(ot, &cdiv1.DataVolume{}, nil); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if reverseTransferExists && reverseTransfer.DeletionTimestamp == nil {
+		if err := h.reconciler.Client.Delete(context.TODO(), reverseTransfer); err != nil {
+			return false, err
+		}
+	}
+
+	return true, h.reconciler.releaseSource(ot, source)
+}
+
+func (h *dataVolumeTransferHandler) createReverseTransfer(ot *cdiv1.ObjectTransfer, pvcName string) error {
+	targetNamespace := getTransferTargetNamespace(ot)
+	targetName := getTransferTargetName(ot)
+	reverseTransfer := &cdiv1.ObjectTransfer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("pvc-rollback-%s", ot.UID),
+		},
+		Spec: cdiv1.ObjectTransferSpec{
+			Source: cdiv1.TransferSource{
+				Kind:      "PersistentVolumeClaim",
+				Namespace: targetNamespace,
+				Name:      targetName,
+			},
+			Target: cdiv1.TransferTarget{
+				Namespace: &ot.Spec.Source.Namespace,
+				Name:      &pvcName,
+			},
+			ParentName: &ot.Name,
+		},
+	}
+
+	if err := h.reconciler.Client.Create(context.TODO(), reverseTransfer); err != nil {
+		return err
 	}
+	recordStep(ot, stepPVCRollbackCreated, reverseTransfer.Name)
 
-	return 0, nil
+	return nil
 }
 
 func (h *dataVolumeTransferHandler) deleteDataVolume(ot *cdiv1.ObjectTransfer, dv *cdiv1.DataVolume) (time.Duration, error) {
@@ -195,6 +343,26 @@ func (h *dataVolumeTransferHandler) deleteDataVolume(ot *cdiv1.ObjectTransfer, d
 		return 0, nil
 	}
 
+	released, err := h.releasePVC(ot, dv, pvc)
+	if err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	if released {
+		recordStep(ot, stepSourcePVCReleased, string(pvc.UID))
+		return time.Second, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	if err := h.reconciler.Client.Delete(context.TODO(), dv); err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+	recordStep(ot, stepSourceDeleted, string(dv.UID))
+
+	return 0, h.reconciler.setCompleteConditionRunning(ot)
+}
+
+// releasePVC removes the ownership of dv from its PVC, so the PVC is kept when dv is deleted
+func (h *dataVolumeTransferHandler) releasePVC(ot *cdiv1.ObjectTransfer, dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim) (bool, error) {
 	idx := -1
 	for i, o := range pvc.OwnerReferences {
 		if o.Kind == "DataVolume" &&
@@ -217,17 +385,13 @@ func (h *dataVolumeTransferHandler) deleteDataVolume(ot *cdiv1.ObjectTransfer, d
 
 	if idx >= 0 || ok {
 		if err := h.reconciler.updateResource(ot, pvc); err != nil {
-			return 0, h.reconciler.setCompleteConditionError(ot, err)
+			return false, err
 		}
 
-		return time.Second, h.reconciler.setCompleteConditionRunning(ot)
-	}
-
-	if err := h.reconciler.Client.Delete(context.TODO(), dv); err != nil {
-		return 0, h.reconciler.setCompleteConditionError(ot, err)
+		return true, nil
 	}
 
-	return 0, h.reconciler.setCompleteConditionRunning(ot)
+	return false, nil
 }
 
 func (h *dataVolumeTransferHandler) addPopulatedAnnotation(ot *cdiv1.ObjectTransfer, pvc *corev1.PersistentVolumeClaim) error {
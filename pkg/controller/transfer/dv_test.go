@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/controller/transfer"
 )
 
 var _ = Describe("DataVolume Transfer Tests", func() {
@@ -89,6 +90,8 @@ var _ = Describe("DataVolume Transfer Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
+			checkJournal(xfer, "SourceReserved", "SourceSaved")
+			delete(xfer.Status.Data, "journal")
 			Expect(xfer.Status.Data).To(Equal(dvTransferRunning().Status.Data))
 			checkCompleteFalse(xfer, "Running", "")
 		})
@@ -195,6 +198,7 @@ var _ = Describe("DataVolume Transfer Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"]).To(Equal("target-dv"))
+			Expect(dv.Labels[transfer.LabelObjectTransferUID]).To(Equal("uid-dvTransfer"))
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
 			checkCompleteFalse(xfer, "Waiting for target DataVolume", "")
 		})
@@ -225,8 +229,149 @@ var _ = Describe("DataVolume Transfer Tests", func() {
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferComplete))
+			checkJournal(xfer, "Complete")
 			checkCompleteTrue(xfer)
 		})
+
+		It("Should wait for the PVC transfer to roll back", func() {
+			t := metav1.Now()
+			xfer := dvTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			pvcTransfer := internalPVCTransfer(xfer)
+			pvcTransfer.Status.Phase = cdiv1.ObjectTransferRunning
+
+			r := createReconciler(xfer, pvcTransfer)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", xfer.Name, xfer)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, "", pvcTransfer.Name, pvcTransfer)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(xfer.Finalizers).To(HaveLen(1))
+			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRollingBack))
+			checkJournal(xfer, "PVCTransferDeleted")
+			checkCompleteFalse(xfer, "Rolling back", "")
+		})
+
+		It("Should delete the target DataVolume it created without a journal entry", func() {
+			t := metav1.Now()
+			xfer := dvTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			pvc := createBoundPVC()
+			pvc.Namespace = "target-ns"
+			pvc.Name = "target-dv"
+			pvc.Annotations = map[string]string{
+				"cdi.kubevirt.io/storage.populatedFor": "target-dv",
+			}
+			dv := createPopulatedDV()
+			dv.Name = "target-dv"
+			dv.Namespace = "target-ns"
+			dv.Labels = map[string]string{
+				transfer.LabelObjectTransferUID: string(xfer.UID),
+			}
+
+			r := createReconciler(xfer, pvc, dv)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, dv.Namespace, dv.Name, dv)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = getResource(r.Client, pvc.Namespace, pvc.Name, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations).ToNot(HaveKey("cdi.kubevirt.io/storage.populatedFor"))
+		})
+
+		It("Should not touch a target DataVolume it did not create", func() {
+			t := metav1.Now()
+			xfer := dvTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			sourcePVC := createBoundPVC()
+			sourcePVC.Name = "source-dv"
+			dv := createPopulatedDV()
+			dv.Name = "target-dv"
+			dv.Namespace = "target-ns"
+
+			r := createReconciler(xfer, sourcePVC, dv)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, dv.Namespace, dv.Name, dv)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should move back the PVC the PVC transfer created", func() {
+			t := metav1.Now()
+			xfer := dvTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			pvc := createBoundPVC()
+			pvc.Namespace = "target-ns"
+			pvc.Name = "target-dv"
+			pvc.Labels = map[string]string{
+				transfer.LabelObjectTransferUID: string(xfer.UID),
+			}
+			reverseTransfer := &cdiv1.ObjectTransfer{}
+
+			r := createReconciler(xfer, pvc)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", "pvc-rollback-"+string(xfer.UID), reverseTransfer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reverseTransfer.Spec.Source.Name).To(Equal("target-dv"))
+			Expect(*reverseTransfer.Spec.Target.Name).To(Equal("source-dv"))
+		})
+
+		It("Should restore the source DataVolume", func() {
+			t := metav1.Now()
+			xfer := dvTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			pvc := createBoundPVC()
+			pvc.Name = "source-dv"
+			pvc.Labels = map[string]string{
+				transfer.LabelObjectTransferUID: string(xfer.UID),
+			}
+			dv := &cdiv1.DataVolume{}
+
+			r := createReconciler(xfer, pvc)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, pvc.Namespace, pvc.Name, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, "source-ns", "source-dv", dv)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"]).To(Equal("source-dv"))
+			Expect(pvc.Labels).ToNot(HaveKey(transfer.LabelObjectTransferUID))
+			Expect(dv.Annotations).ToNot(HaveKey("cdi.kubevirt.io/objectTransferName"))
+
+			_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", xfer.Name, xfer)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(xfer.Finalizers).To(HaveLen(0))
+			checkJournal(xfer, "SourcePVCReacquired", "SourceRestored", "RolledBack")
+			checkCompleteFalse(xfer, "Rolled back", "")
+		})
 	})
 })
 
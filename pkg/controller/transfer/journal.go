@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"encoding/json"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// The journal is kept in the status data of the ObjectTransfer. Every change a transfer makes to the cluster is
+// recorded once it is done, in order, and persisted with the next status update, so the last steps can be missing
+// after a crash. The journal describes what a transfer did, resuming and rolling back never rely on it: they work from
+// the state of the objects, and the objects a transfer creates carry its UID in the LabelObjectTransferUID label.
+const (
+	journalKey = "journal"
+
+	stepSourceReserved        = "SourceReserved"
+	stepSourceSaved           = "SourceSaved"
+	stepSourceReleased        = "SourceReleased"
+	stepSourceDeleted         = "SourceDeleted"
+	stepSourceRestored        = "SourceRestored"
+	stepTargetCreated         = "TargetCreated"
+	stepTargetDeleted         = "TargetDeleted"
+	stepPVReclaimSaved        = "PVReclaimSaved"
+	stepPVRetained            = "PVRetained"
+	stepPVClaimRefUpdated     = "PVClaimRefUpdated"
+	stepPVClaimRefRestored    = "PVClaimRefRestored"
+	stepPVReclaimRestored     = "PVReclaimRestored"
+	stepContentPolicySaved    = "ContentPolicySaved"
+	stepContentRetained       = "ContentRetained"
+	stepContentRefUpdated     = "ContentRefUpdated"
+	stepContentRefRestored    = "ContentRefRestored"
+	stepContentPolicyRestored = "ContentPolicyRestored"
+	stepPVCTransferCreated    = "PVCTransferCreated"
+	stepPVCTransferDeleted    = "PVCTransferDeleted"
+	stepPVCRollbackCreated    = "PVCRollbackCreated"
+	stepSourcePVCReleased     = "SourcePVCReleased"
+	stepSourcePVCReacquired   = "SourcePVCReacquired"
+	stepComplete              = "Complete"
+	stepRolledBack            = "RolledBack"
+)
+
+// journalEntry is a step of a transfer, Object identifies what the step changed
+type journalEntry struct {
+	Step   string `json:"step"`
+	Object string `json:"object,omitempty"`
+}
+
+func readJournal(ot *cdiv1.ObjectTransfer) []journalEntry {
+	var journal []journalEntry
+	if s, ok := ot.Status.Data[journalKey]; ok {
+		// a corrupt journal is treated as empty, rollback then only relies on the state of the objects
+		_ = json.Unmarshal([]byte(s), &journal)
+	}
+	return journal
+}
+
+// recordStep appends step to the journal unless it is the last entry already, so a step repeated by a retried
+// reconcile is only recorded once. The journal is persisted with the next status update.
+func recordStep(ot *cdiv1.ObjectTransfer, step, object string) {
+	journal := readJournal(ot)
+	if n := len(journal); n > 0 && journal[n-1].Step == step && journal[n-1].Object == object {
+		return
+	}
+	journal = append(journal, journalEntry{Step: step, Object: object})
+
+	bs, err := json.Marshal(journal)
+	if err != nil {
+		return
+	}
+	if ot.Status.Data == nil {
+		ot.Status.Data = make(map[string]string)
+	}
+	ot.Status.Data[journalKey] = string(bs)
+}
@@ -116,6 +116,7 @@ func (h *pvcTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Du
 	reclaim, ok := ot.Status.Data["pvReclaim"]
 	if !ok {
 		ot.Status.Data["pvReclaim"] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+		recordStep(ot, stepPVReclaimSaved, pv.Name)
 		if err := h.reconciler.setCompleteConditionRunning(ot); err != nil {
 			return 0, err
 		}
@@ -135,6 +136,7 @@ func (h *pvcTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Du
 			if err := h.reconciler.updateResource(ot, pv); err != nil {
 				return 0, h.reconciler.setCompleteConditionError(ot, err)
 			}
+			recordStep(ot, stepPVRetained, pv.Name)
 
 			return 0, h.reconciler.setCompleteConditionRunning(ot)
 		}
@@ -143,6 +145,7 @@ func (h *pvcTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Du
 			if err := h.reconciler.Client.Delete(context.TODO(), source); err != nil {
 				return 0, h.reconciler.setCompleteConditionError(ot, err)
 			}
+			recordStep(ot, stepSourceDeleted, string(source.UID))
 		}
 
 		return 0, h.reconciler.setCompleteConditionRunning(ot)
@@ -158,6 +161,7 @@ func (h *pvcTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Du
 		if err := h.reconciler.updateResource(ot, pv); err != nil {
 			return 0, h.reconciler.setCompleteConditionError(ot, err)
 		}
+		recordStep(ot, stepPVClaimRefUpdated, pv.Name)
 	}
 
 	if pv.Spec.ClaimRef.Namespace != getTransferTargetNamespace(ot) ||
@@ -202,15 +206,96 @@ func (h *pvcTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Du
 		if err := h.reconciler.updateResource(ot, pv); err != nil {
 			return 0, h.reconciler.setCompleteConditionError(ot, err)
 		}
+		recordStep(ot, stepPVReclaimRestored, pv.Name)
 
 		return 0, h.reconciler.setCompleteConditionRunning(ot)
 	}
 
-	ot.Status.Phase = cdiv1.ObjectTransferComplete
-	ot.Status.Data = nil
-	if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionTrue, "Transfer complete", ""); err != nil {
-		return 0, err
+	return 0, h.reconciler.setCompleted(ot)
+}
+
+func (h *pvcTransferHandler) ReconcileRollback(ot *cdiv1.ObjectTransfer) (bool, error) {
+	source := &corev1.PersistentVolumeClaim{}
+	sourceExists, err := h.reconciler.getSourceResource(ot, source)
+	if err != nil {
+		return false, err
+	}
+
+	pvName, ok := ot.Status.Data["pvName"]
+	if !ok {
+		// the transfer did not start, at most the source is reserved
+		return true, h.releaseIfExists(ot, source, sourceExists)
+	}
+
+	pv := &corev1.PersistentVolume{}
+	pvExists, err := h.reconciler.getResource("", pvName, pv)
+	if err != nil {
+		return false, err
+	}
+
+	if !pvExists {
+		h.reconciler.logger(ot).Info("PV does not exist, nothing to restore", "pv", pvName)
+		return true, h.releaseIfExists(ot, source, sourceExists)
+	}
+
+	// a target bound to the PV can only have been created by the transfer
+	target := &corev1.PersistentVolumeClaim{}
+	targetExists, err := h.reconciler.getTargetResource(ot, target)
+	if err != nil {
+		return false, err
+	}
+
+	if targetExists && target.Spec.VolumeName == pvName {
+		if target.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), target); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepTargetDeleted, string(target.UID))
+		}
+
+		return false, nil
+	}
+
+	if !sourceExists {
+		claimRef := pv.Spec.ClaimRef
+		if claimRef == nil ||
+			claimRef.Namespace != ot.Spec.Source.Namespace ||
+			claimRef.Name != ot.Spec.Source.Name ||
+			claimRef.UID != "" {
+			// the PV is reserved for the source that is restored next
+			pv.Spec.ClaimRef = &corev1.ObjectReference{
+				Namespace: ot.Spec.Source.Namespace,
+				Name:      ot.Spec.Source.Name,
+			}
+			if err := h.reconciler.updateResource(ot, pv); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepPVClaimRefRestored, pv.Name)
+		}
+
+		source = &corev1.PersistentVolumeClaim{}
+		if err := h.reconciler.restoreObjectTransferSource(ot, source, func(o client.Object) {
+			delete(o.GetAnnotations(), annBindCompleted)
+		}); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if source.DeletionTimestamp != nil || source.Status.Phase != corev1.ClaimBound {
+		// wait for the source to be deleted before restoring it, or for the restored source to be bound
+		return false, nil
+	}
+
+	reclaim, ok := ot.Status.Data["pvReclaim"]
+	if ok && pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimPolicy(reclaim) {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimPolicy(reclaim)
+		if err := h.reconciler.updateResource(ot, pv); err != nil {
+			return false, err
+		}
+		recordStep(ot, stepPVReclaimRestored, pv.Name)
 	}
 
-	return 0, nil
+	return true, h.reconciler.releaseSource(ot, source)
 }
@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/controller/transfer"
 )
 
 var _ = Describe("PVC Transfer Tests", func() {
@@ -173,6 +174,8 @@ var _ = Describe("PVC Transfer Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
+			checkJournal(xfer, "SourceReserved", "SourceSaved")
+			delete(xfer.Status.Data, "journal")
 			Expect(xfer.Status.Data).To(Equal(pvcTransferRunning().Status.Data))
 			checkCompleteFalse(xfer, "Running", "")
 		})
@@ -305,10 +308,30 @@ var _ = Describe("PVC Transfer Tests", func() {
 			err = getResource(r.Client, "target-ns", "target-pvc", pvc)
 			Expect(err).ToNot(HaveOccurred())
 
+			Expect(pvc.Labels[transfer.LabelObjectTransferUID]).To(Equal("uid-pvcTransfer"))
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
 			checkCompleteFalse(xfer, "Running", "")
 		})
 
+		It("Should label the target with the UID of the parent transfer", func() {
+			parent := dvTransfer(cdiv1.ObjectTransferRunning)
+			xfer := pvcTransferRunning()
+			xfer.Spec.ParentName = &parent.Name
+			xfer.Status.Data["pvReclaim"] = "Delete"
+			pv := sourcePV()
+			pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+			pv.Spec.ClaimRef = nil
+			pvc := &corev1.PersistentVolumeClaim{}
+
+			r := createReconciler(parent, xfer, pv)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "target-ns", "target-pvc", pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Labels[transfer.LabelObjectTransferUID]).To(Equal(string(parent.UID)))
+		})
+
 		It("Should wait for target to be bound", func() {
 			xfer := pvcTransferRunning()
 			xfer.Status.Data["pvReclaim"] = "Delete"
@@ -399,8 +422,100 @@ var _ = Describe("PVC Transfer Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferComplete))
+			Expect(xfer.Status.Data).To(HaveLen(1))
+			checkJournal(xfer, "Complete")
 			checkCompleteTrue(xfer)
 		})
+
+		It("Should release source when deleted before running", func() {
+			t := metav1.Now()
+			xfer := pvcTransfer(cdiv1.ObjectTransferPending)
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			pvc := createBoundPVC()
+			pvc.Annotations = map[string]string{
+				"cdi.kubevirt.io/objectTransferName": "pvcTransfer",
+			}
+
+			r := createReconciler(xfer, sourcePV(), pvc)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", xfer.Name, xfer)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, pvc.Namespace, pvc.Name, pvc)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pvc.Annotations).ToNot(HaveKey("cdi.kubevirt.io/objectTransferName"))
+			Expect(xfer.Finalizers).To(HaveLen(0))
+			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRollingBack))
+			checkJournal(xfer, "SourceReleased", "RolledBack")
+			checkCompleteFalse(xfer, "Rolled back", "")
+		})
+
+		It("Should roll back a transfer that created the target", func() {
+			t := metav1.Now()
+			xfer := pvcTransferRunning()
+			xfer.Finalizers = []string{
+				"cdi.kubevirt.io/objectTransfer",
+			}
+			xfer.DeletionTimestamp = &t
+			xfer.Status.Data["pvReclaim"] = "Delete"
+			pv := sourcePV()
+			pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+			pv.Spec.ClaimRef.Namespace = "target-ns"
+			pv.Spec.ClaimRef.Name = "target-pvc"
+			pv.Spec.ClaimRef.UID = types.UID("uid-target-pvc")
+			target := createBoundPVC()
+			target.Namespace = "target-ns"
+			target.Name = "target-pvc"
+			source := &corev1.PersistentVolumeClaim{}
+
+			r := createReconciler(xfer, pv, target)
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", xfer.Name, xfer)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, target.Namespace, target.Name, target)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRollingBack))
+			checkCompleteFalse(xfer, "Rolling back", "")
+
+			_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", pv.Name, pv)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, "source-ns", "source-pvc", source)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pv.Spec.ClaimRef.Namespace).To(Equal("source-ns"))
+			Expect(pv.Spec.ClaimRef.Name).To(Equal("source-pvc"))
+			Expect(pv.Spec.ClaimRef.UID).To(BeEmpty())
+			Expect(source.Spec.VolumeName).To(Equal(pv.Name))
+
+			// the restored source is bound by the PV controller
+			source.Status.Phase = corev1.ClaimBound
+			err = r.Client.Status().Update(context.TODO(), source)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getResource(r.Client, "", xfer.Name, xfer)
+			Expect(err).ToNot(HaveOccurred())
+			err = getResource(r.Client, "", pv.Name, pv)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+			Expect(xfer.Finalizers).To(HaveLen(0))
+			checkJournal(xfer, "TargetDeleted", "PVClaimRefRestored", "SourceRestored", "PVReclaimRestored", "RolledBack")
+			checkCompleteFalse(xfer, "Rolled back", "")
+		})
 	})
 })
 
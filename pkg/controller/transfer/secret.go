@@ -0,0 +1,210 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// secretTransferClusterRole is the role the controller needs in the source and target namespace of a Secret transfer
+const secretTransferClusterRole = "cdi.kubevirt.io:secret-transfer"
+
+// secretTransferHandler copies a Secret to the target and deletes the source. The saved source does not include the
+// secret data, so it is never written to the ObjectTransfer status, the data is copied from the live object instead.
+// Secrets are not watched, they are read from the API server and the transfer is requeued until it is done.
+type secretTransferHandler struct {
+	objectTransferHandler
+}
+
+func (h *secretTransferHandler) getSecret(namespace, name string, secret *corev1.Secret) (bool, error) {
+	if err := h.reconciler.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (h *secretTransferHandler) getSource(ot *cdiv1.ObjectTransfer, secret *corev1.Secret) (bool, error) {
+	return h.getSecret(ot.Spec.Source.Namespace, ot.Spec.Source.Name, secret)
+}
+
+func (h *secretTransferHandler) getTarget(ot *cdiv1.ObjectTransfer, secret *corev1.Secret) (bool, error) {
+	return h.getSecret(getTransferTargetNamespace(ot), getTransferTargetName(ot), secret)
+}
+
+// handleError reports an error in the ObjectTransfer status. The controller may only access Secrets in namespaces
+// that bind secretTransferClusterRole to it, a denied access is retried until the role is bound.
+func (h *secretTransferHandler) handleError(ot *cdiv1.ObjectTransfer, err error) (time.Duration, error) {
+	if errors.IsForbidden(err) {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Secret access denied", secretTransferClusterRole); err != nil {
+			return 0, err
+		}
+
+		return defaultRequeue, nil
+	}
+
+	return 0, h.reconciler.setCompleteConditionError(ot, err)
+}
+
+func (h *secretTransferHandler) ReconcilePending(ot *cdiv1.ObjectTransfer) (time.Duration, error) {
+	secret := &corev1.Secret{}
+	secretExists, err := h.getSource(ot, secret)
+	if err != nil {
+		return h.handleError(ot, err)
+	}
+
+	if !secretExists {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "No source", ""); err != nil {
+			return 0, err
+		}
+
+		return defaultRequeue, nil
+	}
+
+	if secret.Type == corev1.SecretTypeServiceAccountToken {
+		// the token is only valid for the service account it was created for
+		ot.Status.Phase = cdiv1.ObjectTransferError
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Unsupported secret type", string(secret.Type)); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	// the target may be created after the webhook checked it, so this is done again before the source is reserved
+	targetExists, err := h.getTarget(ot, &corev1.Secret{})
+	if err != nil {
+		return h.handleError(ot, err)
+	}
+
+	if targetExists {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Target already exists", ""); err != nil {
+			return 0, err
+		}
+
+		return defaultRequeue, nil
+	}
+
+	if err := h.reconciler.pendingHelperWithFilter(ot, secret, func(o client.Object) {
+		secret := o.(*corev1.Secret)
+		secret.Data = nil
+		secret.StringData = nil
+	}, nil); err != nil {
+		return h.handleError(ot, err)
+	}
+
+	return defaultRequeue, nil
+}
+
+func (h *secretTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Duration, error) {
+	source := &corev1.Secret{}
+	sourceExists, err := h.getSource(ot, source)
+	if err != nil {
+		return h.handleError(ot, err)
+	}
+
+	target := &corev1.Secret{}
+	targetExists, err := h.getTarget(ot, target)
+	if err != nil {
+		return h.handleError(ot, err)
+	}
+
+	if !targetExists {
+		if !sourceExists {
+			ot.Status.Phase = cdiv1.ObjectTransferError
+			if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Source deleted before it was copied", ""); err != nil {
+				return 0, err
+			}
+
+			return 0, nil
+		}
+
+		target = &corev1.Secret{}
+		if err := h.reconciler.createObjectTransferTarget(ot, target, copySecretData(source)); err != nil {
+			return h.handleError(ot, err)
+		}
+
+		return defaultRequeue, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	if sourceExists {
+		// only a target created by this transfer replaces the source
+		if !isCreatedByTransfer(ot, target) {
+			ot.Status.Phase = cdiv1.ObjectTransferError
+			if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Target already exists", ""); err != nil {
+				return 0, err
+			}
+
+			return 0, nil
+		}
+
+		if source.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), source); err != nil {
+				return h.handleError(ot, err)
+			}
+			recordStep(ot, stepSourceDeleted, string(source.UID))
+		}
+
+		return defaultRequeue, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	return 0, h.reconciler.setCompleted(ot)
+}
+
+func (h *secretTransferHandler) ReconcileRollback(ot *cdiv1.ObjectTransfer) (bool, error) {
+	source := &corev1.Secret{}
+	sourceExists, err := h.getSource(ot, source)
+	if err != nil {
+		return false, err
+	}
+
+	target := &corev1.Secret{}
+	targetExists, err := h.getTarget(ot, target)
+	if err != nil {
+		return false, err
+	}
+
+	createdTarget := targetExists && isCreatedByTransfer(ot, target)
+
+	if !sourceExists {
+		if _, ok := ot.Status.Data["source"]; !ok || !createdTarget {
+			h.reconciler.logger(ot).Info("Source secret does not exist and can not be restored")
+			return true, nil
+		}
+
+		if err := h.reconciler.restoreObjectTransferSource(ot, &corev1.Secret{}, copySecretData(target)); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if createdTarget {
+		if target.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), target); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepTargetDeleted, string(target.UID))
+		}
+
+		return false, nil
+	}
+
+	return true, h.reconciler.releaseSource(ot, source)
+}
+
+func copySecretData(from *corev1.Secret) func(client.Object) {
+	return func(o client.Object) {
+		o.(*corev1.Secret).Data = from.Data
+	}
+}
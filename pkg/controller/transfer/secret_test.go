@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("Secret Transfer Tests", func() {
+	It("Should not transfer service account tokens", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferPending)
+		secret := createSecret()
+		secret.Type = corev1.SecretTypeServiceAccountToken
+
+		r := createReconciler(xfer, secret)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferError))
+		checkCompleteFalse(xfer, "Unsupported secret type", string(corev1.SecretTypeServiceAccountToken))
+	})
+
+	It("Should become running without saving the secret data", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferPending)
+
+		r := createReconciler(xfer, createSecret())
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
+		Expect(xfer.Status.Data["source"]).ToNot(ContainSubstring(`"data"`))
+		checkCompleteFalse(xfer, "Running", "")
+	})
+
+	It("Should copy the secret and delete the source", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferPending)
+		source := createSecret()
+		target := &corev1.Secret{}
+
+		r := createReconciler(xfer, source)
+		for i := 0; i < 4; i++ {
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		err := getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "target-ns", "target-secret", target)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, source.Namespace, source.Name, source)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		Expect(target.Data).To(Equal(createSecret().Data))
+		Expect(target.Annotations).ToNot(HaveKey("cdi.kubevirt.io/objectTransferName"))
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferComplete))
+		checkJournal(xfer, "SourceReserved", "SourceSaved", "TargetCreated", "SourceDeleted", "Complete")
+		checkCompleteTrue(xfer)
+	})
+
+	It("Should restore the source on rollback", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferPending)
+		source := createSecret()
+
+		r := createReconciler(xfer, source)
+		for i := 0; i < 3; i++ {
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		err := getResource(r.Client, source.Namespace, source.Name, source)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		t := metav1.Now()
+		xfer.DeletionTimestamp = &t
+		err = r.Client.Update(context.TODO(), xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "source-ns", "source-secret", source)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "target-ns", "target-secret", &corev1.Secret{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		Expect(source.Data).To(Equal(createSecret().Data))
+		Expect(xfer.Finalizers).To(HaveLen(0))
+		checkJournal(xfer, "SourceReserved", "SourceSaved", "TargetCreated", "SourceDeleted", "SourceRestored", "TargetDeleted", "RolledBack")
+		checkCompleteFalse(xfer, "Rolled back", "")
+	})
+
+	It("Should wait for access to the secrets", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferPending)
+
+		r := createReconciler(xfer, createSecret())
+		r.APIReader = &forbiddenReader{}
+		result, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferPending))
+		checkCompleteFalse(xfer, "Secret access denied", "cdi.kubevirt.io:secret-transfer")
+	})
+
+	It("Should keep the source and a target it did not create", func() {
+		xfer := secretTransfer(cdiv1.ObjectTransferRunning)
+		source := createSecret()
+		source.Annotations = map[string]string{"cdi.kubevirt.io/objectTransferName": xfer.Name}
+		target := createSecret()
+		target.Namespace = "target-ns"
+		target.Name = "target-secret"
+
+		r := createReconciler(xfer, source, target)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferError))
+		checkCompleteFalse(xfer, "Target already exists", "")
+
+		t := metav1.Now()
+		xfer.DeletionTimestamp = &t
+		err = r.Client.Update(context.TODO(), xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "source-ns", "source-secret", source)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "target-ns", "target-secret", target)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(source.Annotations).ToNot(HaveKey("cdi.kubevirt.io/objectTransferName"))
+		checkCompleteFalse(xfer, "Rolled back", "")
+	})
+})
+
+type forbiddenReader struct{}
+
+func (r *forbiddenReader) Get(_ context.Context, key client.ObjectKey, _ client.Object) error {
+	return errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, key.Name, fmt.Errorf("denied"))
+}
+
+func (r *forbiddenReader) List(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+	return errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", fmt.Errorf("denied"))
+}
+
+func createSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "source-ns",
+			Name:      "source-secret",
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"accessKeyId": []byte("key"),
+		},
+	}
+}
+
+func secretTransfer(phase cdiv1.ObjectTransferPhase) *cdiv1.ObjectTransfer {
+	targetNamespace := "target-ns"
+	targetName := "target-secret"
+	return &cdiv1.ObjectTransfer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "secretTransfer",
+			UID:        types.UID("uid-secretTransfer"),
+			Finalizers: []string{"cdi.kubevirt.io/objectTransfer"},
+		},
+		Spec: cdiv1.ObjectTransferSpec{
+			Source: cdiv1.TransferSource{
+				Kind:      "Secret",
+				Name:      "source-secret",
+				Namespace: "source-ns",
+			},
+			Target: cdiv1.TransferTarget{
+				Namespace: &targetNamespace,
+				Name:      &targetName,
+			},
+		},
+		Status: cdiv1.ObjectTransferStatus{
+			Phase: phase,
+		},
+	}
+}
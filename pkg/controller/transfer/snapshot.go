@@ -0,0 +1,314 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdicontroller "kubevirt.io/containerized-data-importer/pkg/controller"
+)
+
+const (
+	snapshotSourceFinalizer = "snapshot.storage.kubernetes.io/volumesnapshot-as-source-protection"
+)
+
+// snapshotTransferHandler moves a VolumeSnapshot the same way a PVC is moved, the VolumeSnapshotContent is retained
+// while the snapshot is deleted and then bound to the target snapshot
+type snapshotTransferHandler struct {
+	objectTransferHandler
+}
+
+func (h *snapshotTransferHandler) ReconcilePending(ot *cdiv1.ObjectTransfer) (time.Duration, error) {
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	snapshotExists, err := h.reconciler.getSourceResource(ot, snapshot)
+	if err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	if !snapshotExists {
+		// will reconcile again when snapshot is created/updated
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "No source", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	contentName := boundContentName(snapshot)
+	if contentName == "" {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Snapshot not ready", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	if cdicontroller.HasFinalizer(snapshot, snapshotSourceFinalizer) {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Snapshot has finalizer: "+snapshotSourceFinalizer, ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	content := &snapshotv1.VolumeSnapshotContent{}
+	if _, err := h.reconciler.getResource("", contentName, content); err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Namespace != snapshot.Namespace || ref.Name != snapshot.Name {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "VolumeSnapshotContent not bound", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	// the webhook can not check snapshot targets, so this is done before the source is reserved
+	targetExists, err := h.reconciler.getTargetResource(ot, &snapshotv1.VolumeSnapshot{})
+	if err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	if targetExists {
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Target already exists", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	data := map[string]string{
+		"contentName": content.Name,
+	}
+
+	return 0, h.reconciler.pendingHelperWithFilter(ot, snapshot, func(o client.Object) {
+		o.(*snapshotv1.VolumeSnapshot).Status = nil
+	}, data)
+}
+
+func (h *snapshotTransferHandler) ReconcileRunning(ot *cdiv1.ObjectTransfer) (time.Duration, error) {
+	contentName, ok := ot.Status.Data["contentName"]
+	if !ok {
+		ot.Status.Phase = cdiv1.ObjectTransferError
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "VolumeSnapshotContent name missing", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	content := &snapshotv1.VolumeSnapshotContent{}
+	if err := h.reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: contentName}, content); err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	policy, ok := ot.Status.Data["contentDeletionPolicy"]
+	if !ok {
+		ot.Status.Data["contentDeletionPolicy"] = string(content.Spec.DeletionPolicy)
+		recordStep(ot, stepContentPolicySaved, content.Name)
+		if err := h.reconciler.setCompleteConditionRunning(ot); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	source := &snapshotv1.VolumeSnapshot{}
+	sourceExists, err := h.reconciler.getSourceResource(ot, source)
+	if err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	if sourceExists {
+		if content.Spec.DeletionPolicy != snapshotv1.VolumeSnapshotContentRetain {
+			content.Spec.DeletionPolicy = snapshotv1.VolumeSnapshotContentRetain
+			if err := h.reconciler.updateResource(ot, content); err != nil {
+				return 0, h.reconciler.setCompleteConditionError(ot, err)
+			}
+			recordStep(ot, stepContentRetained, content.Name)
+
+			return 0, h.reconciler.setCompleteConditionRunning(ot)
+		}
+
+		if source.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), source); err != nil {
+				return 0, h.reconciler.setCompleteConditionError(ot, err)
+			}
+			recordStep(ot, stepSourceDeleted, string(source.UID))
+		}
+
+		return 0, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Namespace == ot.Spec.Source.Namespace && ref.Name == ot.Spec.Source.Name {
+		setSnapshotRef(content, getTransferTargetNamespace(ot), getTransferTargetName(ot))
+		if err := h.reconciler.updateResource(ot, content); err != nil {
+			return 0, h.reconciler.setCompleteConditionError(ot, err)
+		}
+		recordStep(ot, stepContentRefUpdated, content.Name)
+	}
+
+	ref = content.Spec.VolumeSnapshotRef
+	if ref.Namespace != getTransferTargetNamespace(ot) || ref.Name != getTransferTargetName(ot) {
+		ot.Status.Phase = cdiv1.ObjectTransferError
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "VolumeSnapshotContent bound to wrong VolumeSnapshot", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	target := &snapshotv1.VolumeSnapshot{}
+	targetExists, err := h.reconciler.getTargetResource(ot, target)
+	if err != nil {
+		return 0, h.reconciler.setCompleteConditionError(ot, err)
+	}
+
+	if !targetExists {
+		target = &snapshotv1.VolumeSnapshot{}
+		if err := h.reconciler.createObjectTransferTarget(ot, target, bindToContent(contentName)); err != nil {
+			return 0, h.reconciler.setCompleteConditionError(ot, err)
+		}
+
+		return 0, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	if boundContentName(target) != contentName {
+		ot.Status.Phase = cdiv1.ObjectTransferRunning
+		if err := h.reconciler.setAndUpdateCompleteCondition(ot, corev1.ConditionFalse, "Waiting for target to be ready", ""); err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	if content.Spec.DeletionPolicy != snapshotv1.DeletionPolicy(policy) {
+		content.Spec.DeletionPolicy = snapshotv1.DeletionPolicy(policy)
+		if err := h.reconciler.updateResource(ot, content); err != nil {
+			return 0, h.reconciler.setCompleteConditionError(ot, err)
+		}
+		recordStep(ot, stepContentPolicyRestored, content.Name)
+
+		return 0, h.reconciler.setCompleteConditionRunning(ot)
+	}
+
+	return 0, h.reconciler.setCompleted(ot)
+}
+
+func (h *snapshotTransferHandler) ReconcileRollback(ot *cdiv1.ObjectTransfer) (bool, error) {
+	source := &snapshotv1.VolumeSnapshot{}
+	sourceExists, err := h.reconciler.getSourceResource(ot, source)
+	if err != nil {
+		return false, err
+	}
+
+	contentName, ok := ot.Status.Data["contentName"]
+	if !ok {
+		// the transfer did not start, at most the source is reserved
+		return true, h.releaseIfExists(ot, source, sourceExists)
+	}
+
+	content := &snapshotv1.VolumeSnapshotContent{}
+	contentExists, err := h.reconciler.getResource("", contentName, content)
+	if err != nil {
+		return false, err
+	}
+
+	if !contentExists {
+		h.reconciler.logger(ot).Info("VolumeSnapshotContent does not exist, nothing to restore", "content", contentName)
+		return true, h.releaseIfExists(ot, source, sourceExists)
+	}
+
+	// a target bound to the content can only have been created by the transfer
+	target := &snapshotv1.VolumeSnapshot{}
+	targetExists, err := h.reconciler.getTargetResource(ot, target)
+	if err != nil {
+		return false, err
+	}
+
+	if targetExists && target.Spec.Source.VolumeSnapshotContentName != nil && *target.Spec.Source.VolumeSnapshotContentName == contentName {
+		if target.DeletionTimestamp == nil {
+			if err := h.reconciler.Client.Delete(context.TODO(), target); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepTargetDeleted, string(target.UID))
+		}
+
+		return false, nil
+	}
+
+	if !sourceExists {
+		ref := content.Spec.VolumeSnapshotRef
+		if ref.Namespace != ot.Spec.Source.Namespace || ref.Name != ot.Spec.Source.Name || ref.UID != "" {
+			// the content is reserved for the source that is restored next
+			setSnapshotRef(content, ot.Spec.Source.Namespace, ot.Spec.Source.Name)
+			if err := h.reconciler.updateResource(ot, content); err != nil {
+				return false, err
+			}
+			recordStep(ot, stepContentRefRestored, content.Name)
+		}
+
+		if err := h.reconciler.restoreObjectTransferSource(ot, &snapshotv1.VolumeSnapshot{}, bindToContent(contentName)); err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if source.DeletionTimestamp != nil || boundContentName(source) != contentName {
+		// wait for the source to be deleted before restoring it, or for the restored source to be ready
+		return false, nil
+	}
+
+	policy, ok := ot.Status.Data["contentDeletionPolicy"]
+	if ok && content.Spec.DeletionPolicy != snapshotv1.DeletionPolicy(policy) {
+		content.Spec.DeletionPolicy = snapshotv1.DeletionPolicy(policy)
+		if err := h.reconciler.updateResource(ot, content); err != nil {
+			return false, err
+		}
+		recordStep(ot, stepContentPolicyRestored, content.Name)
+	}
+
+	return true, h.reconciler.releaseSource(ot, source)
+}
+
+// boundContentName returns the name of the content of a ready snapshot, or an empty string
+func boundContentName(snapshot *snapshotv1.VolumeSnapshot) string {
+	status := snapshot.Status
+	if status == nil ||
+		status.ReadyToUse == nil || !*status.ReadyToUse ||
+		status.BoundVolumeSnapshotContentName == nil {
+		return ""
+	}
+
+	return *status.BoundVolumeSnapshotContentName
+}
+
+// setSnapshotRef points the content at a snapshot that does not exist yet, it is bound once the snapshot is created
+func setSnapshotRef(content *snapshotv1.VolumeSnapshotContent, namespace, name string) {
+	ref := content.Spec.VolumeSnapshotRef
+	content.Spec.VolumeSnapshotRef = corev1.ObjectReference{
+		Kind:       ref.Kind,
+		APIVersion: ref.APIVersion,
+		Namespace:  namespace,
+		Name:       name,
+	}
+}
+
+// bindToContent makes a saved snapshot a pre-provisioned snapshot of the content
+func bindToContent(contentName string) func(client.Object) {
+	return func(o client.Object) {
+		snapshot := o.(*snapshotv1.VolumeSnapshot)
+		snapshot.Spec.Source = snapshotv1.VolumeSnapshotSource{
+			VolumeSnapshotContentName: &contentName,
+		}
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("VolumeSnapshot Transfer Tests", func() {
+	It("Should handle snapshot not ready", func() {
+		xfer := snapshotTransfer(cdiv1.ObjectTransferPending)
+		snapshot := createReadySnapshot()
+		snapshot.Status.ReadyToUse = &[]bool{false}[0]
+
+		r := createReconciler(xfer, snapshot, sourceContent())
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferPending))
+		checkCompleteFalse(xfer, "Snapshot not ready", "")
+	})
+
+	It("Should handle existing target", func() {
+		xfer := snapshotTransfer(cdiv1.ObjectTransferPending)
+		target := createReadySnapshot()
+		target.Namespace = "target-ns"
+		target.Name = "target-snapshot"
+
+		r := createReconciler(xfer, createReadySnapshot(), sourceContent(), target)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferPending))
+		checkCompleteFalse(xfer, "Target already exists", "")
+	})
+
+	It("Should become running", func() {
+		xfer := snapshotTransfer(cdiv1.ObjectTransferPending)
+
+		r := createReconciler(xfer, createReadySnapshot(), sourceContent())
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
+		delete(xfer.Status.Data, "journal")
+		Expect(xfer.Status.Data).To(Equal(snapshotTransferRunning().Status.Data))
+		checkCompleteFalse(xfer, "Running", "")
+	})
+
+	It("Should retain content and delete source", func() {
+		xfer := snapshotTransferRunning()
+		xfer.Status.Data["contentDeletionPolicy"] = "Delete"
+		content := sourceContent()
+		snapshot := createReadySnapshot()
+
+		r := createReconciler(xfer, content, snapshot)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", content.Name, content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content.Spec.DeletionPolicy).To(Equal(snapshotv1.VolumeSnapshotContentRetain))
+
+		_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, snapshot.Namespace, snapshot.Name, snapshot)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferRunning))
+		checkJournal(xfer, "ContentRetained", "SourceDeleted")
+		checkCompleteFalse(xfer, "Running", "")
+	})
+
+	It("Should bind content to created target", func() {
+		xfer := snapshotTransferRunning()
+		xfer.Status.Data["contentDeletionPolicy"] = "Delete"
+		content := sourceContent()
+		content.Spec.DeletionPolicy = snapshotv1.VolumeSnapshotContentRetain
+		target := &snapshotv1.VolumeSnapshot{}
+
+		r := createReconciler(xfer, content)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", content.Name, content)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "target-ns", "target-snapshot", target)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(content.Spec.VolumeSnapshotRef.Namespace).To(Equal("target-ns"))
+		Expect(content.Spec.VolumeSnapshotRef.Name).To(Equal("target-snapshot"))
+		Expect(content.Spec.VolumeSnapshotRef.UID).To(BeEmpty())
+		Expect(target.Spec.Source.PersistentVolumeClaimName).To(BeNil())
+		Expect(*target.Spec.Source.VolumeSnapshotContentName).To(Equal(content.Name))
+	})
+
+	It("Should restore deletion policy and complete", func() {
+		xfer := snapshotTransferRunning()
+		xfer.Status.Data["contentDeletionPolicy"] = "Delete"
+		content := sourceContent()
+		content.Spec.DeletionPolicy = snapshotv1.VolumeSnapshotContentRetain
+		content.Spec.VolumeSnapshotRef.Namespace = "target-ns"
+		content.Spec.VolumeSnapshotRef.Name = "target-snapshot"
+		target := createReadySnapshot()
+		target.Namespace = "target-ns"
+		target.Name = "target-snapshot"
+
+		r := createReconciler(xfer, content, target)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "", content.Name, content)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(content.Spec.DeletionPolicy).To(Equal(snapshotv1.VolumeSnapshotContentDelete))
+		Expect(xfer.Status.Phase).To(Equal(cdiv1.ObjectTransferComplete))
+		checkJournal(xfer, "ContentPolicyRestored", "Complete")
+		checkCompleteTrue(xfer)
+	})
+
+	It("Should restore the source on rollback", func() {
+		t := metav1.Now()
+		xfer := snapshotTransferRunning()
+		xfer.Finalizers = []string{
+			"cdi.kubevirt.io/objectTransfer",
+		}
+		xfer.DeletionTimestamp = &t
+		xfer.Status.Data["contentDeletionPolicy"] = "Delete"
+		content := sourceContent()
+		content.Spec.DeletionPolicy = snapshotv1.VolumeSnapshotContentRetain
+		content.Spec.VolumeSnapshotRef.Namespace = "target-ns"
+		content.Spec.VolumeSnapshotRef.Name = "target-snapshot"
+		content.Spec.VolumeSnapshotRef.UID = types.UID("uid-target-snapshot")
+		source := &snapshotv1.VolumeSnapshot{}
+
+		r := createReconciler(xfer, content)
+		_, err := r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", content.Name, content)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "source-ns", "source-snapshot", source)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(content.Spec.VolumeSnapshotRef.Namespace).To(Equal("source-ns"))
+		Expect(content.Spec.VolumeSnapshotRef.Name).To(Equal("source-snapshot"))
+		Expect(content.Spec.VolumeSnapshotRef.UID).To(BeEmpty())
+		Expect(*source.Spec.Source.VolumeSnapshotContentName).To(Equal(content.Name))
+
+		// the restored source is bound by the snapshot controller
+		source.Status = createReadySnapshot().Status
+		err = r.Client.Update(context.TODO(), source)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = r.Reconcile(context.TODO(), rr(xfer.Name))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = getResource(r.Client, "", xfer.Name, xfer)
+		Expect(err).ToNot(HaveOccurred())
+		err = getResource(r.Client, "", content.Name, content)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(content.Spec.DeletionPolicy).To(Equal(snapshotv1.VolumeSnapshotContentDelete))
+		Expect(xfer.Finalizers).To(HaveLen(0))
+		checkJournal(xfer, "ContentRefRestored", "SourceRestored", "ContentPolicyRestored", "RolledBack")
+		checkCompleteFalse(xfer, "Rolled back", "")
+	})
+})
+
+func createReadySnapshot() *snapshotv1.VolumeSnapshot {
+	pvcName := "source-pvc"
+	contentName := "source-content"
+	ready := true
+	return &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "source-ns",
+			Name:      "source-snapshot",
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+		},
+		Status: &snapshotv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &contentName,
+			ReadyToUse:                     &ready,
+		},
+	}
+}
+
+func sourceContent() *snapshotv1.VolumeSnapshotContent {
+	return &snapshotv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source-content",
+		},
+		Spec: snapshotv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Namespace: "source-ns",
+				Name:      "source-snapshot",
+			},
+			DeletionPolicy: snapshotv1.VolumeSnapshotContentDelete,
+		},
+	}
+}
+
+func snapshotTransfer(phase cdiv1.ObjectTransferPhase) *cdiv1.ObjectTransfer {
+	targetNamespace := "target-ns"
+	targetName := "target-snapshot"
+	return &cdiv1.ObjectTransfer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "snapshotTransfer",
+			UID:  types.UID("uid-snapshotTransfer"),
+		},
+		Spec: cdiv1.ObjectTransferSpec{
+			Source: cdiv1.TransferSource{
+				Kind:      "VolumeSnapshot",
+				Name:      "source-snapshot",
+				Namespace: "source-ns",
+			},
+			Target: cdiv1.TransferTarget{
+				Namespace: &targetNamespace,
+				Name:      &targetName,
+			},
+		},
+		Status: cdiv1.ObjectTransferStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func snapshotTransferRunning() *cdiv1.ObjectTransfer {
+	t := snapshotTransfer(cdiv1.ObjectTransferRunning)
+	snapshot := createReadySnapshot()
+	snapshot.Kind = "VolumeSnapshot"
+	snapshot.APIVersion = "snapshot.storage.k8s.io/v1beta1"
+	snapshot.ResourceVersion = "1000"
+	snapshot.Annotations = map[string]string{
+		"cdi.kubevirt.io/objectTransferName": "snapshotTransfer",
+	}
+	snapshot.Status = nil
+	bs, _ := json.Marshal(snapshot)
+	t.Status.Data = map[string]string{
+		"source":      string(bs),
+		"contentName": "source-content",
+	}
+	return t
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/v2/pkg/apis/volumesnapshot/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -111,6 +113,39 @@ func watchPVs(mgr manager.Manager, ctrl controller.Controller) error {
 	))
 }
 
+func watchSnapshotContents(mgr manager.Manager, ctrl controller.Controller) error {
+	return ctrl.Watch(&source.Kind{Type: &snapshotv1.VolumeSnapshotContent{}}, handler.EnqueueRequestsFromMapFunc(
+		func(obj client.Object) []reconcile.Request {
+			content := obj.(*snapshotv1.VolumeSnapshotContent)
+			ref := content.Spec.VolumeSnapshotRef
+			value := indexKeyFunc(ref.Namespace, ref.Name)
+			return indexLookup(mgr.GetClient(), "volumesnapshot", value)
+		},
+	))
+}
+
+func addSnapshotWatches(mgr manager.Manager, ctrl controller.Controller) error {
+	if err := snapshotv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	// check if volume snapshots exist
+	err := mgr.GetAPIReader().List(context.TODO(), &snapshotv1.VolumeSnapshotList{}, client.Limit(1))
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := indexAndWatch(mgr, ctrl, &snapshotv1.VolumeSnapshot{}, "volumesnapshot"); err != nil {
+		return err
+	}
+
+	return watchSnapshotContents(mgr, ctrl)
+}
+
 func addObjectTransferControllerWatches(mgr manager.Manager, ctrl controller.Controller) error {
 	// Add schemes.
 	if err := cdiv1.AddToScheme(mgr.GetScheme()); err != nil {
@@ -133,5 +168,9 @@ func addObjectTransferControllerWatches(mgr manager.Manager, ctrl controller.Con
 		return err
 	}
 
+	if err := addSnapshotWatches(mgr, ctrl); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -61,6 +61,10 @@ const (
 	// AnnUploadPod name of the upload pod
 	AnnUploadPod = "cdi.kubevirt.io/storage.uploadPodName"
 
+	// AnnUploadNoScratch marks that the upload will be raw, optionally compressed on the wire, or a streamable qcow2
+	// image, so the upload pod is created without a scratch PVC
+	AnnUploadNoScratch = "cdi.kubevirt.io/storage.upload.noScratch"
+
 	annCreatedByUpload = "cdi.kubevirt.io/storage.createdByUploadController"
 
 	uploadServerClientName = "client.upload-server.cdi.kubevirt.io"
@@ -220,6 +224,19 @@ func (r *UploadReconciler) reconcilePVC(log logr.Logger, pvc *corev1.PersistentV
 
 	// Always try to get or create the scratch PVC for a pod that is not successful yet, if it exists nothing happens otherwise attempt to create.
 	scratchPVCName, exists := getScratchNameFromPod(pod)
+	if !exists && !isCloneTarget && isUploadNoScratch(pvc) && uploadPodRequiresScratch(pod) {
+		// The upload was expected to go without scratch space but needs it, recreate the pod with it
+		log.V(1).Info("Upload pod requires scratch space, terminating pod, and restarting with scratch space", "pod.Name", pod.Name)
+		anno[AnnRequiresScratch] = "true"
+		anno[AnnPodReady] = "false"
+		if err := r.updatePVC(pvcCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.client.Delete(context.TODO(), pod); IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
 	if exists {
 		_, err := r.getOrCreateScratchPvc(pvcCopy, pod, scratchPVCName)
 		if err != nil {
@@ -538,6 +555,15 @@ func (r *UploadReconciler) createUploadPod(args UploadPodArgs) (*v1.Pod, error)
 
 	pod := r.makeUploadPodSpec(args, podResourceRequirements, workloadNodePlacement)
 
+	// The source pod of a host assisted clone reports the progress of the clone
+	if _, isCloneTarget := args.PVC.Annotations[AnnCloneRequest]; !isCloneTarget {
+		progressTLS, err := newProgressServerTLS(r.serverCertGenerator, r.clientCAFetcher, ns, args.Name)
+		if err != nil {
+			return nil, err
+		}
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, progressTLS.envVars()...)
+	}
+
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: args.Name, Namespace: ns}, pod); err != nil {
 		if !k8serrors.IsNotFound(err) {
 			return nil, errors.Wrap(err, "upload pod should exist but couldn't retrieve it")
@@ -600,14 +626,34 @@ func addUploadControllerWatches(mgr manager.Manager, uploadController controller
 	return nil
 }
 
+// createScratchPvcNameFromPvc returns the name of the scratch PVC of the upload pod, or an empty string if the pod does
+// not get one. An upload opted out of scratch space only gets it once the upload server asked for it.
 func createScratchPvcNameFromPvc(pvc *v1.PersistentVolumeClaim, isCloneTarget bool) string {
 	if isCloneTarget {
 		return ""
 	}
+	if isUploadNoScratch(pvc) {
+		if requiresScratch, err := strconv.ParseBool(getValueFromAnnotation(pvc, AnnRequiresScratch)); err != nil || !requiresScratch {
+			return ""
+		}
+	}
 
 	return naming.GetResourceName(pvc.Name, common.ScratchNameSuffix)
 }
 
+// isUploadNoScratch returns true if the upload pod of the PVC is created without scratch space
+func isUploadNoScratch(pvc *v1.PersistentVolumeClaim) bool {
+	noScratch, err := strconv.ParseBool(getValueFromAnnotation(pvc, AnnUploadNoScratch))
+	return err == nil && noScratch
+}
+
+// uploadPodRequiresScratch returns true if the upload server exited because the upload needs scratch space
+func uploadPodRequiresScratch(pod *v1.Pod) bool {
+	return len(pod.Status.ContainerStatuses) > 0 &&
+		pod.Status.ContainerStatuses[0].LastTerminationState.Terminated != nil &&
+		pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.ExitCode == common.ScratchSpaceNeededExitCode
+}
+
 // getUploadResourceName returns the name given to upload resources
 func getUploadResourceNameFromPvc(pvc *corev1.PersistentVolumeClaim) string {
 	podName, ok := pvc.Annotations[AnnUploadPod]
@@ -741,12 +787,37 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 
 	if !checkPVC(args.PVC, AnnCloneRequest, r.log.WithValues("Name", args.PVC.Name, "Namspace", args.PVC.Namespace)) {
 		pod.Spec.SecurityContext.FSGroup = &fsGroup
+
+		// Uploads report their progress, the progress of a clone is reported by the clone source pod
+		ownerUID := args.PVC.UID
+		if len(args.PVC.OwnerReferences) == 1 {
+			ownerUID = args.PVC.OwnerReferences[0].UID
+		}
+		pod.Labels[common.PrometheusLabel] = ""
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, v1.EnvVar{
+			Name:  common.OwnerUID,
+			Value: string(ownerUID),
+		})
+		pod.Spec.Containers[0].Ports = []v1.ContainerPort{
+			{
+				Name:          "metrics",
+				ContainerPort: common.UploadServerMetricsPort,
+				Protocol:      v1.ProtocolTCP,
+			},
+		}
 	}
 
 	if resourceRequirements != nil {
 		pod.Spec.Containers[0].Resources = *resourceRequirements
 	}
 
+	if checksum := args.PVC.Annotations[AnnChecksum]; checksum != "" {
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, v1.EnvVar{
+			Name:  common.UploadChecksum,
+			Value: checksum,
+		})
+	}
+
 	if getVolumeMode(args.PVC) == v1.PersistentVolumeBlock {
 		pod.Spec.Containers[0].VolumeDevices = []v1.VolumeDevice{
 			{
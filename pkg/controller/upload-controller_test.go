@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -255,6 +256,9 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uploadPod.Name).To(Equal(createUploadResourceName(testPvc.Name)))
 		Expect(uploadPod.Spec.PriorityClassName).To(Equal("p0"))
+		By("Verifying the clone source pod reports the progress instead")
+		Expect(uploadPod.Labels).ToNot(HaveKey(common.PrometheusLabel))
+		Expect(uploadPod.Spec.Containers[0].Ports).To(BeEmpty())
 
 		uploadService = &corev1.Service{}
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: createUploadResourceName("testPvc1"), Namespace: "default"}, uploadService)
@@ -418,6 +422,117 @@ var _ = Describe("reconcilePVC loop", func() {
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, scratchPvc)
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("Should pass the expected checksum to the pod", func() {
+			checksum := "sha256:" + strings.Repeat("a", 64)
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName, AnnChecksum: checksum}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.UploadChecksum, Value: checksum}))
+		})
+
+		It("Should pass the progress endpoint to the pod", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Labels).To(HaveKey(common.PrometheusLabel))
+			port, err := getPodMetricsPort(uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(port).To(Equal(common.UploadServerMetricsPort))
+			env := uploadPod.Spec.Containers[0].Env
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: common.OwnerUID, Value: string(testPvc.UID)}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressServerKey, Value: "bar"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressServerCert, Value: "foo"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ProgressClientCA, Value: "baz"}))
+		})
+
+		It("Should not create a scratch pvc if the upload does not need scratch space", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName, AnnUploadNoScratch: "true"}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			for _, vol := range uploadPod.Spec.Volumes {
+				Expect(vol.Name).ToNot(Equal(ScratchVolName))
+			}
+
+			scratchPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, scratchPvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should create a scratch pvc if an upload without scratch space requires it", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName, AnnUploadNoScratch: "true", AnnRequiresScratch: "true"}, nil)
+			reconciler := createUploadReconciler(testPvc)
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			foundScratch := false
+			for _, vol := range uploadPod.Spec.Volumes {
+				if vol.Name == ScratchVolName {
+					foundScratch = true
+				}
+			}
+			Expect(foundScratch).To(BeTrue())
+
+			scratchPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, scratchPvc)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should restart the upload pod with scratch space if the upload server asked for it", func() {
+			testPvc := createPvc(testPvcName, "default", map[string]string{AnnUploadRequest: "", AnnUploadPod: uploadResourceName, AnnUploadNoScratch: "true"}, nil)
+			pod := createUploadClonePod(testPvc, uploadServerClientName)
+			pod.Name = uploadResourceName
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: common.ScratchSpaceNeededExitCode,
+							},
+						},
+					},
+				},
+			}
+			reconciler := createUploadReconciler(testPvc, pod)
+			result, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+
+			By("Verifying the pvc requires scratch space and the pod is gone")
+			resultPvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: testPvcName, Namespace: "default"}, resultPvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resultPvc.GetAnnotations()[AnnRequiresScratch]).To(Equal("true"))
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: "default"}, uploadPod)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+			By("Verifying the new pod gets scratch space")
+			_, err = reconciler.reconcilePVC(reconciler.log, resultPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+			uploadPod = &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			scratchPVCName, exists := getScratchNameFromPod(uploadPod)
+			Expect(exists).To(BeTrue())
+			Expect(scratchPVCName).To(Equal("testPvc1-scratch"))
+		})
 	})
 })
 
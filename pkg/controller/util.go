@@ -86,6 +86,8 @@ const (
 	AnnMultiStageImportDone = AnnAPIGroup + "/storage.checkpoint.done"
 	// AnnPreallocationRequested provides a const to indicate whether preallocation should be performed on the PV
 	AnnPreallocationRequested = AnnAPIGroup + "/storage.preallocation.requested"
+	// AnnClaimPropertySet is the index of the StorageProfile claim property set the PVC of a DataVolume is created with
+	AnnClaimPropertySet = AnnAPIGroup + "/storage.claimPropertySet"
 
 	// AnnRunningCondition provides a const for the running condition
 	AnnRunningCondition = AnnAPIGroup + "/storage.condition.running"
@@ -115,6 +117,12 @@ const (
 	AnnVddkVersion = AnnAPIGroup + "/storage.pod.vddk.version"
 	// AnnVddkHostConnection shows the last ESX host that serviced a DV's importer pod
 	AnnVddkHostConnection = AnnAPIGroup + "/storage.pod.vddk.host"
+	// AnnImageDigest shows the resolved manifest digest of the registry image imported by a DV's importer pod
+	AnnImageDigest = AnnAPIGroup + "/storage.import.imageDigest"
+	// AnnChecksum provides a const for our PVC expected source data checksum annotation
+	AnnChecksum = AnnAPIGroup + "/storage.checksum"
+	// AnnComputedChecksum shows the checksum of the source data computed and verified by a DV's importer or upload pod
+	AnnComputedChecksum = AnnAPIGroup + "/storage.checksum.computed"
 
 	// PodRunningReason is const that defines the pod was started as a reason
 	podRunningReason = "Pod is running"
@@ -124,6 +132,8 @@ const (
 
 	// ProxyCertVolName is the name of the volumecontaining certs
 	ProxyCertVolName = "cdi-proxy-cert-vol"
+	// SignatureVolName is the name of the volume containing the image signature verification policy
+	SignatureVolName = "cdi-signature-vol"
 	// ClusterWideProxyAPIGroup is the APIGroup for OpenShift Cluster Wide Proxy
 	ClusterWideProxyAPIGroup = "config.openshift.io"
 	// ClusterWideProxyAPIKind is the APIKind for OpenShift Cluster Wide Proxy
@@ -151,8 +161,16 @@ const (
 	AnnPodSidecarInjectionDefault = "false"
 )
 
+const (
+	// the resources a clone token can be issued for
+	pvcTokenResource      = "persistentvolumeclaims"
+	snapshotTokenResource = "volumesnapshots"
+)
+
 var (
-	vddkInfoMatch = regexp.MustCompile(`((.*; )|^)VDDK: (?P<info>{.*})`)
+	vddkInfoMatch    = regexp.MustCompile(`((.*; )|^)VDDK: (?P<info>{.*})`)
+	imageDigestMatch = regexp.MustCompile(`((.*; )|^)Image digest: (?P<digest>[a-z0-9]+:[a-f0-9]+)`)
+	checksumMatch    = regexp.MustCompile(`((.*; )|^)Checksum: (?P<checksum>[a-z0-9]+:[a-f0-9]+)`)
 )
 
 func isCrossNamespaceClone(dv *cdiv1.DataVolume) bool {
@@ -565,6 +583,8 @@ func setAnnotationsFromPodWithPrefix(anno map[string]string, pod *v1.Pod, prefix
 		anno[AnnPodRestarts] = strconv.Itoa(podRestarts)
 	}
 	setVddkAnnotations(anno, pod)
+	setImageDigestAnnotation(anno, pod)
+	setChecksumAnnotation(anno, pod)
 	containerState := pod.Status.ContainerStatuses[0].State
 	if containerState.Running != nil {
 		anno[prefix] = "true"
@@ -624,6 +644,34 @@ func setVddkAnnotations(anno map[string]string, pod *corev1.Pod) {
 	}
 }
 
+func setImageDigestAnnotation(anno map[string]string, pod *corev1.Pod) {
+	if pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return
+	}
+	terminationMessage := pod.Status.ContainerStatuses[0].State.Terminated.Message
+	matches := imageDigestMatch.FindStringSubmatch(terminationMessage)
+	for index, matchName := range imageDigestMatch.SubexpNames() {
+		if matchName == "digest" && len(matches) > 0 {
+			anno[AnnImageDigest] = matches[index]
+			return
+		}
+	}
+}
+
+func setChecksumAnnotation(anno map[string]string, pod *corev1.Pod) {
+	if pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return
+	}
+	terminationMessage := pod.Status.ContainerStatuses[0].State.Terminated.Message
+	matches := checksumMatch.FindStringSubmatch(terminationMessage)
+	for index, matchName := range checksumMatch.SubexpNames() {
+		if matchName == "checksum" && len(matches) > 0 {
+			anno[AnnComputedChecksum] = matches[index]
+			return
+		}
+	}
+}
+
 func setBoundConditionFromPVC(anno map[string]string, prefix string, pvc *v1.PersistentVolumeClaim) {
 	switch pvc.Status.Phase {
 	case v1.ClaimBound:
@@ -873,11 +921,25 @@ func validateCloneTokenPVC(validator token.Validator, source, target *corev1.Per
 		return errors.Wrap(err, "error verifying token")
 	}
 
-	return validateTokenData(tokenData, source.Namespace, source.Name, target.Namespace, target.Name)
+	// a PVC restored from a snapshot holds the data of the snapshot, a token for the snapshot allows cloning the PVC
+	// restored for the DataVolume of the target
+	if tokenData.Resource.Resource == snapshotTokenResource && isSnapshotRestorePvcOf(source, target) {
+		return validateTokenData(tokenData, snapshotTokenResource, source.Namespace, source.Spec.DataSource.Name, target.Namespace, target.Name)
+	}
+
+	return validateTokenData(tokenData, pvcTokenResource, source.Namespace, source.Name, target.Namespace, target.Name)
 }
 
 func validateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error {
-	if dv.Spec.Source.PVC == nil || dv.Spec.Source.PVC.Namespace == "" || dv.Spec.Source.PVC.Namespace == dv.Namespace {
+	resource, sourceNamespace, sourceName := pvcTokenResource, "", ""
+	if dv.Spec.Source.PVC != nil {
+		sourceNamespace, sourceName = dv.Spec.Source.PVC.Namespace, dv.Spec.Source.PVC.Name
+	} else if dv.Spec.Source.Snapshot != nil {
+		resource = snapshotTokenResource
+		sourceNamespace, sourceName = dv.Spec.Source.Snapshot.Namespace, dv.Spec.Source.Snapshot.Name
+	}
+
+	if sourceNamespace == "" || sourceNamespace == dv.Namespace {
 		return nil
 	}
 
@@ -891,14 +953,14 @@ func validateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error
 		return errors.Wrap(err, "error verifying token")
 	}
 
-	return validateTokenData(tokenData, dv.Spec.Source.PVC.Namespace, dv.Spec.Source.PVC.Name, dv.Namespace, dv.Name)
+	return validateTokenData(tokenData, resource, sourceNamespace, sourceName, dv.Namespace, dv.Name)
 }
 
-func validateTokenData(tokenData *token.Payload, srcNamespace, srcName, targetNamespace, targetName string) error {
+func validateTokenData(tokenData *token.Payload, resource, srcNamespace, srcName, targetNamespace, targetName string) error {
 	if tokenData.Operation != token.OperationClone ||
 		tokenData.Name != srcName ||
 		tokenData.Namespace != srcNamespace ||
-		tokenData.Resource.Resource != "persistentvolumeclaims" ||
+		tokenData.Resource.Resource != resource ||
 		tokenData.Params["targetNamespace"] != targetNamespace ||
 		tokenData.Params["targetName"] != targetName {
 		return errors.New("invalid token")
@@ -907,6 +969,45 @@ func validateTokenData(tokenData *token.Payload, srcNamespace, srcName, targetNa
 	return nil
 }
 
+// cloneTokenIdentity identifies the clone token that authorized a clone, the user who requested the clone is
+// recorded with the token ID by the webhook that issued the token
+type cloneTokenIdentity struct {
+	TokenID         string `json:"tokenID,omitempty"`
+	Resource        string `json:"resource"`
+	SourceNamespace string `json:"sourceNamespace"`
+	SourceName      string `json:"sourceName"`
+	TargetNamespace string `json:"targetNamespace"`
+	TargetName      string `json:"targetName"`
+}
+
+// setCloneAuthorization persists the identity of a valid clone token, the token itself expires shortly after the
+// DataVolume is created
+func setCloneAuthorization(validator token.Validator, annotations map[string]string, tok string) error {
+	if validator == nil {
+		return nil
+	}
+
+	tokenData, err := validator.Validate(tok)
+	if err != nil {
+		return errors.Wrap(err, "error verifying token")
+	}
+
+	identity, err := json.Marshal(&cloneTokenIdentity{
+		TokenID:         tokenData.ID,
+		Resource:        tokenData.Resource.Resource,
+		SourceNamespace: tokenData.Namespace,
+		SourceName:      tokenData.Name,
+		TargetNamespace: tokenData.Params["targetNamespace"],
+		TargetName:      tokenData.Params["targetName"],
+	})
+	if err != nil {
+		return err
+	}
+
+	annotations[AnnCloneAuthorization] = string(identity)
+	return nil
+}
+
 func addAnnotation(obj metav1.Object, key, value string) {
 	if obj.GetAnnotations() == nil {
 		obj.SetAnnotations(make(map[string]string))
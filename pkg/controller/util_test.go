@@ -625,6 +625,12 @@ func createCDIConfigWithStorageClass(name string, storageClass string) *cdiv1.CD
 	}
 }
 
+func createCDIConfigWithStorageCapabilities(rules ...cdiv1.ProvisionerCapabilities) *cdiv1.CDIConfig {
+	config := createCDIConfig(common.ConfigName)
+	config.Spec.StorageCapabilities = rules
+	return config
+}
+
 func createCDIConfigWithGlobalPreallocation(globalPreallocation bool) *cdiv1.CDIConfig {
 	return &cdiv1.CDIConfig{
 		TypeMeta: metav1.TypeMeta{
@@ -678,6 +684,18 @@ func createStorageProfileWithCloneStrategy(name string,
 	}
 }
 
+func createStorageProfileWithClaimPropertySets(name string, claimPropertySets ...cdiv1.ClaimPropertySet) *cdiv1.StorageProfile {
+	return &cdiv1.StorageProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: cdiv1.StorageProfileStatus{
+			StorageClass:      &name,
+			ClaimPropertySets: claimPropertySets,
+		},
+	}
+}
+
 func createStorageClassWithBindingMode(name string, annotations map[string]string, bindingMode storagev1.VolumeBindingMode) *storagev1.StorageClass {
 	return &storagev1.StorageClass{
 		VolumeBindingMode: &bindingMode,
@@ -0,0 +1,382 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package downloadserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	healthzPort = 8080
+	healthzPath = "/healthz"
+
+	qcow2ImageName = "disk.qcow2"
+)
+
+// DownloadServer is the interface to downloadServerApp
+type DownloadServer interface {
+	Run() error
+}
+
+type downloadServerApp struct {
+	bindAddress string
+	bindPort    int
+	source      string
+	scratchDir  string
+	tlsKey      string
+	tlsCert     string
+	clientCert  string
+	clientName  string
+	keyFile     string
+	certFile    string
+	mux         *http.ServeMux
+	qcow2Path   string
+	qcow2Mutex  sync.Mutex
+	errChan     chan error
+
+	activityMutex  sync.Mutex
+	activeRequests int
+	lastActivity   time.Time
+}
+
+// may be overridden in tests
+var convertToQcow2Func = image.ConvertToCompressedQcow2
+var newTarReaderFunc = util.NewTarReader
+
+// The server exits once no download was served for idleTimeout, the download controller then removes the download
+// request of the PVC so the PVC is no longer kept mounted. idleTimeout is longer than the lifetime of a download token.
+var idleTimeout = 10 * time.Minute
+var idleCheckInterval = 30 * time.Second
+
+// NewDownloadServer returns a new instance of downloadServerApp. source is either the block device or the directory
+// holding the disk image of the PVC, scratchDir is where converted images are written before being served.
+func NewDownloadServer(bindAddress string, bindPort int, source, scratchDir, tlsKey, tlsCert, clientCert, clientName string) DownloadServer {
+	server := &downloadServerApp{
+		bindAddress:  bindAddress,
+		bindPort:     bindPort,
+		source:       source,
+		scratchDir:   scratchDir,
+		tlsKey:       tlsKey,
+		tlsCert:      tlsCert,
+		clientCert:   clientCert,
+		clientName:   clientName,
+		mux:          http.NewServeMux(),
+		errChan:      make(chan error),
+		lastActivity: time.Now(),
+	}
+
+	server.mux.HandleFunc(common.DownloadPathRaw, server.rawHandler)
+	server.mux.HandleFunc(common.DownloadPathQcow2, server.qcow2Handler)
+	server.mux.HandleFunc(common.DownloadPathTar, server.tarHandler)
+
+	return server
+}
+
+func (app *downloadServerApp) Run() error {
+	downloadServer, err := app.createDownloadServer()
+	if err != nil {
+		return errors.Wrap(err, "Error creating download http server")
+	}
+
+	healthzServer := app.createHealthzServer()
+
+	downloadListener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", app.bindAddress, app.bindPort))
+	if err != nil {
+		return errors.Wrap(err, "Error creating download listener")
+	}
+
+	healthzListener, err := net.Listen("tcp", fmt.Sprintf(":%d", healthzPort))
+	if err != nil {
+		return errors.Wrap(err, "Error creating healthz listener")
+	}
+
+	go func() {
+		defer downloadListener.Close()
+
+		if app.keyFile != "" && app.certFile != "" {
+			app.errChan <- downloadServer.ServeTLS(downloadListener, app.certFile, app.keyFile)
+			return
+		}
+
+		app.errChan <- downloadServer.Serve(downloadListener)
+	}()
+
+	go func() {
+		defer healthzServer.Close()
+
+		app.errChan <- healthzServer.Serve(healthzListener)
+	}()
+
+	idleChan := make(chan struct{})
+	go func() {
+		app.waitForIdle()
+		close(idleChan)
+	}()
+
+	select {
+	case err = <-app.errChan:
+		klog.Errorf("HTTP server returned error %s", err.Error())
+		return err
+	case <-idleChan:
+		klog.Infof("No download served for %s, exiting", idleTimeout)
+		return nil
+	}
+}
+
+// waitForIdle returns once no request is in progress and the last one finished more than idleTimeout ago
+func (app *downloadServerApp) waitForIdle() {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if app.isIdle() {
+			return
+		}
+	}
+}
+
+func (app *downloadServerApp) isIdle() bool {
+	app.activityMutex.Lock()
+	defer app.activityMutex.Unlock()
+	return app.activeRequests == 0 && time.Since(app.lastActivity) >= idleTimeout
+}
+
+func (app *downloadServerApp) startRequest() {
+	app.activityMutex.Lock()
+	defer app.activityMutex.Unlock()
+	app.activeRequests++
+}
+
+func (app *downloadServerApp) finishRequest() {
+	app.activityMutex.Lock()
+	defer app.activityMutex.Unlock()
+	app.activeRequests--
+	app.lastActivity = time.Now()
+}
+
+func (app *downloadServerApp) createDownloadServer() (*http.Server, error) {
+	server := &http.Server{
+		Handler: app,
+	}
+
+	if app.tlsKey != "" && app.tlsCert != "" {
+		certDir, err := ioutil.TempDir("", "downloadserver-tls")
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating cert dir")
+		}
+
+		app.keyFile = filepath.Join(certDir, "tls.key")
+		app.certFile = filepath.Join(certDir, "tls.crt")
+
+		err = ioutil.WriteFile(app.keyFile, []byte(app.tlsKey), 0600)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating key file")
+		}
+
+		err = ioutil.WriteFile(app.certFile, []byte(app.tlsCert), 0600)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating cert file")
+		}
+	}
+
+	if app.clientCert != "" {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(app.clientCert)); !ok {
+			return nil, errors.Errorf("Invalid ca cert file %s", app.clientCert)
+		}
+
+		server.TLSConfig = &tls.Config{
+			ClientCAs:  caCertPool,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+		}
+	}
+
+	return server, nil
+}
+
+func (app *downloadServerApp) createHealthzServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthzPath, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "OK")
+	})
+	return &http.Server{Handler: mux}
+}
+
+func (app *downloadServerApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	app.startRequest()
+	defer app.finishRequest()
+	app.mux.ServeHTTP(w, r)
+}
+
+func (app *downloadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	}
+
+	if r.TLS != nil {
+		found := false
+
+		for _, cert := range r.TLS.PeerCertificates {
+			if cert.Subject.CommonName == app.clientName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+	} else {
+		klog.V(3).Infof("Handling HTTP connection")
+	}
+
+	return true
+}
+
+// isBlock returns true if the source of the download is a block device
+func (app *downloadServerApp) isBlock() bool {
+	return app.source == common.WriteBlockPath
+}
+
+// imagePath returns the path of the raw disk image on the PVC
+func (app *downloadServerApp) imagePath() string {
+	if app.isBlock() {
+		return app.source
+	}
+	return filepath.Join(app.source, common.DiskImageName)
+}
+
+func (app *downloadServerApp) rawHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateShouldHandleRequest(w, r) {
+		return
+	}
+
+	app.serveFile(w, r, app.imagePath(), common.DiskImageName)
+}
+
+func (app *downloadServerApp) qcow2Handler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateShouldHandleRequest(w, r) {
+		return
+	}
+
+	qcow2Path, err := app.convertToQcow2()
+	if err != nil {
+		klog.Errorf("Converting to qcow2 failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("Converting to qcow2 failed: %s", err.Error())))
+		return
+	}
+
+	app.serveFile(w, r, qcow2Path, qcow2ImageName)
+}
+
+func (app *downloadServerApp) tarHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateShouldHandleRequest(w, r) {
+		return
+	}
+
+	if app.isBlock() {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("tar download is not supported for block volumes"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	reader, err := newTarReaderFunc(app.source, true)
+	if err != nil {
+		klog.Errorf("Creating tar reader failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		// Headers are already sent, the client notices the truncated archive
+		klog.Errorf("Error streaming tar archive after %d bytes: %s", n, err)
+		return
+	}
+	klog.Infof("Sent %d bytes of tar archive", n)
+}
+
+// convertToQcow2 converts the disk image to compressed qcow2 in the scratch space, the conversion is only done once
+func (app *downloadServerApp) convertToQcow2() (string, error) {
+	app.qcow2Mutex.Lock()
+	defer app.qcow2Mutex.Unlock()
+
+	if app.qcow2Path != "" {
+		return app.qcow2Path, nil
+	}
+
+	dest := filepath.Join(app.scratchDir, qcow2ImageName)
+	klog.Infof("Converting %s to compressed qcow2 at %s", app.imagePath(), dest)
+	if err := convertToQcow2Func(app.imagePath(), dest); err != nil {
+		return "", err
+	}
+	app.qcow2Path = dest
+	return dest, nil
+}
+
+func (app *downloadServerApp) serveFile(w http.ResponseWriter, r *http.Request, path, name string) {
+	f, err := os.Open(path)
+	if err != nil {
+		klog.Errorf("Error opening %s: %s", path, err)
+		if os.IsNotExist(err) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	info, err := f.Stat()
+	if err != nil {
+		klog.Errorf("Error getting info of %s: %s", path, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	// Block devices report a size of 0, ServeContent finds the size by seeking to the end
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
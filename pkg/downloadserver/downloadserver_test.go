@@ -0,0 +1,261 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package downloadserver
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+)
+
+var _ = Describe("Download server tests", func() {
+	var (
+		sourceDir  string
+		scratchDir string
+		imageData  = []byte("raw disk image contents")
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourceDir, err = ioutil.TempDir("", "download-source")
+		Expect(err).ToNot(HaveOccurred())
+		scratchDir, err = ioutil.TempDir("", "download-scratch")
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(filepath.Join(sourceDir, common.DiskImageName), imageData, 0644)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(sourceDir)
+		os.RemoveAll(scratchDir)
+	})
+
+	newServer := func() *downloadServerApp {
+		return NewDownloadServer("127.0.0.1", 0, sourceDir, scratchDir, "", "", "", "").(*downloadServerApp)
+	}
+
+	newTLSServer := func(clientCertName, expectedName string) (*downloadServerApp, *triple.KeyPair, *x509.Certificate) {
+		serverCA, err := triple.NewCA("server")
+		Expect(err).ToNot(HaveOccurred())
+
+		clientCA, err := triple.NewCA("client")
+		Expect(err).ToNot(HaveOccurred())
+
+		serverKeyPair, err := triple.NewServerKeyPair(serverCA, "localhost", "localhost", "default", "local", []string{"127.0.0.1"}, []string{"localhost"})
+		Expect(err).ToNot(HaveOccurred())
+
+		tlsKey := string(cert.EncodePrivateKeyPEM(serverKeyPair.Key))
+		tlsCert := string(cert.EncodeCertPEM(serverKeyPair.Cert))
+		clientCert := string(cert.EncodeCertPEM(clientCA.Cert))
+
+		server := NewDownloadServer("127.0.0.1", 0, sourceDir, scratchDir, tlsKey, tlsCert, clientCert, expectedName).(*downloadServerApp)
+
+		clientKeyPair, err := triple.NewClientKeyPair(clientCA, clientCertName, []string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		return server, clientKeyPair, serverCA.Cert
+	}
+
+	table.DescribeTable("should reject method", func(method, path string) {
+		req, err := http.NewRequest(method, path, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	},
+		table.Entry("POST raw", http.MethodPost, common.DownloadPathRaw),
+		table.Entry("PUT qcow2", http.MethodPut, common.DownloadPathQcow2),
+		table.Entry("DELETE tar", http.MethodDelete, common.DownloadPathTar),
+	)
+
+	It("should serve the raw disk image", func() {
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathRaw, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.Bytes()).To(Equal(imageData))
+		Expect(rr.Header().Get("Content-Length")).To(Equal(fmt.Sprintf("%d", len(imageData))))
+	})
+
+	It("should serve a range of the raw disk image", func() {
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathRaw, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Range", "bytes=4-7")
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusPartialContent))
+		Expect(rr.Body.Bytes()).To(Equal(imageData[4:8]))
+	})
+
+	It("should return not found if there is no disk image", func() {
+		Expect(os.Remove(filepath.Join(sourceDir, common.DiskImageName))).To(Succeed())
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathRaw, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should convert to qcow2 only once", func() {
+		conversions := 0
+		origConvert := convertToQcow2Func
+		defer func() { convertToQcow2Func = origConvert }()
+		convertToQcow2Func = func(src, dest string) error {
+			conversions++
+			Expect(src).To(Equal(filepath.Join(sourceDir, common.DiskImageName)))
+			return ioutil.WriteFile(dest, []byte("qcow2"), 0644)
+		}
+
+		server := newServer()
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(http.MethodGet, common.DownloadPathQcow2, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Body.String()).To(Equal("qcow2"))
+		}
+		Expect(conversions).To(Equal(1))
+	})
+
+	It("should return an error if qcow2 conversion fails", func() {
+		origConvert := convertToQcow2Func
+		defer func() { convertToQcow2Func = origConvert }()
+		convertToQcow2Func = func(src, dest string) error {
+			return fmt.Errorf("conversion failed")
+		}
+
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathQcow2, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rr.Body.String()).To(ContainSubstring("conversion failed"))
+	})
+
+	It("should stream a tar archive of the source directory", func() {
+		origTarReader := newTarReaderFunc
+		defer func() { newTarReaderFunc = origTarReader }()
+		newTarReaderFunc = func(dir string, sparse bool) (io.ReadCloser, error) {
+			Expect(dir).To(Equal(sourceDir))
+			Expect(sparse).To(BeTrue())
+			return ioutil.NopCloser(strings.NewReader("tar archive")), nil
+		}
+
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathTar, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Header().Get("Content-Type")).To(Equal("application/x-tar"))
+		Expect(rr.Body.String()).To(Equal("tar archive"))
+	})
+
+	It("should reject tar download of a block volume", func() {
+		server := NewDownloadServer("127.0.0.1", 0, common.WriteBlockPath, scratchDir, "", "", "", "").(*downloadServerApp)
+		req, err := http.NewRequest(http.MethodGet, common.DownloadPathTar, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should be idle only without requests in progress for the idle timeout", func() {
+		defer func(timeout time.Duration) { idleTimeout = timeout }(idleTimeout)
+		server := newServer()
+
+		idleTimeout = time.Hour
+		Expect(server.isIdle()).To(BeFalse())
+
+		idleTimeout = 0
+		Expect(server.isIdle()).To(BeTrue())
+		server.startRequest()
+		Expect(server.isIdle()).To(BeFalse())
+		server.finishRequest()
+		Expect(server.isIdle()).To(BeTrue())
+	})
+
+	table.DescribeTable("should validate the client certificate", func(certName, expectedName string, expectedStatus int) {
+		server, clientKeyPair, serverCACert := newTLSServer(certName, expectedName)
+		tlsServer, err := server.createDownloadServer()
+		Expect(err).ToNot(HaveOccurred())
+		ts := httptest.NewUnstartedServer(tlsServer.Handler)
+		ts.TLS = tlsServer.TLSConfig
+		ts.StartTLS()
+		defer ts.Close()
+
+		clientCert, err := tls.X509KeyPair(cert.EncodeCertPEM(clientKeyPair.Cert), cert.EncodePrivateKeyPEM(clientKeyPair.Key))
+		Expect(err).ToNot(HaveOccurred())
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(cert.EncodeCertPEM(serverCACert))
+		caCertPool.AddCert(ts.Certificate())
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      caCertPool,
+		}}}
+
+		resp, err := client.Get(ts.URL + common.DownloadPathRaw)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(expectedStatus))
+		if expectedStatus == http.StatusOK {
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Equal(body, imageData)).To(BeTrue())
+		}
+	},
+		table.Entry("with valid client name", "client", "client", http.StatusOK),
+		table.Entry("with invalid client name", "foo", "bar", http.StatusUnauthorized),
+	)
+})
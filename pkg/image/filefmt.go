@@ -70,6 +70,20 @@ var knownHeaders = Headers{
 		SizeOff:     0,
 		SizeLen:     0,
 	},
+	"zst": Header{
+		Format:      "zst",
+		magicNumber: []byte{0x28, 0xB5, 0x2F, 0xFD},
+		// TODO: size not in hdr
+		SizeOff: 0,
+		SizeLen: 0,
+	},
+	"bz2": Header{
+		Format:      "bz2",
+		magicNumber: []byte{'B', 'Z', 'h'},
+		// TODO: size not in hdr
+		SizeOff: 0,
+		SizeLen: 0,
+	},
 }
 
 // Header represents our parameters for a file format header
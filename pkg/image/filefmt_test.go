@@ -61,6 +61,22 @@ var _ = Describe("File format tests", func() {
 			Header{"vhdx", []byte("vhdxfile"), 0, 24, 8},
 			[]byte("vhdxfile"),
 			true),
+		table.Entry("match zst",
+			Header{"zst", []byte{0x28, 0xB5, 0x2F, 0xFD}, 0, 0, 0},
+			[]byte{0x28, 0xB5, 0x2F, 0xFD},
+			true),
+		table.Entry("match bz2",
+			Header{"bz2", []byte{'B', 'Z', 'h'}, 0, 0, 0},
+			[]byte("BZh91AY&SY"),
+			true),
+		table.Entry("failed match zst",
+			knownHeaders["zst"],
+			[]byte{0xFD, 0x2F, 0xB5, 0x28},
+			false),
+		table.Entry("failed match bz2",
+			knownHeaders["bz2"],
+			[]byte("BZ0"),
+			false),
 	)
 
 	tokenQcow := make([]byte, 20)
@@ -0,0 +1,516 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	qcow2Magic          = 0x514649fb
+	qcow2HeaderV2Length = 72
+	qcow2HeaderV3Length = 104
+	qcow2MinClusterBits = 9
+	qcow2MaxClusterBits = 21
+	// qcow2MaxL1Size is the maximum L1 table size qemu accepts, in bytes
+	qcow2MaxL1Size = 32 << 20
+
+	qcow2OffsetMask     = 0x00fffffffffffe00
+	qcow2CompressedFlag = uint64(1) << 62
+	qcow2ZeroFlag       = uint64(1)
+
+	qcow2IncompatDirty = uint64(1) << 0
+
+	// maxQcow2PendingBytes limits the memory used to hold clusters that are streamed before the metadata mapping them
+	maxQcow2PendingBytes = 128 << 20
+	zeroWriteSize        = 1 << 20
+)
+
+// Qcow2Header holds the fields of a qcow2 header needed to convert the image while streaming it
+type Qcow2Header struct {
+	Version       uint32
+	ClusterBits   uint32
+	Size          uint64
+	L1Size        uint32
+	L1TableOffset uint64
+}
+
+// ParseQcow2Header parses the qcow2 header at the start of the passed in slice, and returns an error if the
+// image cannot be converted while streaming, e.g. because it has a backing file or it is encrypted.
+func ParseQcow2Header(b []byte) (*Qcow2Header, error) {
+	if len(b) < qcow2HeaderV2Length || binary.BigEndian.Uint32(b[0:]) != qcow2Magic {
+		return nil, errors.New("not a qcow2 image")
+	}
+	h := &Qcow2Header{
+		Version:       binary.BigEndian.Uint32(b[4:]),
+		ClusterBits:   binary.BigEndian.Uint32(b[20:]),
+		Size:          binary.BigEndian.Uint64(b[24:]),
+		L1Size:        binary.BigEndian.Uint32(b[36:]),
+		L1TableOffset: binary.BigEndian.Uint64(b[40:]),
+	}
+	if h.Version != 2 && h.Version != 3 {
+		return nil, errors.Errorf("unsupported qcow2 version %d", h.Version)
+	}
+	if binary.BigEndian.Uint64(b[8:]) != 0 {
+		return nil, errors.New("qcow2 images with a backing file are not supported")
+	}
+	if binary.BigEndian.Uint32(b[32:]) != 0 {
+		return nil, errors.New("encrypted qcow2 images are not supported")
+	}
+	if h.ClusterBits < qcow2MinClusterBits || h.ClusterBits > qcow2MaxClusterBits {
+		return nil, errors.Errorf("invalid qcow2 cluster bits %d", h.ClusterBits)
+	}
+	if h.Version == 3 {
+		if len(b) < qcow2HeaderV3Length {
+			return nil, errors.New("qcow2 header is truncated")
+		}
+		// The dirty bit only means the refcounts may be stale, which doesn't matter as they are not read
+		if incompatible := binary.BigEndian.Uint64(b[72:]) &^ qcow2IncompatDirty; incompatible != 0 {
+			return nil, errors.Errorf("unsupported qcow2 incompatible features 0x%x", incompatible)
+		}
+	}
+	clusterSize := uint64(1) << h.ClusterBits
+	if uint64(h.L1Size)*8 > qcow2MaxL1Size {
+		return nil, errors.Errorf("qcow2 L1 table size %d is too large", h.L1Size)
+	}
+	if h.L1TableOffset%clusterSize != 0 || (h.L1Size > 0 && h.L1TableOffset == 0) {
+		return nil, errors.Errorf("invalid qcow2 L1 table offset %d", h.L1TableOffset)
+	}
+	l2Coverage := clusterSize / 8 * clusterSize
+	if (h.Size+l2Coverage-1)/l2Coverage > uint64(h.L1Size) {
+		return nil, errors.Errorf("qcow2 L1 table size %d is too small for virtual size %d", h.L1Size, h.Size)
+	}
+	return h, nil
+}
+
+// ConvertQcow2ToRawStream converts the qcow2 image read from the stream to a raw image in dest, which is either
+// a block device or a file that is created. The stream is read only once from start to end, so no scratch space is
+// needed. Clusters that are streamed before the L1/L2 tables mapping them are held in memory, up to a limit.
+func ConvertQcow2ToRawStream(r io.Reader, dest string) error {
+	hdrBuf := make([]byte, MaxExpectedHdrSize)
+	if _, err := io.ReadFull(r, hdrBuf); err != nil {
+		return errors.Wrap(err, "unable to read qcow2 header")
+	}
+	hdr, err := ParseQcow2Header(hdrBuf)
+	if err != nil {
+		return err
+	}
+
+	blockSize, err := util.GetAvailableSpaceBlock(dest)
+	if err != nil {
+		return errors.Wrapf(err, "error determining if block device exists")
+	}
+	isBlockDev := blockSize >= 0
+	var outFile *os.File
+	if isBlockDev {
+		if uint64(blockSize) < hdr.Size {
+			return errors.Errorf("virtual image size %d is larger than the block device size %d", hdr.Size, blockSize)
+		}
+		outFile, err = os.OpenFile(dest, os.O_EXCL|os.O_WRONLY, os.ModePerm)
+	} else {
+		outFile, err = os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.ModePerm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", dest)
+	}
+	defer outFile.Close()
+
+	cleanup := func(err error) error {
+		if !isBlockDev {
+			os.Remove(outFile.Name())
+		}
+		return err
+	}
+	if !isBlockDev {
+		// Sparse file, clusters that are not written read as zeroes
+		if err := outFile.Truncate(int64(hdr.Size)); err != nil {
+			return cleanup(errors.Wrap(err, "unable to set the raw image size"))
+		}
+	}
+
+	klog.V(1).Infof("Converting qcow2 image with virtual size %d to raw while streaming\n", hdr.Size)
+	c := newQcow2Converter(hdr, outFile, isBlockDev)
+	if err := c.convert(io.MultiReader(bytes.NewReader(hdrBuf), r)); err != nil {
+		return cleanup(err)
+	}
+	if err := outFile.Sync(); err != nil {
+		return cleanup(errors.Wrap(err, "unable to sync raw image"))
+	}
+	return nil
+}
+
+// qcow2CompressedCluster describes a compressed guest cluster, the compressed data may span several host clusters
+type qcow2CompressedCluster struct {
+	offset int64
+	length int64
+	guest  int64
+	// remaining is the number of host clusters holding the compressed data that are not streamed yet
+	remaining int
+}
+
+// qcow2Converter converts a qcow2 image to raw, reading the image one host cluster at a time. The L1 table and
+// the L2 tables are parsed when they are streamed, and every data cluster is written to the guest offset it maps to.
+type qcow2Converter struct {
+	hdr         *Qcow2Header
+	clusterSize int64
+	dst         io.WriterAt
+	// writeZeroes is set when the destination is not known to read as zeroes, like a block device
+	writeZeroes bool
+	maxPending  int64
+
+	// pos is the host offset of the next cluster in the stream
+	pos        int64
+	l1         []byte
+	l1Received int64
+	l1Done     bool
+	// pendingL2 maps the host offset of every L2 table not parsed yet to the guest offset it covers
+	pendingL2 map[int64]int64
+	// data maps host clusters not streamed yet to the guest offsets they hold the data for
+	data       map[int64][]int64
+	compressed map[int64][]*qcow2CompressedCluster
+	// held keeps clusters already streamed that are, or may be, needed later
+	held       map[int64][]byte
+	heldRefs   map[int64]int
+	heldBytes  int64
+	zeroBuffer []byte
+}
+
+func newQcow2Converter(hdr *Qcow2Header, dst io.WriterAt, writeZeroes bool) *qcow2Converter {
+	return &qcow2Converter{
+		hdr:         hdr,
+		clusterSize: int64(1) << hdr.ClusterBits,
+		dst:         dst,
+		writeZeroes: writeZeroes,
+		maxPending:  maxQcow2PendingBytes,
+		l1:          make([]byte, int64(hdr.L1Size)*8),
+		l1Done:      hdr.L1Size == 0,
+		pendingL2:   make(map[int64]int64),
+		data:        make(map[int64][]int64),
+		compressed:  make(map[int64][]*qcow2CompressedCluster),
+		held:        make(map[int64][]byte),
+		heldRefs:    make(map[int64]int),
+	}
+}
+
+func (c *qcow2Converter) convert(r io.Reader) error {
+	// The header was already parsed, nothing else lives in the first cluster
+	if _, err := io.CopyN(ioutil.Discard, r, c.clusterSize); err != nil {
+		return errors.Wrap(err, "unable to read qcow2 header")
+	}
+	c.pos = c.clusterSize
+	if c.l1Done {
+		// No L1 table means the whole image reads as zeroes
+		if err := c.zero(0, int64(c.hdr.Size)); err != nil {
+			return err
+		}
+	}
+	for {
+		buf := make([]byte, c.clusterSize)
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return errors.Wrap(err, "unable to read qcow2 image")
+		}
+		if n > 0 {
+			// A partial last cluster reads as zero padded
+			if err := c.processCluster(c.pos, buf); err != nil {
+				return err
+			}
+			c.pos += c.clusterSize
+		}
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+	return c.finish()
+}
+
+func (c *qcow2Converter) processCluster(host int64, buf []byte) error {
+	l1Offset := int64(c.hdr.L1TableOffset)
+	if !c.l1Done && host >= l1Offset && host < l1Offset+int64(len(c.l1)) {
+		c.l1Received += int64(copy(c.l1[host-l1Offset:], buf))
+		if c.l1Received == int64(len(c.l1)) {
+			return c.parseL1()
+		}
+		return nil
+	}
+	if _, ok := c.pendingL2[host]; ok {
+		return c.parseL2(host, buf)
+	}
+	if guests, ok := c.data[host]; ok {
+		delete(c.data, host)
+		for _, guest := range guests {
+			if err := c.write(guest, buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if descs, ok := c.compressed[host]; ok {
+		delete(c.compressed, host)
+		if err := c.hold(host, buf); err != nil {
+			return err
+		}
+		c.heldRefs[host] += len(descs)
+		for _, desc := range descs {
+			desc.remaining--
+			if desc.remaining == 0 {
+				if err := c.decompress(desc); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if !c.mappingComplete() {
+		// This may be a cluster whose L2 table, or an L2 table whose L1 entry, comes later in the stream
+		return c.hold(host, buf)
+	}
+	return nil
+}
+
+func (c *qcow2Converter) mappingComplete() bool {
+	return c.l1Done && len(c.pendingL2) == 0
+}
+
+func (c *qcow2Converter) parseL1() error {
+	c.l1Done = true
+	l2Coverage := c.clusterSize / 8 * c.clusterSize
+	for i := int64(0); i < int64(c.hdr.L1Size); i++ {
+		guest := i * l2Coverage
+		if guest >= int64(c.hdr.Size) {
+			break
+		}
+		l2Offset := int64(binary.BigEndian.Uint64(c.l1[i*8:]) & qcow2OffsetMask)
+		if l2Offset == 0 {
+			if err := c.zero(guest, l2Coverage); err != nil {
+				return err
+			}
+			continue
+		}
+		c.pendingL2[l2Offset] = guest
+	}
+	c.l1 = nil
+	// L2 tables may precede the L1 table in the stream
+	for l2Offset := range c.pendingL2 {
+		if l2Offset >= c.pos {
+			continue
+		}
+		buf, ok := c.held[l2Offset]
+		if !ok {
+			return errors.Errorf("qcow2 L2 table at offset %d was not found", l2Offset)
+		}
+		c.release(l2Offset)
+		if err := c.parseL2(l2Offset, buf); err != nil {
+			return err
+		}
+	}
+	c.releaseUnused()
+	return nil
+}
+
+func (c *qcow2Converter) parseL2(l2Offset int64, buf []byte) error {
+	base := c.pendingL2[l2Offset]
+	delete(c.pendingL2, l2Offset)
+	for i := int64(0); i < c.clusterSize/8; i++ {
+		guest := base + i*c.clusterSize
+		if guest >= int64(c.hdr.Size) {
+			break
+		}
+		entry := binary.BigEndian.Uint64(buf[i*8:])
+		if entry&qcow2CompressedFlag != 0 {
+			if err := c.addCompressed(entry, guest); err != nil {
+				return err
+			}
+			continue
+		}
+		host := int64(entry & qcow2OffsetMask)
+		if host == 0 || (c.hdr.Version >= 3 && entry&qcow2ZeroFlag != 0) {
+			if err := c.zero(guest, c.clusterSize); err != nil {
+				return err
+			}
+			continue
+		}
+		if host >= c.pos {
+			c.data[host] = append(c.data[host], guest)
+			continue
+		}
+		held, ok := c.held[host]
+		if !ok {
+			return errors.Errorf("qcow2 data cluster at offset %d was not found", host)
+		}
+		if err := c.write(guest, held); err != nil {
+			return err
+		}
+	}
+	c.releaseUnused()
+	return nil
+}
+
+func (c *qcow2Converter) addCompressed(entry uint64, guest int64) error {
+	offsetBits := 62 - (c.hdr.ClusterBits - 8)
+	offset := int64(entry & (uint64(1)<<offsetBits - 1))
+	sectors := int64((entry>>offsetBits)&(uint64(1)<<(c.hdr.ClusterBits-8)-1)) + 1
+	desc := &qcow2CompressedCluster{
+		offset: offset,
+		length: sectors*512 - offset%512,
+		guest:  guest,
+	}
+	for host := c.hostCluster(desc.offset); host <= c.hostCluster(desc.offset+desc.length-1); host += c.clusterSize {
+		if host >= c.pos {
+			c.compressed[host] = append(c.compressed[host], desc)
+			desc.remaining++
+			continue
+		}
+		if _, ok := c.held[host]; !ok {
+			return errors.Errorf("qcow2 compressed data at offset %d was not found", host)
+		}
+		c.heldRefs[host]++
+	}
+	if desc.remaining == 0 {
+		return c.decompress(desc)
+	}
+	return nil
+}
+
+func (c *qcow2Converter) decompress(desc *qcow2CompressedCluster) error {
+	compressed := make([]byte, 0, desc.length+c.clusterSize)
+	first := c.hostCluster(desc.offset)
+	for host := first; host <= c.hostCluster(desc.offset+desc.length-1); host += c.clusterSize {
+		compressed = append(compressed, c.held[host]...)
+	}
+	start := desc.offset - first
+	decompressed := make([]byte, c.clusterSize)
+	if _, err := io.ReadFull(flate.NewReader(bytes.NewReader(compressed[start:start+desc.length])), decompressed); err != nil {
+		return errors.Wrapf(err, "unable to decompress qcow2 cluster at offset %d", desc.offset)
+	}
+	for host := first; host <= c.hostCluster(desc.offset+desc.length-1); host += c.clusterSize {
+		c.heldRefs[host]--
+		if c.heldRefs[host] <= 0 && c.mappingComplete() {
+			c.release(host)
+		}
+	}
+	return c.write(desc.guest, decompressed)
+}
+
+func (c *qcow2Converter) hostCluster(offset int64) int64 {
+	return offset &^ (c.clusterSize - 1)
+}
+
+func (c *qcow2Converter) hold(host int64, buf []byte) error {
+	if _, ok := c.held[host]; ok {
+		return nil
+	}
+	if c.heldBytes+int64(len(buf)) > c.maxPending {
+		return errors.Errorf("qcow2 image metadata is stored after more than %d bytes of the data it maps, the image cannot be converted while streaming", c.maxPending)
+	}
+	c.held[host] = buf
+	c.heldBytes += int64(len(buf))
+	return nil
+}
+
+func (c *qcow2Converter) release(host int64) {
+	if buf, ok := c.held[host]; ok {
+		c.heldBytes -= int64(len(buf))
+		delete(c.held, host)
+		delete(c.heldRefs, host)
+	}
+}
+
+// releaseUnused drops the held clusters once all the metadata is parsed, except the ones holding compressed data
+// of clusters that are not fully streamed yet.
+func (c *qcow2Converter) releaseUnused() {
+	if !c.mappingComplete() {
+		return
+	}
+	for host := range c.held {
+		if c.heldRefs[host] <= 0 {
+			c.release(host)
+		}
+	}
+}
+
+func (c *qcow2Converter) write(guest int64, buf []byte) error {
+	if remaining := int64(c.hdr.Size) - guest; remaining < int64(len(buf)) {
+		buf = buf[:remaining]
+	}
+	if !c.writeZeroes && isZero(buf) {
+		return nil
+	}
+	if _, err := c.dst.WriteAt(buf, guest); err != nil {
+		return errors.Wrap(err, "unable to write raw image")
+	}
+	return nil
+}
+
+func (c *qcow2Converter) zero(guest, length int64) error {
+	if !c.writeZeroes {
+		return nil
+	}
+	if remaining := int64(c.hdr.Size) - guest; remaining < length {
+		length = remaining
+	}
+	if c.zeroBuffer == nil {
+		c.zeroBuffer = make([]byte, zeroWriteSize)
+	}
+	for length > 0 {
+		n := length
+		if n > int64(len(c.zeroBuffer)) {
+			n = int64(len(c.zeroBuffer))
+		}
+		if _, err := c.dst.WriteAt(c.zeroBuffer[:n], guest); err != nil {
+			return errors.Wrap(err, "unable to write raw image")
+		}
+		guest += n
+		length -= n
+	}
+	return nil
+}
+
+func (c *qcow2Converter) finish() error {
+	if !c.l1Done {
+		return errors.New("qcow2 image is truncated, the L1 table was not found")
+	}
+	if len(c.pendingL2) > 0 {
+		return errors.Errorf("qcow2 image is truncated, %d L2 tables were not found", len(c.pendingL2))
+	}
+	if len(c.data) > 0 || len(c.compressed) > 0 {
+		return errors.New("qcow2 image is truncated, data clusters were not found")
+	}
+	return nil
+}
+
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package image
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+type qcow2TestOptions struct {
+	clusterBits uint32
+	version     uint32
+	compress    bool
+	// metadataLast places the L2 tables and the L1 table after the data clusters
+	metadataLast bool
+}
+
+// buildQcow2 creates a qcow2 image holding the passed in raw data. Every other data cluster is compressed when
+// requested, and the compressed data is packed so it spans host clusters. Refcounts are not written.
+func buildQcow2(raw []byte, opts qcow2TestOptions) []byte {
+	clusterSize := int64(1) << opts.clusterBits
+	l2Entries := clusterSize / 8
+	clusters := (int64(len(raw)) + clusterSize - 1) / clusterSize
+	l1Size := (clusters + l2Entries - 1) / l2Entries
+
+	img := []byte{}
+	alloc := func(length int64) int64 {
+		offset := (int64(len(img)) + clusterSize - 1) &^ (clusterSize - 1)
+		img = append(img, make([]byte, offset+length-int64(len(img)))...)
+		return offset
+	}
+	alloc(clusterSize)
+
+	var l1Offset int64
+	l2Offsets := make([]int64, l1Size)
+	l2Tables := make([][]uint64, l1Size)
+	for i := range l2Tables {
+		l2Tables[i] = make([]uint64, l2Entries)
+	}
+	if !opts.metadataLast {
+		l1Offset = alloc(l1Size * 8)
+	}
+	for i := int64(0); i < clusters; i++ {
+		table := i / l2Entries
+		if !opts.metadataLast && l2Offsets[table] == 0 {
+			l2Offsets[table] = alloc(clusterSize)
+		}
+		data := make([]byte, clusterSize)
+		copy(data, raw[i*clusterSize:])
+		if isZero(data) {
+			if opts.version == 3 && i%2 == 0 {
+				l2Tables[table][i%l2Entries] = qcow2ZeroFlag
+			}
+			continue
+		}
+		if opts.compress && i%2 == 1 {
+			var compressed bytes.Buffer
+			w, err := flate.NewWriter(&compressed, flate.BestCompression)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = w.Write(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			if int64(compressed.Len()) < clusterSize {
+				offset := int64(len(img))
+				img = append(img, compressed.Bytes()...)
+				offsetBits := 62 - (opts.clusterBits - 8)
+				sectors := uint64((offset+int64(compressed.Len())-1)/512 - offset/512)
+				l2Tables[table][i%l2Entries] = qcow2CompressedFlag | sectors<<offsetBits | uint64(offset)
+				continue
+			}
+		}
+		offset := alloc(clusterSize)
+		copy(img[offset:], data)
+		l2Tables[table][i%l2Entries] = uint64(1)<<63 | uint64(offset)
+	}
+	if opts.metadataLast {
+		for table := range l2Offsets {
+			l2Offsets[table] = alloc(clusterSize)
+		}
+		l1Offset = alloc(l1Size * 8)
+	}
+	for table, l2Offset := range l2Offsets {
+		binary.BigEndian.PutUint64(img[l1Offset+int64(table)*8:], uint64(1)<<63|uint64(l2Offset))
+		for i, entry := range l2Tables[table] {
+			binary.BigEndian.PutUint64(img[l2Offset+int64(i)*8:], entry)
+		}
+	}
+
+	binary.BigEndian.PutUint32(img[0:], qcow2Magic)
+	binary.BigEndian.PutUint32(img[4:], opts.version)
+	binary.BigEndian.PutUint32(img[20:], opts.clusterBits)
+	binary.BigEndian.PutUint64(img[24:], uint64(len(raw)))
+	binary.BigEndian.PutUint32(img[36:], uint32(l1Size))
+	binary.BigEndian.PutUint64(img[40:], uint64(l1Offset))
+	if opts.version == 3 {
+		binary.BigEndian.PutUint32(img[96:], 4)
+		binary.BigEndian.PutUint32(img[100:], qcow2HeaderV3Length)
+	}
+	return img
+}
+
+// testRawImage returns raw data mixing random, compressible and zero clusters, not ending on a cluster boundary
+func testRawImage(clusterSize int64) []byte {
+	raw := make([]byte, 200*clusterSize+clusterSize/2)
+	for i := int64(0); i < 200; i++ {
+		cluster := raw[i*clusterSize : (i+1)*clusterSize]
+		switch i % 5 {
+		case 0, 1:
+			rand.Read(cluster)
+		case 2:
+			copy(cluster, bytes.Repeat([]byte{byte(i)}, int(clusterSize/2)))
+		case 3:
+			copy(cluster[clusterSize/3:], []byte("qcow2 stream"))
+		}
+	}
+	rand.Read(raw[200*clusterSize:])
+	return raw
+}
+
+type memWriterAt []byte
+
+func (m memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	return copy(m[off:], p), nil
+}
+
+var _ = Describe("Qcow2 stream conversion", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "qcow2")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should convert qcow2 to a raw file", func(opts qcow2TestOptions) {
+		raw := testRawImage(int64(1) << opts.clusterBits)
+		dest := filepath.Join(tmpDir, "disk.img")
+		Expect(ConvertQcow2ToRawStream(bytes.NewReader(buildQcow2(raw, opts)), dest)).To(Succeed())
+		converted, err := ioutil.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(converted, raw)).To(BeTrue())
+	},
+		table.Entry("version 2", qcow2TestOptions{clusterBits: 16, version: 2}),
+		table.Entry("version 3", qcow2TestOptions{clusterBits: 16, version: 3}),
+		table.Entry("small clusters and several L2 tables", qcow2TestOptions{clusterBits: 9, version: 3}),
+		table.Entry("compressed clusters", qcow2TestOptions{clusterBits: 12, version: 3, compress: true}),
+		table.Entry("compressed small clusters", qcow2TestOptions{clusterBits: 9, version: 2, compress: true}),
+		table.Entry("metadata after the data", qcow2TestOptions{clusterBits: 12, version: 3, metadataLast: true}),
+		table.Entry("compressed clusters and metadata after the data", qcow2TestOptions{clusterBits: 9, version: 3, compress: true, metadataLast: true}),
+	)
+
+	It("should write zeroes over unallocated clusters when the target does not read as zeroes", func() {
+		opts := qcow2TestOptions{clusterBits: 12, version: 3, compress: true}
+		raw := testRawImage(int64(1) << opts.clusterBits)
+		img := buildQcow2(raw, opts)
+		hdr, err := ParseQcow2Header(img)
+		Expect(err).ToNot(HaveOccurred())
+		target := memWriterAt(bytes.Repeat([]byte{0xff}, len(raw)))
+		Expect(newQcow2Converter(hdr, target, true).convert(bytes.NewReader(img))).To(Succeed())
+		Expect(bytes.Equal(target, raw)).To(BeTrue())
+	})
+
+	It("should fail when the metadata comes after too much data", func() {
+		opts := qcow2TestOptions{clusterBits: 12, version: 3, metadataLast: true}
+		img := buildQcow2(testRawImage(int64(1)<<opts.clusterBits), opts)
+		hdr, err := ParseQcow2Header(img)
+		Expect(err).ToNot(HaveOccurred())
+		c := newQcow2Converter(hdr, memWriterAt(make([]byte, hdr.Size)), false)
+		c.maxPending = 16 << opts.clusterBits
+		err = c.convert(bytes.NewReader(img))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot be converted while streaming"))
+	})
+
+	It("should fail on a truncated image and remove the target file", func() {
+		opts := qcow2TestOptions{clusterBits: 12, version: 3}
+		img := buildQcow2(testRawImage(int64(1)<<opts.clusterBits), opts)
+		dest := filepath.Join(tmpDir, "disk.img")
+		err := ConvertQcow2ToRawStream(bytes.NewReader(img[:len(img)/2]), dest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("truncated"))
+		_, err = os.Stat(dest)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	table.DescribeTable("should reject headers", func(modify func([]byte), errorMessage string) {
+		hdr := buildQcow2(make([]byte, 4096), qcow2TestOptions{clusterBits: 16, version: 3})
+		modify(hdr)
+		_, err := ParseQcow2Header(hdr)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(errorMessage))
+	},
+		table.Entry("not qcow2", func(b []byte) { b[0] = 0 }, "not a qcow2 image"),
+		table.Entry("unknown version", func(b []byte) { binary.BigEndian.PutUint32(b[4:], 4) }, "unsupported qcow2 version"),
+		table.Entry("backing file", func(b []byte) { binary.BigEndian.PutUint64(b[8:], 512) }, "backing file"),
+		table.Entry("encryption", func(b []byte) { binary.BigEndian.PutUint32(b[32:], 1) }, "encrypted"),
+		table.Entry("cluster bits", func(b []byte) { binary.BigEndian.PutUint32(b[20:], 30) }, "cluster bits"),
+		table.Entry("external data file", func(b []byte) { binary.BigEndian.PutUint64(b[72:], 1<<2) }, "incompatible features"),
+		table.Entry("L1 table too small", func(b []byte) { binary.BigEndian.PutUint64(b[24:], 1<<40) }, "too small"),
+	)
+
+	It("should accept dirty images", func() {
+		hdr := buildQcow2(make([]byte, 4096), qcow2TestOptions{clusterBits: 16, version: 3})
+		binary.BigEndian.PutUint64(hdr[72:], qcow2IncompatDirty)
+		_, err := ParseQcow2Header(hdr)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
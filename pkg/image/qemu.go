@@ -63,6 +63,7 @@ type QEMUOperations interface {
 	Info(url *url.URL) (*ImgInfo, error)
 	Validate(*url.URL, int64, float64) error
 	CreateBlankImage(string, resource.Quantity, bool) error
+	ConvertToCompressedQcow2(string, string) error
 }
 
 type qemuOperations struct{}
@@ -233,11 +234,25 @@ func (o *qemuOperations) Validate(url *url.URL, availableSize int64, filesystemO
 	return checkIfURLIsValid(info, availableSize, filesystemOverhead, url.String())
 }
 
+func (o *qemuOperations) ConvertToCompressedQcow2(src, dest string) error {
+	args := []string{"convert", "-t", "none", "-p", "-c", "-O", "qcow2", src, dest}
+	if _, err := qemuExecFunction(nil, reportProgress, "qemu-img", args...); err != nil {
+		os.Remove(dest)
+		return errors.Wrap(err, "could not convert image to compressed qcow2")
+	}
+	return nil
+}
+
 // ConvertToRawStream converts an http accessible image to raw format without locally caching the image
 func ConvertToRawStream(url *url.URL, dest string, preallocate bool) error {
 	return qemuIterface.ConvertToRawStream(url, dest, preallocate)
 }
 
+// ConvertToCompressedQcow2 converts a local image to a compressed qcow2 image
+func ConvertToCompressedQcow2(src, dest string) error {
+	return qemuIterface.ConvertToCompressedQcow2(src, dest)
+}
+
 // Validate does basic validation of a qemu image
 func Validate(url *url.URL, availableSize int64, filesystemOverhead float64) error {
 	return qemuIterface.Validate(url, availableSize, filesystemOverhead)
@@ -171,6 +171,23 @@ var _ = Describe("Convert to Raw", func() {
 	})
 })
 
+var _ = Describe("Convert to compressed qcow2", func() {
+	It("should return no error if exec function returns no error", func() {
+		replaceExecFunction(mockExecFunctionStrict("", "", nil, "convert", "-t", "none", "-p", "-c", "-O", "qcow2", "source", "dest"), func() {
+			err := ConvertToCompressedQcow2("source", "dest")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should return conversion error if exec function returns error", func() {
+		replaceExecFunction(mockExecFunction("", "exit 1", nil, "convert", "-c", "-O", "qcow2", "source", "dest"), func() {
+			err := ConvertToCompressedQcow2("source", "dest")
+			Expect(err).To(HaveOccurred())
+			Expect(strings.Contains(err.Error(), "could not convert image to compressed qcow2")).To(BeTrue())
+		})
+	})
+})
+
 var _ = Describe("Resize", func() {
 	It("Should complete successfully if qemu-img resize succeeds", func() {
 		quantity, err := resource.ParseQuantity("10Gi")
@@ -21,6 +21,10 @@ const (
 	ExtTar = ".tar"
 	// ExtXz is a constant for the .xz extenstion
 	ExtXz = ".xz"
+	// ExtZst is a constant for the .zst extenstion
+	ExtZst = ".zst"
+	// ExtBz2 is a constant for the .bz2 extenstion
+	ExtBz2 = ".bz2"
 	// ExtTarXz is a constant for the .tar.xz extenstion
 	ExtTarXz = ExtTar + ExtXz
 	// ExtTarGz is a constant for the .tar.gz extenstion
@@ -28,6 +28,7 @@ import (
 
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 var qemuOperations = image.NewQEMUOperations()
@@ -66,6 +67,13 @@ type ValidationSizeError struct {
 
 func (e ValidationSizeError) Error() string { return e.err.Error() }
 
+// ChecksumValidationError is an error indicating the checksum of the source data does not match the expected checksum.
+type ChecksumValidationError struct {
+	err error
+}
+
+func (e ChecksumValidationError) Error() string { return e.err.Error() }
+
 // ErrRequiresScratchSpace indicates that we require scratch space.
 var ErrRequiresScratchSpace = fmt.Errorf("scratch space required and none found")
 
@@ -120,6 +128,10 @@ type DataProcessor struct {
 	preallocation bool
 	// preallocationApplied is used to pass information whether preallocation has been performed, or not
 	preallocationApplied bool
+	// keepPartialDownloads decides if interrupted downloads survive the cleanup, so the data source can resume them
+	keepPartialDownloads bool
+	// stagedFile is an upload staged in scratch space that the data source reads, it survives the cleanup
+	stagedFile string
 }
 
 // NewDataProcessor create a new instance of a data processor using the passed in data provider.
@@ -129,16 +141,23 @@ func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratch
 	if isVddk {
 		needsDataCleanup = !vddkSource.IsDeltaCopy()
 	}
+	_, keepPartialDownloads := dataSource.(*HTTPDataSource)
+	var stagedFile string
+	if uploadSource, isUpload := dataSource.(*UploadDataSource); isUpload {
+		stagedFile = uploadSource.stagedFile
+	}
 	dp := &DataProcessor{
-		currentPhase:       ProcessingPhaseInfo,
-		source:             dataSource,
-		dataFile:           dataFile,
-		dataDir:            dataDir,
-		scratchDataDir:     scratchDataDir,
-		requestImageSize:   requestImageSize,
-		filesystemOverhead: filesystemOverhead,
-		needsDataCleanup:   needsDataCleanup,
-		preallocation:      preallocation,
+		currentPhase:         ProcessingPhaseInfo,
+		source:               dataSource,
+		dataFile:             dataFile,
+		dataDir:              dataDir,
+		scratchDataDir:       scratchDataDir,
+		requestImageSize:     requestImageSize,
+		filesystemOverhead:   filesystemOverhead,
+		needsDataCleanup:     needsDataCleanup,
+		preallocation:        preallocation,
+		keepPartialDownloads: keepPartialDownloads,
+		stagedFile:           stagedFile,
 	}
 	// Calculate available space before doing anything.
 	dp.availableSpace = dp.calculateTargetSize()
@@ -149,22 +168,34 @@ func NewDataProcessor(dataSource DataSourceInterface, dataFile, dataDir, scratch
 func (dp *DataProcessor) ProcessData() error {
 	if size, _ := util.GetAvailableSpace(dp.scratchDataDir); size > int64(0) {
 		// Clean up before trying to write, in case a previous attempt left a mess. Note the deferred cleanup is intentional.
-		if err := CleanDir(dp.scratchDataDir); err != nil {
+		if err := dp.cleanDir(dp.scratchDataDir); err != nil {
 			return errors.Wrap(err, "Failure cleaning up temporary scratch space")
 		}
 		// Attempt to be a good citizen and clean up my mess at the end.
-		defer CleanDir(dp.scratchDataDir)
+		defer dp.cleanDir(dp.scratchDataDir)
 	}
 
 	if size, _ := util.GetAvailableSpace(dp.dataDir); size > int64(0) && dp.needsDataCleanup {
 		// Clean up data dir before trying to write in case a previous attempt failed and left some stuff behind.
-		if err := CleanDir(dp.dataDir); err != nil {
+		if err := dp.cleanDir(dp.dataDir); err != nil {
 			return errors.Wrap(err, "Failure cleaning up target space")
 		}
 	}
 	return dp.ProcessDataWithPause()
 }
 
+// cleanDir cleans up the passed in directory, keeping partial downloads if the data source can resume them, and the
+// staged upload the data source reads.
+func (dp *DataProcessor) cleanDir(dir string) error {
+	if dp.keepPartialDownloads {
+		return CleanDirExceptPartialDownloads(dir)
+	}
+	if dp.stagedFile != "" {
+		return cleanDirExcept(dir, dp.stagedFile)
+	}
+	return CleanDir(dir)
+}
+
 // ProcessDataResume Resume a paused processor, assumes the provided data source is ResumableDataSource
 func (dp *DataProcessor) ProcessDataResume() error {
 	rds, ok := dp.source.(ResumableDataSource)
@@ -180,6 +211,7 @@ func (dp *DataProcessor) ProcessDataResume() error {
 func (dp *DataProcessor) ProcessDataWithPause() error {
 	var err error
 	for dp.currentPhase != ProcessingPhaseComplete && dp.currentPhase != ProcessingPhasePause {
+		prometheusutil.SetProgressPhase(string(dp.currentPhase))
 		switch dp.currentPhase {
 		case ProcessingPhaseInfo:
 			dp.currentPhase, err = dp.source.Info()
@@ -230,6 +262,7 @@ func (dp *DataProcessor) ProcessDataWithPause() error {
 		}
 		klog.V(1).Infof("New phase: %s\n", dp.currentPhase)
 	}
+	prometheusutil.SetProgressPhase(string(dp.currentPhase))
 	return err
 }
 
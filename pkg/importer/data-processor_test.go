@@ -470,6 +470,10 @@ func (o *fakeQEMUOperations) CreateBlankImage(dest string, size resource.Quantit
 	return o.e6
 }
 
+func (o *fakeQEMUOperations) ConvertToCompressedQcow2(src, dest string) error {
+	return o.e2
+}
+
 func NewQEMUAllErrors() image.QEMUOperations {
 	err := errors.New("qemu should not be called from this test override with replaceQEMUOperations")
 	return NewFakeQEMUOperations(err, err, fakeInfoOpRetVal{nil, err}, err, err, nil)
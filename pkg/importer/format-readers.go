@@ -18,12 +18,16 @@ package importer
 
 import (
 	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"encoding/hex"
+	"hash"
 	"io"
 	"io/ioutil"
 	"strconv"
+	"strings"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 	"github.com/ulikunitz/xz"
 
@@ -71,10 +75,47 @@ type FormatReaders struct {
 	readers        []reader
 	buf            []byte // holds file headers
 	Convert        bool
+	StreamConvert  bool // qcow2 image that can be converted to raw while streaming, without scratch space
 	Archived       bool
 	ArchiveXz      bool
 	ArchiveGz      bool
+	ArchiveZst     bool
+	ArchiveBz2     bool
 	progressReader *prometheusutil.ProgressReader
+	checksumReader *checksumReader
+}
+
+// checksumReader computes the checksum of the data read through it, and compares it to the expected value
+type checksumReader struct {
+	io.ReadCloser
+	algorithm string
+	expected  string
+	hash      hash.Hash
+	verified  bool
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+// checksum returns the computed checksum in the form <algorithm>:<hex value>
+func (r *checksumReader) checksum() string {
+	return r.algorithm + ":" + hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// verify reads any data the upper readers left unread, and compares the checksum of the whole stream
+func (r *checksumReader) verify() error {
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return errors.Wrap(err, "could not read the remaining data to verify the checksum")
+	}
+	if computed := r.checksum(); computed != r.algorithm+":"+r.expected {
+		return ChecksumValidationError{err: errors.Errorf("checksum mismatch, expected %s:%s, computed %s", r.algorithm, r.expected, computed)}
+	}
+	klog.V(1).Infof("Verified checksum %s:%s", r.algorithm, r.expected)
+	r.verified = true
+	return nil
 }
 
 const (
@@ -82,6 +123,8 @@ const (
 	rdrMulti
 	rdrXz
 	rdrStream
+	rdrZst
+	rdrBz2
 )
 
 // map scheme and format to rdrType
@@ -89,14 +132,36 @@ var rdrTypM = map[string]int{
 	"gz":     rdrGz,
 	"xz":     rdrXz,
 	"stream": rdrStream,
+	"zst":    rdrZst,
+	"bz2":    rdrBz2,
 }
 
 // NewFormatReaders creates a new instance of FormatReaders using the input stream and content type passed in.
 func NewFormatReaders(stream io.ReadCloser, total uint64) (*FormatReaders, error) {
+	return NewFormatReadersWithChecksum(stream, total, "")
+}
+
+// NewFormatReadersWithChecksum creates a new instance of FormatReaders using the input stream passed in. If a checksum
+// of the form <algorithm>:<hex value> is passed in, the checksum of the whole input stream is computed and can be
+// verified once the data has been transferred.
+func NewFormatReadersWithChecksum(stream io.ReadCloser, total uint64, checksum string) (*FormatReaders, error) {
 	var err error
 	readers := &FormatReaders{
 		buf: make([]byte, image.MaxExpectedHdrSize),
 	}
+	if checksum != "" {
+		h, expected, err := util.ParseChecksum(checksum)
+		if err != nil {
+			return readers, err
+		}
+		readers.checksumReader = &checksumReader{
+			ReadCloser: stream,
+			algorithm:  checksum[:strings.Index(checksum, ":")],
+			expected:   expected,
+			hash:       h,
+		}
+		stream = readers.checksumReader
+	}
 	if total > uint64(0) {
 		readers.progressReader = prometheusutil.NewProgressReader(stream, total, progress, ownerUID)
 		err = readers.constructReaders(readers.progressReader)
@@ -178,6 +243,18 @@ func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
 			fr.Archived = true
 			fr.ArchiveXz = true
 		}
+	case "zst":
+		r, err = fr.zstReader()
+		if err == nil {
+			fr.Archived = true
+			fr.ArchiveZst = true
+		}
+	case "bz2":
+		r, err = fr.bz2Reader()
+		if err == nil {
+			fr.Archived = true
+			fr.ArchiveBz2 = true
+		}
 	case "vmdk":
 		r = nil
 		fr.Convert = true
@@ -219,9 +296,47 @@ func (fr *FormatReaders) qcow2NopReader(h *image.Header) (io.Reader, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to determine original qcow2 file size from %+v", s)
 	}
+	if _, err := image.ParseQcow2Header(fr.buf); err != nil {
+		klog.V(1).Infof("qcow2 image cannot be converted while streaming: %v", err)
+	} else {
+		fr.StreamConvert = true
+	}
 	return nil, nil
 }
 
+// streamToFile writes the data of the top reader to the passed in file. Images that can be converted while
+// streaming are written as raw.
+func (fr *FormatReaders) streamToFile(fileName string) error {
+	var err error
+	if fr.StreamConvert {
+		err = image.ConvertQcow2ToRawStream(fr.TopReader(), fileName)
+	} else {
+		err = util.StreamDataToFile(fr.TopReader(), fileName)
+	}
+	if err != nil {
+		return err
+	}
+	return fr.VerifyChecksum()
+}
+
+// VerifyChecksum compares the checksum of the input stream to the expected checksum, if one was passed in. It must
+// be called after the data has been transferred.
+func (fr *FormatReaders) VerifyChecksum() error {
+	if fr.checksumReader == nil {
+		return nil
+	}
+	return fr.checksumReader.verify()
+}
+
+// Checksum returns the verified checksum of the input stream in the form <algorithm>:<hex value>, or an empty
+// string if no checksum was requested or it has not been verified.
+func (fr *FormatReaders) Checksum() string {
+	if fr.checksumReader == nil || !fr.checksumReader.verified {
+		return ""
+	}
+	return fr.checksumReader.checksum()
+}
+
 // Return the xz reader and size of the endpoint "through the eye" of the previous reader.
 // Assumes a single file was compressed. Note: the xz reader is not a closer so we wrap a
 // nop Closer around it.
@@ -236,6 +351,25 @@ func (fr *FormatReaders) xzReader() (io.Reader, error) {
 	return xz, nil
 }
 
+// Return the zstd reader of the endpoint "through the eye" of the previous reader.
+// Assumes a single file was compressed.
+//NOTE: the decompressed size is optional in the zstd frame header. For now 0 is returned.
+func (fr *FormatReaders) zstReader() (io.ReadCloser, error) {
+	zst, err := zstd.NewReader(fr.TopReader())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create zstd reader")
+	}
+	return zst.IOReadCloser(), nil
+}
+
+// Return the bzip2 reader of the endpoint "through the eye" of the previous reader.
+// Assumes a single file was compressed. The bzip2 reader never fails to be created, errors in the
+// compressed data are returned by its Read. It is not a closer, appendReader wraps it in a nop Closer.
+//NOTE: size is not stored in the bzip2 header, so the size of the endpoint is not updated.
+func (fr *FormatReaders) bz2Reader() (io.Reader, error) {
+	return bzip2.NewReader(fr.TopReader()), nil
+}
+
 // Return the matching header, if one is found, from the passed-in map of known headers. After a
 // successful read append a multi-reader to the receiver's reader stack.
 // Note: .iso files are not detected here but rather in the Size() function.
@@ -1,7 +1,13 @@
 package importer
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
@@ -23,6 +29,8 @@ var (
 	tinyCoreFilePath          = filepath.Join(imageDir, tinyCoreFileName)
 	tinyCoreXzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtXz)
 	tinyCoreGzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtGz)
+	tinyCoreZstFilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtZst)
+	tinyCoreBz2FilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtBz2)
 	tinyCoreTarFilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtTar)
 	archiveFilePath, _        = utils.ArchiveFiles(archiveFileNameWithoutExt, os.TempDir(), tinyCoreFilePath, cirrosFilePath)
 	archiveFileNameWithoutExt = strings.TrimSuffix(archiveFileName, filepath.Ext(archiveFileName))
@@ -62,6 +70,8 @@ var _ = Describe("Format Readers", func() {
 	},
 		table.Entry("successfully construct a xz reader", tinyCoreXzFilePath, 4, false, true, false),              // [stream, multi-r, xz, multi-r] convert = false
 		table.Entry("successfully construct a gz reader", tinyCoreGzFilePath, 4, false, true, false),              // [stream, multi-r, gz, multi-r] convert = false
+		table.Entry("successfully construct a zst reader", tinyCoreZstFilePath, 4, false, true, false),            // [stream, multi-r, zst, multi-r] convert = false
+		table.Entry("successfully construct a bz2 reader", tinyCoreBz2FilePath, 4, false, true, false),            // [stream, multi-r, bz2, multi-r] convert = false
 		table.Entry("successfully return the base reader when archived", archiveFilePath, 3, false, false, false), // [stream, multi-r, multi-r] convert = false
 		table.Entry("successfully construct qcow2 reader", cirrosFilePath, 2, false, false, true),                 // [stream, multi-r] convert = true
 		table.Entry("successfully construct .iso reader", tinyCoreFilePath, 2, false, false, false),               // [stream, multi-r] convert = false
@@ -100,4 +110,38 @@ var _ = Describe("Format Readers", func() {
 		// This should not crash
 		testReader.StartProgressUpdate()
 	})
+	table.DescribeTable("should verify the checksum of the source stream", func(filename, algorithm string, newHash func() hash.Hash, mismatch bool) {
+		data, err := ioutil.ReadFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		h := newHash()
+		h.Write(data)
+		checksum := algorithm + ":" + hex.EncodeToString(h.Sum(nil))
+		if mismatch {
+			checksum = algorithm + ":" + strings.Repeat("0", 2*h.Size())
+		}
+		fr, err = NewFormatReadersWithChecksum(ioutil.NopCloser(bytes.NewReader(data)), uint64(0), checksum)
+		Expect(err).NotTo(HaveOccurred())
+		// Only read part of the decompressed data, the rest of the source is read by the verification
+		_, err = io.CopyN(ioutil.Discard, fr.TopReader(), 1024)
+		Expect(err).NotTo(HaveOccurred())
+		err = fr.VerifyChecksum()
+		if mismatch {
+			Expect(err).To(BeAssignableToTypeOf(ChecksumValidationError{}))
+			Expect(fr.Checksum()).To(BeEmpty())
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fr.Checksum()).To(Equal(checksum))
+		}
+	},
+		table.Entry("sha256 of a gz file", tinyCoreGzFilePath, "sha256", sha256.New, false),
+		table.Entry("sha512 of a qcow2 file", cirrosFilePath, "sha512", sha512.New, false),
+		table.Entry("md5 of a raw file", tinyCoreFilePath, "md5", md5.New, false),
+		table.Entry("mismatch on a xz file", tinyCoreXzFilePath, "sha256", sha256.New, true),
+	)
+
+	It("should fail on an invalid checksum", func() {
+		stringReader := ioutil.NopCloser(strings.NewReader("This is a test string"))
+		_, err := NewFormatReadersWithChecksum(stringReader, uint64(0), "sha1:1234")
+		Expect(err).To(HaveOccurred())
+	})
 })
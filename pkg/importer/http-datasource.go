@@ -52,6 +52,8 @@ const (
 // 1a. Info -> Convert (In Info phase the format readers are configured), if the source Reader image is not archived, and no custom CA is used, and can be converted by QEMU-IMG (RAW/QCOW2)
 // 1b. Info -> TransferArchive if the content type is archive
 // 1c. Info -> Transfer in all other cases.
+// 1d. Info -> TransferDataFile if the image is raw and downloaded with concurrent range requests
+// 1e. Info -> TransferScratch if the image needs conversion and is downloaded with concurrent range requests
 // 2a. Transfer -> Convert if content type is kube virt
 // 2b. Transfer -> Complete if content type is archive (Transfer is called with the target instead of the scratch space). Non block PVCs only.
 type HTTPDataSource struct {
@@ -73,6 +75,12 @@ type HTTPDataSource struct {
 	brokenForQemuImg bool
 	// the content length reported by the http server.
 	contentLength uint64
+	// expected checksum of the endpoint data, empty if not verified
+	checksum string
+	// validator of the endpoint data if an interrupted download can be resumed, nil otherwise
+	resume *resumeValidator
+	// number of concurrent range requests used to download the endpoint data
+	parallelism int
 
 	n image.NbdkitOperation
 }
@@ -80,13 +88,13 @@ type HTTPDataSource struct {
 var createNbdkitCurl = image.NewNbdkitCurl
 
 // NewHTTPDataSource creates a new instance of the http data provider.
-func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType) (*HTTPDataSource, error) {
+func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType, checksum string, parallelism int) (*HTTPDataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
 	}
 	ctx, cancel := context.WithCancel(context.Background())
-	httpReader, contentLength, brokenForQemuImg, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir)
+	httpReader, contentLength, brokenForQemuImg, resume, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -104,6 +112,9 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 		customCA:         certDir,
 		brokenForQemuImg: brokenForQemuImg,
 		contentLength:    contentLength,
+		checksum:         checksum,
+		resume:           resume,
+		parallelism:      parallelism,
 	}
 	httpSource.n = createNbdkitCurl(nbdkitPid, certDir, nbdkitSocket)
 	// We know this is a counting reader, so no need to check.
@@ -115,7 +126,7 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 // Info is called to get initial information about the data.
 func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 	var err error
-	hs.readers, err = NewFormatReaders(hs.httpReader, hs.contentLength)
+	hs.readers, err = NewFormatReadersWithChecksum(hs.httpReader, hs.contentLength, hs.checksum)
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
@@ -135,6 +146,33 @@ func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 	if hs.contentType == cdiv1.DataVolumeArchive {
 		return ProcessingPhaseTransferDataDir, nil
 	}
+	if hs.readers.ArchiveZst || hs.readers.ArchiveBz2 {
+		// nbdkit has no zstd or bzip2 filter, the data is decompressed by the readers instead
+		if !hs.readers.Convert || hs.readers.StreamConvert {
+			return ProcessingPhaseTransferDataFile, nil
+		}
+		return ProcessingPhaseTransferScratch, nil
+	}
+	if hs.checksum != "" {
+		// nbdkit reads the endpoint itself, the data has to go through the readers to compute the checksum
+		if !hs.readers.Convert || hs.readers.StreamConvert {
+			return ProcessingPhaseTransferDataFile, nil
+		}
+		return ProcessingPhaseTransferScratch, nil
+	}
+	if hs.downloadsParallel() {
+		if !hs.readers.Convert {
+			klog.V(1).Infof("Downloading raw image with %d concurrent range requests", hs.parallelism)
+			return ProcessingPhaseTransferDataFile, nil
+		}
+		klog.V(1).Infof("Downloading to scratch space with %d concurrent range requests", hs.parallelism)
+		return ProcessingPhaseTransferScratch, nil
+	}
+	if hs.readers.StreamConvert && (hs.brokenForQemuImg || hs.customCA != "") {
+		// nbdkit can't be used, convert while streaming instead of downloading to scratch space
+		klog.V(1).Infof("Converting qcow2 image while streaming, no scratch space needed")
+		return ProcessingPhaseTransferDataFile, nil
+	}
 	if hs.brokenForQemuImg {
 		return ProcessingPhaseTransferScratch, nil
 	}
@@ -158,10 +196,17 @@ func (hs *HTTPDataSource) Transfer(path string) (ProcessingPhase, error) {
 			return ProcessingPhaseError, ErrInvalidPath
 		}
 		file := filepath.Join(path, tempFile)
-		err = util.StreamDataToFile(hs.readers.TopReader(), file)
+		if hs.canResume() {
+			err = hs.download(file)
+		} else {
+			err = util.StreamDataToFile(hs.readers.TopReader(), file)
+		}
 		if err != nil {
 			return ProcessingPhaseError, err
 		}
+		if err := hs.readers.VerifyChecksum(); err != nil {
+			return ProcessingPhaseError, err
+		}
 		// If we successfully wrote to the file, then the parse will succeed.
 		hs.url, _ = url.Parse(file)
 		return ProcessingPhaseConvert, nil
@@ -169,6 +214,9 @@ func (hs *HTTPDataSource) Transfer(path string) (ProcessingPhase, error) {
 		if err := util.UnArchiveTar(hs.readers.TopReader(), path); err != nil {
 			return ProcessingPhaseError, errors.Wrap(err, "unable to untar files from endpoint")
 		}
+		if err := hs.readers.VerifyChecksum(); err != nil {
+			return ProcessingPhaseError, err
+		}
 		hs.url = nil
 		return ProcessingPhaseComplete, nil
 	}
@@ -178,7 +226,12 @@ func (hs *HTTPDataSource) Transfer(path string) (ProcessingPhase, error) {
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (hs *HTTPDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
 	hs.readers.StartProgressUpdate()
-	err := util.StreamDataToFile(hs.readers.TopReader(), fileName)
+	var err error
+	if hs.downloadsParallel() && !hs.readers.Convert && !isBlockDevice(fileName) {
+		err = hs.download(fileName)
+	} else {
+		err = hs.readers.streamToFile(fileName)
+	}
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -194,6 +247,9 @@ func (hs *HTTPDataSource) GetURL() *url.URL {
 func (hs *HTTPDataSource) Close() error {
 	var err error
 	if hs.readers != nil {
+		if checksum := hs.readers.Checksum(); checksum != "" {
+			appendTerminationMessage("Checksum: " + checksum)
+		}
 		err = hs.readers.Close()
 	}
 	hs.cancelLock.Lock()
@@ -268,11 +324,11 @@ func createHTTPClient(certDir string) (*http.Client, error) {
 	return client, nil
 }
 
-func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string) (io.ReadCloser, uint64, bool, error) {
+func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string) (io.ReadCloser, uint64, bool, *resumeValidator, error) {
 	var brokenForQemuImg bool
 	client, err := createHTTPClient(certDir)
 	if err != nil {
-		return nil, uint64(0), false, errors.Wrap(err, "Error creating http client")
+		return nil, uint64(0), false, nil, errors.Wrap(err, "Error creating http client")
 	}
 
 	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
@@ -296,11 +352,11 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 	klog.V(2).Infof("Attempting to get object %q via http client\n", ep.String())
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, uint64(0), true, errors.Wrap(err, "HTTP request errored")
+		return nil, uint64(0), true, nil, errors.Wrap(err, "HTTP request errored")
 	}
 	if resp.StatusCode != 200 {
 		klog.Errorf("http: expected status code 200, got %d", resp.StatusCode)
-		return nil, uint64(0), true, errors.Errorf("expected status code 200, got %d. Status: %s", resp.StatusCode, resp.Status)
+		return nil, uint64(0), true, nil, errors.Errorf("expected status code 200, got %d. Status: %s", resp.StatusCode, resp.Status)
 	}
 
 	acceptRanges, ok := resp.Header["Accept-Ranges"]
@@ -317,17 +373,17 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 		Reader:  resp.Body,
 		Current: 0,
 	}
-	return countingReader, total, brokenForQemuImg, nil
+	return countingReader, total, brokenForQemuImg, getResumeValidator(resp, ep, total), nil
 }
 
 func (hs *HTTPDataSource) pollProgress(reader *util.CountingReader, idleTime, pollInterval time.Duration) {
-	count := reader.Current
+	count := reader.Count()
 	lastUpdate := time.Now()
 	for {
-		if count < reader.Current {
+		if current := reader.Count(); count < current {
 			// Some progress was made, reset now.
 			lastUpdate = time.Now()
-			count = reader.Current
+			count = current
 		}
 
 		if time.Until(lastUpdate.Add(idleTime)).Nanoseconds() < 0 {
@@ -1,8 +1,12 @@
 package importer
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
 	"crypto/x509"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -72,14 +76,14 @@ var _ = Describe("Http data source", func() {
 	})
 
 	It("NewHTTPDataSource should fail when called with an invalid endpoint", func() {
-		_, err = NewHTTPDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", cdiv1.DataVolumeKubeVirt)
+		_, err = NewHTTPDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).To(HaveOccurred())
 		Expect(strings.Contains(err.Error(), "unable to parse endpoint")).To(BeTrue())
 	})
 
 	It("endpoint User object should be set when accessKey and secKey are not blank", func() {
 		image := ts.URL + "/" + cirrosFileName
-		dp, err = NewHTTPDataSource(image, "user", "password", "", cdiv1.DataVolumeKubeVirt)
+		dp, err = NewHTTPDataSource(image, "user", "password", "", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		user := dp.endpoint.User
 		Expect("user").To(Equal(user.Username()))
@@ -90,7 +94,7 @@ var _ = Describe("Http data source", func() {
 
 	It("NewHTTPDataSource should fail when called with an invalid certdir", func() {
 		image := ts.URL + "/" + cirrosFileName
-		_, err = NewHTTPDataSource(image, "", "", "/invaliddir", cdiv1.DataVolumeKubeVirt)
+		_, err = NewHTTPDataSource(image, "", "", "/invaliddir", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -99,7 +103,7 @@ var _ = Describe("Http data source", func() {
 		if image != "" {
 			image = ts.URL + "/" + image
 		}
-		dp, err = NewHTTPDataSource(image, "", "", "", contentType)
+		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		newPhase, err := dp.Info()
 		if !wantErr {
@@ -120,7 +124,7 @@ var _ = Describe("Http data source", func() {
 	)
 
 	It("calling info with raw image should return TransferDataFile", func() {
-		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		newPhase, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -135,7 +139,7 @@ var _ = Describe("Http data source", func() {
 		if image != "" {
 			image = ts.URL + "/" + image
 		}
-		dp, err = NewHTTPDataSource(image, "", "", "", contentType)
+		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		_, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -166,7 +170,7 @@ var _ = Describe("Http data source", func() {
 	)
 
 	It("TransferFile should succeed when writing to valid file, and reading raw gz", func() {
-		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		result, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -174,12 +178,61 @@ var _ = Describe("Http data source", func() {
 	})
 
 	It("TransferFile should succeed when writing to valid file and reading raw xz", func() {
-		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreXz, "", "", "", cdiv1.DataVolumeKubeVirt)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreXz, "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
 		Expect(err).NotTo(HaveOccurred())
 		result, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseConvert).To(Equal(result))
 	})
+
+	table.DescribeTable("TransferFile should decompress raw images nbdkit has no filter for", func(fileName string) {
+		compressedServer := createTestServer(filepath.Dir(fileName))
+		defer compressedServer.Close()
+		dp, err = NewHTTPDataSource(compressedServer.URL+"/"+filepath.Base(fileName), "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = dp.TransferFile(filepath.Join(tmpDir, "file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		want, err := readFile(tinyCoreFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		got, err := readFile(filepath.Join(tmpDir, "file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(got, want)).To(BeTrue())
+	},
+		table.Entry("zst", tinyCoreZstFilePath),
+		table.Entry("bz2", tinyCoreBz2FilePath),
+	)
+
+	table.DescribeTable("TransferFile with a checksum should", func(checksum func([]byte) string, wantErr bool) {
+		compressedServer := createTestServer(filepath.Dir(tinyCoreGzFilePath))
+		defer compressedServer.Close()
+		data, err := readFile(tinyCoreGzFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		dp, err = NewHTTPDataSource(compressedServer.URL+"/"+filepath.Base(tinyCoreGzFilePath), "", "", "", cdiv1.DataVolumeKubeVirt, checksum(data), 1)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := dp.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = dp.TransferFile(filepath.Join(tmpDir, "file"))
+		if wantErr {
+			Expect(err).To(BeAssignableToTypeOf(ChecksumValidationError{}))
+			Expect(ProcessingPhaseError).To(Equal(result))
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ProcessingPhaseResize).To(Equal(result))
+			Expect(dp.readers.Checksum()).To(Equal(checksum(data)))
+		}
+	},
+		table.Entry("succeed when the checksum matches", func(data []byte) string {
+			return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+		}, false),
+		table.Entry("fail when the checksum does not match", func(data []byte) string {
+			return fmt.Sprintf("md5:%x", md5.Sum(append(data, 0)))
+		}, true),
+	)
 })
 
 var _ = Describe("Http client", func() {
@@ -226,7 +279,7 @@ var _ = Describe("Http client", func() {
 
 var _ = Describe("Http reader", func() {
 	It("should fail when passed an invalid cert directory", func() {
-		_, total, _, err := createHTTPReader(context.Background(), nil, "", "", "/invalid")
+		_, total, _, _, err := createHTTPReader(context.Background(), nil, "", "", "/invalid")
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 	})
@@ -243,7 +296,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "user", "password", "")
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "user", "password", "")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
 		err = r.Close()
@@ -266,7 +319,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "user", "password", "")
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "user", "password", "")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
 		err = r.Close()
@@ -288,7 +341,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "")
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "")
 		Expect(brokenForQemuImg).To(BeFalse())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -309,7 +362,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "", "", "")
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 		err = r.Close()
@@ -333,7 +386,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "")
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "")
 		Expect(brokenForQemuImg).To(BeTrue())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -353,7 +406,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "")
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "")
 		Expect(brokenForQemuImg).To(BeTrue())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -368,7 +421,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		_, total, _, err := createHTTPReader(context.Background(), ep, "", "", "")
+		_, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "")
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 		Expect("expected status code 200, got 500. Status: 500 Internal Server Error").To(Equal(err.Error()))
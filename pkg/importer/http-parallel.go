@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+// parallelChunkSize is the amount of data fetched by a single range request of a parallel download
+var parallelChunkSize = int64(64 * 1024 * 1024)
+
+// downloadsParallel returns true if the endpoint data is downloaded with concurrent range requests instead of nbdkit.
+// Otherwise, only the data saved to scratch space is downloaded by the importer itself.
+func (hs *HTTPDataSource) downloadsParallel() bool {
+	return hs.canResume() && hs.parallelism > 1
+}
+
+// download writes the endpoint data as is to the passed in file, with concurrent range requests if requested.
+func (hs *HTTPDataSource) download(fileName string) error {
+	if hs.parallelism > 1 {
+		return hs.downloadParallel(fileName)
+	}
+	return hs.downloadResumable(fileName)
+}
+
+// chunkTracker records the progress of a parallel download. Chunks complete out of order, the progress marker records
+// the end of the data downloaded without gaps, so a restarted download never skips a chunk.
+type chunkTracker struct {
+	lock       sync.Mutex
+	file       *os.File
+	markerFile string
+	marker     progressMarker
+	// completed maps the start of the chunks completed after a gap to their end
+	completed map[int64]int64
+}
+
+func (t *chunkTracker) complete(start, end int64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.completed[start] = end
+	advanced := false
+	for {
+		end, ok := t.completed[t.marker.Offset]
+		if !ok {
+			break
+		}
+		delete(t.completed, t.marker.Offset)
+		t.marker.Offset = end
+		advanced = true
+	}
+	if !advanced {
+		return nil
+	}
+	return t.sync()
+}
+
+// sync makes sure all the data up to the marker offset is on disk, before recording the offset in the marker.
+func (t *chunkTracker) sync() error {
+	if err := t.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync file")
+	}
+	return writeProgressMarker(t.markerFile, &t.marker)
+}
+
+// rangeWriter writes the data of a single range request at its offset in the file. Blocks of zeroes are skipped to
+// keep the file sparse.
+type rangeWriter struct {
+	file     *os.File
+	offset   int64
+	progress func(int64)
+}
+
+func (w *rangeWriter) Write(p []byte) (int, error) {
+	if !isZeroBuffer(p) {
+		if _, err := w.file.WriteAt(p, w.offset); err != nil {
+			return 0, err
+		}
+	}
+	w.offset += int64(len(p))
+	w.progress(int64(len(p)))
+	return len(p), nil
+}
+
+// downloadParallel splits the endpoint data in chunks, and downloads the chunks with concurrent range requests
+// straight to their offset in the passed in file. Like a sequential download, the progress is recorded in a marker next
+// to the file, so a download interrupted by a restart of the importer is resumed.
+func (hs *HTTPDataSource) downloadParallel(fileName string) error {
+	markerFile := fileName + progressMarkerSuffix
+	total := int64(hs.resume.ContentLength)
+	start := int64(0)
+	if marker := readProgressMarker(markerFile); marker != nil && marker.resumeValidator == *hs.resume {
+		if info, err := os.Stat(fileName); err == nil && info.Size() >= marker.Offset {
+			klog.Infof("Resuming the download at offset %d of %d", marker.Offset, total)
+			start = marker.Offset
+		}
+	}
+	client, err := hs.newRangeClient()
+	if err != nil {
+		return err
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport.MaxIdleConnsPerHost = hs.parallelism
+	} else if client.Transport == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = hs.parallelism
+		client.Transport = transport
+	}
+
+	// All the data is fetched with range requests, the stream used to detect the format is not needed anymore
+	countingReader := hs.httpReader.(*util.CountingReader)
+	countingReader.Reader.Close()
+	atomic.StoreUint64(&countingReader.Current, uint64(start))
+	if hs.readers.progressReader != nil {
+		atomic.StoreUint64(&hs.readers.progressReader.Current, uint64(start))
+	}
+
+	out, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	defer out.Close()
+	// Anything written after the recorded offset may not have reached the disk, clear it before filling in the chunks
+	if err := out.Truncate(start); err != nil {
+		return errors.Wrapf(err, "unable to truncate file %q", fileName)
+	}
+	if err := out.Truncate(total); err != nil {
+		return errors.Wrapf(err, "unable to truncate file %q", fileName)
+	}
+	tracker := &chunkTracker{
+		file:       out,
+		markerFile: markerFile,
+		marker:     progressMarker{resumeValidator: *hs.resume, Offset: start},
+		completed:  make(map[int64]int64),
+	}
+	if err := tracker.sync(); err != nil {
+		return err
+	}
+
+	klog.Infof("Downloading %d bytes with %d concurrent range requests", total-start, hs.parallelism)
+	ctx, cancel := context.WithCancel(hs.ctx)
+	defer cancel()
+	chunks := make(chan int64)
+	errs := make(chan error, hs.parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < hs.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range chunks {
+				end := offset + parallelChunkSize
+				if end > total {
+					end = total
+				}
+				err := hs.downloadChunk(ctx, client, out, offset, end)
+				if err == nil {
+					err = tracker.complete(offset, end)
+				}
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+feed:
+	for offset := start; offset < total; offset += parallelChunkSize {
+		select {
+		case chunks <- offset:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	if tracker.marker.Offset != total {
+		return errors.Errorf("download incomplete, got %d of %d bytes", tracker.marker.Offset, total)
+	}
+	if err := out.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync file")
+	}
+	return errors.Wrap(os.Remove(markerFile), "unable to remove progress marker")
+}
+
+// downloadChunk fetches the endpoint data from start up to end, and writes it at the same offset in the file.
+func (hs *HTTPDataSource) downloadChunk(ctx context.Context, client *http.Client, out *os.File, start, end int64) error {
+	body, err := hs.requestRange(ctx, client, start, end-1)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	w := &rangeWriter{file: out, offset: start, progress: hs.addProgress}
+	n, err := io.Copy(w, io.LimitReader(body, end-start))
+	if err != nil {
+		return errors.Wrapf(err, "unable to download range %d-%d", start, end-1)
+	}
+	if n != end-start {
+		return errors.Errorf("range %d-%d incomplete, got %d bytes", start, end-1, n)
+	}
+	return nil
+}
+
+// addProgress reports data fetched by the concurrent range requests, to the idle check and to the progress.
+func (hs *HTTPDataSource) addProgress(n int64) {
+	atomic.AddUint64(&hs.httpReader.(*util.CountingReader).Current, uint64(n))
+	if hs.readers.progressReader != nil {
+		atomic.AddUint64(&hs.readers.progressReader.Current, uint64(n))
+	}
+}
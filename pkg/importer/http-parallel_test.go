@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("Parallel http download", func() {
+	const chunkSize = 256 * 1024
+	var (
+		ts               *httptest.Server
+		hs               *HTTPDataSource
+		tmpDir           string
+		data             []byte
+		rangesLock       sync.Mutex
+		ranges           []string
+		failRange        string
+		defaultChunkSize int64
+	)
+
+	BeforeEach(func() {
+		var err error
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		defaultChunkSize = parallelChunkSize
+		parallelChunkSize = chunkSize
+		tmpDir, err = ioutil.TempDir("", "parallel")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Random data around a hole of zeroes, not ending on a chunk boundary
+		data = make([]byte, 16*chunkSize+chunkSize/2)
+		rand.Read(data[:4*chunkSize])
+		rand.Read(data[12*chunkSize:])
+		ranges = nil
+		failRange = ""
+		modTime := time.Now()
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+				rangesLock.Lock()
+				ranges = append(ranges, rangeHeader)
+				rangesLock.Unlock()
+				if rangeHeader == failRange {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+			}
+			http.ServeContent(w, r, "disk.img", modTime, bytes.NewReader(data))
+		}))
+	})
+
+	AfterEach(func() {
+		if hs != nil {
+			hs.Close()
+			hs = nil
+		}
+		parallelChunkSize = defaultChunkSize
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	download := func(fileName string) error {
+		var err error
+		hs, err = NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt, "", 4)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := hs.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		_, err = hs.TransferFile(fileName)
+		return err
+	}
+
+	chunkRange := func(chunk int64) string {
+		end := (chunk+1)*chunkSize - 1
+		if end >= int64(len(data)) {
+			end = int64(len(data)) - 1
+		}
+		return "bytes=" + strconv.FormatInt(chunk*chunkSize, 10) + "-" + strconv.FormatInt(end, 10)
+	}
+
+	It("should download all chunks with concurrent range requests", func() {
+		fileName := filepath.Join(tmpDir, "disk.img")
+		Expect(download(fileName)).To(Succeed())
+		written, err := ioutil.ReadFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+		_, err = os.Stat(fileName + progressMarkerSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		expected := []string{}
+		for chunk := int64(0); chunk < 17; chunk++ {
+			expected = append(expected, chunkRange(chunk))
+		}
+		Expect(ranges).To(ConsistOf(expected))
+	})
+
+	It("should record the data downloaded without gaps when a chunk fails", func() {
+		fileName := filepath.Join(tmpDir, "disk.img")
+		failRange = chunkRange(6)
+		Expect(download(fileName)).ToNot(Succeed())
+		marker := readProgressMarker(fileName + progressMarkerSuffix)
+		Expect(marker).ToNot(BeNil())
+		Expect(marker.Offset).To(BeNumerically("<=", 6*chunkSize))
+		Expect(marker.Offset % chunkSize).To(BeZero())
+	})
+
+	It("should resume at the recorded offset", func() {
+		fileName := filepath.Join(tmpDir, "disk.img")
+		failRange = chunkRange(6)
+		Expect(download(fileName)).ToNot(Succeed())
+		offset := readProgressMarker(fileName + progressMarkerSuffix).Offset
+		hs.Close()
+		failRange = ""
+		ranges = nil
+
+		Expect(download(fileName)).To(Succeed())
+		written, err := ioutil.ReadFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+		// Handlers of requests canceled by the failed download may still record their range, only check what was
+		// requested and what was skipped
+		for chunk := int64(0); chunk < 17; chunk++ {
+			if chunk < offset/chunkSize {
+				Expect(ranges).ToNot(ContainElement(chunkRange(chunk)))
+			} else {
+				Expect(ranges).To(ContainElement(chunkRange(chunk)))
+			}
+		}
+	})
+})
@@ -0,0 +1,333 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	// progressMarkerSuffix is appended to the name of a file being downloaded to get the name of its progress marker
+	progressMarkerSuffix = ".progress"
+)
+
+// progressMarkerInterval is the amount of data written between two updates of the progress marker
+var progressMarkerInterval = int64(128 * 1024 * 1024)
+
+// resumeValidator identifies the version of the endpoint data. A download can only be resumed against the same version.
+type resumeValidator struct {
+	URL           string `json:"url"`
+	ETag          string `json:"etag,omitempty"`
+	LastModified  string `json:"lastModified,omitempty"`
+	ContentLength uint64 `json:"contentLength"`
+}
+
+// progressMarker records how much of the endpoint data has safely been written to a file.
+type progressMarker struct {
+	resumeValidator
+	Offset int64 `json:"offset"`
+}
+
+// getResumeValidator returns the validator of the endpoint data, or nil if the server doesn't allow resuming a download
+// of the data with a range request.
+func getResumeValidator(resp *http.Response, ep *url.URL, total uint64) *resumeValidator {
+	if resp.Header.Get("Accept-Ranges") != "bytes" || total == 0 || resp.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	etag := resp.Header.Get("ETag")
+	if strings.HasPrefix(etag, "W/") {
+		// Weak validators can't be used in If-Range
+		etag = ""
+	}
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+	return &resumeValidator{
+		URL:           ep.String(),
+		ETag:          etag,
+		LastModified:  lastModified,
+		ContentLength: total,
+	}
+}
+
+func readProgressMarker(markerFile string) *progressMarker {
+	data, err := ioutil.ReadFile(markerFile)
+	if err != nil {
+		return nil
+	}
+	marker := &progressMarker{}
+	if err := json.Unmarshal(data, marker); err != nil {
+		klog.Warningf("Ignoring invalid progress marker %s: %v", markerFile, err)
+		return nil
+	}
+	return marker
+}
+
+// writeProgressMarker replaces the marker atomically, so a crash never leaves a partially written marker behind.
+func writeProgressMarker(markerFile string, marker *progressMarker) error {
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal progress marker")
+	}
+	tmpFile := markerFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0644); err != nil {
+		return errors.Wrapf(err, "unable to write progress marker %s", tmpFile)
+	}
+	return errors.Wrap(os.Rename(tmpFile, markerFile), "unable to replace progress marker")
+}
+
+// isPartialDownload returns true if the file in the passed in directory belongs to a download that can be resumed.
+func isPartialDownload(dir string, name string) bool {
+	if strings.HasSuffix(name, progressMarkerSuffix) {
+		name = strings.TrimSuffix(name, progressMarkerSuffix)
+	}
+	if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+		return false
+	}
+	return readProgressMarker(filepath.Join(dir, name+progressMarkerSuffix)) != nil
+}
+
+// CleanDirExceptPartialDownloads removes all files from the passed in directory, except partial downloads and their
+// progress markers, so the download can be resumed.
+func CleanDirExceptPartialDownloads(dest string) error {
+	dir, err := ioutil.ReadDir(dest)
+	if err != nil {
+		klog.Errorf("Unable read directory to clean: %s, %v", dest, err)
+		return err
+	}
+	for _, d := range dir {
+		if !d.IsDir() && isPartialDownload(dest, d.Name()) {
+			klog.V(1).Infoln("keeping partial download: " + filepath.Join(dest, d.Name()))
+			continue
+		}
+		klog.V(1).Infoln("deleting file: " + filepath.Join(dest, d.Name()))
+		err = os.RemoveAll(filepath.Join(dest, d.Name()))
+		if err != nil {
+			klog.Errorf("Unable to delete file: %s, %v", filepath.Join(dest, d.Name()), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// progressMarkerWriter writes the endpoint data to a file, and periodically syncs the file and records the amount of
+// data written in the progress marker. Blocks of zeroes are skipped to keep the file sparse.
+type progressMarkerWriter struct {
+	file       *os.File
+	markerFile string
+	marker     progressMarker
+	offset     int64
+	unsynced   int64
+}
+
+func (w *progressMarkerWriter) Write(p []byte) (int, error) {
+	if !isZeroBuffer(p) {
+		if _, err := w.file.WriteAt(p, w.offset); err != nil {
+			return 0, err
+		}
+	}
+	w.offset += int64(len(p))
+	w.unsynced += int64(len(p))
+	if w.unsynced >= progressMarkerInterval {
+		if err := w.sync(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// sync makes sure all the data up to the current offset is on disk, before recording the offset in the marker.
+func (w *progressMarkerWriter) sync() error {
+	if err := w.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync file")
+	}
+	w.marker.Offset = w.offset
+	w.unsynced = 0
+	return writeProgressMarker(w.markerFile, &w.marker)
+}
+
+func isZeroBuffer(p []byte) bool {
+	for _, b := range p {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// canResume returns true if the endpoint data is written to a file as is, and the server allows resuming the download.
+func (hs *HTTPDataSource) canResume() bool {
+	return hs.resume != nil && !hs.readers.Archived && hs.checksum == ""
+}
+
+// downloadResumable writes the endpoint data as is to the passed in file, and records the progress in a marker next to
+// the file. A download interrupted by a restart of the importer is resumed with a range request, as long as the
+// endpoint data did not change in the meantime.
+func (hs *HTTPDataSource) downloadResumable(fileName string) error {
+	markerFile := fileName + progressMarkerSuffix
+	offset := int64(0)
+	if marker := readProgressMarker(markerFile); marker != nil && marker.resumeValidator == *hs.resume && marker.Offset > 0 {
+		if info, err := os.Stat(fileName); err == nil && info.Size() >= marker.Offset {
+			if err := hs.resumeAt(marker.Offset); err != nil {
+				klog.Warningf("Unable to resume the download at offset %d, starting over: %v", marker.Offset, err)
+			} else {
+				klog.Infof("Resuming the download at offset %d of %d", marker.Offset, marker.ContentLength)
+				offset = marker.Offset
+			}
+		}
+	}
+	reader := hs.readers.TopReader()
+	if offset > 0 {
+		// The header readers buffered the start of the data, read the remainder of the stream directly
+		reader = hs.readers.readers[0].rdr
+	}
+
+	out, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	defer out.Close()
+	// Anything written after the recorded offset may not have reached the disk
+	if err := out.Truncate(offset); err != nil {
+		return errors.Wrapf(err, "unable to truncate file %q", fileName)
+	}
+	w := &progressMarkerWriter{
+		file:       out,
+		markerFile: markerFile,
+		marker:     progressMarker{resumeValidator: *hs.resume},
+		offset:     offset,
+	}
+	if err := w.sync(); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return errors.Wrap(err, "unable to write to file")
+	}
+	if w.offset != int64(hs.resume.ContentLength) {
+		return errors.Errorf("download incomplete, got %d of %d bytes", w.offset, hs.resume.ContentLength)
+	}
+	// Extend the file over any trailing zeroes that were skipped
+	if err := out.Truncate(w.offset); err != nil {
+		return errors.Wrapf(err, "unable to truncate file %q", fileName)
+	}
+	if err := out.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync file")
+	}
+	return errors.Wrap(os.Remove(markerFile), "unable to remove progress marker")
+}
+
+// resumeAt replaces the endpoint stream with the remainder of the data starting at the passed in offset. The range is
+// only returned by the server if the data still matches the validator, otherwise the download has to start over.
+func (hs *HTTPDataSource) resumeAt(offset int64) error {
+	client, err := hs.newRangeClient()
+	if err != nil {
+		return err
+	}
+	body, err := hs.requestRange(hs.ctx, client, offset, -1)
+	if err != nil {
+		return err
+	}
+
+	// Keep reading through the counting reader, so the idle check and the progress keep working
+	countingReader := hs.httpReader.(*util.CountingReader)
+	countingReader.Reader.Close()
+	countingReader.Reader = body
+	if hs.readers.progressReader != nil {
+		atomic.StoreUint64(&hs.readers.progressReader.Current, uint64(offset))
+	}
+	return nil
+}
+
+// newRangeClient creates an http client for range requests to the endpoint.
+func (hs *HTTPDataSource) newRangeClient() (*http.Client, error) {
+	client, err := createHTTPClient(hs.customCA)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	accessKey, secKey := hs.credentials()
+	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
+		}
+		return nil
+	}
+	return client, nil
+}
+
+// requestRange requests the endpoint data from start to end inclusive, or to the end of the data if end is negative.
+// The range is requested with If-Range, so the server returns all of the data instead if it changed since the
+// validator was recorded, in which case an error is returned.
+func (hs *HTTPDataSource) requestRange(ctx context.Context, client *http.Client, start, end int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", hs.resume.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create HTTP request")
+	}
+	req = req.WithContext(ctx)
+	accessKey, secKey := hs.credentials()
+	if len(accessKey) > 0 && len(secKey) > 0 {
+		req.SetBasicAuth(accessKey, secKey)
+	}
+	if end < 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+	if hs.resume.ETag != "" {
+		req.Header.Set("If-Range", hs.resume.ETag)
+	} else {
+		req.Header.Set("If-Range", hs.resume.LastModified)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "HTTP request errored")
+	}
+	if resp.StatusCode != http.StatusPartialContent || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", start)) {
+		resp.Body.Close()
+		return nil, errors.Errorf("expected partial content starting at %d, got status %d", start, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func (hs *HTTPDataSource) credentials() (string, string) {
+	if hs.endpoint.User == nil {
+		return "", ""
+	}
+	secKey, _ := hs.endpoint.User.Password()
+	return hs.endpoint.User.Username(), secKey
+}
+
+func isBlockDevice(fileName string) bool {
+	info, err := os.Stat(fileName)
+	return err == nil && info.Mode()&os.ModeDevice != 0
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+var _ = Describe("Resumable http download", func() {
+	const mib = 1024 * 1024
+	var (
+		ts              *httptest.Server
+		hs              *HTTPDataSource
+		tmpDir          string
+		data            []byte
+		etag            string
+		ranges          []string
+		failAt          int
+		defaultInterval int64
+	)
+
+	BeforeEach(func() {
+		var err error
+		createNbdkitCurl = image.NewMockNbdkitCurl
+		defaultInterval = progressMarkerInterval
+		progressMarkerInterval = mib
+		tmpDir, err = ioutil.TempDir("", "resume")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Random data around a hole of zeroes
+		data = make([]byte, 4*mib)
+		rand.Read(data[:mib])
+		rand.Read(data[3*mib:])
+		etag = `"v1"`
+		ranges = nil
+		failAt = 0
+		modTime := time.Now()
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", etag)
+			if r.Method != http.MethodGet {
+				http.ServeContent(w, r, "disk.img", modTime, bytes.NewReader(data))
+				return
+			}
+			ranges = append(ranges, r.Header.Get("Range"))
+			if failAt > 0 && r.Header.Get("Range") == "" {
+				// Announce all the data but drop the connection half way
+				w.Header().Set("Accept-Ranges", "bytes")
+				w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+				w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+				w.Write(data[:failAt])
+				return
+			}
+			http.ServeContent(w, r, "disk.img", modTime, bytes.NewReader(data))
+		}))
+	})
+
+	AfterEach(func() {
+		if hs != nil {
+			hs.Close()
+			hs = nil
+		}
+		progressMarkerInterval = defaultInterval
+		ts.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	download := func(fileName string) error {
+		var err error
+		if hs != nil {
+			hs.Close()
+		}
+		hs, err = NewHTTPDataSource(ts.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt, "", 1)
+		Expect(err).NotTo(HaveOccurred())
+		phase, err := hs.Info()
+		Expect(err).NotTo(HaveOccurred())
+		// without parallelism nbdkit converts the raw image, the importer only downloads to scratch space itself
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(filepath.Base(fileName)).To(Equal(tempFile))
+		_, err = hs.Transfer(filepath.Dir(fileName))
+		return err
+	}
+
+	interruptedDownload := func(fileName string) int64 {
+		failAt = 3*mib + mib/2
+		Expect(download(fileName)).ToNot(Succeed())
+		marker := readProgressMarker(fileName + progressMarkerSuffix)
+		Expect(marker).ToNot(BeNil())
+		Expect(marker.ETag).To(Equal(etag))
+		Expect(marker.Offset).To(BeNumerically(">=", 3*mib))
+		Expect(marker.Offset).To(BeNumerically("<", failAt))
+		failAt = 0
+		return marker.Offset
+	}
+
+	expectData := func(fileName string) {
+		written, err := ioutil.ReadFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+		_, err = os.Stat(fileName + progressMarkerSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	}
+
+	It("should download a raw image and remove the progress marker", func() {
+		fileName := filepath.Join(tmpDir, tempFile)
+		Expect(download(fileName)).To(Succeed())
+		expectData(fileName)
+		Expect(ranges).To(Equal([]string{""}))
+	})
+
+	It("should resume an interrupted download with a range request", func() {
+		fileName := filepath.Join(tmpDir, tempFile)
+		offset := interruptedDownload(fileName)
+		Expect(download(fileName)).To(Succeed())
+		expectData(fileName)
+		Expect(ranges).To(Equal([]string{"", "", "bytes=" + strconv.FormatInt(offset, 10) + "-"}))
+	})
+
+	It("should start over when the endpoint data changed", func() {
+		fileName := filepath.Join(tmpDir, tempFile)
+		interruptedDownload(fileName)
+		etag = `"v2"`
+		Expect(download(fileName)).To(Succeed())
+		expectData(fileName)
+		Expect(ranges).To(Equal([]string{"", ""}))
+	})
+
+	It("should not resume when the server doesn't support range requests", func() {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("ETag", etag)
+		Expect(getResumeValidator(resp, nil, uint64(len(data)))).To(BeNil())
+		resp.Header.Set("Accept-Ranges", "bytes")
+		resp.Header.Set("ETag", `W/"weak"`)
+		Expect(getResumeValidator(resp, nil, uint64(len(data)))).To(BeNil())
+	})
+
+	It("should keep partial downloads when cleaning up", func() {
+		partial := filepath.Join(tmpDir, "disk.img")
+		Expect(ioutil.WriteFile(partial, data[:mib], 0644)).To(Succeed())
+		Expect(writeProgressMarker(partial+progressMarkerSuffix, &progressMarker{Offset: mib})).To(Succeed())
+		other := filepath.Join(tmpDir, tempFile)
+		Expect(ioutil.WriteFile(other, data[:mib], 0644)).To(Succeed())
+		orphan := filepath.Join(tmpDir, "orphan"+progressMarkerSuffix)
+		Expect(ioutil.WriteFile(orphan, []byte("{}"), 0644)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(tmpDir, "dir"), 0755)).To(Succeed())
+
+		Expect(CleanDirExceptPartialDownloads(tmpDir)).To(Succeed())
+		files, err := ioutil.ReadDir(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		Expect(names).To(ConsistOf("disk.img", "disk.img"+progressMarkerSuffix))
+	})
+})
@@ -170,13 +170,13 @@ func (is *ImageioDataSource) Close() error {
 }
 
 func (is *ImageioDataSource) pollProgress(reader *util.CountingReader, idleTime, pollInterval time.Duration) {
-	count := reader.Current
+	count := reader.Count()
 	lastUpdate := time.Now()
 	for {
-		if count < reader.Current {
+		if current := reader.Count(); count < current {
 			// Some progress was made, reset now.
 			lastUpdate = time.Now()
-			count = reader.Current
+			count = current
 		}
 
 		if time.Until(lastUpdate.Add(idleTime)).Nanoseconds() < 0 {
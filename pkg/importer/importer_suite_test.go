@@ -23,7 +23,7 @@ var sizeExceptions = map[string]struct{}{
 	".iso.xz": {},
 }
 
-var testfiles = []string{tinyCoreXzFilePath, tinyCoreGzFilePath, tinyCoreTarFilePath, archiveFilePath}
+var testfiles = []string{tinyCoreXzFilePath, tinyCoreGzFilePath, tinyCoreZstFilePath, tinyCoreBz2FilePath, tinyCoreTarFilePath, archiveFilePath}
 
 func TestImporter(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -47,18 +47,26 @@ type RegistryDataSource struct {
 	certDir     string
 	insecureTLS bool
 	imageDir    string
+	//The path of the disk image file within the container image, empty to use the single file under containerDiskImageDir.
+	diskPath string
 	//The discovered image file in scratch space.
 	url *url.URL
+	//The requirements the image has to meet before it is imported.
+	verification *ImageVerification
+	//The resolved digest of the image manifest.
+	imageDigest string
 }
 
 // NewRegistryDataSource creates a new instance of the Registry Data Source.
-func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecureTLS bool) *RegistryDataSource {
+func NewRegistryDataSource(endpoint, accessKey, secKey, certDir string, insecureTLS bool, diskPath string, verification *ImageVerification) *RegistryDataSource {
 	return &RegistryDataSource{
-		endpoint:    endpoint,
-		accessKey:   accessKey,
-		secKey:      secKey,
-		certDir:     certDir,
-		insecureTLS: insecureTLS,
+		endpoint:     endpoint,
+		accessKey:    accessKey,
+		secKey:       secKey,
+		certDir:      certDir,
+		insecureTLS:  insecureTLS,
+		diskPath:     diskPath,
+		verification: verification,
 	}
 }
 
@@ -77,17 +85,26 @@ func (rd *RegistryDataSource) Transfer(path string) (ProcessingPhase, error) {
 		//Path provided is invalid.
 		return ProcessingPhaseError, ErrInvalidPath
 	}
+	// Without a disk path the image file is the single file under the container disk directory
+	pathPrefix := containerDiskImageDir
 	rd.imageDir = filepath.Join(path, containerDiskImageDir)
+	if rd.diskPath != "" {
+		pathPrefix = rd.diskPath
+		rd.imageDir = filepath.Join(path, filepath.Dir(rd.diskPath))
+	}
 
 	klog.V(1).Infof("Copying registry image to scratch space.")
-	err = CopyRegistryImage(rd.endpoint, path, containerDiskImageDir, rd.accessKey, rd.secKey, rd.certDir, rd.insecureTLS)
+	rd.imageDigest, err = CopyVerifiedRegistryImage(rd.endpoint, path, pathPrefix, rd.accessKey, rd.secKey, rd.certDir, rd.insecureTLS, rd.verification)
 	if err != nil {
 		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
 	}
 
-	imageFile, err := getImageFileName(rd.imageDir)
-	if err != nil {
-		return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
+	imageFile := filepath.Base(rd.diskPath)
+	if rd.diskPath == "" {
+		imageFile, err = getImageFileName(rd.imageDir)
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
+		}
 	}
 
 	// imageFile and rd.imageDir are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
@@ -108,7 +125,10 @@ func (rd *RegistryDataSource) GetURL() *url.URL {
 
 // Close closes any readers or other open resources.
 func (rd *RegistryDataSource) Close() error {
-	// No-op, no open readers
+	// No open readers, only report the resolved digest to the controller
+	if rd.imageDigest != "" {
+		appendTerminationMessage("Image digest: " + rd.imageDigest)
+	}
 	return nil
 }
 
@@ -36,17 +36,32 @@ var _ = Describe("Registry data source", func() {
 	})
 
 	It("should return transfer after info is called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", true, "", nil)
 		result, err := ds.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
 	})
 
+	It("should locate the selected disk file after transfer", func() {
+		ds = NewRegistryDataSource("oci-archive:"+imageFile, "", "", "", true, "disk/cirros-0.3.4-x86_64-disk.img", nil)
+		result, err := ds.Transfer(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseConvert).To(Equal(result))
+		Expect(ds.GetURL().String()).To(Equal(filepath.Join(tmpDir, "disk/cirros-0.3.4-x86_64-disk.img")))
+	})
+
+	It("should fail the transfer if the selected disk file is not in the image", func() {
+		ds = NewRegistryDataSource("oci-archive:"+imageFile, "", "", "", true, "disk/missing.qcow2", nil)
+		result, err := ds.Transfer(tmpDir)
+		Expect(err).To(HaveOccurred())
+		Expect(ProcessingPhaseError).To(Equal(result))
+	})
+
 	table.DescribeTable("Transfer should ", func(ep, accKey, secKey, certDir, scratchPath string, insecureRegistry bool, wantErr bool) {
 		if scratchPath == "" {
 			scratchPath = tmpDir
 		}
-		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, insecureRegistry)
+		ds = NewRegistryDataSource(ep, accKey, secKey, certDir, insecureRegistry, "", nil)
 
 		// Need to pass in a real path if we don't want scratch space needed error.
 		result, err := ds.Transfer(scratchPath)
@@ -66,7 +81,7 @@ var _ = Describe("Registry data source", func() {
 	)
 
 	It("TransferFile should not be called", func() {
-		ds = NewRegistryDataSource("", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", true, "", nil)
 		result, err := ds.TransferFile("file")
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
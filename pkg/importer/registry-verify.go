@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// cosign stores the signatures of an image in the same repository, tagged by the image digest
+	cosignSignatureTagSuffix  = ".sig"
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureType       = "cosign container image signature"
+	// maxSignaturePayloadSize limits the size of a signature payload read from the registry
+	maxSignaturePayloadSize = 4 * 1024 * 1024
+
+	policyRequirementInsecureAcceptAnything = "insecureAcceptAnything"
+	policyRequirementReject                 = "reject"
+	policyRequirementSigstoreSigned         = "sigstoreSigned"
+)
+
+// ImageVerification holds the integrity requirements a registry image has to meet before it is imported.
+type ImageVerification struct {
+	// Digest is the manifest digest the image is required to have.
+	Digest string
+	// PublicKeyFile is the path of a PEM encoded cosign public key the image has to be signed with.
+	PublicKeyFile string
+	// PolicyFile is the path of a containers/image signature policy the image has to satisfy.
+	PolicyFile string
+}
+
+// signaturePolicy is the subset of the containers/image policy.json format supported by the importer
+type signaturePolicy struct {
+	Default    []policyRequirement                       `json:"default"`
+	Transports map[string]map[string][]policyRequirement `json:"transports"`
+}
+
+type policyRequirement struct {
+	Type    string `json:"type"`
+	KeyPath string `json:"keyPath,omitempty"`
+	KeyData []byte `json:"keyData,omitempty"`
+}
+
+// cosignPayload is the simple signing payload signed by cosign
+type cosignPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifyImageSource resolves the manifest digest of the image and checks it against the verification requirements.
+func verifyImageSource(ctx context.Context, sys *types.SystemContext, src types.ImageSource, verification *ImageVerification) (digest.Digest, error) {
+	rawManifest, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		klog.Errorf("Could not read image manifest: %v", err)
+		return "", errors.Wrap(err, "Could not read image manifest")
+	}
+	imageDigest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute image digest")
+	}
+	klog.Infof("Resolved image digest %s", imageDigest)
+	if verification == nil {
+		return imageDigest, nil
+	}
+
+	if verification.Digest != "" && imageDigest.String() != verification.Digest {
+		return "", errors.Errorf("Image digest %s does not match the required digest %s", imageDigest, verification.Digest)
+	}
+
+	if verification.PublicKeyFile != "" {
+		key, err := readPublicKeyFile(verification.PublicKeyFile)
+		if err != nil {
+			return "", err
+		}
+		if err := verifyCosignSignature(ctx, sys, src.Reference(), imageDigest, key); err != nil {
+			return "", err
+		}
+	} else if verification.PolicyFile != "" {
+		if err := verifySignaturePolicy(ctx, sys, src.Reference(), imageDigest, verification.PolicyFile); err != nil {
+			return "", err
+		}
+	}
+	return imageDigest, nil
+}
+
+func verifySignaturePolicy(ctx context.Context, sys *types.SystemContext, ref types.ImageReference, imageDigest digest.Digest, policyFile string) error {
+	policy, err := readSignaturePolicy(policyFile)
+	if err != nil {
+		return err
+	}
+	requirements := policy.requirementsForReference(ref)
+	if len(requirements) == 0 {
+		return errors.Errorf("Signature policy has no requirements for image %s", transportsName(ref))
+	}
+	// All the requirements have to be satisfied
+	for _, requirement := range requirements {
+		switch requirement.Type {
+		case policyRequirementInsecureAcceptAnything:
+			continue
+		case policyRequirementReject:
+			return errors.Errorf("Image %s is rejected by the signature policy", transportsName(ref))
+		case policyRequirementSigstoreSigned:
+			keyData := requirement.KeyData
+			if requirement.KeyPath != "" {
+				keyPath := requirement.KeyPath
+				if !filepath.IsAbs(keyPath) {
+					// Keys can be shipped in the same ConfigMap as the policy
+					keyPath = filepath.Join(filepath.Dir(policyFile), keyPath)
+				}
+				if keyData, err = ioutil.ReadFile(keyPath); err != nil {
+					return errors.Wrapf(err, "Could not read signature policy key %s", requirement.KeyPath)
+				}
+			}
+			key, err := parsePublicKey(keyData)
+			if err != nil {
+				return err
+			}
+			if err := verifyCosignSignature(ctx, sys, ref, imageDigest, key); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("Signature policy requirement type %q is not supported", requirement.Type)
+		}
+	}
+	return nil
+}
+
+func readSignaturePolicy(policyFile string) (*signaturePolicy, error) {
+	data, err := ioutil.ReadFile(policyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read signature policy")
+	}
+	policy := &signaturePolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, errors.Wrap(err, "Invalid signature policy")
+	}
+	return policy, nil
+}
+
+// requirementsForReference returns the requirements of the most specific scope matching the reference,
+// following the containers/image policy scope semantics.
+func (p *signaturePolicy) requirementsForReference(ref types.ImageReference) []policyRequirement {
+	if scopes, ok := p.Transports[ref.Transport().Name()]; ok {
+		if requirements, ok := scopes[ref.PolicyConfigurationIdentity()]; ok {
+			return requirements
+		}
+		for _, namespace := range ref.PolicyConfigurationNamespaces() {
+			if requirements, ok := scopes[namespace]; ok {
+				return requirements
+			}
+		}
+		if requirements, ok := scopes[""]; ok {
+			return requirements
+		}
+	}
+	return p.Default
+}
+
+// verifyCosignSignature looks up the cosign signatures of the image digest and succeeds if any of them is valid for the key.
+func verifyCosignSignature(ctx context.Context, sys *types.SystemContext, ref types.ImageReference, imageDigest digest.Digest, key crypto.PublicKey) error {
+	sigRef, err := getCosignSignatureReference(ref, imageDigest)
+	if err != nil {
+		return err
+	}
+	src, err := sigRef.NewImageSource(ctx, sys)
+	if err != nil {
+		return errors.Wrapf(err, "Image %s is not signed", transportsName(ref))
+	}
+	defer closeImage(src)
+
+	rawManifest, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Image %s is not signed", transportsName(ref))
+	}
+	sigManifest, err := manifest.OCI1FromManifest(rawManifest)
+	if err != nil {
+		return errors.Wrap(err, "Could not parse signature manifest")
+	}
+
+	for _, layer := range sigManifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := readSignaturePayload(ctx, src, manifest.BlobInfoFromOCI1Descriptor(layer))
+		if err != nil {
+			klog.Warningf("Skipping signature %s: %v", layer.Digest, err)
+			continue
+		}
+		if err := verifyCosignPayload(payload, signature, key, imageDigest); err != nil {
+			klog.Warningf("Skipping signature %s: %v", layer.Digest, err)
+			continue
+		}
+		klog.Infof("Image signature %s verified", layer.Digest)
+		return nil
+	}
+	return errors.Errorf("Image signature verification failed, no valid signature found for digest %s", imageDigest)
+}
+
+func getCosignSignatureReference(ref types.ImageReference, imageDigest digest.Digest) (types.ImageReference, error) {
+	named := ref.DockerReference()
+	if ref.Transport().Name() != docker.Transport.Name() || named == nil {
+		return nil, errors.Errorf("Signature verification is not supported for image %s", transportsName(ref))
+	}
+	tag := strings.Replace(imageDigest.String(), ":", "-", 1) + cosignSignatureTagSuffix
+	tagged, err := reference.WithTag(reference.TrimNamed(named), tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not build signature reference")
+	}
+	return docker.NewReference(tagged)
+}
+
+func readSignaturePayload(ctx context.Context, src types.ImageSource, blob types.BlobInfo) ([]byte, error) {
+	reader, _, err := src.GetBlob(ctx, blob, none.NoCache)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read signature payload")
+	}
+	defer reader.Close()
+	payload, err := ioutil.ReadAll(io.LimitReader(reader, maxSignaturePayloadSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read signature payload")
+	}
+	if blob.Digest != "" && digest.FromBytes(payload) != blob.Digest {
+		return nil, errors.Errorf("Signature payload does not match digest %s", blob.Digest)
+	}
+	return payload, nil
+}
+
+// verifyCosignPayload checks the signature of the payload and that the payload signs the image digest.
+func verifyCosignPayload(payload []byte, signature string, key crypto.PublicKey, imageDigest digest.Digest) error {
+	rawSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, "Invalid signature encoding")
+	}
+	if err := verifySignature(key, payload, rawSignature); err != nil {
+		return err
+	}
+	var simpleSigning cosignPayload
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return errors.Wrap(err, "Invalid signature payload")
+	}
+	if simpleSigning.Critical.Type != cosignSignatureType {
+		return errors.Errorf("Unexpected signature type %q", simpleSigning.Critical.Type)
+	}
+	if simpleSigning.Critical.Image.DockerManifestDigest != imageDigest.String() {
+		return errors.Errorf("Signature is for digest %s", simpleSigning.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) error {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var ecdsaSignature struct {
+			R, S *big.Int
+		}
+		if _, err := asn1.Unmarshal(signature, &ecdsaSignature); err != nil {
+			return errors.Wrap(err, "Invalid ECDSA signature")
+		}
+		hash := sha256.Sum256(payload)
+		if !ecdsa.Verify(k, hash[:], ecdsaSignature.R, ecdsaSignature.S) {
+			return errors.New("Invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		hash := sha256.Sum256(payload)
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature); err != nil {
+			return errors.Wrap(err, "Invalid RSA signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, signature) {
+			return errors.New("Invalid ed25519 signature")
+		}
+	default:
+		return errors.Errorf("Unsupported public key type %T", key)
+	}
+	return nil
+}
+
+func readPublicKeyFile(keyFile string) (crypto.PublicKey, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read signature public key")
+	}
+	return parsePublicKey(data)
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("Signature public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid signature public key")
+	}
+	return key, nil
+}
+
+func transportsName(ref types.ImageReference) string {
+	return ref.Transport().Name() + ":" + ref.StringWithinTransport()
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry image verification", func() {
+	var (
+		tmpDir      string
+		key         *ecdsa.PrivateKey
+		imageDigest = digest.Digest("sha256:" + strings.Repeat("a", 64))
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "verify")
+		Expect(err).NotTo(HaveOccurred())
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	publicKeyPEM := func() []byte {
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		Expect(err).NotTo(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	signPayload := func(signedDigest digest.Digest) ([]byte, string) {
+		payload := cosignPayload{}
+		payload.Critical.Identity.DockerReference = "quay.io/kubevirt/fedora"
+		payload.Critical.Image.DockerManifestDigest = signedDigest.String()
+		payload.Critical.Type = cosignSignatureType
+		data, err := json.Marshal(payload)
+		Expect(err).NotTo(HaveOccurred())
+		hash := sha256.Sum256(data)
+		r, sig, err := ecdsa.Sign(rand.Reader, key, hash[:])
+		Expect(err).NotTo(HaveOccurred())
+		signature, err := asn1.Marshal(struct {
+			R, S *big.Int
+		}{r, sig})
+		Expect(err).NotTo(HaveOccurred())
+		return data, base64.StdEncoding.EncodeToString(signature)
+	}
+
+	dockerReference := func(name string) types.ImageReference {
+		ref, err := docker.ParseReference(name)
+		Expect(err).NotTo(HaveOccurred())
+		return ref
+	}
+
+	writePolicy := func(policy *signaturePolicy) string {
+		data, err := json.Marshal(policy)
+		Expect(err).NotTo(HaveOccurred())
+		policyFile := filepath.Join(tmpDir, "policy.json")
+		Expect(ioutil.WriteFile(policyFile, data, 0644)).To(Succeed())
+		return policyFile
+	}
+
+	It("should accept a payload signed by the key for the image digest", func() {
+		parsedKey, err := parsePublicKey(publicKeyPEM())
+		Expect(err).NotTo(HaveOccurred())
+		payload, signature := signPayload(imageDigest)
+		Expect(verifyCosignPayload(payload, signature, parsedKey, imageDigest)).To(Succeed())
+	})
+
+	It("should reject a payload signed for another digest", func() {
+		parsedKey, err := parsePublicKey(publicKeyPEM())
+		Expect(err).NotTo(HaveOccurred())
+		payload, signature := signPayload(digest.Digest("sha256:" + strings.Repeat("b", 64)))
+		err = verifyCosignPayload(payload, signature, parsedKey, imageDigest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Signature is for digest"))
+	})
+
+	It("should reject a tampered payload", func() {
+		parsedKey, err := parsePublicKey(publicKeyPEM())
+		Expect(err).NotTo(HaveOccurred())
+		payload, signature := signPayload(imageDigest)
+		payload = append(payload, ' ')
+		err = verifyCosignPayload(payload, signature, parsedKey, imageDigest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid ECDSA signature"))
+	})
+
+	It("should reject a payload signed by another key", func() {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		payload, signature := signPayload(imageDigest)
+		Expect(verifyCosignPayload(payload, signature, otherKey.Public(), imageDigest)).ToNot(Succeed())
+	})
+
+	It("should fail to parse a key that is not PEM encoded", func() {
+		_, err := parsePublicKey([]byte("not a key"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should build the cosign signature reference from the image digest", func() {
+		sigRef, err := getCosignSignatureReference(dockerReference("//quay.io/kubevirt/fedora:latest"), imageDigest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sigRef.DockerReference().String()).To(Equal("quay.io/kubevirt/fedora:sha256-" + strings.Repeat("a", 64) + ".sig"))
+	})
+
+	table.DescribeTable("should select the policy requirements of the most specific scope", func(scopes map[string][]policyRequirement, expected string) {
+		policy := &signaturePolicy{
+			Default:    []policyRequirement{{Type: "default"}},
+			Transports: map[string]map[string][]policyRequirement{"docker": scopes},
+		}
+		requirements := policy.requirementsForReference(dockerReference("//quay.io/kubevirt/fedora:latest"))
+		Expect(requirements).To(HaveLen(1))
+		Expect(requirements[0].Type).To(Equal(expected))
+	},
+		table.Entry("image", map[string][]policyRequirement{
+			"quay.io/kubevirt/fedora:latest": {{Type: "image"}},
+			"quay.io/kubevirt/fedora":        {{Type: "repository"}},
+		}, "image"),
+		table.Entry("repository", map[string][]policyRequirement{
+			"quay.io/kubevirt/fedora": {{Type: "repository"}},
+			"quay.io":                 {{Type: "registry"}},
+		}, "repository"),
+		table.Entry("registry", map[string][]policyRequirement{
+			"quay.io": {{Type: "registry"}},
+			"":        {{Type: "transport"}},
+		}, "registry"),
+		table.Entry("transport", map[string][]policyRequirement{
+			"docker.io": {{Type: "registry"}},
+			"":          {{Type: "transport"}},
+		}, "transport"),
+		table.Entry("default", map[string][]policyRequirement{}, "default"),
+	)
+
+	table.DescribeTable("should evaluate the signature policy", func(requirements []policyRequirement, errorMessage string) {
+		policyFile := writePolicy(&signaturePolicy{Default: requirements})
+		err := verifySignaturePolicy(context.Background(), &types.SystemContext{}, dockerReference("//quay.io/kubevirt/fedora:latest"), imageDigest, policyFile)
+		if errorMessage == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(errorMessage))
+		}
+	},
+		table.Entry("accept anything", []policyRequirement{{Type: policyRequirementInsecureAcceptAnything}}, ""),
+		table.Entry("reject", []policyRequirement{{Type: policyRequirementInsecureAcceptAnything}, {Type: policyRequirementReject}}, "rejected by the signature policy"),
+		table.Entry("unsupported requirement", []policyRequirement{{Type: "signedBy"}}, "not supported"),
+		table.Entry("no requirements", []policyRequirement{}, "no requirements"),
+		table.Entry("missing key", []policyRequirement{{Type: policyRequirementSigstoreSigned, KeyPath: "missing.pub"}}, "Could not read signature policy key"),
+	)
+
+	It("should fail the copy when the image does not match the required digest", func() {
+		_, err := CopyVerifiedRegistryImage("oci-archive:"+imageFile, tmpDir, containerDiskImageDir, "", "", "", true, &ImageVerification{
+			Digest: imageDigest.String(),
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match the required digest"))
+	})
+
+	It("should return the image digest when no verification is required", func() {
+		resolved, err := CopyVerifiedRegistryImage("oci-archive:"+imageFile, tmpDir, containerDiskImageDir, "", "", "", true, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = digest.Parse(resolved)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not verify signatures of images outside of a registry", func() {
+		keyFile := filepath.Join(tmpDir, "cosign.pub")
+		Expect(ioutil.WriteFile(keyFile, publicKeyPEM(), 0644)).To(Succeed())
+		_, err := CopyVerifiedRegistryImage("oci-archive:"+imageFile, tmpDir, containerDiskImageDir, "", "", "", true, &ImageVerification{
+			PublicKeyFile: keyFile,
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Signature verification is not supported"))
+	})
+})
@@ -47,10 +47,12 @@ type S3DataSource struct {
 	readers *FormatReaders
 	// The image file in scratch space.
 	url *url.URL
+	// expected checksum of the object, empty if not verified
+	checksum string
 }
 
 // NewS3DataSource creates a new instance of the S3DataSource
-func NewS3DataSource(endpoint, accessKey, secKey string, certDir string) (*S3DataSource, error) {
+func NewS3DataSource(endpoint, accessKey, secKey string, certDir, checksum string) (*S3DataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("unable to parse endpoint %q", endpoint))
@@ -64,19 +66,20 @@ func NewS3DataSource(endpoint, accessKey, secKey string, certDir string) (*S3Dat
 		accessKey: accessKey,
 		secKey:    secKey,
 		s3Reader:  s3Reader,
+		checksum:  checksum,
 	}, nil
 }
 
 // Info is called to get initial information about the data.
 func (sd *S3DataSource) Info() (ProcessingPhase, error) {
 	var err error
-	sd.readers, err = NewFormatReaders(sd.s3Reader, uint64(0))
+	sd.readers, err = NewFormatReadersWithChecksum(sd.s3Reader, uint64(0), sd.checksum)
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
-	if !sd.readers.Convert {
-		// Downloading a raw file, we can write that directly to the target.
+	if !sd.readers.Convert || sd.readers.StreamConvert {
+		// Downloading a raw file or a qcow2 image converted while streaming, we can write that directly to the target.
 		return ProcessingPhaseTransferDataFile, nil
 	}
 
@@ -95,6 +98,9 @@ func (sd *S3DataSource) Transfer(path string) (ProcessingPhase, error) {
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := sd.readers.VerifyChecksum(); err != nil {
+		return ProcessingPhaseError, err
+	}
 	// If streaming succeeded, then parsing the file into URL will also succeed, no need to check error status
 	sd.url, _ = url.Parse(file)
 	return ProcessingPhaseConvert, nil
@@ -102,7 +108,7 @@ func (sd *S3DataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (sd *S3DataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	err := util.StreamDataToFile(sd.readers.TopReader(), fileName)
+	err := sd.readers.streamToFile(fileName)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -118,6 +124,9 @@ func (sd *S3DataSource) GetURL() *url.URL {
 func (sd *S3DataSource) Close() error {
 	var err error
 	if sd.readers != nil {
+		if checksum := sd.readers.Checksum(); checksum != "" {
+			appendTerminationMessage("Checksum: " + checksum)
+		}
 		err = sd.readers.Close()
 	}
 	return err
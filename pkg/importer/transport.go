@@ -99,9 +99,11 @@ func closeImage(src types.ImageSource) {
 	}
 }
 
+// hasPrefix returns true if the path is the pathPrefix file or is located under the pathPrefix directory
 func hasPrefix(path string, pathPrefix string) bool {
-	return strings.HasPrefix(path, pathPrefix) ||
-		strings.HasPrefix(path, "./"+pathPrefix)
+	path = strings.TrimPrefix(path, "./")
+	dir := strings.TrimSuffix(pathPrefix, "/")
+	return dir == "" || path == dir || strings.HasPrefix(path, dir+"/")
 }
 
 func isWhiteout(path string) bool {
@@ -169,7 +171,7 @@ func processLayer(ctx context.Context,
 	return found, nil
 }
 
-func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry, stopAtFirst bool) error {
+func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry, stopAtFirst bool, verification *ImageVerification) (string, error) {
 	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", url, pathPrefix, destDir)
 
 	ctx, cancel := commandTimeoutContext()
@@ -178,14 +180,21 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 
 	src, err := readImageSource(ctx, srcCtx, url)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer closeImage(src)
 
+	// The image source caches the manifest, so the verified manifest is the one the layers are read from
+	imageDigest, err := verifyImageSource(ctx, srcCtx, src, verification)
+	if err != nil {
+		klog.Errorf("Image verification failed: %v", err)
+		return "", err
+	}
+
 	imgCloser, err := image.FromSource(ctx, srcCtx, src)
 	if err != nil {
 		klog.Errorf("Error retrieving image: %v", err)
-		return errors.Wrap(err, "Error retrieving image")
+		return "", errors.Wrap(err, "Error retrieving image")
 	}
 	defer imgCloser.Close()
 
@@ -209,10 +218,10 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 
 	if !found {
 		klog.Errorf("Failed to find VM disk image file in the container image")
-		return errors.New("Failed to find VM disk image file in the container image")
+		return "", errors.New("Failed to find VM disk image file in the container image")
 	}
 
-	return nil
+	return imageDigest.String(), nil
 }
 
 // CopyRegistryImage download image from registry with docker image API. It will extract first file under the pathPrefix
@@ -224,7 +233,22 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 // certDir: directory public CA keys are stored for registry identity verification
 // insecureRegistry: boolean if true will allow insecure registries.
 func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry bool) error {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, true)
+	_, err := copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, true, nil)
+	return err
+}
+
+// CopyVerifiedRegistryImage download image from registry with docker image API after verifying its digest and signature.
+// It will extract first file under the pathPrefix and return the digest of the image manifest.
+// url: source registry url.
+// destDir: the scratch space destination.
+// pathPrefix: path to extract files from.
+// accessKey: accessKey for the registry described in url.
+// secKey: secretKey for the registry described in url.
+// certDir: directory public CA keys are stored for registry identity verification
+// insecureRegistry: boolean if true will allow insecure registries.
+// verification: requirements the image has to meet, nil to only resolve the digest.
+func CopyVerifiedRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry bool, verification *ImageVerification) (string, error) {
+	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, true, verification)
 }
 
 // CopyRegistryImageAll download image from registry with docker image API. It will extract all files under the pathPrefix
@@ -236,5 +260,6 @@ func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir stri
 // certDir: directory public CA keys are stored for registry identity verification
 // insecureRegistry: boolean if true will allow insecure registries.
 func CopyRegistryImageAll(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry bool) error {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, false)
+	_, err := copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, certDir, insecureRegistry, false, nil)
+	return err
 }
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -69,3 +70,18 @@ var _ = Describe("Registry Importer", func() {
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("Registry layer path matching", func() {
+	table.DescribeTable("hasPrefix should", func(path, pathPrefix string, expected bool) {
+		Expect(hasPrefix(path, pathPrefix)).To(Equal(expected))
+	},
+		table.Entry("match a file under the directory", "disk/data.qcow2", "disk", true),
+		table.Entry("match a file under the directory with a trailing slash", "disk/data.qcow2", "disk/", true),
+		table.Entry("match a relative tar entry", "./disk/data.qcow2", "disk", true),
+		table.Entry("match the exact file", "disk/data.qcow2", "disk/data.qcow2", true),
+		table.Entry("match anything with an empty prefix", "etc/hosts", "", true),
+		table.Entry("not match a file sharing the name prefix", "disk/data.qcow2.bak", "disk/data.qcow2", false),
+		table.Entry("not match a directory sharing the name prefix", "disks/data.qcow2", "disk", false),
+		table.Entry("not match another file in the directory", "disk/boot.qcow2", "disk/data.qcow2", false),
+	)
+})
@@ -23,12 +23,27 @@ type UploadDataSource struct {
 	readers *FormatReaders
 	// url to a file in scratch space.
 	url *url.URL
+	// expected checksum of the uploaded data, empty if not verified
+	checksum string
+	// file in scratch space the stream reads, if the upload was staged there before processing
+	stagedFile string
 }
 
 // NewUploadDataSource creates a new instance of an UploadDataSource
-func NewUploadDataSource(stream io.ReadCloser) *UploadDataSource {
+func NewUploadDataSource(stream io.ReadCloser, checksum string) *UploadDataSource {
 	return &UploadDataSource{
-		stream: stream,
+		stream:   stream,
+		checksum: checksum,
+	}
+}
+
+// NewStagedUploadDataSource creates an UploadDataSource reading an upload that was already saved to a file in scratch
+// space. An image that has to be converted is converted from that file, instead of being copied to scratch space again.
+func NewStagedUploadDataSource(stream io.ReadCloser, stagedFile, checksum string) *UploadDataSource {
+	return &UploadDataSource{
+		stream:     stream,
+		checksum:   checksum,
+		stagedFile: stagedFile,
 	}
 }
 
@@ -36,15 +51,20 @@ func NewUploadDataSource(stream io.ReadCloser) *UploadDataSource {
 func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 	var err error
 	// Hardcoded to only accept kubevirt content type.
-	ud.readers, err = NewFormatReaders(ud.stream, uint64(0))
+	ud.readers, err = NewFormatReadersWithChecksum(ud.stream, uint64(0), ud.checksum)
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
 	}
-	if !ud.readers.Convert {
-		// Uploading a raw file, we can write that directly to the target.
+	if !ud.readers.Convert || ud.readers.StreamConvert {
+		// Uploading a raw file or a qcow2 image converted while streaming, we can write that directly to the target.
 		return ProcessingPhaseTransferDataFile, nil
 	}
+	if ud.stagedFile != "" && !ud.readers.Archived && ud.checksum == "" {
+		// The staged file is the image itself, convert it where it is.
+		ud.url, _ = url.Parse(ud.stagedFile)
+		return ProcessingPhaseConvert, nil
+	}
 	return ProcessingPhaseTransferScratch, nil
 }
 
@@ -63,6 +83,9 @@ func (ud *UploadDataSource) Transfer(path string) (ProcessingPhase, error) {
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := ud.readers.VerifyChecksum(); err != nil {
+		return ProcessingPhaseError, err
+	}
 	// If we successfully wrote to the file, then the parse will succeed.
 	ud.url, _ = url.Parse(file)
 	return ProcessingPhaseConvert, nil
@@ -70,7 +93,7 @@ func (ud *UploadDataSource) Transfer(path string) (ProcessingPhase, error) {
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (ud *UploadDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	err := util.StreamDataToFile(ud.readers.TopReader(), fileName)
+	err := ud.readers.streamToFile(fileName)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -101,10 +124,11 @@ type AsyncUploadDataSource struct {
 }
 
 // NewAsyncUploadDataSource creates a new instance of an UploadDataSource
-func NewAsyncUploadDataSource(stream io.ReadCloser) *AsyncUploadDataSource {
+func NewAsyncUploadDataSource(stream io.ReadCloser, checksum string) *AsyncUploadDataSource {
 	return &AsyncUploadDataSource{
 		uploadDataSource: UploadDataSource{
-			stream: stream,
+			stream:   stream,
+			checksum: checksum,
 		},
 		ResumePhase: ProcessingPhaseInfo,
 	}
@@ -130,6 +154,9 @@ func (aud *AsyncUploadDataSource) Transfer(path string) (ProcessingPhase, error)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := aud.uploadDataSource.readers.VerifyChecksum(); err != nil {
+		return ProcessingPhaseError, err
+	}
 	// If we successfully wrote to the file, then the parse will succeed.
 	aud.uploadDataSource.url, _ = url.Parse(file)
 	aud.ResumePhase = ProcessingPhaseConvert
@@ -138,7 +165,7 @@ func (aud *AsyncUploadDataSource) Transfer(path string) (ProcessingPhase, error)
 
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (aud *AsyncUploadDataSource) TransferFile(fileName string) (ProcessingPhase, error) {
-	err := util.StreamDataToFile(aud.uploadDataSource.readers.TopReader(), fileName)
+	err := aud.uploadDataSource.readers.streamToFile(fileName)
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"encoding/binary"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -9,6 +10,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
 )
 
 var _ = Describe("Upload data source", func() {
@@ -37,27 +40,27 @@ var _ = Describe("Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file)
+		ud = NewUploadDataSource(file, "")
 		result, err := ud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
-	It("Info should return Transfer, when passed in a valid image", func() {
+	It("Info should return TransferDataFile, when passed in a valid qcow2 image", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file)
+		ud = NewUploadDataSource(file, "")
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
 	})
 
 	It("Info should return TransferData, when passed in a valid raw image", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file)
+		ud = NewUploadDataSource(file, "")
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -70,10 +73,10 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile)
+		ud = NewUploadDataSource(sourceFile, "")
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		result, err := ud.Transfer(scratchPath)
 		if !wantErr {
 			Expect(err).NotTo(HaveOccurred())
@@ -100,10 +103,10 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile)
+		ud = NewUploadDataSource(sourceFile, "")
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		err = sourceFile.Close()
 		Expect(err).NotTo(HaveOccurred())
 		result, err := ud.Transfer(tmpDir)
@@ -115,7 +118,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile)
+		ud = NewUploadDataSource(sourceFile, "")
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -124,11 +127,30 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseResize).To(Equal(result))
 	})
 
+	It("TransferFile should convert a qcow2 image to raw while streaming", func() {
+		hdr, err := image.ParseQcow2Header(cirrosData)
+		Expect(err).NotTo(HaveOccurred())
+		// Don't need to defer close, since ud.Close will close the reader
+		sourceFile, err := os.Open(cirrosFilePath)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(sourceFile, "")
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		fileName := filepath.Join(tmpDir, "file")
+		result, err = ud.TransferFile(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		fileStat, err := os.Stat(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileStat.Size()).To(Equal(int64(hdr.Size)))
+	})
+
 	It("TransferFile should fail on streaming error", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile)
+		ud = NewUploadDataSource(sourceFile, "")
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -137,8 +159,51 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
+	Context("with an upload staged in scratch space", func() {
+		var stagedFile string
+
+		// stageImage writes the header of a qcow2 image that cannot be converted while streaming, it claims to be
+		// encrypted
+		stageImage := func() *os.File {
+			data := make([]byte, 4096)
+			copy(data, []byte{'Q', 'F', 'I', 0xfb})
+			binary.BigEndian.PutUint32(data[4:], 2)
+			binary.BigEndian.PutUint64(data[24:], 1024*1024)
+			binary.BigEndian.PutUint32(data[32:], 1)
+			stagedFile = filepath.Join(tmpDir, "staged")
+			Expect(ioutil.WriteFile(stagedFile, data, 0600)).To(Succeed())
+			file, err := os.Open(stagedFile)
+			Expect(err).NotTo(HaveOccurred())
+			return file
+		}
+
+		It("Info should convert the staged file where it is", func() {
+			ud = NewStagedUploadDataSource(stageImage(), stagedFile, "")
+			result, err := ud.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ProcessingPhaseConvert))
+			Expect(ud.GetURL().String()).To(Equal(stagedFile))
+		})
+
+		It("Info should copy the staged file to scratch space to verify a checksum", func() {
+			ud = NewStagedUploadDataSource(stageImage(), stagedFile, "md5:00000000000000000000000000000000")
+			result, err := ud.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ProcessingPhaseTransferScratch))
+		})
+
+		It("should keep the staged file when the data processor cleans scratch space", func() {
+			ud = NewStagedUploadDataSource(stageImage(), stagedFile, "")
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "leftover"), []byte("data"), 0600)).To(Succeed())
+			dp := NewDataProcessor(ud, filepath.Join(tmpDir, "disk.img"), tmpDir, tmpDir, "", 0.055, false)
+			Expect(dp.cleanDir(tmpDir)).To(Succeed())
+			Expect(stagedFile).To(BeAnExistingFile())
+			Expect(filepath.Join(tmpDir, "leftover")).ToNot(BeAnExistingFile())
+		})
+	})
+
 	It("Close with nil stream should not fail", func() {
-		ud = NewUploadDataSource(nil)
+		ud = NewUploadDataSource(nil, "")
 		err := ud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -170,27 +235,27 @@ var _ = Describe("Async Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, "")
 		result, err := aud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
-	It("Info should return Transfer, when passed in a valid image", func() {
+	It("Info should return TransferDataFile, when passed in a valid qcow2 image", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, "")
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
 	})
 
 	It("Info should return TransferData, when passed in a valid raw image", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, "")
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -203,10 +268,10 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, "")
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		result, err := aud.Transfer(scratchPath)
 		if !wantErr {
 			Expect(err).NotTo(HaveOccurred())
@@ -224,10 +289,10 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, "")
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(nextPhase))
 		err = sourceFile.Close()
 		Expect(err).NotTo(HaveOccurred())
 		result, err := aud.Transfer(tmpDir)
@@ -239,7 +304,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, "")
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -253,7 +318,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, "")
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -263,7 +328,7 @@ var _ = Describe("Async Upload data source", func() {
 	})
 
 	It("Close with nil stream should not fail", func() {
-		aud = NewAsyncUploadDataSource(nil)
+		aud = NewAsyncUploadDataSource(nil, "")
 		err := aud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
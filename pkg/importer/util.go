@@ -46,6 +46,28 @@ func CleanDir(dest string) error {
 	return nil
 }
 
+// cleanDirExcept cleans the contents of a directory like CleanDir, but keeps the passed in file.
+func cleanDirExcept(dest, keep string) error {
+	dir, err := ioutil.ReadDir(dest)
+	if err != nil {
+		klog.Errorf("Unable read directory to clean: %s, %v", dest, err)
+		return err
+	}
+	for _, d := range dir {
+		if filepath.Join(dest, d.Name()) == filepath.Clean(keep) {
+			klog.V(1).Infoln("keeping file: " + keep)
+			continue
+		}
+		klog.V(1).Infoln("deleting file: " + filepath.Join(dest, d.Name()))
+		err = os.RemoveAll(filepath.Join(dest, d.Name()))
+		if err != nil {
+			klog.Errorf("Unable to delete file: %s, %v", filepath.Join(dest, d.Name()), err)
+			return err
+		}
+	}
+	return nil
+}
+
 // GetTerminationChannel returns a channel that listens for SIGTERM
 func GetTerminationChannel() <-chan os.Signal {
 	terminationChannel := make(chan os.Signal, 1)
@@ -55,3 +77,16 @@ func GetTerminationChannel() <-chan os.Signal {
 
 // newTerminationChannel should be overriden for unit tests
 var newTerminationChannel = GetTerminationChannel
+
+// appendTerminationMessage appends the passed in message to the existing termination message, so it can be reported
+// to the controller along with the completion message.
+func appendTerminationMessage(message string) {
+	existingbytes, _ := ioutil.ReadFile(common.PodTerminationMessageFile)
+	existing := string(existingbytes)
+	if existing != "" {
+		existing += "; "
+	}
+	if err := util.WriteTerminationMessage(existing + message); err != nil {
+		klog.Errorf("Unable to write termination message: %v", err)
+	}
+}
@@ -145,8 +145,8 @@ func deleteWorkerResources(l logr.Logger, c client.Client) error {
 	listTypes := []client.ObjectList{&corev1.PodList{}, &corev1.ServiceList{}}
 
 	for _, lt := range listTypes {
-		ls, err := labels.Parse(fmt.Sprintf("cdi.kubevirt.io in (%s, %s, %s)",
-			common.ImporterPodName, common.UploadServerCDILabel, common.ClonerSourcePodName))
+		ls, err := labels.Parse(fmt.Sprintf("cdi.kubevirt.io in (%s, %s, %s, %s)",
+			common.ImporterPodName, common.UploadServerCDILabel, common.DownloadServerCDILabel, common.ClonerSourcePodName))
 		if err != nil {
 			return err
 		}
@@ -70,6 +70,17 @@ func getAPIServerClusterPolicyRules() []rbacv1.PolicyRule {
 				"create",
 			},
 		},
+		{
+			APIGroups: []string{
+				"authentication.k8s.io",
+			},
+			Resources: []string{
+				"tokenreviews",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
@@ -92,6 +103,41 @@ func getAPIServerClusterPolicyRules() []rbacv1.PolicyRule {
 			},
 			Verbs: []string{
 				"get",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"secrets",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"snapshot.storage.k8s.io",
+			},
+			Resources: []string{
+				"volumesnapshots",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"events",
+			},
+			Verbs: []string{
+				"create",
+				"patch",
 			},
 		},
 		{
@@ -273,6 +319,8 @@ func createCDIValidatingWebhook(namespace string, c client.Client, l logr.Logger
 				Name: "cdi-validate.cdi.kubevirt.io",
 				Rules: []admissionregistrationv1.RuleWithOperations{{
 					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
 						admissionregistrationv1.Delete,
 					},
 					Rule: admissionregistrationv1.Rule{
@@ -401,7 +449,8 @@ func createDataVolumeMutatingWebhook(namespace string, c client.Client, l logr.L
 	failurePolicy := admissionregistrationv1.Fail
 	defaultTimeoutSeconds := int32(30)
 	reinvocationNever := admissionregistrationv1.NeverReinvocationPolicy
-	sideEffect := admissionregistrationv1.SideEffectClassNone
+	// clone authorizations are audited, except for dry runs
+	sideEffect := admissionregistrationv1.SideEffectClassNoneOnDryRun
 	whc := &admissionregistrationv1.MutatingWebhookConfiguration{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "admissionregistration.k8s.io/v1",
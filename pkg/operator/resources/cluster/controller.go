@@ -25,12 +25,21 @@ import (
 const (
 	controllerServiceAccountName = "cdi-sa"
 	controlerClusterRoleName     = "cdi"
+
+	// SecretTransferClusterRoleName is the role that allows the controller to transfer Secrets, it is bound to the
+	// controller service account in the namespaces Secrets are transferred from and to
+	SecretTransferClusterRoleName = "cdi.kubevirt.io:secret-transfer"
+	// RegistrySecretClusterRoleName is the role that allows the controller to read the registry Secret of a
+	// DataImportCron, it is bound to the controller service account in the namespace of the DataImportCron
+	RegistrySecretClusterRoleName = "cdi.kubevirt.io:registry-secret-reader"
 )
 
 func createControllerResources(args *FactoryArgs) []client.Object {
 	return []client.Object{
 		createControllerClusterRole(),
 		createControllerClusterRoleBinding(args.Namespace),
+		createSecretTransferClusterRole(),
+		createRegistrySecretClusterRole(),
 	}
 }
 
@@ -99,6 +108,19 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 				"delete",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"nodes",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
 		{
 			APIGroups: []string{
 				"networking.k8s.io",
@@ -129,6 +151,7 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"storageclasses",
+				"csidrivers",
 			},
 			Verbs: []string{
 				"get",
@@ -203,3 +226,46 @@ func getControllerClusterPolicyRules() []rbacv1.PolicyRule {
 func createControllerClusterRole() *rbacv1.ClusterRole {
 	return utils.ResourcesBuiler.CreateClusterRole(controlerClusterRoleName, getControllerClusterPolicyRules())
 }
+
+func createSecretTransferClusterRole() *rbacv1.ClusterRole {
+	return utils.ResourcesBuiler.CreateClusterRole(SecretTransferClusterRoleName, getSecretTransferPolicyRules())
+}
+
+func getSecretTransferPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"secrets",
+			},
+			Verbs: []string{
+				"get",
+				"create",
+				"update",
+				"delete",
+			},
+		},
+	}
+}
+
+func createRegistrySecretClusterRole() *rbacv1.ClusterRole {
+	return utils.ResourcesBuiler.CreateClusterRole(RegistrySecretClusterRoleName, getRegistrySecretPolicyRules())
+}
+
+func getRegistrySecretPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"secrets",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+	}
+}
@@ -110,5 +110,7 @@ func GetClusterRolePolicyRules() []rbacv1.PolicyRule {
 	result := getAPIServerClusterPolicyRules()
 	result = append(result, getControllerClusterPolicyRules()...)
 	result = append(result, getUploadProxyClusterPolicyRules()...)
+	result = append(result, getSecretTransferPolicyRules()...)
+	result = append(result, getRegistrySecretPolicyRules()...)
 	return result
 }
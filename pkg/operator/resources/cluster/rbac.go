@@ -79,6 +79,7 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"uploadtokenrequests",
+				"downloadtokenrequests",
 			},
 			Verbs: []string{
 				"*",
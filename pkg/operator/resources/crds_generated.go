@@ -1033,6 +1033,12 @@ spec:
                         description: StorageClass specifies how much space of a Filesystem volume should be reserved for safety. The keys are the storageClass and the values are the overhead. This value overrides the global value
                         type: object
                     type: object
+                  importParallelism:
+                    description: ImportParallelism is the number of concurrent range requests used to download http imports, when the server supports range requests. Defaults to 1, at most 16, it can be overridden per DataVolume with the cdi.kubevirt.io/storage.import.parallelism annotation
+                    format: int32
+                    maximum: 16
+                    minimum: 1
+                    type: integer
                   importProxy:
                     description: ImportProxy contains importer pod proxy configuration.
                     properties:
@@ -1082,6 +1088,31 @@ spec:
                   scratchSpaceStorageClass:
                     description: 'Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn''t exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space'
                     type: string
+                  storageCapabilities:
+                    description: StorageCapabilities are provisioner rules extending the storage capabilities CDI knows for well known provisioners. The first rule matching a storage class is used, before the rules built into CDI
+                    items:
+                      description: ProvisionerCapabilities defines the recommended claim properties of the storage classes of a provisioner
+                      properties:
+                        accessMode:
+                          description: AccessMode is the recommended access mode of the claims
+                          type: string
+                        parameters:
+                          additionalProperties:
+                            type: string
+                          description: Parameters the storage class must have for the rule to apply, e.g. the parameter picking the backend of a driver serving several kinds of storage
+                          type: object
+                        provisioner:
+                          description: Provisioner is the provisioner of the storage classes the rule applies to
+                          type: string
+                        volumeMode:
+                          description: VolumeMode is the recommended volume mode of the claims
+                          type: string
+                      required:
+                      - accessMode
+                      - provisioner
+                      - volumeMode
+                      type: object
+                    type: array
                   uploadProxyURLOverride:
                     description: Override the URL used when uploading to a DataVolume
                     type: string
@@ -2112,6 +2143,12 @@ spec:
                     description: StorageClass specifies how much space of a Filesystem volume should be reserved for safety. The keys are the storageClass and the values are the overhead. This value overrides the global value
                     type: object
                 type: object
+              importParallelism:
+                description: ImportParallelism is the number of concurrent range requests used to download http imports, when the server supports range requests. Defaults to 1, at most 16, it can be overridden per DataVolume with the cdi.kubevirt.io/storage.import.parallelism annotation
+                format: int32
+                maximum: 16
+                minimum: 1
+                type: integer
               importProxy:
                 description: ImportProxy contains importer pod proxy configuration.
                 properties:
@@ -2161,6 +2198,31 @@ spec:
               scratchSpaceStorageClass:
                 description: 'Override the storage class to used for scratch space during transfer operations. The scratch space storage class is determined in the following order: 1. value of scratchSpaceStorageClass, if that doesn''t exist, use the default storage class, if there is no default storage class, use the storage class of the DataVolume, if no storage class specified, use no storage class for scratch space'
                 type: string
+              storageCapabilities:
+                description: StorageCapabilities are provisioner rules extending the storage capabilities CDI knows for well known provisioners. The first rule matching a storage class is used, before the rules built into CDI
+                items:
+                  description: ProvisionerCapabilities defines the recommended claim properties of the storage classes of a provisioner
+                  properties:
+                    accessMode:
+                      description: AccessMode is the recommended access mode of the claims
+                      type: string
+                    parameters:
+                      additionalProperties:
+                        type: string
+                      description: Parameters the storage class must have for the rule to apply, e.g. the parameter picking the backend of a driver serving several kinds of storage
+                      type: object
+                    provisioner:
+                      description: Provisioner is the provisioner of the storage classes the rule applies to
+                      type: string
+                    volumeMode:
+                      description: VolumeMode is the recommended volume mode of the claims
+                      type: string
+                  required:
+                  - accessMode
+                  - provisioner
+                  - volumeMode
+                  type: object
+                type: array
               uploadProxyURLOverride:
                 description: Override the URL used when uploading to a DataVolume
                 type: string
@@ -2278,6 +2340,13 @@ spec:
               garbageCollect:
                 description: GarbageCollect specifies whether old PVCs should be cleaned up after a new PVC is imported. Options are currently "Never" and "Outdated", defaults to "Never".
                 type: string
+              importsMaxAge:
+                description: ImportsMaxAge specifies that imports newer than this duration are kept as well when garbage collection is "Outdated".
+                type: string
+              importsToKeep:
+                description: ImportsToKeep specifies the number of latest imports to keep when garbage collection is "Outdated", defaults to 3.
+                format: int32
+                type: integer
               managedDataSource:
                 description: ManagedDataSource specifies the name of the corresponding DataSource this cron will manage. DataSource has to be in the same namespace.
                 type: string
@@ -2293,9 +2362,25 @@ spec:
                       certConfigMap:
                         description: CertConfigMap provides a reference to the Registry certs
                         type: string
+                      digest:
+                        description: Digest is the manifest digest the image is required to have (e.g. sha256:...), the import fails on mismatch
+                        type: string
+                      diskPath:
+                        description: DiskPath is the path of the disk image file within the container image (e.g. disk/data.qcow2), defaults to the single file under disk/
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed to access the Registry source
                         type: string
+                      signatureVerification:
+                        description: SignatureVerification provides the policy the image signature has to satisfy before it is imported
+                        properties:
+                          policyConfigMap:
+                            description: PolicyConfigMap is the name of a ConfigMap holding a containers/image signature policy under the "policy.json" key
+                            type: string
+                          publicKeyConfigMap:
+                            description: PublicKeyConfigMap is the name of a ConfigMap holding a PEM encoded cosign public key under the "cosign.pub" key
+                            type: string
+                        type: object
                       url:
                         description: URL is the url of the Docker registry source
                         type: string
@@ -2305,10 +2390,580 @@ spec:
                 required:
                 - registry
                 type: object
+              template:
+                description: Template specifies template for the DVs to be created
+                properties:
+                  apiVersion:
+                    description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+                    type: string
+                  kind:
+                    description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+                    type: string
+                  metadata:
+                    description: ObjectMeta is metadata that all persisted resources must have, which includes all objects users must create.
+                    type: object
+                  spec:
+                    description: DataVolumeSpec defines the DataVolume type specification
+                    properties:
+                      checkpoints:
+                        description: Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.
+                        items:
+                          description: DataVolumeCheckpoint defines a stage in a warm migration.
+                          properties:
+                            current:
+                              description: Current is the identifier of the snapshot created for this checkpoint.
+                              type: string
+                            previous:
+                              description: Previous is the identifier of the snapshot from the previous checkpoint.
+                              type: string
+                          required:
+                          - current
+                          - previous
+                          type: object
+                        type: array
+                      contentType:
+                        description: 'DataVolumeContentType options: "kubevirt", "archive"'
+                        type: string
+                      finalCheckpoint:
+                        description: FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.
+                        type: boolean
+                      preallocation:
+                        description: Preallocation controls whether storage for DataVolumes should be allocated in advance.
+                        type: boolean
+                      priorityClassName:
+                        description: PriorityClassName for Importer, Cloner and Uploader pod
+                        type: string
+                      pvc:
+                        description: PVC is the PVC specification
+                        properties:
+                          accessModes:
+                            description: 'AccessModes contains the desired access modes the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1'
+                            items:
+                              type: string
+                            type: array
+                          dataSource:
+                            description: 'This field can be used to specify either: * An existing VolumeSnapshot object (snapshot.storage.k8s.io/VolumeSnapshot) * An existing PVC (PersistentVolumeClaim) * An existing custom resource that implements data population (Alpha) In order to use custom resource types that implement data population, the AnyVolumeDataSource feature gate must be enabled. If the provisioner or an external controller can support the specified data source, it will create a new volume based on the contents of the specified data source.'
+                            properties:
+                              apiGroup:
+                                description: APIGroup is the group for the resource being referenced. If APIGroup is not specified, the specified Kind must be in the core API group. For any other third-party types, APIGroup is required.
+                                type: string
+                              kind:
+                                description: Kind is the type of resource being referenced
+                                type: string
+                              name:
+                                description: Name is the name of resource being referenced
+                                type: string
+                            required:
+                            - kind
+                            - name
+                            type: object
+                          resources:
+                            description: 'Resources represents the minimum resources the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources'
+                            properties:
+                              limits:
+                                additionalProperties:
+                                  anyOf:
+                                  - type: integer
+                                  - type: string
+                                  description: |-
+                                    Quantity is a fixed-point representation of a number. It provides convenient marshaling/unmarshaling in JSON and YAML, in addition to String() and AsInt64() accessors.
+
+                                    The serialization format is:
+
+                                    <quantity>        ::= <signedNumber><suffix>
+                                      (Note that <suffix> may be empty, from the "" case in <decimalSI>.)
+                                    <digit>           ::= 0 | 1 | ... | 9 <digits>          ::= <digit> | <digit><digits> <number>          ::= <digits> | <digits>.<digits> | <digits>. | .<digits> <sign>            ::= "+" | "-" <signedNumber>    ::= <number> | <sign><number> <suffix>          ::= <binarySI> | <decimalExponent> | <decimalSI> <binarySI>        ::= Ki | Mi | Gi | Ti | Pi | Ei
+                                      (International System of units; See: http://physics.nist.gov/cuu/Units/binary.html)
+                                    <decimalSI>       ::= m | "" | k | M | G | T | P | E
+                                      (Note that 1024 = 1Ki but 1000 = 1k; I didn't choose the capitalization.)
+                                    <decimalExponent> ::= "e" <signedNumber> | "E" <signedNumber>
+
+                                    No matter which of the three exponent forms is used, no quantity may represent a number greater than 2^63-1 in magnitude, nor may it have more than 3 decimal places. Numbers larger or more precise will be capped or rounded up. (E.g.: 0.1m will rounded up to 1m.) This may be extended in the future if we require larger or smaller quantities.
+
+                                    When a Quantity is parsed from a string, it will remember the type of suffix it had, and will use the same type again when it is serialized.
+
+                                    Before serializing, Quantity will be put in "canonical form". This means that Exponent/suffix will be adjusted up or down (with a corresponding increase or decrease in Mantissa) such that:
+                                      a. No precision is lost
+                                      b. No fractional digits will be emitted
+                                      c. The exponent (or suffix) is as large as possible.
+                                    The sign will be omitted unless the number is negative.
+
+                                    Examples:
+                                      1.5 will be serialized as "1500m"
+                                      1.5Gi will be serialized as "1536Mi"
+
+                                    Note that the quantity will NEVER be internally represented by a floating point number. That is the whole point of this exercise.
+
+                                    Non-canonical values will still parse as long as they are well formed, but will be re-emitted in their canonical form. (So always use canonical form, or don't diff.)
+
+                                    This format is intended to make it difficult to use these numbers without writing some sort of special handling code in the hopes that that will cause implementors to also use a fixed point implementation.
+                                  pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                                  x-kubernetes-int-or-string: true
+                                description: 'Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/'
+                                type: object
+                              requests:
+                                additionalProperties:
+                                  anyOf:
+                                  - type: integer
+                                  - type: string
+                                  description: |-
+                                    Quantity is a fixed-point representation of a number. It provides convenient marshaling/unmarshaling in JSON and YAML, in addition to String() and AsInt64() accessors.
+
+                                    The serialization format is:
+
+                                    <quantity>        ::= <signedNumber><suffix>
+                                      (Note that <suffix> may be empty, from the "" case in <decimalSI>.)
+                                    <digit>           ::= 0 | 1 | ... | 9 <digits>          ::= <digit> | <digit><digits> <number>          ::= <digits> | <digits>.<digits> | <digits>. | .<digits> <sign>            ::= "+" | "-" <signedNumber>    ::= <number> | <sign><number> <suffix>          ::= <binarySI> | <decimalExponent> | <decimalSI> <binarySI>        ::= Ki | Mi | Gi | Ti | Pi | Ei
+                                      (International System of units; See: http://physics.nist.gov/cuu/Units/binary.html)
+                                    <decimalSI>       ::= m | "" | k | M | G | T | P | E
+                                      (Note that 1024 = 1Ki but 1000 = 1k; I didn't choose the capitalization.)
+                                    <decimalExponent> ::= "e" <signedNumber> | "E" <signedNumber>
+
+                                    No matter which of the three exponent forms is used, no quantity may represent a number greater than 2^63-1 in magnitude, nor may it have more than 3 decimal places. Numbers larger or more precise will be capped or rounded up. (E.g.: 0.1m will rounded up to 1m.) This may be extended in the future if we require larger or smaller quantities.
+
+                                    When a Quantity is parsed from a string, it will remember the type of suffix it had, and will use the same type again when it is serialized.
+
+                                    Before serializing, Quantity will be put in "canonical form". This means that Exponent/suffix will be adjusted up or down (with a corresponding increase or decrease in Mantissa) such that:
+                                      a. No precision is lost
+                                      b. No fractional digits will be emitted
+                                      c. The exponent (or suffix) is as large as possible.
+                                    The sign will be omitted unless the number is negative.
+
+                                    Examples:
+                                      1.5 will be serialized as "1500m"
+                                      1.5Gi will be serialized as "1536Mi"
+
+                                    Note that the quantity will NEVER be internally represented by a floating point number. That is the whole point of this exercise.
+
+                                    Non-canonical values will still parse as long as they are well formed, but will be re-emitted in their canonical form. (So always use canonical form, or don't diff.)
+
+                                    This format is intended to make it difficult to use these numbers without writing some sort of special handling code in the hopes that that will cause implementors to also use a fixed point implementation.
+                                  pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                                  x-kubernetes-int-or-string: true
+                                description: 'Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/'
+                                type: object
+                            type: object
+                          selector:
+                            description: A label query over volumes to consider for binding.
+                            properties:
+                              matchExpressions:
+                                description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                items:
+                                  description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                  properties:
+                                    key:
+                                      description: key is the label key that the selector applies to.
+                                      type: string
+                                    operator:
+                                      description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                      type: string
+                                    values:
+                                      description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                      items:
+                                        type: string
+                                      type: array
+                                  required:
+                                  - key
+                                  - operator
+                                  type: object
+                                type: array
+                              matchLabels:
+                                additionalProperties:
+                                  type: string
+                                description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                type: object
+                            type: object
+                          storageClassName:
+                            description: 'Name of the StorageClass required by the claim. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#class-1'
+                            type: string
+                          volumeMode:
+                            description: volumeMode defines what type of volume is required by the claim. Value of Filesystem is implied when not included in claim spec.
+                            type: string
+                          volumeName:
+                            description: VolumeName is the binding reference to the PersistentVolume backing this claim.
+                            type: string
+                        type: object
+                      source:
+                        description: Source is the src of the data for the requested DataVolume
+                        properties:
+                          blank:
+                            description: DataVolumeBlankImage provides the parameters to create a new raw blank image for the PVC
+                            type: object
+                          http:
+                            description: DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
+                                type: string
+                              checksum:
+                                description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                                type: string
+                              secretRef:
+                                description: SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded
+                                type: string
+                              url:
+                                description: URL is the URL of the http(s) endpoint
+                                type: string
+                            required:
+                            - url
+                            type: object
+                          imageio:
+                            description: DataVolumeSourceImageIO provides the parameters to create a Data Volume from an imageio source
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap provides a reference to the CA cert
+                                type: string
+                              diskId:
+                                description: DiskID provides id of a disk to be imported
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret reference needed to access the ovirt-engine
+                                type: string
+                              url:
+                                description: URL is the URL of the ovirt-engine
+                                type: string
+                            required:
+                            - diskId
+                            - url
+                            type: object
+                          pvc:
+                            description: DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC
+                            properties:
+                              name:
+                                description: The name of the source PVC
+                                type: string
+                              namespace:
+                                description: The namespace of the source PVC
+                                type: string
+                            required:
+                            - name
+                            - namespace
+                            type: object
+                          registry:
+                            description: DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap provides a reference to the Registry certs
+                                type: string
+                              digest:
+                                description: Digest is the manifest digest the image is required to have (e.g. sha256:...), the import fails on mismatch
+                                type: string
+                              diskPath:
+                                description: DiskPath is the path of the disk image file within the container image (e.g. disk/data.qcow2), defaults to the single file under disk/
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret reference needed to access the Registry source
+                                type: string
+                              signatureVerification:
+                                description: SignatureVerification provides the policy the image signature has to satisfy before it is imported
+                                properties:
+                                  policyConfigMap:
+                                    description: PolicyConfigMap is the name of a ConfigMap holding a containers/image signature policy under the "policy.json" key
+                                    type: string
+                                  publicKeyConfigMap:
+                                    description: PublicKeyConfigMap is the name of a ConfigMap holding a PEM encoded cosign public key under the "cosign.pub" key
+                                    type: string
+                                type: object
+                              url:
+                                description: URL is the url of the Docker registry source
+                                type: string
+                            required:
+                            - url
+                            type: object
+                          s3:
+                            description: DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source
+                            properties:
+                              certConfigMap:
+                                description: CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
+                                type: string
+                              checksum:
+                                description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                                type: string
+                              secretRef:
+                                description: SecretRef provides the secret reference needed to access the S3 source
+                                type: string
+                              url:
+                                description: URL is the url of the S3 source
+                                type: string
+                            required:
+                            - url
+                            type: object
+                          snapshot:
+                            description: DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot
+                            properties:
+                              name:
+                                description: The name of the source VolumeSnapshot
+                                type: string
+                              namespace:
+                                description: The namespace of the source VolumeSnapshot
+                                type: string
+                            required:
+                            - name
+                            - namespace
+                            type: object
+                          upload:
+                            description: DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source
+                            properties:
+                              checksum:
+                                description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                                type: string
+                            type: object
+                          vddk:
+                            description: DataVolumeSourceVDDK provides the parameters to create a Data Volume from a Vmware source
+                            properties:
+                              backingFile:
+                                description: BackingFile is the path to the virtual hard disk to migrate from vCenter/ESXi
+                                type: string
+                              secretRef:
+                                description: SecretRef provides a reference to a secret containing the username and password needed to access the vCenter or ESXi host
+                                type: string
+                              thumbprint:
+                                description: Thumbprint is the certificate thumbprint of the vCenter or ESXi host
+                                type: string
+                              url:
+                                description: URL is the URL of the vCenter or ESXi host with the VM to migrate
+                                type: string
+                              uuid:
+                                description: UUID is the UUID of the virtual machine that the backing file is attached to in vCenter/ESXi
+                                type: string
+                            type: object
+                        type: object
+                      sourceRef:
+                        description: SourceRef is an indirect reference to the source of data for the requested DataVolume
+                        properties:
+                          kind:
+                            description: The kind of the source reference, currently only "DataSource" is supported
+                            type: string
+                          name:
+                            description: The name of the source reference
+                            type: string
+                          namespace:
+                            description: The namespace of the source reference, defaults to the DataVolume namespace
+                            type: string
+                        required:
+                        - kind
+                        - name
+                        type: object
+                      storage:
+                        description: Storage is the requested storage specification
+                        properties:
+                          accessModes:
+                            description: 'AccessModes contains the desired access modes the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1'
+                            items:
+                              type: string
+                            type: array
+                          dataSource:
+                            description: 'This field can be used to specify either: * An existing VolumeSnapshot object (snapshot.storage.k8s.io/VolumeSnapshot) * An existing PVC (PersistentVolumeClaim) * An existing custom resource that implements data population (Alpha) In order to use custom resource types that implement data population, the AnyVolumeDataSource feature gate must be enabled. If the provisioner or an external controller can support the specified data source, it will create a new volume based on the contents of the specified data source.'
+                            properties:
+                              apiGroup:
+                                description: APIGroup is the group for the resource being referenced. If APIGroup is not specified, the specified Kind must be in the core API group. For any other third-party types, APIGroup is required.
+                                type: string
+                              kind:
+                                description: Kind is the type of resource being referenced
+                                type: string
+                              name:
+                                description: Name is the name of resource being referenced
+                                type: string
+                            required:
+                            - kind
+                            - name
+                            type: object
+                          resources:
+                            description: 'Resources represents the minimum resources the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources'
+                            properties:
+                              limits:
+                                additionalProperties:
+                                  anyOf:
+                                  - type: integer
+                                  - type: string
+                                  description: |-
+                                    Quantity is a fixed-point representation of a number. It provides convenient marshaling/unmarshaling in JSON and YAML, in addition to String() and AsInt64() accessors.
+
+                                    The serialization format is:
+
+                                    <quantity>        ::= <signedNumber><suffix>
+                                      (Note that <suffix> may be empty, from the "" case in <decimalSI>.)
+                                    <digit>           ::= 0 | 1 | ... | 9 <digits>          ::= <digit> | <digit><digits> <number>          ::= <digits> | <digits>.<digits> | <digits>. | .<digits> <sign>            ::= "+" | "-" <signedNumber>    ::= <number> | <sign><number> <suffix>          ::= <binarySI> | <decimalExponent> | <decimalSI> <binarySI>        ::= Ki | Mi | Gi | Ti | Pi | Ei
+                                      (International System of units; See: http://physics.nist.gov/cuu/Units/binary.html)
+                                    <decimalSI>       ::= m | "" | k | M | G | T | P | E
+                                      (Note that 1024 = 1Ki but 1000 = 1k; I didn't choose the capitalization.)
+                                    <decimalExponent> ::= "e" <signedNumber> | "E" <signedNumber>
+
+                                    No matter which of the three exponent forms is used, no quantity may represent a number greater than 2^63-1 in magnitude, nor may it have more than 3 decimal places. Numbers larger or more precise will be capped or rounded up. (E.g.: 0.1m will rounded up to 1m.) This may be extended in the future if we require larger or smaller quantities.
+
+                                    When a Quantity is parsed from a string, it will remember the type of suffix it had, and will use the same type again when it is serialized.
+
+                                    Before serializing, Quantity will be put in "canonical form". This means that Exponent/suffix will be adjusted up or down (with a corresponding increase or decrease in Mantissa) such that:
+                                      a. No precision is lost
+                                      b. No fractional digits will be emitted
+                                      c. The exponent (or suffix) is as large as possible.
+                                    The sign will be omitted unless the number is negative.
+
+                                    Examples:
+                                      1.5 will be serialized as "1500m"
+                                      1.5Gi will be serialized as "1536Mi"
+
+                                    Note that the quantity will NEVER be internally represented by a floating point number. That is the whole point of this exercise.
+
+                                    Non-canonical values will still parse as long as they are well formed, but will be re-emitted in their canonical form. (So always use canonical form, or don't diff.)
+
+                                    This format is intended to make it difficult to use these numbers without writing some sort of special handling code in the hopes that that will cause implementors to also use a fixed point implementation.
+                                  pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                                  x-kubernetes-int-or-string: true
+                                description: 'Limits describes the maximum amount of compute resources allowed. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/'
+                                type: object
+                              requests:
+                                additionalProperties:
+                                  anyOf:
+                                  - type: integer
+                                  - type: string
+                                  description: |-
+                                    Quantity is a fixed-point representation of a number. It provides convenient marshaling/unmarshaling in JSON and YAML, in addition to String() and AsInt64() accessors.
+
+                                    The serialization format is:
+
+                                    <quantity>        ::= <signedNumber><suffix>
+                                      (Note that <suffix> may be empty, from the "" case in <decimalSI>.)
+                                    <digit>           ::= 0 | 1 | ... | 9 <digits>          ::= <digit> | <digit><digits> <number>          ::= <digits> | <digits>.<digits> | <digits>. | .<digits> <sign>            ::= "+" | "-" <signedNumber>    ::= <number> | <sign><number> <suffix>          ::= <binarySI> | <decimalExponent> | <decimalSI> <binarySI>        ::= Ki | Mi | Gi | Ti | Pi | Ei
+                                      (International System of units; See: http://physics.nist.gov/cuu/Units/binary.html)
+                                    <decimalSI>       ::= m | "" | k | M | G | T | P | E
+                                      (Note that 1024 = 1Ki but 1000 = 1k; I didn't choose the capitalization.)
+                                    <decimalExponent> ::= "e" <signedNumber> | "E" <signedNumber>
+
+                                    No matter which of the three exponent forms is used, no quantity may represent a number greater than 2^63-1 in magnitude, nor may it have more than 3 decimal places. Numbers larger or more precise will be capped or rounded up. (E.g.: 0.1m will rounded up to 1m.) This may be extended in the future if we require larger or smaller quantities.
+
+                                    When a Quantity is parsed from a string, it will remember the type of suffix it had, and will use the same type again when it is serialized.
+
+                                    Before serializing, Quantity will be put in "canonical form". This means that Exponent/suffix will be adjusted up or down (with a corresponding increase or decrease in Mantissa) such that:
+                                      a. No precision is lost
+                                      b. No fractional digits will be emitted
+                                      c. The exponent (or suffix) is as large as possible.
+                                    The sign will be omitted unless the number is negative.
+
+                                    Examples:
+                                      1.5 will be serialized as "1500m"
+                                      1.5Gi will be serialized as "1536Mi"
+
+                                    Note that the quantity will NEVER be internally represented by a floating point number. That is the whole point of this exercise.
+
+                                    Non-canonical values will still parse as long as they are well formed, but will be re-emitted in their canonical form. (So always use canonical form, or don't diff.)
+
+                                    This format is intended to make it difficult to use these numbers without writing some sort of special handling code in the hopes that that will cause implementors to also use a fixed point implementation.
+                                  pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                                  x-kubernetes-int-or-string: true
+                                description: 'Requests describes the minimum amount of compute resources required. If Requests is omitted for a container, it defaults to Limits if that is explicitly specified, otherwise to an implementation-defined value. More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/'
+                                type: object
+                            type: object
+                          selector:
+                            description: A label query over volumes to consider for binding.
+                            properties:
+                              matchExpressions:
+                                description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                items:
+                                  description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                  properties:
+                                    key:
+                                      description: key is the label key that the selector applies to.
+                                      type: string
+                                    operator:
+                                      description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                      type: string
+                                    values:
+                                      description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                      items:
+                                        type: string
+                                      type: array
+                                  required:
+                                  - key
+                                  - operator
+                                  type: object
+                                type: array
+                              matchLabels:
+                                additionalProperties:
+                                  type: string
+                                description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                type: object
+                            type: object
+                          storageClassName:
+                            description: 'Name of the StorageClass required by the claim. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#class-1'
+                            type: string
+                          volumeMode:
+                            description: volumeMode defines what type of volume is required by the claim. Value of Filesystem is implied when not included in claim spec.
+                            type: string
+                          volumeName:
+                            description: VolumeName is the binding reference to the PersistentVolume backing this claim.
+                            type: string
+                        type: object
+                    type: object
+                  status:
+                    description: DataVolumeStatus contains the current status of the DataVolume
+                    properties:
+                      conditions:
+                        items:
+                          description: DataVolumeCondition represents the state of a data volume condition.
+                          properties:
+                            lastHeartbeatTime:
+                              description: Time is a wrapper around time.Time which supports correct marshaling to YAML and JSON.  Wrappers are provided for many of the factory methods that the time package offers.
+                              format: date-time
+                              type: string
+                            lastTransitionTime:
+                              description: Time is a wrapper around time.Time which supports correct marshaling to YAML and JSON.  Wrappers are provided for many of the factory methods that the time package offers.
+                              format: date-time
+                              type: string
+                            message:
+                              type: string
+                            reason:
+                              type: string
+                            status:
+                              type: string
+                            type:
+                              type: string
+                          required:
+                          - status
+                          - type
+                          type: object
+                        type: array
+                      phase:
+                        description: Phase is the current phase of the data volume
+                        type: string
+                      progress:
+                        type: string
+                      restartCount:
+                        description: RestartCount is the number of times the pod populating the DataVolume has restarted
+                        format: int32
+                        type: integer
+                      transfer:
+                        description: Transfer is the detailed progress reported by the pod populating the DataVolume
+                        properties:
+                          bytesTransferred:
+                            description: BytesTransferred is the number of bytes read from the source
+                            format: int64
+                            type: integer
+                          estimatedSecondsRemaining:
+                            description: EstimatedSecondsRemaining is the time needed to read the remaining bytes at the current throughput
+                            format: int64
+                            type: integer
+                          phase:
+                            description: Phase is the processing phase of the pod populating the DataVolume
+                            type: string
+                          throughput:
+                            description: Throughput is the number of bytes read from the source per second
+                            format: int64
+                            type: integer
+                          totalBytes:
+                            description: TotalBytes is the number of bytes to read from the source, if known
+                            format: int64
+                            type: integer
+                        type: object
+                    type: object
+                required:
+                - spec
+                type: object
             required:
             - managedDataSource
             - schedule
             - source
+            - template
             type: object
           status:
             description: DataImportCronStatus provides the most recently observed status of the DataImportCron
@@ -3033,6 +3688,9 @@ spec:
                       certConfigMap:
                         description: CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
                         type: string
+                      checksum:
+                        description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                        type: string
                       secretRef:
                         description: SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded
                         type: string
@@ -3080,9 +3738,25 @@ spec:
                       certConfigMap:
                         description: CertConfigMap provides a reference to the Registry certs
                         type: string
+                      digest:
+                        description: Digest is the manifest digest the image is required to have (e.g. sha256:...), the import fails on mismatch
+                        type: string
+                      diskPath:
+                        description: DiskPath is the path of the disk image file within the container image (e.g. disk/data.qcow2), defaults to the single file under disk/
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed to access the Registry source
                         type: string
+                      signatureVerification:
+                        description: SignatureVerification provides the policy the image signature has to satisfy before it is imported
+                        properties:
+                          policyConfigMap:
+                            description: PolicyConfigMap is the name of a ConfigMap holding a containers/image signature policy under the "policy.json" key
+                            type: string
+                          publicKeyConfigMap:
+                            description: PublicKeyConfigMap is the name of a ConfigMap holding a PEM encoded cosign public key under the "cosign.pub" key
+                            type: string
+                        type: object
                       url:
                         description: URL is the url of the Docker registry source
                         type: string
@@ -3095,6 +3769,9 @@ spec:
                       certConfigMap:
                         description: CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
                         type: string
+                      checksum:
+                        description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed to access the S3 source
                         type: string
@@ -3104,8 +3781,25 @@ spec:
                     required:
                     - url
                     type: object
+                  snapshot:
+                    description: DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot
+                    properties:
+                      name:
+                        description: The name of the source VolumeSnapshot
+                        type: string
+                      namespace:
+                        description: The namespace of the source VolumeSnapshot
+                        type: string
+                    required:
+                    - name
+                    - namespace
+                    type: object
                   upload:
                     description: DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source
+                    properties:
+                      checksum:
+                        description: Checksum is the expected checksum of the source data in the form <algorithm>:<hex value>, where algorithm is sha256, sha512 or md5
+                        type: string
                     type: object
                   vddk:
                     description: DataVolumeSourceVDDK provides the parameters to create a Data Volume from a Vmware source
@@ -3267,6 +3961,29 @@ spec:
                 description: RestartCount is the number of times the pod populating the DataVolume has restarted
                 format: int32
                 type: integer
+              transfer:
+                description: Transfer is the detailed progress reported by the pod populating the DataVolume
+                properties:
+                  bytesTransferred:
+                    description: BytesTransferred is the number of bytes read from the source
+                    format: int64
+                    type: integer
+                  estimatedSecondsRemaining:
+                    description: EstimatedSecondsRemaining is the time needed to read the remaining bytes at the current throughput
+                    format: int64
+                    type: integer
+                  phase:
+                    description: Phase is the processing phase of the pod populating the DataVolume
+                    type: string
+                  throughput:
+                    description: Throughput is the number of bytes read from the source per second
+                    format: int64
+                    type: integer
+                  totalBytes:
+                    description: TotalBytes is the number of bytes to read from the source, if known
+                    format: int64
+                    type: integer
+                type: object
             type: object
         required:
         - spec
@@ -3460,6 +4177,20 @@ spec:
                       type: string
                   type: object
                 type: array
+              probe:
+                description: Probe enables the detection of the storage capabilities by provisioning test PVCs
+                properties:
+                  interval:
+                    description: Interval is the time between the probes of the Periodic policy. Defaults to 24h
+                    type: string
+                  policy:
+                    description: Policy is when the storage class is probed, Never, Once or Periodic. Defaults to Never
+                    enum:
+                    - Never
+                    - Once
+                    - Periodic
+                    type: string
+                type: object
             type: object
           status:
             description: StorageProfileStatus provides the most recently observed status of the StorageProfile
@@ -3482,6 +4213,51 @@ spec:
                       type: string
                   type: object
                 type: array
+              probe:
+                description: Probe is the result of the last probe of the storage class
+                properties:
+                  claimPropertySets:
+                    description: ClaimPropertySets are the volume and access modes the storage class provisioned, ordered by preference
+                    items:
+                      description: ClaimPropertySet is a set of properties applicable to PVC
+                      properties:
+                        accessModes:
+                          description: 'AccessModes contains the desired access modes the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1'
+                          items:
+                            type: string
+                          type: array
+                        cloneStrategy:
+                          description: CloneStrategy defines the preferred method for performing a CDI clone
+                          type: string
+                        volumeMode:
+                          description: VolumeMode defines what type of volume is required by the claim. Value of Filesystem is implied when not included in claim spec.
+                          type: string
+                      type: object
+                    type: array
+                  clone:
+                    description: Clone is true if the storage class provisioned a PVC with a PVC data source
+                    type: boolean
+                  completionTime:
+                    description: CompletionTime is the time the probe completed
+                    format: date-time
+                    type: string
+                  expansion:
+                    description: Expansion is true if the storage class allows volume expansion
+                    type: boolean
+                  message:
+                    description: Message is a human readable description of the probe result
+                    type: string
+                  phase:
+                    description: Phase is the phase of the probe, InProgress, Succeeded or Failed
+                    type: string
+                  snapshotClass:
+                    description: SnapshotClass is the VolumeSnapshotClass of the storage class driver
+                    type: string
+                  startTime:
+                    description: StartTime is the time the probe started
+                    format: date-time
+                    type: string
+                type: object
               provisioner:
                 description: The Storage class provisioner plugin name
                 type: string
@@ -120,6 +120,15 @@ func createAPIServerDeployment(image, verbosity, pullPolicy string, infraNodePla
 			ReadOnly:  true,
 		},
 	}
+	// the webhooks export clone authorization metrics on the https port
+	container.Ports = []corev1.ContainerPort{
+		{
+			Name:          "metrics",
+			ContainerPort: 8443,
+			Protocol:      "TCP",
+		},
+	}
+	deployment.Spec.Template.ObjectMeta.Labels[common.PrometheusLabel] = ""
 	deployment.Spec.Template.Spec.Containers = []corev1.Container{container}
 	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
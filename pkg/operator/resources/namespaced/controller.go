@@ -129,6 +129,15 @@ func createControllerDeployment(controllerImage, importerImage, clonerImage, upl
 		SuccessThreshold:    1,
 		TimeoutSeconds:      1,
 	}
+	// the controllers export DataVolume metrics on the https port
+	container.Ports = []corev1.ContainerPort{
+		{
+			Name:          "metrics",
+			ContainerPort: 8443,
+			Protocol:      "TCP",
+		},
+	}
+	deployment.Spec.Template.ObjectMeta.Labels[common.PrometheusLabel] = ""
 	container.VolumeMounts = []corev1.VolumeMount{
 		{
 			Name:      "cdi-api-signing-key",
@@ -2,8 +2,12 @@
 package storagecapabilities
 
 import (
+	"fmt"
+
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 )
 
 // StorageCapabilities is a simple holder of storage capabilities (accessMode etc.)
@@ -55,6 +59,72 @@ func Get(sc *storagev1.StorageClass) (StorageCapabilities, bool) {
 	return capabilities, found
 }
 
+// GetWithRules finds and returns the StorageCapabilities for a given StorageClass, the first matching provisioner rule
+// takes precedence over the predefined StorageCapabilities
+func GetWithRules(sc *storagev1.StorageClass, rules []cdiv1.ProvisionerCapabilities) (StorageCapabilities, bool) {
+	for _, rule := range rules {
+		if ruleMatches(rule, sc) {
+			return StorageCapabilities{AccessMode: rule.AccessMode, VolumeMode: rule.VolumeMode}, true
+		}
+	}
+	return Get(sc)
+}
+
+// ValidateRules checks the provisioner rules can be used to find StorageCapabilities
+func ValidateRules(rules []cdiv1.ProvisionerCapabilities) error {
+	for i, rule := range rules {
+		if rule.Provisioner == "" {
+			return fmt.Errorf("rule %d: provisioner is missing", i)
+		}
+		for key := range rule.Parameters {
+			if key == "" {
+				return fmt.Errorf("rule %d: parameter name is missing", i)
+			}
+		}
+		switch rule.AccessMode {
+		case v1.ReadWriteOnce, v1.ReadOnlyMany, v1.ReadWriteMany:
+		default:
+			return fmt.Errorf("rule %d: unsupported accessMode %q", i, rule.AccessMode)
+		}
+		switch rule.VolumeMode {
+		case v1.PersistentVolumeBlock, v1.PersistentVolumeFilesystem:
+		default:
+			return fmt.Errorf("rule %d: unsupported volumeMode %q", i, rule.VolumeMode)
+		}
+		// a rule matching the same storage classes as a previous one would never be used
+		for j := 0; j < i; j++ {
+			if rules[j].Provisioner == rule.Provisioner && sameParameters(rules[j].Parameters, rule.Parameters) {
+				return fmt.Errorf("rule %d: duplicates rule %d", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+func ruleMatches(rule cdiv1.ProvisionerCapabilities, sc *storagev1.StorageClass) bool {
+	if rule.Provisioner != sc.Provisioner {
+		return false
+	}
+	for key, value := range rule.Parameters {
+		if val, found := sc.Parameters[key]; !found || val != value {
+			return false
+		}
+	}
+	return true
+}
+
+func sameParameters(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if val, found := b[key]; !found || val != value {
+			return false
+		}
+	}
+	return true
+}
+
 func storageProvisionerKey(sc *storagev1.StorageClass) string {
 	keyMapper, found := storageClassToProvisionerKeyMapper[sc.Provisioner]
 	if found {
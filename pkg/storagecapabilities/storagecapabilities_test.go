@@ -0,0 +1,99 @@
+package storagecapabilities
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("GetWithRules", func() {
+	sharedRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "csi.example.com",
+		Parameters:  map[string]string{"shared": "true"},
+		AccessMode:  v1.ReadWriteMany,
+		VolumeMode:  v1.PersistentVolumeFilesystem,
+	}
+	defaultRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "csi.example.com",
+		AccessMode:  v1.ReadWriteOnce,
+		VolumeMode:  v1.PersistentVolumeBlock,
+	}
+	rbdRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "rbd.csi.ceph.com",
+		AccessMode:  v1.ReadWriteOnce,
+		VolumeMode:  v1.PersistentVolumeFilesystem,
+	}
+
+	table.DescribeTable("should find the capabilities of a storage class", func(provisioner string, parameters map[string]string, rules []cdiv1.ProvisionerCapabilities, expectedFound bool, expected StorageCapabilities) {
+		capabilities, found := GetWithRules(createStorageClass(provisioner, parameters), rules)
+		Expect(found).To(Equal(expectedFound))
+		Expect(capabilities).To(Equal(expected))
+	},
+		table.Entry("from the first rule matching the parameters", "csi.example.com", map[string]string{"shared": "true", "other": "value"},
+			[]cdiv1.ProvisionerCapabilities{sharedRule, defaultRule}, true, StorageCapabilities{v1.ReadWriteMany, v1.PersistentVolumeFilesystem}),
+		table.Entry("from a rule without parameters", "csi.example.com", map[string]string{"shared": "false"},
+			[]cdiv1.ProvisionerCapabilities{sharedRule, defaultRule}, true, StorageCapabilities{v1.ReadWriteOnce, v1.PersistentVolumeBlock}),
+		table.Entry("from the first matching rule only", "csi.example.com", map[string]string{"shared": "true"},
+			[]cdiv1.ProvisionerCapabilities{defaultRule, sharedRule}, true, StorageCapabilities{v1.ReadWriteOnce, v1.PersistentVolumeBlock}),
+		table.Entry("from a rule over the predefined capabilities", "rbd.csi.ceph.com", nil,
+			[]cdiv1.ProvisionerCapabilities{rbdRule}, true, StorageCapabilities{v1.ReadWriteOnce, v1.PersistentVolumeFilesystem}),
+		table.Entry("from the predefined capabilities without a matching rule", "rbd.csi.ceph.com", nil,
+			[]cdiv1.ProvisionerCapabilities{defaultRule}, true, StorageCapabilities{v1.ReadWriteMany, v1.PersistentVolumeBlock}),
+		table.Entry("from the predefined capabilities by the provisioner key", "csi.trident.netapp.io", map[string]string{"backendType": "ontap-nas"},
+			nil, true, StorageCapabilities{v1.ReadWriteMany, v1.PersistentVolumeFilesystem}),
+		table.Entry("not for an unknown provisioner", "csi.unknown.com", nil,
+			[]cdiv1.ProvisionerCapabilities{defaultRule}, false, StorageCapabilities{}),
+		table.Entry("not if a rule parameter is missing", "csi.example.com", nil,
+			[]cdiv1.ProvisionerCapabilities{sharedRule}, false, StorageCapabilities{}),
+	)
+})
+
+var _ = Describe("ValidateRules", func() {
+	validRule := cdiv1.ProvisionerCapabilities{
+		Provisioner: "csi.example.com",
+		AccessMode:  v1.ReadWriteOnce,
+		VolumeMode:  v1.PersistentVolumeBlock,
+	}
+
+	It("should accept valid rules", func() {
+		Expect(ValidateRules(nil)).To(Succeed())
+		Expect(ValidateRules([]cdiv1.ProvisionerCapabilities{
+			{Provisioner: "csi.example.com", Parameters: map[string]string{"shared": "true"}, AccessMode: v1.ReadWriteMany, VolumeMode: v1.PersistentVolumeFilesystem},
+			validRule,
+			{Provisioner: "csi.other.com", AccessMode: v1.ReadOnlyMany, VolumeMode: v1.PersistentVolumeFilesystem},
+		})).To(Succeed())
+	})
+
+	table.DescribeTable("should reject an invalid rule", func(rule cdiv1.ProvisionerCapabilities, message string) {
+		err := ValidateRules([]cdiv1.ProvisionerCapabilities{validRule, rule})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(message))
+	},
+		table.Entry("without provisioner", cdiv1.ProvisionerCapabilities{AccessMode: v1.ReadWriteOnce, VolumeMode: v1.PersistentVolumeBlock},
+			"rule 1: provisioner is missing"),
+		table.Entry("with an empty parameter name", cdiv1.ProvisionerCapabilities{Provisioner: "csi.example.com", Parameters: map[string]string{"": "true"}, AccessMode: v1.ReadWriteOnce, VolumeMode: v1.PersistentVolumeBlock},
+			"rule 1: parameter name is missing"),
+		table.Entry("with an unknown access mode", cdiv1.ProvisionerCapabilities{Provisioner: "csi.other.com", AccessMode: "ReadWriteSometimes", VolumeMode: v1.PersistentVolumeBlock},
+			`rule 1: unsupported accessMode "ReadWriteSometimes"`),
+		table.Entry("without volume mode", cdiv1.ProvisionerCapabilities{Provisioner: "csi.other.com", AccessMode: v1.ReadWriteOnce},
+			`rule 1: unsupported volumeMode ""`),
+		table.Entry("shadowed by a previous rule", cdiv1.ProvisionerCapabilities{Provisioner: "csi.example.com", AccessMode: v1.ReadWriteMany, VolumeMode: v1.PersistentVolumeFilesystem},
+			"rule 1: duplicates rule 0"),
+	)
+})
+
+func createStorageClass(provisioner string, parameters map[string]string) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-sc",
+		},
+		Provisioner: provisioner,
+		Parameters:  parameters,
+	}
+}
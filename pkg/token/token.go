@@ -37,6 +37,9 @@ const (
 
 	// OperationUpload is the type of token for uploading to a PVC
 	OperationUpload Operation = "Upload"
+
+	// OperationDownload is the type of token for downloading from a PVC
+	OperationDownload Operation = "Download"
 )
 
 // Operation is the type of the token
@@ -44,6 +47,8 @@ type Operation string
 
 // Payload is the data inside our token
 type Payload struct {
+	// ID is the unique ID of the token, it is sent as the standard jti claim
+	ID        string                      `json:"-"`
 	Operation Operation                   `json:"opertation,omitempty"`
 	Name      string                      `json:"name,omitempty"`
 	Namespace string                      `json:"namespace,omitempty"`
@@ -90,6 +95,8 @@ func (v *validator) Validate(token string) (*Payload, error) {
 		return nil, err
 	}
 
+	private.ID = public.ID
+
 	return private, nil
 }
 
@@ -121,6 +128,7 @@ func (g *generator) Generate(payload *Payload) (string, error) {
 	return jwt.Signed(signer).
 		Claims(payload).
 		Claims(&jwt.Claims{
+			ID:        payload.ID,
 			Issuer:    g.issuer,
 			IssuedAt:  jwt.NewNumericDate(t),
 			NotBefore: jwt.NewNumericDate(t),
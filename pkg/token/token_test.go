@@ -77,6 +77,35 @@ var _ = Describe("Token test", func() {
 		Expect(reflect.DeepEqual(tokenData, payload)).To(BeTrue())
 	})
 
+	It("Token with ID", func() {
+		issuer := "issuer"
+
+		key, err := generateTestKey()
+		Expect(err).ToNot(HaveOccurred())
+
+		tokenData := &Payload{
+			ID:        "fakeid",
+			Operation: OperationClone,
+			Name:      "fakepvc",
+			Namespace: "fakenamespace",
+			Resource: metav1.GroupVersionResource{
+				Resource: "persistentvolumeclaims",
+			},
+		}
+
+		g := NewGenerator(issuer, key, 5*time.Minute)
+
+		signedToken, err := g.Generate(tokenData)
+		Expect(err).ToNot(HaveOccurred())
+
+		validator := NewValidator(issuer, &key.PublicKey, 0)
+
+		payload, err := validator.Validate(signedToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.ID).To(Equal("fakeid"))
+		Expect(reflect.DeepEqual(tokenData, payload)).To(BeTrue())
+	})
+
 	It("Token timeout", func() {
 		issuer := "issuer"
 
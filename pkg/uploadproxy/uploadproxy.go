@@ -72,8 +72,10 @@ type uploadProxyApp struct {
 	handler http.Handler
 
 	// test hooks
-	urlResolver    urlLookupFunc
-	uploadPossible uploadPossibleFunc
+	urlResolver         urlLookupFunc
+	uploadPossible      uploadPossibleFunc
+	downloadURLResolver urlLookupFunc
+	downloadPossible    uploadPossibleFunc
 }
 
 type clientCreator struct {
@@ -93,13 +95,15 @@ func NewUploadProxy(bindAddress string,
 	client kubernetes.Interface) (Server, error) {
 	var err error
 	app := &uploadProxyApp{
-		bindAddress:    bindAddress,
-		bindPort:       bindPort,
-		certWatcher:    certWatcher,
-		clientCreator:  &clientCreator{certFetcher: clientCertFetcher, bundleFetcher: serverCAFetcher},
-		client:         client,
-		urlResolver:    controller.GetUploadServerURL,
-		uploadPossible: controller.UploadPossibleForPVC,
+		bindAddress:         bindAddress,
+		bindPort:            bindPort,
+		certWatcher:         certWatcher,
+		clientCreator:       &clientCreator{certFetcher: clientCertFetcher, bundleFetcher: serverCAFetcher},
+		client:              client,
+		urlResolver:         controller.GetUploadServerURL,
+		uploadPossible:      controller.UploadPossibleForPVC,
+		downloadURLResolver: controller.GetDownloadServerURL,
+		downloadPossible:    controller.DownloadPossibleForPVC,
 	}
 	// retrieve RSA key used by apiserver to sign tokens
 	err = app.getSigningKey(apiServerPublicKey)
@@ -154,7 +158,24 @@ func (app *uploadProxyApp) initHandler() {
 	for _, path := range common.ProxyPaths {
 		mux.HandleFunc(path, app.handleUploadRequest)
 	}
-	app.handler = cors.AllowAll().Handler(mux)
+	for _, path := range common.DownloadPaths {
+		mux.HandleFunc(path, app.handleDownloadRequest)
+	}
+	// Same as cors.AllowAll, exposing the headers browsers need to resume uploads and to pick an encoding
+	app.handler = cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{common.UploadOffsetHeader, common.UploadLengthHeader, "Accept-Encoding"},
+		AllowCredentials: false,
+	}).Handler(mux)
 }
 
 func (app *uploadProxyApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -166,45 +187,75 @@ func (app *uploadProxyApp) handleHealthzRequest(w http.ResponseWriter, r *http.R
 }
 
 func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Request) {
+	tokenData, ok := app.validateToken(w, r, token.OperationUpload)
+	if !ok {
+		return
+	}
+
+	klog.V(1).Infof("Received valid token: pvc: %s, namespace: %s", tokenData.Name, tokenData.Namespace)
+
+	err := app.uploadReady(tokenData.Name, tokenData.Namespace)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// Return the error to the caller in the body.
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	app.proxyRequest(app.urlResolver(tokenData.Namespace, tokenData.Name, r.URL.Path), w, r)
+}
+
+func (app *uploadProxyApp) handleDownloadRequest(w http.ResponseWriter, r *http.Request) {
+	tokenData, ok := app.validateToken(w, r, token.OperationDownload)
+	if !ok {
+		return
+	}
+
+	klog.V(1).Infof("Received valid download token: pvc: %s, namespace: %s", tokenData.Name, tokenData.Namespace)
+
+	err := app.downloadReady(tokenData.Name, tokenData.Namespace)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// Return the error to the caller in the body.
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	app.proxyRequest(app.downloadURLResolver(tokenData.Namespace, tokenData.Name, r.URL.Path), w, r)
+}
+
+// validateToken checks the bearer token of the request is valid for operation on a PVC, writing the error status if not
+func (app *uploadProxyApp) validateToken(w http.ResponseWriter, r *http.Request, operation token.Operation) (*token.Payload, bool) {
 	tokenHeader := r.Header.Get("Authorization")
 	if tokenHeader == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	match := authHeaderMatcher.FindStringSubmatch(tokenHeader)
 	if len(match) != 2 {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	tokenData, err := app.tokenValidator.Validate(match[1])
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
-		return
+		return nil, false
 	}
 
-	if tokenData.Operation != token.OperationUpload ||
+	if tokenData.Operation != operation ||
 		tokenData.Name == "" ||
 		tokenData.Namespace == "" ||
 		tokenData.Resource.Resource != "persistentvolumeclaims" {
 		klog.Errorf("Bad token %+v", tokenData)
 		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	klog.V(1).Infof("Received valid token: pvc: %s, namespace: %s", tokenData.Name, tokenData.Namespace)
-
-	err = app.uploadReady(tokenData.Name, tokenData.Namespace)
-	if err != nil {
-		klog.Error(err)
-		w.WriteHeader(http.StatusServiceUnavailable)
-		// Return the error to the caller in the body.
-		w.Write([]byte(err.Error()))
-		return
+		return nil, false
 	}
 
-	app.proxyUploadRequest(tokenData.Namespace, tokenData.Name, w, r)
+	return tokenData, true
 }
 
 func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) error {
@@ -232,7 +283,28 @@ func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) error {
 	})
 }
 
-func (app *uploadProxyApp) proxyUploadRequest(namespace, pvc string, w http.ResponseWriter, r *http.Request) {
+func (app *uploadProxyApp) downloadReady(pvcName, pvcNamespace string) error {
+	return wait.PollImmediate(waitReadyImterval, waitReadyTime, func() (bool, error) {
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(context.TODO(), pvcName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, fmt.Errorf("rejecting Download Request for PVC %s that doesn't exist", pvcName)
+			}
+
+			return false, err
+		}
+
+		err = app.downloadPossible(pvc)
+		if err != nil {
+			return false, err
+		}
+
+		ready, _ := strconv.ParseBool(pvc.Annotations[controller.AnnDownloadPodReady])
+		return ready, nil
+	})
+}
+
+func (app *uploadProxyApp) proxyRequest(serverURL string, w http.ResponseWriter, r *http.Request) {
 	client, err := app.clientCreator.CreateClient()
 	if err != nil {
 		klog.Error("Error creating http client")
@@ -242,7 +314,7 @@ func (app *uploadProxyApp) proxyUploadRequest(namespace, pvc string, w http.Resp
 
 	p := &httputil.ReverseProxy{
 		Director: func(req *http.Request) {
-			req.URL, _ = url.Parse(app.urlResolver(namespace, pvc, r.URL.Path))
+			req.URL, _ = url.Parse(serverURL)
 			if _, ok := req.Header["User-Agent"]; !ok {
 				// explicitly disable User-Agent so it's not set to default value
 				req.Header.Set("User-Agent", "")
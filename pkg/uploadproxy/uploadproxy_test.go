@@ -48,6 +48,21 @@ func (*validateSuccess) Validate(string) (*token.Payload, error) {
 	}, nil
 }
 
+type validateDownloadSuccess struct{}
+
+func (*validateDownloadSuccess) Validate(string) (*token.Payload, error) {
+	return &token.Payload{
+		Operation: token.OperationDownload,
+		Name:      "testpvc",
+		Namespace: "default",
+		Resource: metav1.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "persistentvolumeclaims",
+		},
+	}, nil
+}
+
 func (*validateFailure) Validate(string) (*token.Payload, error) {
 	return nil, fmt.Errorf("Bad token")
 }
@@ -86,6 +101,16 @@ func newProxyRequest(path, authHeaderValue string) *http.Request {
 	return req
 }
 
+func newDownloadRequest(path, authHeaderValue string) *http.Request {
+	req, err := http.NewRequest("GET", path, nil)
+	Expect(err).ToNot(HaveOccurred())
+
+	if authHeaderValue != "" {
+		req.Header.Set("Authorization", authHeaderValue)
+	}
+	return req
+}
+
 func newProxyHeadRequest(authHeaderValue string) *http.Request {
 	req, err := http.NewRequest("HEAD", common.UploadPathSync, nil)
 	Expect(err).ToNot(HaveOccurred())
@@ -164,8 +189,9 @@ func setupProxyTests(handler http.HandlerFunc) *uploadProxyApp {
 			Name:      "testpvc",
 			Namespace: "default",
 			Annotations: map[string]string{
-				"cdi.kubevirt.io/storage.pod.phase": "Running",
-				"cdi.kubevirt.io/storage.pod.ready": "true",
+				"cdi.kubevirt.io/storage.pod.phase":         "Running",
+				"cdi.kubevirt.io/storage.pod.ready":         "true",
+				"cdi.kubevirt.io/storage.download.podReady": "true",
 			},
 		},
 	}
@@ -176,6 +202,7 @@ func setupProxyTests(handler http.HandlerFunc) *uploadProxyApp {
 	app.client = k8sfake.NewSimpleClientset(objects...)
 	app.tokenValidator = &validateSuccess{}
 	app.urlResolver = urlResolver
+	app.downloadURLResolver = urlResolver
 	app.clientCreator = &fakeClientCreator{client: server.Client()}
 
 	return app
@@ -231,6 +258,33 @@ var _ = Describe("submit request and check status", func() {
 		table.Entry("Test OK", http.StatusOK),
 		table.Entry("Test error", http.StatusInternalServerError),
 	)
+	table.DescribeTable("Test resumable upload methods are forwarded", func(method string) {
+		var receivedMethod, receivedOffset string
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedMethod = r.Method
+			receivedOffset = r.Header.Get(common.UploadOffsetHeader)
+			w.Header().Set(common.UploadOffsetHeader, "8")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req, err := http.NewRequest(method, common.UploadPathResumable, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		req.Header.Set(common.UploadOffsetHeader, "4")
+		req.Header.Set("Origin", "foo.bar.com")
+
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(receivedMethod).To(Equal(method))
+		Expect(receivedOffset).To(Equal("4"))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("8"))
+		Expect(rr.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring(common.UploadOffsetHeader))
+	},
+		table.Entry("PATCH", http.MethodPatch),
+		table.Entry("HEAD", http.MethodHead),
+	)
 	It("Invalid token", func() {
 		app := createApp()
 		app.tokenValidator = &validateFailure{}
@@ -271,3 +325,81 @@ var _ = Describe("submit request and check status", func() {
 		submitRequestAndCheckStatus(req, http.StatusOK, nil)
 	})
 })
+
+var _ = Describe("submit download request and check status", func() {
+	table.DescribeTable("Test download proxy status code", func(path string, statusCode int) {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+		app.tokenValidator = &validateDownloadSuccess{}
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req := newDownloadRequest(path, "Bearer valid")
+		submitRequestAndCheckStatus(req, statusCode, app)
+	},
+		table.Entry("Test raw OK", common.DownloadPathRaw, http.StatusOK),
+		table.Entry("Test raw error", common.DownloadPathRaw, http.StatusInternalServerError),
+		table.Entry("Test qcow2 OK", common.DownloadPathQcow2, http.StatusOK),
+		table.Entry("Test qcow2 error", common.DownloadPathQcow2, http.StatusInternalServerError),
+		table.Entry("Test tar OK", common.DownloadPathTar, http.StatusOK),
+		table.Entry("Test tar error", common.DownloadPathTar, http.StatusInternalServerError),
+	)
+
+	It("Should proxy to the path of the requested format", func() {
+		var requestedPath string
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.tokenValidator = &validateDownloadSuccess{}
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		resolver := app.downloadURLResolver
+		app.downloadURLResolver = func(namespace, pvc, path string) string {
+			requestedPath = path
+			return resolver(namespace, pvc, path)
+		}
+
+		req := newDownloadRequest(common.DownloadPathQcow2, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusOK, app)
+		Expect(requestedPath).To(Equal(common.DownloadPathQcow2))
+	})
+
+	It("Should reject an upload token", func() {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req := newDownloadRequest(common.DownloadPathRaw, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusBadRequest, app)
+	})
+
+	It("Should reject a download token for upload", func() {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.tokenValidator = &validateDownloadSuccess{}
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+
+		req := newProxyRequest(common.UploadPathSync, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusBadRequest, app)
+	})
+
+	It("Should return unavailable if the pvc is not a download source", func() {
+		app := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.tokenValidator = &validateDownloadSuccess{}
+		app.downloadPossible = func(*v1.PersistentVolumeClaim) error { return fmt.Errorf("NOPE") }
+
+		req := newDownloadRequest(common.DownloadPathRaw, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusServiceUnavailable, app)
+	})
+
+	It("Invalid token", func() {
+		app := createApp()
+		app.tokenValidator = &validateFailure{}
+
+		req := newDownloadRequest(common.DownloadPathRaw, "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusUnauthorized, app)
+	})
+})
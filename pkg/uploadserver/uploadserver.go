@@ -21,38 +21,81 @@ package uploadserver
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/extents"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 const (
 	healthzPort = 8080
 	healthzPath = "/healthz"
+
+	// resumableUploadFile is where the chunks of a resumable upload are assembled in the scratch space
+	resumableUploadFile = "resumable-upload"
+	// resumableUploadStateFile records the committed offset of a resumable upload in the scratch space
+	resumableUploadStateFile = "resumable-upload.json"
+
+	// acceptedEncodings is advertised in the Accept-Encoding header, uploads may be compressed on the wire with any
+	// of these and are decoded while streaming to the target
+	acceptedEncodings = "gzip, zstd"
 )
 
+var progress = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "upload_progress",
+		Help: "The upload progress in percentage",
+	},
+	[]string{"ownerUID"},
+)
+
+func init() {
+	if err := prometheus.Register(progress); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			// A counter for that metric has been registered before.
+			// Use the old counter from now on.
+			progress = are.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			klog.Errorf("Unable to create prometheus progress counter")
+		}
+	}
+}
+
+// errUnsupportedEncoding indicates the upload was sent with a Content-Encoding the upload server does not accept
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
 // UploadServer is the interface to uploadServerApp
 type UploadServer interface {
 	Run() error
 	PreallocationApplied() bool
+	Checksum() string
+	Cloned() bool
 }
 
 type uploadServerApp struct {
@@ -68,21 +111,76 @@ type uploadServerApp struct {
 	imageSize            string
 	filesystemOverhead   float64
 	preallocation        bool
+	checksum             string
+	ownerUID             string
+	scratchDir           string
 	mux                  *http.ServeMux
 	uploading            bool
 	processing           bool
 	done                 bool
 	preallocationApplied bool
+	cloned               bool
+	extentsClone         *extentsCloneSession
+	// extentsCloneAttempt is the attempt of the extents clone streams are accepted for, streams of the earlier attempts
+	// in extentsCloneAttempts are rejected
+	extentsCloneAttempt  string
+	extentsCloneAttempts map[string]bool
 	doneChan             chan struct{}
 	errChan              chan error
 	mutex                sync.Mutex
+	// resumableLength is the length of the resumable upload being processed, its state is removed with the staged data
+	resumableLength int64
 }
 
 type imageReadCloser func(*http.Request) (io.ReadCloser, error)
 
+// resumableUpload is the state of a resumable upload, persisted in the scratch space so an upload interrupted by a
+// dropped connection or a restart of the upload server continues at the committed offset
+type resumableUpload struct {
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length,omitempty"`
+	// Encoding is the Content-Encoding of the whole upload, the chunks are slices of the encoded data
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// extentsCloneSession tracks the concurrent streams of one attempt of an extents clone, the clone is done once all
+// streams finished. The session is the sink of its streams, writes fail once a new attempt cancelled it.
+type extentsCloneSession struct {
+	attempt     string
+	contentType string
+	streams     int
+	size        int64
+	sink        *extents.FileSink
+	started     int
+	active      int
+	finished    int
+	err         error
+	cancelled   int32
+	// stopped is closed once the session failed or was cancelled and none of its streams is active anymore
+	stopped chan struct{}
+}
+
+var errExtentsCloneCancelled = errors.New("superseded by a new clone attempt")
+
+func (s *extentsCloneSession) WriteAt(data []byte, offset int64) (int, error) {
+	if atomic.LoadInt32(&s.cancelled) != 0 {
+		return 0, errExtentsCloneCancelled
+	}
+	return s.sink.WriteAt(data, offset)
+}
+
+func (s *extentsCloneSession) ZeroRange(offset, length int64) error {
+	if atomic.LoadInt32(&s.cancelled) != 0 {
+		return errExtentsCloneCancelled
+	}
+	return s.sink.ZeroRange(offset, length)
+}
+
 // may be overridden in tests
 var uploadProcessorFunc = newUploadStreamProcessor
+var stagedUploadProcessorFunc = newStagedUploadStreamProcessor
 var uploadProcessorFuncAsync = newAsyncUploadStreamProcessor
+var resizeImageFunc = resizeImage
 
 func bodyReadCloser(r *http.Request) (io.ReadCloser, error) {
 	return r.Body, nil
@@ -113,7 +211,7 @@ func formReadCloser(r *http.Request) (io.ReadCloser, error) {
 }
 
 // NewUploadServer returns a new instance of uploadServerApp
-func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool) UploadServer {
+func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsCert, clientCert, clientName, imageSize string, filesystemOverhead float64, preallocation bool, checksum string) UploadServer {
 	server := &uploadServerApp{
 		bindAddress:        bindAddress,
 		bindPort:           bindPort,
@@ -124,7 +222,10 @@ func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsC
 		clientName:         clientName,
 		filesystemOverhead: filesystemOverhead,
 		preallocation:      preallocation,
+		checksum:           checksum,
 		imageSize:          imageSize,
+		ownerUID:           os.Getenv(common.OwnerUID),
+		scratchDir:         common.ScratchDataDir,
 		mux:                http.NewServeMux(),
 		uploading:          false,
 		done:               false,
@@ -144,6 +245,9 @@ func NewUploadServer(bindAddress string, bindPort int, destination, tlsKey, tlsC
 	for _, path := range common.AsyncUploadFormPaths {
 		server.mux.HandleFunc(path, server.uploadHandlerAsync(formReadCloser))
 	}
+	for _, path := range common.ResumableUploadPaths {
+		server.mux.HandleFunc(path, server.resumableUploadHandler)
+	}
 
 	return server
 }
@@ -193,6 +297,11 @@ func (app *uploadServerApp) Run() error {
 	select {
 	case err = <-app.errChan:
 		klog.Errorf("HTTP server returned error %s", err.Error())
+		if err == importer.ErrRequiresScratchSpace {
+			// Let the client get the response before the pod restarts with scratch space
+			healthzServer.Shutdown(context.Background())
+			uploadServer.Shutdown(context.Background())
+		}
 	case <-app.doneChan:
 		klog.Info("Shutting down http server after successful upload")
 		healthzServer.Shutdown(context.Background())
@@ -270,6 +379,10 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		return false
 	}
 
+	return app.validateShouldHandleUpload(w, r)
+}
+
+func (app *uploadServerApp) validateClient(w http.ResponseWriter, r *http.Request) bool {
 	if r.TLS != nil {
 		found := false
 
@@ -288,6 +401,14 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		klog.V(3).Infof("Handling HTTP connection")
 	}
 
+	return true
+}
+
+func (app *uploadServerApp) validateShouldHandleUpload(w http.ResponseWriter, r *http.Request) bool {
+	if !app.validateClient(w, r) {
+		return false
+	}
+
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
 
@@ -310,12 +431,13 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 
 func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Encoding", acceptedEncodings)
 		if r.Method == "HEAD" {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
 
-		if !app.validateShouldHandleRequest(w, r) {
+		if !validateContentEncoding(w, r) || !app.validateShouldHandleRequest(w, r) {
 			return
 		}
 
@@ -328,13 +450,21 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 			w.WriteHeader(http.StatusBadRequest)
 		}
 
-		processor, err := uploadProcessorFuncAsync(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType)
+		readCloser, ok := app.decodeContent(w, r, app.newProgressReader(readCloser, 0, r.ContentLength))
+		if !ok {
+			return
+		}
+		app.setCloned(cdiContentType)
+
+		processor, err := uploadProcessorFuncAsync(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType, app.checksum)
 
 		app.mutex.Lock()
 
 		if err != nil {
 			klog.Errorf("Saving stream failed: %s", err)
-			if _, ok := err.(importer.ValidationSizeError); ok {
+			if errors.Cause(err) == importer.ErrRequiresScratchSpace {
+				app.requestScratchSpace(w)
+			} else if isValidationError(err) {
 				w.WriteHeader(http.StatusBadRequest)
 			} else {
 				w.WriteHeader(http.StatusInternalServerError)
@@ -370,7 +500,13 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 
 func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !app.validateShouldHandleRequest(w, r) {
+		if isExtentsClone(r.Header.Get(common.UploadContentTypeHeader)) {
+			app.extentsCloneHandler(w, r)
+			return
+		}
+
+		w.Header().Set("Accept-Encoding", acceptedEncodings)
+		if !validateContentEncoding(w, r) || !app.validateShouldHandleRequest(w, r) {
 			return
 		}
 
@@ -383,14 +519,27 @@ func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc
 			w.WriteHeader(http.StatusBadRequest)
 		}
 
-		app.preallocationApplied, err = uploadProcessorFunc(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType)
+		readCloser, ok := app.decodeContent(w, r, app.newProgressReader(readCloser, 0, r.ContentLength))
+		if !ok {
+			return
+		}
+		app.setCloned(cdiContentType)
+
+		app.preallocationApplied, err = uploadProcessorFunc(readCloser, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, cdiContentType, app.checksum)
 
 		app.mutex.Lock()
 		defer app.mutex.Unlock()
 
 		if err != nil {
 			klog.Errorf("Saving stream failed: %s", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			if errors.Cause(err) == importer.ErrRequiresScratchSpace {
+				app.requestScratchSpace(w)
+			} else if _, ok := errors.Cause(err).(importer.ChecksumValidationError); ok {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("Saving stream failed: %s", err.Error())))
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
 			app.uploading = false
 			return
 		}
@@ -404,27 +553,603 @@ func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc
 	}
 }
 
+// extentsCloneHandler applies one of the concurrent streams of an extents clone to the target
+func (app *uploadServerApp) extentsCloneHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	contentType := r.Header.Get(common.UploadContentTypeHeader)
+	streams, err := strconv.Atoi(r.Header.Get(common.CloneStreamsHeader))
+	if err != nil || streams < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header", common.CloneStreamsHeader)))
+		return
+	}
+	size, err := strconv.ParseInt(r.Header.Get(common.CloneSizeHeader), 10, 64)
+	if err != nil || size < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header", common.CloneSizeHeader)))
+		return
+	}
+
+	attempt := r.Header.Get(common.CloneAttemptHeader)
+	if attempt == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Missing %s header", common.CloneAttemptHeader)))
+		return
+	}
+
+	session, ok := app.joinExtentsClone(r.Context(), w, attempt, contentType, streams, size)
+	if !ok {
+		return
+	}
+
+	written, err := extents.ReadFrames(newSnappyReadCloser(r.Body), session)
+	if err = app.finishExtentsStream(session, err); err != nil {
+		klog.Errorf("Saving extents failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("Saving extents failed: %s", err.Error())))
+		return
+	}
+
+	klog.Infof("Wrote %d bytes of extents to %s", written, app.destination)
+}
+
+// joinExtentsClone adds a stream to the running extents clone, the first stream opens the target. A restarted cloner
+// sends all streams again with a new attempt ID, the first of them cancels the earlier attempt and waits for its streams
+// to stop, streams of earlier attempts are rejected from then on.
+func (app *uploadServerApp) joinExtentsClone(ctx context.Context, w http.ResponseWriter, attempt, contentType string, streams int, size int64) (*extentsCloneSession, bool) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.done {
+		klog.Warning("Got clone stream after already done")
+		w.WriteHeader(http.StatusConflict)
+		return nil, false
+	}
+
+	if attempt != app.extentsCloneAttempt {
+		if app.extentsCloneAttempts[attempt] {
+			klog.Warningf("Got clone stream of stale attempt %s", attempt)
+			w.WriteHeader(http.StatusConflict)
+			return nil, false
+		}
+		klog.Infof("Starting clone attempt %s", attempt)
+		if app.extentsCloneAttempts == nil {
+			app.extentsCloneAttempts = map[string]bool{}
+		}
+		app.extentsCloneAttempts[attempt] = true
+		app.extentsCloneAttempt = attempt
+		if app.extentsClone != nil {
+			app.cancelExtentsClone(app.extentsClone)
+		}
+	}
+
+	for app.extentsClone != nil && app.extentsClone.attempt != attempt {
+		stopped := app.extentsClone.stopped
+		app.mutex.Unlock()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+		}
+		app.mutex.Lock()
+		if ctx.Err() != nil || attempt != app.extentsCloneAttempt {
+			klog.Warningf("Clone attempt %s was superseded while waiting for the previous one", attempt)
+			w.WriteHeader(http.StatusConflict)
+			return nil, false
+		}
+	}
+
+	session := app.extentsClone
+	if session == nil {
+		if app.uploading || app.processing {
+			klog.Warning("Got clone stream during upload")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return nil, false
+		}
+
+		sink, err := app.newExtentsSink(size)
+		if err != nil {
+			klog.Errorf("Opening %s failed: %s", app.destination, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return nil, false
+		}
+
+		klog.Infof("Starting %s of %d bytes with %d streams", contentType, size, streams)
+		session = &extentsCloneSession{
+			attempt:     attempt,
+			contentType: contentType,
+			streams:     streams,
+			size:        size,
+			sink:        sink,
+			stopped:     make(chan struct{}),
+		}
+		app.extentsClone = session
+		app.uploading = true
+		app.cloned = true
+	} else if session.contentType != contentType || session.streams != streams || session.size != size {
+		klog.Warningf("Got %s stream of %d bytes with %d streams during another clone", contentType, size, streams)
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	} else if session.started == session.streams {
+		klog.Warning("Got more clone streams than announced")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	session.started++
+	session.active++
+
+	return session, true
+}
+
+// finishExtentsStream records the result of a stream. If any stream failed the attempt is stopped once all its streams
+// stopped, and the clone starts over with a new attempt. The last successful stream finalizes the target.
+func (app *uploadServerApp) finishExtentsStream(session *extentsCloneSession, streamErr error) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	session.active--
+	if streamErr != nil && session.err == nil {
+		session.err = streamErr
+	}
+	if streamErr == nil {
+		session.finished++
+	}
+
+	if session.err != nil {
+		if session.active == 0 {
+			if app.extentsCloneAttempt == session.attempt {
+				// Streams of the failed attempt arriving late are rejected
+				app.extentsCloneAttempt = ""
+			}
+			app.stopExtentsClone(session)
+		}
+		if streamErr != nil {
+			return streamErr
+		}
+		return errors.Wrap(session.err, "another stream of the clone failed")
+	}
+
+	if session.finished < session.streams {
+		return nil
+	}
+
+	app.extentsClone = nil
+	app.uploading = false
+
+	if err := session.sink.Close(); err != nil {
+		return errors.Wrapf(err, "error closing %s", app.destination)
+	}
+
+	// A block device copied to a file system is resized to the target like an image processed by the data processor
+	if session.contentType == common.BlockdeviceExtentsClone && app.destination != common.WriteBlockPath {
+		// Resizing can take a while, don't block health checks and status requests meanwhile
+		app.processing = true
+		app.mutex.Unlock()
+		preallocationApplied, err := resizeImageFunc(app.destination, app.imageSize, app.filesystemOverhead, app.preallocation)
+		app.mutex.Lock()
+		app.processing = false
+		if err != nil {
+			return err
+		}
+		app.preallocationApplied = preallocationApplied
+	}
+
+	app.done = true
+	close(app.doneChan)
+
+	klog.Infof("Wrote data to %s", app.destination)
+	return nil
+}
+
+// cancelExtentsClone makes the streams of a superseded attempt fail at their next frame, the attempt is stopped once
+// none of them is active anymore
+func (app *uploadServerApp) cancelExtentsClone(session *extentsCloneSession) {
+	atomic.StoreInt32(&session.cancelled, 1)
+	if session.err == nil {
+		session.err = errExtentsCloneCancelled
+	}
+	if session.active == 0 {
+		app.stopExtentsClone(session)
+	}
+}
+
+// stopExtentsClone closes the target of a failed or cancelled attempt, and lets a new attempt start
+func (app *uploadServerApp) stopExtentsClone(session *extentsCloneSession) {
+	session.sink.Close()
+	if app.extentsClone == session {
+		app.extentsClone = nil
+		app.uploading = false
+	}
+	close(session.stopped)
+}
+
+// newExtentsSink opens the target of an extents clone, a file system target is cleaned up first
+func (app *uploadServerApp) newExtentsSink(size int64) (*extents.FileSink, error) {
+	if app.destination != common.WriteBlockPath {
+		destDir := filepath.Dir(app.destination)
+		if err := importer.CleanDir(destDir); err != nil {
+			return nil, errors.Wrapf(err, "error removing contents of %s", destDir)
+		}
+	}
+	return extents.NewFileSink(app.destination, size)
+}
+
+// validateContentEncoding rejects uploads compressed with an encoding that cannot be decoded, before any state changes
+func validateContentEncoding(w http.ResponseWriter, r *http.Request) bool {
+	if !isAcceptedEncoding(r.Header.Get("Content-Encoding")) {
+		klog.Warningf("Got upload with unsupported Content-Encoding %q", r.Header.Get("Content-Encoding"))
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte(fmt.Sprintf("Unsupported Content-Encoding, accepted encodings are: %s", acceptedEncodings)))
+		return false
+	}
+	return true
+}
+
+// requestScratchSpace tells the client the upload needs scratch space and stops the upload server, the upload pod is
+// restarted with scratch space and the client has to retry.
+func (app *uploadServerApp) requestScratchSpace(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusServiceUnavailable)
+	go func() {
+		app.errChan <- importer.ErrRequiresScratchSpace
+	}()
+}
+
+// decodeContent wraps the upload stream in a decoder for the Content-Encoding of the request. On error the response
+// is written and the upload is marked as not running anymore.
+func (app *uploadServerApp) decodeContent(w http.ResponseWriter, r *http.Request, stream io.ReadCloser) (io.ReadCloser, bool) {
+	encoding := r.Header.Get("Content-Encoding")
+	decoded, err := newContentEncodingReader(stream, encoding)
+	if err != nil {
+		stream.Close()
+		klog.Errorf("Decoding %q upload failed: %s", encoding, err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Decoding %q upload failed: %s", encoding, err.Error())))
+		app.mutex.Lock()
+		app.uploading = false
+		app.mutex.Unlock()
+		return nil, false
+	}
+	if encoding != "" {
+		klog.Infof("Decoding %q upload while streaming", encoding)
+	}
+	return decoded, true
+}
+
+func (app *uploadServerApp) setCloned(cdiContentType string) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.cloned = cdiContentType != ""
+}
+
+func (app *uploadServerApp) resumableUploadHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodHead:
+		app.resumableUploadOffsetHandler(w, r)
+	case http.MethodPatch:
+		app.resumableUploadChunkHandler(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// resumableUploadOffsetHandler reports the committed offset, where the client has to continue the upload
+func (app *uploadServerApp) resumableUploadOffsetHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	state, err := app.readResumableUpload()
+	if err != nil {
+		klog.Errorf("Reading resumable upload state failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if app.processing || app.done {
+		// The staged upload may already be cleaned up by the processing, it is complete
+		state.Offset, state.Length = app.resumableLength, app.resumableLength
+	}
+	setResumableUploadHeaders(w, state)
+	w.WriteHeader(http.StatusOK)
+}
+
+// resumableUploadChunkHandler appends a chunk at the committed offset, and starts processing the upload in the
+// background once all of it was received
+func (app *uploadServerApp) resumableUploadChunkHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Accept-Encoding", acceptedEncodings)
+	if !validateContentEncoding(w, r) || !app.validateShouldHandleUpload(w, r) {
+		return
+	}
+
+	defer func() {
+		app.mutex.Lock()
+		defer app.mutex.Unlock()
+		app.uploading = false
+	}()
+
+	// The chunks are assembled in scratch space
+	scratchSpace, _ := util.GetAvailableSpace(app.scratchDir)
+	if scratchSpace <= 0 {
+		klog.Warning("Got resumable upload without scratch space")
+		app.requestScratchSpace(w)
+		w.Write([]byte("Resumable uploads require scratch space, retry the upload once the upload server restarted with scratch space"))
+		return
+	}
+
+	state, err := app.readResumableUpload()
+	if err != nil {
+		klog.Errorf("Reading resumable upload state failed: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(common.UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Invalid %s header", common.UploadOffsetHeader)))
+		return
+	}
+	if offset != state.Offset {
+		klog.Warningf("Got chunk at offset %d, committed offset is %d", offset, state.Offset)
+		setResumableUploadHeaders(w, state)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	if value := r.Header.Get(common.UploadLengthHeader); value != "" {
+		length, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || length <= 0 || length < state.Offset || (state.Length > 0 && length != state.Length) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("Invalid %s header", common.UploadLengthHeader)))
+			return
+		}
+		// The length is only set by the first chunk, nothing was staged yet
+		if state.Length == 0 && length > scratchSpace {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			w.Write([]byte(fmt.Sprintf("Upload of %d bytes does not fit in the %d bytes of scratch space", length, scratchSpace)))
+			return
+		}
+		state.Length = length
+	} else if state.Length == 0 {
+		// Without the length the end of the upload is unknown, and the staged chunks are not limited
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Missing %s header", common.UploadLengthHeader)))
+		return
+	}
+
+	encoding := normalizeEncoding(r.Header.Get("Content-Encoding"))
+	if encoding == "identity" {
+		encoding = ""
+	}
+	if state.Offset == 0 {
+		state.Encoding = encoding
+	} else if encoding != state.Encoding {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Content-Encoding %q does not match the encoding %q of the upload", encoding, state.Encoding)))
+		return
+	}
+
+	state.Offset, err = app.appendResumableUpload(r.Body, state)
+	if err != nil {
+		klog.Errorf("Saving chunk failed: %s", err)
+		setResumableUploadHeaders(w, state)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	setResumableUploadHeaders(w, state)
+
+	if state.Offset == state.Length {
+		file, err := os.Open(filepath.Join(app.scratchDir, resumableUploadFile))
+		if err != nil {
+			klog.Errorf("Opening resumable upload file failed: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		stream, err := newContentEncodingReader(file, state.Encoding)
+		if err != nil {
+			// The upload can't be processed, drop it so it can be sent again from the start
+			file.Close()
+			os.Remove(filepath.Join(app.scratchDir, resumableUploadFile))
+			os.Remove(filepath.Join(app.scratchDir, resumableUploadStateFile))
+			klog.Errorf("Decoding %q upload failed: %s", state.Encoding, err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("Decoding %q upload failed: %s", state.Encoding, err.Error())))
+			return
+		}
+		app.processResumableUpload(stream, r.Header.Get(common.UploadContentTypeHeader), state)
+		klog.Info("Returning success to caller, continue processing in background")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// appendResumableUpload writes the chunk at the committed offset, and commits what was written even if the chunk was
+// not received completely, returning the new committed offset
+func (app *uploadServerApp) appendResumableUpload(chunk io.Reader, state *resumableUpload) (int64, error) {
+	f, err := os.OpenFile(filepath.Join(app.scratchDir, resumableUploadFile), os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return state.Offset, errors.Wrap(err, "could not open resumable upload file")
+	}
+	defer f.Close()
+
+	// Drop data written after the last commit
+	if err := f.Truncate(state.Offset); err != nil {
+		return state.Offset, errors.Wrap(err, "could not truncate resumable upload file")
+	}
+	if _, err := f.Seek(state.Offset, io.SeekStart); err != nil {
+		return state.Offset, errors.Wrap(err, "could not seek resumable upload file")
+	}
+	limited := app.newProgressReader(ioutil.NopCloser(io.LimitReader(chunk, state.Length-state.Offset)), state.Offset, state.Length)
+
+	n, copyErr := io.Copy(f, limited)
+	if err := f.Sync(); err != nil {
+		return state.Offset, errors.Wrap(err, "could not sync resumable upload file")
+	}
+	committed := &resumableUpload{Offset: state.Offset + n, Length: state.Length, Encoding: state.Encoding}
+	if err := app.writeResumableUpload(committed); err != nil {
+		return state.Offset, err
+	}
+	klog.Infof("Committed %d bytes of resumable upload at offset %d", n, state.Offset)
+	if copyErr != nil {
+		return committed.Offset, errors.Wrap(copyErr, "could not receive chunk")
+	}
+	return committed.Offset, nil
+}
+
+// newProgressReader counts the bytes read from an upload stream in the progress report of the pod. offset is the number
+// of bytes received before the stream, and total the number of bytes of the whole upload if known.
+func (app *uploadServerApp) newProgressReader(stream io.ReadCloser, offset, total int64) io.ReadCloser {
+	if app.ownerUID == "" || total <= 0 {
+		return stream
+	}
+	reader := prometheusutil.NewProgressReader(stream, uint64(total), progress, app.ownerUID)
+	reader.Current = uint64(offset)
+	reader.StartTimedUpdate()
+	return reader
+}
+
+// processResumableUpload processes the assembled upload in the background, and removes the staged upload and its state
+// once done
+func (app *uploadServerApp) processResumableUpload(stream io.ReadCloser, contentType string, state *resumableUpload) {
+	app.mutex.Lock()
+	app.processing = true
+	app.resumableLength = state.Length
+	app.mutex.Unlock()
+
+	// A decoded upload has to be written out, only the raw staged data can be converted where it is
+	stagedFile := filepath.Join(app.scratchDir, resumableUploadFile)
+	if state.Encoding != "" {
+		stagedFile = ""
+	}
+
+	go func() {
+		defer os.Remove(filepath.Join(app.scratchDir, resumableUploadStateFile))
+		defer os.Remove(filepath.Join(app.scratchDir, resumableUploadFile))
+		defer stream.Close()
+		preallocationApplied, err := stagedUploadProcessorFunc(stream, stagedFile, app.destination, app.imageSize, app.filesystemOverhead, app.preallocation, contentType, app.checksum)
+		if err != nil {
+			klog.Errorf("Saving stream failed: %s", err)
+			app.errChan <- err
+			return
+		}
+		app.mutex.Lock()
+		defer app.mutex.Unlock()
+		app.processing = false
+		app.done = true
+		app.preallocationApplied = preallocationApplied
+		close(app.doneChan)
+		klog.Infof("Wrote data to %s", app.destination)
+	}()
+}
+
+func (app *uploadServerApp) readResumableUpload() (*resumableUpload, error) {
+	state := &resumableUpload{}
+	data, err := ioutil.ReadFile(filepath.Join(app.scratchDir, resumableUploadStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (app *uploadServerApp) writeResumableUpload(state *resumableUpload) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	// Replace the state atomically, a restart must never see a partially written state
+	tmpFile := filepath.Join(app.scratchDir, resumableUploadStateFile+".tmp")
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return errors.Wrap(err, "could not write resumable upload state")
+	}
+	return os.Rename(tmpFile, filepath.Join(app.scratchDir, resumableUploadStateFile))
+}
+
+func setResumableUploadHeaders(w http.ResponseWriter, state *resumableUpload) {
+	w.Header().Set(common.UploadOffsetHeader, strconv.FormatInt(state.Offset, 10))
+	if state.Length > 0 {
+		w.Header().Set(common.UploadLengthHeader, strconv.FormatInt(state.Length, 10))
+	}
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+// Cloned returns true if the data was sent by a clone source pod instead of being uploaded by a user
+func (app *uploadServerApp) Cloned() bool {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	return app.cloned
+}
+
 func (app *uploadServerApp) PreallocationApplied() bool {
 	return app.preallocationApplied
 }
 
-func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string) (*importer.DataProcessor, error) {
+// Checksum returns the checksum the uploaded data was verified against, or an empty string if none was requested
+func (app *uploadServerApp) Checksum() string {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if !app.done || app.checksum == "" {
+		return ""
+	}
+	_, value, err := util.ParseChecksum(app.checksum)
+	if err != nil {
+		return ""
+	}
+	return app.checksum[:strings.Index(app.checksum, ":")+1] + value
+}
+
+// isValidationError returns true if the upload failed because the uploaded data is not valid
+func isValidationError(err error) bool {
+	switch errors.Cause(err).(type) {
+	case importer.ValidationSizeError, importer.ChecksumValidationError:
+		return true
+	}
+	return false
+}
+
+func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType, checksum string) (*importer.DataProcessor, error) {
 	if sourceContentType == common.FilesystemCloneContentType {
 		return nil, fmt.Errorf("async filesystem clone not supported")
 	}
 
-	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType))
+	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType), checksum)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
 	return processor, processor.ProcessDataWithPause()
 }
 
-func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string) (bool, error) {
+func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType, checksum string) (bool, error) {
 	if sourceContentType == common.FilesystemCloneContentType {
 		return false, filesystemCloneProcessor(stream, dest)
 	}
 
 	// Clone block device to block device or file system
-	uds := importer.NewUploadDataSource(newContentReader(stream, sourceContentType))
+	uds := importer.NewUploadDataSource(newContentReader(stream, sourceContentType), checksum)
+	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
+	err := processor.ProcessData()
+	return processor.PreallocationApplied(), err
+}
+
+// newStagedUploadStreamProcessor processes an upload assembled in scratch space. If stagedFile is set the stream reads
+// it as is, and an image that needs conversion is converted from it without another copy to scratch space.
+func newStagedUploadStreamProcessor(stream io.ReadCloser, stagedFile, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType, checksum string) (bool, error) {
+	if stagedFile == "" || sourceContentType != "" {
+		return uploadProcessorFunc(stream, dest, imageSize, filesystemOverhead, preallocation, sourceContentType, checksum)
+	}
+	uds := importer.NewStagedUploadDataSource(stream, stagedFile, checksum)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
 	err := processor.ProcessData()
 	return processor.PreallocationApplied(), err
@@ -463,7 +1188,9 @@ func untarToBlockdev(stream io.Reader, dest string) error {
 		case header == nil:
 			continue
 		}
-		if header.Typeflag == tar.TypeGNUSparse && strings.Contains(header.Name, common.DiskImageName) {
+		// Only the disk image is written to the device, tar writes it as a regular file unless it is sparse
+		isFile := header.Typeflag == tar.TypeGNUSparse || header.Typeflag == tar.TypeReg
+		if isFile && strings.Contains(header.Name, common.DiskImageName) {
 			klog.Infof("Untaring %d bytes to %s", header.Size, dest)
 			f, err := os.OpenFile(dest, os.O_APPEND|os.O_WRONLY, os.ModeDevice|os.ModePerm)
 			if err != nil {
@@ -488,6 +1215,92 @@ func newContentReader(stream io.ReadCloser, contentType string) io.ReadCloser {
 	return stream
 }
 
+func isExtentsClone(contentType string) bool {
+	return contentType == common.BlockdeviceExtentsClone || contentType == common.FilesystemExtentsClone
+}
+
+// resizeImage resizes and validates an image already written to the target, like the data processor does for uploads
+func resizeImage(dest, imageSize string, filesystemOverhead float64, preallocation bool) (bool, error) {
+	processor := importer.NewDataProcessor(&resizeDataSource{dest: dest}, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation)
+	err := processor.ProcessDataWithPause()
+	return processor.PreallocationApplied(), err
+}
+
+// resizeDataSource starts the data processor at the resize phase
+type resizeDataSource struct {
+	dest string
+}
+
+func (rds *resizeDataSource) Info() (importer.ProcessingPhase, error) {
+	return importer.ProcessingPhaseResize, nil
+}
+
+func (rds *resizeDataSource) Transfer(path string) (importer.ProcessingPhase, error) {
+	return importer.ProcessingPhaseError, errors.New("transfer not supported when resizing")
+}
+
+func (rds *resizeDataSource) TransferFile(fileName string) (importer.ProcessingPhase, error) {
+	return importer.ProcessingPhaseError, errors.New("transfer not supported when resizing")
+}
+
+func (rds *resizeDataSource) GetURL() *url.URL {
+	u, _ := url.Parse(rds.dest)
+	return u
+}
+
+func (rds *resizeDataSource) Close() error {
+	return nil
+}
+
 func newSnappyReadCloser(stream io.ReadCloser) io.ReadCloser {
 	return ioutil.NopCloser(snappy.NewReader(stream))
 }
+
+func isAcceptedEncoding(encoding string) bool {
+	switch normalizeEncoding(encoding) {
+	case "", "identity", "gzip", "x-gzip", "zstd":
+		return true
+	}
+	return false
+}
+
+func normalizeEncoding(encoding string) string {
+	return strings.ToLower(strings.TrimSpace(encoding))
+}
+
+// newContentEncodingReader returns a reader decoding the stream according to the Content-Encoding of the request
+func newContentEncodingReader(stream io.ReadCloser, encoding string) (io.ReadCloser, error) {
+	switch normalizeEncoding(encoding) {
+	case "", "identity":
+		return stream, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(stream)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create gzip reader")
+		}
+		return &decodingReadCloser{Reader: gz, closers: []io.Closer{gz, stream}}, nil
+	case "zstd":
+		zr, err := zstd.NewReader(stream)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create zstd reader")
+		}
+		return &decodingReadCloser{Reader: zr, closers: []io.Closer{zr.IOReadCloser(), stream}}, nil
+	}
+	return nil, errUnsupportedEncoding
+}
+
+// decodingReadCloser closes the decoder as well as the underlying stream
+type decodingReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (d *decodingReadCloser) Close() error {
+	var result error
+	for _, c := range d.closers {
+		if err := c.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
@@ -20,30 +20,43 @@
 package uploadserver
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/extents"
+	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
 )
 
 func newServer() *uploadServerApp {
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false)
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, "")
 	return server.(*uploadServerApp)
 }
 
@@ -61,7 +74,7 @@ func newTLSServer(clientCertName, expectedName string) (*uploadServerApp, *tripl
 	tlsCert := string(cert.EncodeCertPEM(serverKeyPair.Cert))
 	clientCert := string(cert.EncodeCertPEM(clientCA.Cert))
 
-	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false).(*uploadServerApp)
+	server := NewUploadServer("127.0.0.1", 0, "disk.img", tlsKey, tlsCert, clientCert, expectedName, "", 0.055, false, "").(*uploadServerApp)
 
 	clientKeyPair, err := triple.NewClientKeyPair(clientCA, clientCertName, []string{})
 	Expect(err).ToNot(HaveOccurred())
@@ -88,14 +101,25 @@ func newHTTPClient(clientKeyPair *triple.KeyPair, serverCACert *x509.Certificate
 	return client
 }
 
-func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (bool, error) {
+func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
 	return false, nil
 }
 
-func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (bool, error) {
+func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
 	return false, fmt.Errorf("Error using datastream")
 }
 
+func checksumMismatch(checksum string) error {
+	// the test request body is too small to hold an image header, verify a zeroed image instead
+	readers, err := importer.NewFormatReadersWithChecksum(ioutil.NopCloser(bytes.NewReader(make([]byte, 1024*1024))), 0, checksum)
+	Expect(err).ToNot(HaveOccurred())
+	return errors.Wrap(readers.VerifyChecksum(), "Unable to transfer source data to target file")
+}
+
+func saveProcessorChecksumFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
+	return false, checksumMismatch(checksum)
+}
+
 func withProcessorSuccess(f func()) {
 	replaceProcessorFunc(saveProcessorSuccess, f)
 }
@@ -104,7 +128,7 @@ func withProcessorFailure(f func()) {
 	replaceProcessorFunc(saveProcessorFailure, f)
 }
 
-func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string) (bool, error), f func()) {
+func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, string) (bool, error), f func()) {
 	origProcessorFunc := uploadProcessorFunc
 	uploadProcessorFunc = replacement
 	defer func() {
@@ -151,14 +175,18 @@ func (amd *AsyncMockDataSource) GetResumePhase() importer.ProcessingPhase {
 	return importer.ProcessingPhaseComplete
 }
 
-func saveAsyncProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (*importer.DataProcessor, error) {
+func saveAsyncProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (*importer.DataProcessor, error) {
 	return importer.NewDataProcessor(&AsyncMockDataSource{}, "", "", "", "", 0.055, false), nil
 }
 
-func saveAsyncProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (*importer.DataProcessor, error) {
+func saveAsyncProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (*importer.DataProcessor, error) {
 	return importer.NewDataProcessor(&AsyncMockDataSource{}, "", "", "", "", 0.055, false), fmt.Errorf("Error using datastream")
 }
 
+func saveAsyncProcessorChecksumFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (*importer.DataProcessor, error) {
+	return nil, checksumMismatch(checksum)
+}
+
 func withAsyncProcessorSuccess(f func()) {
 	replaceAsyncProcessorFunc(saveAsyncProcessorSuccess, f)
 }
@@ -167,7 +195,7 @@ func withAsyncProcessorFailure(f func()) {
 	replaceAsyncProcessorFunc(saveAsyncProcessorFailure, f)
 }
 
-func replaceAsyncProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string) (*importer.DataProcessor, error), f func()) {
+func replaceAsyncProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, string) (*importer.DataProcessor, error), f func()) {
 	origProcessorFuncAsync := uploadProcessorFuncAsync
 	uploadProcessorFuncAsync = replacement
 	defer func() {
@@ -332,6 +360,163 @@ var _ = Describe("Upload server tests", func() {
 		table.Entry("sync", withProcessorFailure, common.UploadFormSync),
 	)
 
+	table.DescribeTable("Checksum mismatch", func(replace func(func()), uploadPath string) {
+		replace(func() {
+			req, err := http.NewRequest("POST", uploadPath, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+
+			rr := httptest.NewRecorder()
+
+			server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, "md5:"+strings.Repeat("0", 32)).(*uploadServerApp)
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(rr.Body.String()).To(ContainSubstring("checksum mismatch"))
+			Expect(server.Checksum()).To(BeEmpty())
+		})
+	},
+		table.Entry("async", func(f func()) { replaceAsyncProcessorFunc(saveAsyncProcessorChecksumFailure, f) }, common.UploadPathAsync),
+		table.Entry("sync", func(f func()) { replaceProcessorFunc(saveProcessorChecksumFailure, f) }, common.UploadPathSync),
+	)
+
+	It("Success reports the verified checksum", func() {
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+
+			rr := httptest.NewRecorder()
+
+			server := NewUploadServer("127.0.0.1", 0, "disk.img", "", "", "", "", "", 0.055, false, "md5:"+strings.Repeat("A", 32)).(*uploadServerApp)
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(server.Checksum()).To(Equal("md5:" + strings.Repeat("a", 32)))
+		})
+	})
+
+	table.DescribeTable("Compressed upload is decoded", func(encoding string, encode func([]byte) []byte) {
+		var received []byte
+		replaceProcessorFunc(func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
+			var err error
+			received, err = ioutil.ReadAll(stream)
+			return false, err
+		}, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, bytes.NewReader(encode([]byte("raw image data"))))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Encoding", encoding)
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get("Accept-Encoding")).To(Equal(acceptedEncodings))
+			Expect(string(received)).To(Equal("raw image data"))
+			Expect(server.Cloned()).To(BeFalse())
+		})
+	},
+		table.Entry("identity", "", func(data []byte) []byte { return data }),
+		table.Entry("gzip", "gzip", gzipData),
+		table.Entry("zstd", "zstd", zstdData),
+	)
+
+	It("Unsupported encoding is rejected", func() {
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Encoding", "br")
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusUnsupportedMediaType))
+			Expect(rr.Header().Get("Accept-Encoding")).To(Equal(acceptedEncodings))
+			Expect(server.uploading).To(BeFalse())
+		})
+	})
+
+	It("Invalid compressed data is rejected and the upload can be retried", func() {
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, nil)
+			Expect(err).ToNot(HaveOccurred())
+			body := &closeTrackingReader{Reader: strings.NewReader("not gzip")}
+			req.Body = body
+			req.Header.Set("Content-Encoding", "gzip")
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(body.closed).To(BeTrue())
+			Expect(server.uploading).To(BeFalse())
+			Expect(server.done).To(BeFalse())
+		})
+	})
+
+	It("Upload that requires scratch space restarts the server", func() {
+		replaceProcessorFunc(func(io.ReadCloser, string, string, float64, bool, string, string) (bool, error) {
+			return false, errors.Wrap(importer.ErrRequiresScratchSpace, "Unable to transfer source data to scratch space")
+		}, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(<-server.errChan).To(Equal(importer.ErrRequiresScratchSpace))
+			Expect(server.uploading).To(BeFalse())
+		})
+	})
+
+	It("Clone stream is reported as cloned", func() {
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceClone)
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(server.Cloned()).To(BeTrue())
+		})
+	})
+
+	It("Upload progress is reported for the owner", func() {
+		readAll := func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
+			_, err := ioutil.ReadAll(stream)
+			return false, err
+		}
+		replaceProcessorFunc(readAll, func() {
+			req, err := http.NewRequest("POST", common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.ownerUID = "1234"
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Eventually(func() uint64 {
+				return prometheusutil.GetProgressReport().BytesTransferred
+			}, 5*time.Second).Should(BeEquivalentTo(4))
+			report := prometheusutil.GetProgressReport()
+			Expect(report.OwnerUID).To(Equal("1234"))
+			Expect(report.TotalBytes).To(BeEquivalentTo(4))
+		})
+	})
+
 	table.DescribeTable("Real upload with client", func(certName string, expectedName string, expectedResponse int) {
 		withProcessorSuccess(func() {
 			server, clientKeyPair, serverCACert := newTLSServer(certName, expectedName)
@@ -373,6 +558,248 @@ var _ = Describe("Upload server tests", func() {
 	)
 })
 
+var _ = Describe("Extents clone tests", func() {
+	var targetDir string
+
+	BeforeEach(func() {
+		var err error
+		targetDir, err = ioutil.TempDir("", "extents-clone")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(targetDir)
+	})
+
+	newExtentsServer := func() *uploadServerApp {
+		server := newServer()
+		server.destination = filepath.Join(targetDir, "disk.img")
+		return server
+	}
+
+	encodeChunks := func(source []byte, chunks []extents.Chunk) *bytes.Buffer {
+		var buf bytes.Buffer
+		sbw := snappy.NewBufferedWriter(&buf)
+		Expect(extents.WriteChunks(sbw, bytes.NewReader(source), chunks, nil)).To(Succeed())
+		Expect(sbw.Close()).To(Succeed())
+		return &buf
+	}
+
+	postAttemptStream := func(server *uploadServerApp, attempt, contentType string, streams int, size int64, body io.Reader) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, body)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadContentTypeHeader, contentType)
+		req.Header.Set(common.CloneStreamsHeader, strconv.Itoa(streams))
+		req.Header.Set(common.CloneSizeHeader, strconv.FormatInt(size, 10))
+		req.Header.Set(common.CloneAttemptHeader, attempt)
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	postStream := func(server *uploadServerApp, contentType string, streams int, size int64, body io.Reader) *httptest.ResponseRecorder {
+		return postAttemptStream(server, "attempt", contentType, streams, size, body)
+	}
+
+	source := func() []byte {
+		data := make([]byte, 3*extents.ChunkSize)
+		copy(data, "start")
+		copy(data[2*extents.ChunkSize+extents.BlockSize:], "middle")
+		return data
+	}
+
+	It("should apply the extents of all streams before finishing", func() {
+		data := source()
+		size := int64(len(data))
+		parts := extents.Partition(extents.Plan([]extents.Extent{{Offset: 0, Length: size}}, size), 2)
+		server := newExtentsServer()
+
+		rr := postStream(server, common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[0]))
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(server.done).To(BeFalse())
+		Expect(server.uploading).To(BeTrue())
+
+		rr = postStream(server, common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[1]))
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(server.done).To(BeTrue())
+		Expect(server.uploading).To(BeFalse())
+		Expect(server.Cloned()).To(BeTrue())
+
+		written, err := ioutil.ReadFile(server.destination)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+	})
+
+	It("should resize a block device cloned to a file system", func() {
+		resized := ""
+		server := newExtentsServer()
+		origResizeImageFunc := resizeImageFunc
+		resizeImageFunc = func(dest, imageSize string, filesystemOverhead float64, preallocation bool) (bool, error) {
+			// the server must not be locked while resizing
+			server.mutex.Lock()
+			defer server.mutex.Unlock()
+			Expect(server.processing).To(BeTrue())
+			resized = dest
+			return false, nil
+		}
+		defer func() {
+			resizeImageFunc = origResizeImageFunc
+		}()
+
+		data := source()
+		size := int64(len(data))
+		rr := postStream(server, common.BlockdeviceExtentsClone, 1, size, encodeChunks(data, extents.Plan([]extents.Extent{{Offset: 0, Length: size}}, size)))
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(server.done).To(BeTrue())
+		Expect(server.processing).To(BeFalse())
+		Expect(resized).To(Equal(server.destination))
+	})
+
+	It("should start over after a stream failed", func() {
+		server := newExtentsServer()
+		rr := postStream(server, common.FilesystemExtentsClone, 2, 1024, strings.NewReader("garbage"))
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(server.extentsClone).To(BeNil())
+		Expect(server.uploading).To(BeFalse())
+		Expect(server.done).To(BeFalse())
+	})
+
+	It("should reject the late streams of a failed attempt", func() {
+		data := source()
+		size := int64(len(data))
+		parts := extents.Partition(extents.Plan([]extents.Extent{{Offset: 0, Length: size}}, size), 2)
+		server := newExtentsServer()
+
+		rr := postAttemptStream(server, "first", common.FilesystemExtentsClone, 2, size, strings.NewReader("garbage"))
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		rr = postAttemptStream(server, "first", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[1]))
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(server.extentsClone).To(BeNil())
+
+		// The restarted cloner sends all streams again
+		Expect(postAttemptStream(server, "second", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[0])).Code).To(Equal(http.StatusOK))
+		Expect(postAttemptStream(server, "second", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[1])).Code).To(Equal(http.StatusOK))
+		Expect(server.done).To(BeTrue())
+
+		written, err := ioutil.ReadFile(server.destination)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+	})
+
+	It("should start over when a new attempt starts and reject the streams of the earlier one", func() {
+		data := source()
+		size := int64(len(data))
+		parts := extents.Partition(extents.Plan([]extents.Extent{{Offset: 0, Length: size}}, size), 2)
+		server := newExtentsServer()
+
+		Expect(postAttemptStream(server, "first", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[0])).Code).To(Equal(http.StatusOK))
+		Expect(postAttemptStream(server, "second", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[0])).Code).To(Equal(http.StatusOK))
+		Expect(postAttemptStream(server, "first", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[1])).Code).To(Equal(http.StatusConflict))
+		Expect(server.done).To(BeFalse())
+		Expect(postAttemptStream(server, "second", common.FilesystemExtentsClone, 2, size, encodeChunks(data, parts[1])).Code).To(Equal(http.StatusOK))
+		Expect(server.done).To(BeTrue())
+
+		written, err := ioutil.ReadFile(server.destination)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, data)).To(BeTrue())
+	})
+
+	It("should wait for the active streams of a cancelled attempt before starting a new one", func() {
+		server := newExtentsServer()
+		first, ok := server.joinExtentsClone(context.Background(), httptest.NewRecorder(), "first", common.FilesystemExtentsClone, 2, 1024)
+		Expect(ok).To(BeTrue())
+
+		joined := make(chan *extentsCloneSession)
+		go func() {
+			defer GinkgoRecover()
+			second, ok := server.joinExtentsClone(context.Background(), httptest.NewRecorder(), "second", common.FilesystemExtentsClone, 2, 1024)
+			Expect(ok).To(BeTrue())
+			joined <- second
+		}()
+
+		// The stream of the first attempt fails at its next write
+		Eventually(func() error {
+			_, err := first.WriteAt([]byte("data"), 0)
+			return err
+		}).Should(Equal(errExtentsCloneCancelled))
+		Consistently(joined).ShouldNot(Receive())
+
+		Expect(server.finishExtentsStream(first, errExtentsCloneCancelled)).To(HaveOccurred())
+		var second *extentsCloneSession
+		Eventually(joined).Should(Receive(&second))
+		Expect(second.attempt).To(Equal("second"))
+		Expect(server.extentsClone).To(Equal(second))
+	})
+
+	table.DescribeTable("should reject a stream with invalid headers", func(streams, size, attempt string) {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadContentTypeHeader, common.BlockdeviceExtentsClone)
+		req.Header.Set(common.CloneStreamsHeader, streams)
+		req.Header.Set(common.CloneSizeHeader, size)
+		req.Header.Set(common.CloneAttemptHeader, attempt)
+		rr := httptest.NewRecorder()
+		newExtentsServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	},
+		table.Entry("missing streams", "", "1024", "attempt"),
+		table.Entry("zero streams", "0", "1024", "attempt"),
+		table.Entry("missing size", "2", "", "attempt"),
+		table.Entry("negative size", "2", "-1", "attempt"),
+		table.Entry("missing attempt", "2", "1024", ""),
+	)
+
+	It("should reject a stream of another clone", func() {
+		server := newExtentsServer()
+		server.extentsClone = &extentsCloneSession{attempt: "attempt", contentType: common.FilesystemExtentsClone, streams: 2, size: 1024}
+		server.extentsCloneAttempt = "attempt"
+		rr := postStream(server, common.FilesystemExtentsClone, 3, 1024, strings.NewReader(""))
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject a stream during an upload", func() {
+		server := newExtentsServer()
+		server.uploading = true
+		rr := postStream(server, common.FilesystemExtentsClone, 1, 1024, strings.NewReader(""))
+		Expect(rr.Code).To(Equal(http.StatusServiceUnavailable))
+	})
+})
+
+var _ = Describe("Untar to block device", func() {
+	var targetDir string
+
+	BeforeEach(func() {
+		var err error
+		targetDir, err = ioutil.TempDir("", "untar-blockdev")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(targetDir)
+	})
+
+	It("should write only the disk image of the archive", func() {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, file := range []struct{ name, content string }{
+			{"lost+found/other", "other"},
+			{"./" + common.DiskImageName, "disk image"},
+		} {
+			Expect(tw.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(file.content))})).To(Succeed())
+			_, err := tw.Write([]byte(file.content))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+
+		dest := filepath.Join(targetDir, "device")
+		Expect(ioutil.WriteFile(dest, nil, 0644)).To(Succeed())
+		Expect(untarToBlockdev(&buf, dest)).To(Succeed())
+		content, err := ioutil.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("disk image"))
+	})
+})
+
 func newFormRequest(path string) *http.Request {
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
@@ -393,3 +820,244 @@ func newFormRequest(path string) *http.Request {
 
 	return req
 }
+
+func gzipData(data []byte) []byte {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write(data)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(w.Close()).To(Succeed())
+	return b.Bytes()
+}
+
+func zstdData(data []byte) []byte {
+	w, err := zstd.NewWriter(nil)
+	Expect(err).ToNot(HaveOccurred())
+	defer w.Close()
+	return w.EncodeAll(data, nil)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+type closeTrackingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeTrackingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+var _ = Describe("Resumable upload tests", func() {
+	var scratchDir string
+
+	BeforeEach(func() {
+		var err error
+		scratchDir, err = ioutil.TempDir("", "resumable-upload")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(scratchDir)
+	})
+
+	newResumableServer := func() *uploadServerApp {
+		server := newServer()
+		server.scratchDir = scratchDir
+		return server
+	}
+
+	headOffset := func(server *uploadServerApp) string {
+		req, err := http.NewRequest(http.MethodHead, common.UploadPathResumable, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		return rr.Header().Get(common.UploadOffsetHeader)
+	}
+
+	patchChunk := func(server *uploadServerApp, offset, length int64, body io.Reader) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPatch, common.UploadPathResumable, body)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+		if length > 0 {
+			req.Header.Set(common.UploadLengthHeader, strconv.FormatInt(length, 10))
+		}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	It("should ask for scratch space if there is none", func() {
+		server := newServer()
+		server.scratchDir = filepath.Join(scratchDir, "missing")
+		rr := patchChunk(server, 0, 8, strings.NewReader("data"))
+		Expect(rr.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rr.Body.String()).To(ContainSubstring("scratch space"))
+		Expect(<-server.errChan).To(Equal(importer.ErrRequiresScratchSpace))
+	})
+
+	It("should report offset 0 before anything was uploaded", func() {
+		Expect(headOffset(newResumableServer())).To(Equal("0"))
+	})
+
+	It("should reject methods other than HEAD and PATCH", func() {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathResumable, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		newResumableServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject a chunk without offset", func() {
+		rr := patchChunk(newResumableServer(), 0, 8, strings.NewReader("data"))
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		req, err := http.NewRequest(http.MethodPatch, common.UploadPathResumable, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		rr = httptest.NewRecorder()
+		newResumableServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject a first chunk without length", func() {
+		server := newResumableServer()
+		rr := patchChunk(server, 0, 0, strings.NewReader("data"))
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(ContainSubstring(common.UploadLengthHeader))
+		Expect(headOffset(server)).To(Equal("0"))
+	})
+
+	It("should reject a length larger than the scratch space", func() {
+		server := newResumableServer()
+		rr := patchChunk(server, 0, math.MaxInt64, strings.NewReader("data"))
+		Expect(rr.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		Expect(headOffset(server)).To(Equal("0"))
+	})
+
+	It("should reject a chunk not at the committed offset", func() {
+		server := newResumableServer()
+		Expect(patchChunk(server, 0, 8, strings.NewReader("data")).Code).To(Equal(http.StatusNoContent))
+		rr := patchChunk(server, 2, 0, strings.NewReader("data"))
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+	})
+
+	It("should reject a changed length", func() {
+		server := newResumableServer()
+		Expect(patchChunk(server, 0, 8, strings.NewReader("data")).Code).To(Equal(http.StatusNoContent))
+		Expect(patchChunk(server, 4, 10, strings.NewReader("data")).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should commit a partially received chunk and resume after a restart", func() {
+		server := newResumableServer()
+		body := io.MultiReader(strings.NewReader("da"), &failingReader{err: errors.New("connection dropped")})
+		rr := patchChunk(server, 0, 8, body)
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("2"))
+
+		// A new server finds the committed offset in the scratch space
+		server = newResumableServer()
+		Expect(headOffset(server)).To(Equal("2"))
+		Expect(patchChunk(server, 2, 0, strings.NewReader("ta")).Code).To(Equal(http.StatusNoContent))
+		Expect(headOffset(server)).To(Equal("4"))
+	})
+
+	// replaceStagedProcessorFunc records what the processing of the assembled upload received
+	replaceStagedProcessorFunc := func(received *[]byte, receivedFile *string, f func()) {
+		origProcessorFunc := stagedUploadProcessorFunc
+		stagedUploadProcessorFunc = func(stream io.ReadCloser, stagedFile, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType, checksum string) (bool, error) {
+			var err error
+			*received, err = ioutil.ReadAll(stream)
+			*receivedFile = stagedFile
+			return false, err
+		}
+		defer func() {
+			stagedUploadProcessorFunc = origProcessorFunc
+		}()
+		f()
+	}
+
+	It("should process the assembled upload once complete", func() {
+		var received []byte
+		var stagedFile string
+		replaceStagedProcessorFunc(&received, &stagedFile, func() {
+			server := newResumableServer()
+			Expect(patchChunk(server, 0, 8, strings.NewReader("data")).Code).To(Equal(http.StatusNoContent))
+			Expect(server.processing).To(BeFalse())
+			rr := patchChunk(server, 4, 0, strings.NewReader("data and more"))
+			Expect(rr.Code).To(Equal(http.StatusNoContent))
+			Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("8"))
+			Eventually(server.doneChan).Should(BeClosed())
+			Expect(string(received)).To(Equal("datadata"))
+			Expect(stagedFile).To(Equal(filepath.Join(scratchDir, resumableUploadFile)))
+			Expect(headOffset(server)).To(Equal("8"))
+
+			rr = patchChunk(server, 8, 0, strings.NewReader("data"))
+			Expect(rr.Code).To(Equal(http.StatusConflict))
+
+			// The staged upload and its state are removed once processed
+			Eventually(func() bool {
+				_, err := os.Stat(filepath.Join(scratchDir, resumableUploadFile))
+				return os.IsNotExist(err)
+			}).Should(BeTrue())
+			Eventually(func() bool {
+				_, err := os.Stat(filepath.Join(scratchDir, resumableUploadStateFile))
+				return os.IsNotExist(err)
+			}).Should(BeTrue())
+		})
+	})
+
+	It("should decode the assembled upload according to its Content-Encoding", func() {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		_, err := gz.Write([]byte("datadata"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gz.Close()).To(Succeed())
+		data := compressed.Bytes()
+		length := int64(len(data))
+
+		var received []byte
+		var stagedFile string
+		replaceStagedProcessorFunc(&received, &stagedFile, func() {
+			server := newResumableServer()
+			patchEncoded := func(offset int64, chunk []byte) int {
+				req, err := http.NewRequest(http.MethodPatch, common.UploadPathResumable, bytes.NewReader(chunk))
+				Expect(err).ToNot(HaveOccurred())
+				req.Header.Set(common.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+				req.Header.Set(common.UploadLengthHeader, strconv.FormatInt(length, 10))
+				req.Header.Set("Content-Encoding", "gzip")
+				rr := httptest.NewRecorder()
+				server.ServeHTTP(rr, req)
+				return rr.Code
+			}
+			Expect(patchEncoded(0, data[:10])).To(Equal(http.StatusNoContent))
+			// The encoding of the upload can't change between chunks
+			Expect(patchChunk(server, 10, length, bytes.NewReader(data[10:])).Code).To(Equal(http.StatusBadRequest))
+			Expect(patchEncoded(10, data[10:])).To(Equal(http.StatusNoContent))
+			Eventually(server.doneChan).Should(BeClosed())
+			Expect(string(received)).To(Equal("datadata"))
+			Expect(stagedFile).To(BeEmpty())
+		})
+	})
+
+	It("should reject an assembled upload that cannot be decoded", func() {
+		server := newResumableServer()
+		req, err := http.NewRequest(http.MethodPatch, common.UploadPathResumable, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set(common.UploadOffsetHeader, "0")
+		req.Header.Set(common.UploadLengthHeader, "4")
+		req.Header.Set("Content-Encoding", "gzip")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(server.processing).To(BeFalse())
+		Expect(headOffset(server)).To(Equal("0"))
+	})
+})
@@ -0,0 +1,330 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+// Package extents implements the sparse aware transport of host assisted clone. The source is split in chunks, only
+// chunks holding data are read and zero blocks are sent as ranges without payload. The chunks can be spread over
+// several concurrent streams, each stream is applied at the offsets of its frames on the target.
+package extents
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BlockSize is the granularity zero blocks are detected at
+	BlockSize = 64 * 1024
+	// ChunkSize is the largest unit of data read at once, and the largest payload of a frame
+	ChunkSize = 8 * 1024 * 1024
+
+	frameData = byte('D')
+	frameZero = byte('Z')
+
+	// lseek whence values, not defined by the syscall package
+	seekData = 3
+	seekHole = 4
+)
+
+var zeroBlock = make([]byte, BlockSize)
+
+// Extent is a range of the source
+type Extent struct {
+	Offset int64
+	Length int64
+}
+
+// Chunk is a range of the source assigned to a stream, Hole is true if the range is known to read as zeroes
+type Chunk struct {
+	Extent
+	Hole bool
+}
+
+// DataExtents returns the ranges of the file holding data, using SEEK_DATA and SEEK_HOLE. If the file system does
+// not support finding holes the whole file is returned as a single extent. Block devices always report a single
+// extent, their zero blocks are found while reading.
+func DataExtents(f *os.File, size int64) ([]Extent, error) {
+	var result []Extent
+	for offset := int64(0); offset < size; {
+		start, err := f.Seek(offset, seekData)
+		if err != nil {
+			if errors.Is(err, syscall.ENXIO) {
+				// No data after offset, the rest of the file is a hole
+				break
+			}
+			if errors.Is(err, syscall.EINVAL) && offset == 0 {
+				klog.V(3).Infof("Finding holes not supported on %s, reading everything", f.Name())
+				return []Extent{{Offset: 0, Length: size}}, nil
+			}
+			return nil, errors.Wrapf(err, "could not find data after %d in %s", offset, f.Name())
+		}
+		if start >= size {
+			break
+		}
+		end, err := f.Seek(start, seekHole)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not find hole after %d in %s", start, f.Name())
+		}
+		if end > size {
+			end = size
+		}
+		result = append(result, Extent{Offset: start, Length: end - start})
+		offset = end
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Plan covers the range [0, size) with chunks, data extents are split in chunks of at most ChunkSize and the holes
+// between them become hole chunks
+func Plan(extents []Extent, size int64) []Chunk {
+	var chunks []Chunk
+	offset := int64(0)
+	for _, extent := range extents {
+		if extent.Offset > offset {
+			chunks = append(chunks, Chunk{Extent: Extent{Offset: offset, Length: extent.Offset - offset}, Hole: true})
+		}
+		for start := extent.Offset; start < extent.Offset+extent.Length; start += ChunkSize {
+			length := extent.Offset + extent.Length - start
+			if length > ChunkSize {
+				length = ChunkSize
+			}
+			chunks = append(chunks, Chunk{Extent: Extent{Offset: start, Length: length}})
+		}
+		offset = extent.Offset + extent.Length
+	}
+	if offset < size {
+		chunks = append(chunks, Chunk{Extent: Extent{Offset: offset, Length: size - offset}, Hole: true})
+	}
+	return chunks
+}
+
+// Partition spreads the chunks round robin over n streams, so every stream reads from the whole source
+func Partition(chunks []Chunk, n int) [][]Chunk {
+	if n < 1 {
+		n = 1
+	}
+	streams := make([][]Chunk, n)
+	for i, chunk := range chunks {
+		streams[i%n] = append(streams[i%n], chunk)
+	}
+	return streams
+}
+
+// WriteChunks reads the data chunks from src and writes them to w as frames, zero blocks and hole chunks are written
+// as frames without payload. progress, if not nil, is called with the number of source bytes each frame covers.
+func WriteChunks(w io.Writer, src io.ReaderAt, chunks []Chunk, progress func(int64)) error {
+	buf := make([]byte, ChunkSize)
+	for _, chunk := range chunks {
+		if chunk.Hole {
+			if err := writeFrame(w, frameZero, chunk.Offset, chunk.Length, nil); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(chunk.Length)
+			}
+			continue
+		}
+
+		data := buf[:chunk.Length]
+		if n, err := src.ReadAt(data, chunk.Offset); err != nil && !(err == io.EOF && int64(n) == chunk.Length) {
+			return errors.Wrapf(err, "could not read %d bytes at %d", chunk.Length, chunk.Offset)
+		}
+		if err := writeRuns(w, data, chunk.Offset); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(chunk.Length)
+		}
+	}
+	return nil
+}
+
+// writeRuns writes consecutive zero blocks and consecutive data blocks of data as one frame each
+func writeRuns(w io.Writer, data []byte, offset int64) error {
+	start := 0
+	startZero := false
+	for pos := 0; pos < len(data); pos += BlockSize {
+		end := pos + BlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		zero := bytes.Equal(data[pos:end], zeroBlock[:end-pos])
+		if pos > start && zero != startZero {
+			if err := writeRun(w, data[start:pos], offset+int64(start), startZero); err != nil {
+				return err
+			}
+			start = pos
+		}
+		if pos == start {
+			startZero = zero
+		}
+	}
+	if start < len(data) {
+		return writeRun(w, data[start:], offset+int64(start), startZero)
+	}
+	return nil
+}
+
+func writeRun(w io.Writer, data []byte, offset int64, zero bool) error {
+	if zero {
+		return writeFrame(w, frameZero, offset, int64(len(data)), nil)
+	}
+	return writeFrame(w, frameData, offset, int64(len(data)), data)
+}
+
+func writeFrame(w io.Writer, frameType byte, offset, length int64, data []byte) error {
+	var header [17]byte
+	header[0] = frameType
+	binary.BigEndian.PutUint64(header[1:9], uint64(offset))
+	binary.BigEndian.PutUint64(header[9:17], uint64(length))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if data != nil {
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sink is where frames are applied
+type Sink interface {
+	io.WriterAt
+	// ZeroRange makes the range read as zeroes
+	ZeroRange(offset, length int64) error
+}
+
+// ReadFrames applies the frames read from r to sink until r is exhausted, it returns the number of bytes of data
+// written
+func ReadFrames(r io.Reader, sink Sink) (int64, error) {
+	var header [17]byte
+	buf := make([]byte, ChunkSize)
+	written := int64(0)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return written, nil
+			}
+			return written, errors.Wrap(err, "could not read frame header")
+		}
+		offset := int64(binary.BigEndian.Uint64(header[1:9]))
+		length := int64(binary.BigEndian.Uint64(header[9:17]))
+		if offset < 0 || length < 0 {
+			return written, errors.Errorf("invalid frame at %d with length %d", offset, length)
+		}
+
+		switch header[0] {
+		case frameZero:
+			if err := sink.ZeroRange(offset, length); err != nil {
+				return written, errors.Wrapf(err, "could not zero %d bytes at %d", length, offset)
+			}
+		case frameData:
+			if length > ChunkSize {
+				return written, errors.Errorf("frame at %d with length %d exceeds %d", offset, length, ChunkSize)
+			}
+			data := buf[:length]
+			if _, err := io.ReadFull(r, data); err != nil {
+				return written, errors.Wrapf(err, "could not read %d bytes of frame at %d", length, offset)
+			}
+			if _, err := sink.WriteAt(data, offset); err != nil {
+				return written, errors.Wrapf(err, "could not write %d bytes at %d", length, offset)
+			}
+			written += length
+		default:
+			return written, errors.Errorf("unknown frame type %q", header[0])
+		}
+	}
+}
+
+// FileSink applies frames to a regular file or a block device
+type FileSink struct {
+	file    *os.File
+	isBlock bool
+}
+
+// NewFileSink opens path to apply frames to. A regular file is truncated and then extended to size, so holes need no
+// writes. On a block device holes are punched, falling back to writing zeroes.
+func NewFileSink(path string, size int64) (*FileSink, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeDevice != 0 {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &FileSink{file: file, isBlock: true}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "could not extend %s to %d bytes", path, size)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// WriteAt writes data at offset, it is safe for concurrent use
+func (s *FileSink) WriteAt(data []byte, offset int64) (int, error) {
+	return s.file.WriteAt(data, offset)
+}
+
+// ZeroRange makes the range read as zeroes
+func (s *FileSink) ZeroRange(offset, length int64) error {
+	if !s.isBlock || length == 0 {
+		return nil
+	}
+	err := syscall.Fallocate(int(s.file.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, offset, length)
+	if err == nil {
+		return nil
+	}
+	klog.V(3).Infof("Unable to punch hole %d - %d, falling back to writing zeroes: %v", offset, offset+length, err)
+	zeroes := make([]byte, ChunkSize)
+	for length > 0 {
+		n := length
+		if n > ChunkSize {
+			n = ChunkSize
+		}
+		if _, err := s.file.WriteAt(zeroes[:n], offset); err != nil {
+			return err
+		}
+		offset += n
+		length -= n
+	}
+	return nil
+}
+
+// Close syncs and closes the target
+func (s *FileSink) Close() error {
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
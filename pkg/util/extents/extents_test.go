@@ -0,0 +1,136 @@
+package extents
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingSink struct {
+	data  map[int64][]byte
+	zeros []Extent
+}
+
+func (s *recordingSink) WriteAt(data []byte, offset int64) (int, error) {
+	s.data[offset] = append([]byte{}, data...)
+	return len(data), nil
+}
+
+func (s *recordingSink) ZeroRange(offset, length int64) error {
+	s.zeros = append(s.zeros, Extent{Offset: offset, Length: length})
+	return nil
+}
+
+var _ = Describe("Plan", func() {
+	It("Should cover holes and split data extents", func() {
+		chunks := Plan([]Extent{{Offset: 4096, Length: ChunkSize + 1}}, 3*ChunkSize)
+		Expect(chunks).To(Equal([]Chunk{
+			{Extent: Extent{Offset: 0, Length: 4096}, Hole: true},
+			{Extent: Extent{Offset: 4096, Length: ChunkSize}},
+			{Extent: Extent{Offset: 4096 + ChunkSize, Length: 1}},
+			{Extent: Extent{Offset: 4097 + ChunkSize, Length: 2*ChunkSize - 4097}, Hole: true},
+		}))
+	})
+
+	It("Should plan a single hole without extents", func() {
+		Expect(Plan(nil, 1024)).To(Equal([]Chunk{{Extent: Extent{Offset: 0, Length: 1024}, Hole: true}}))
+	})
+
+	It("Should spread chunks round robin", func() {
+		chunks := Plan([]Extent{{Offset: 0, Length: 3 * ChunkSize}}, 3*ChunkSize)
+		streams := Partition(chunks, 2)
+		Expect(streams).To(HaveLen(2))
+		Expect(streams[0]).To(Equal([]Chunk{chunks[0], chunks[2]}))
+		Expect(streams[1]).To(Equal([]Chunk{chunks[1]}))
+	})
+})
+
+var _ = Describe("Frames", func() {
+	It("Should send zero blocks without payload", func() {
+		data := make([]byte, 3*BlockSize)
+		data[0] = 1
+		data[2*BlockSize+1] = 2
+
+		var buf bytes.Buffer
+		Expect(WriteChunks(&buf, bytes.NewReader(data), []Chunk{{Extent: Extent{Offset: 0, Length: int64(len(data))}}}, nil)).To(Succeed())
+		Expect(buf.Len()).To(BeNumerically("<", len(data)))
+
+		sink := &recordingSink{data: map[int64][]byte{}}
+		written, err := ReadFrames(&buf, sink)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(written).To(Equal(int64(2 * BlockSize)))
+		Expect(sink.data).To(HaveLen(2))
+		Expect(sink.data[0]).To(Equal(data[:BlockSize]))
+		Expect(sink.data[2*BlockSize]).To(Equal(data[2*BlockSize:]))
+		Expect(sink.zeros).To(Equal([]Extent{{Offset: BlockSize, Length: BlockSize}}))
+	})
+
+	It("Should reject an unknown frame", func() {
+		_, err := ReadFrames(bytes.NewReader(append([]byte{'X'}, make([]byte, 16)...)), &recordingSink{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should reject a truncated frame", func() {
+		var buf bytes.Buffer
+		Expect(writeFrame(&buf, frameData, 0, 10, []byte("short"))).To(Succeed())
+		_, err := ReadFrames(&buf, &recordingSink{data: map[int64][]byte{}})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Sparse file clone", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "extents")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("Should copy a sparse file over several streams", func() {
+		size := int64(4*ChunkSize + 12345)
+		source, err := os.Create(filepath.Join(tempDir, "source"))
+		Expect(err).ToNot(HaveOccurred())
+		defer source.Close()
+		Expect(source.Truncate(size)).To(Succeed())
+		_, err = source.WriteAt(bytes.Repeat([]byte("a"), 3*BlockSize), 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = source.WriteAt(bytes.Repeat([]byte("b"), 100), 2*ChunkSize+BlockSize)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = source.WriteAt([]byte("end"), size-3)
+		Expect(err).ToNot(HaveOccurred())
+
+		extents, err := DataExtents(source, size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extents).ToNot(BeEmpty())
+
+		sink, err := NewFileSink(filepath.Join(tempDir, "target"), size)
+		Expect(err).ToNot(HaveOccurred())
+
+		sent := 0
+		for _, chunks := range Partition(Plan(extents, size), 3) {
+			var buf bytes.Buffer
+			Expect(WriteChunks(&buf, source, chunks, nil)).To(Succeed())
+			sent += buf.Len()
+			_, err := ReadFrames(&buf, sink)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(sink.Close()).To(Succeed())
+		Expect(int64(sent)).To(BeNumerically("<", size/4))
+
+		expected, err := ioutil.ReadFile(filepath.Join(tempDir, "source"))
+		Expect(err).ToNot(HaveOccurred())
+		actual, err := ioutil.ReadFile(filepath.Join(tempDir, "target"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(HaveLen(len(expected)))
+		Expect(bytes.Equal(actual, expected)).To(BeTrue())
+	})
+})
@@ -0,0 +1,92 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// ProgressReport is the structured progress of the transfer done by a worker pod, the controller reads it from the
+// progress endpoint of the pod.
+type ProgressReport struct {
+	// OwnerUID is the UID of the entity the pod populates
+	OwnerUID string `json:"ownerUID"`
+	// Phase is the processing phase of the pod
+	Phase string `json:"phase,omitempty"`
+	// BytesTransferred is the number of bytes read from the source
+	BytesTransferred uint64 `json:"bytesTransferred"`
+	// TotalBytes is the number of bytes to read from the source
+	TotalBytes uint64 `json:"totalBytes"`
+	// Progress is the transfer progress in percentage
+	Progress float64 `json:"progress"`
+	// Throughput is the number of bytes read per second since the previous update
+	Throughput uint64 `json:"throughput"`
+	// EstimatedSecondsRemaining is the time needed to read the remaining bytes at the current throughput
+	EstimatedSecondsRemaining int64 `json:"estimatedSecondsRemaining,omitempty"`
+}
+
+var (
+	reportLock sync.Mutex
+	report     = ProgressReport{}
+)
+
+// SetProgressPhase records the processing phase of the pod in the progress report.
+func SetProgressPhase(phase string) {
+	reportLock.Lock()
+	defer reportLock.Unlock()
+	report.Phase = phase
+}
+
+// GetProgressReport returns a copy of the current progress report.
+func GetProgressReport() ProgressReport {
+	reportLock.Lock()
+	defer reportLock.Unlock()
+	return report
+}
+
+// updateReport records the bytes read by a progress reader, the throughput is computed from the bytes read since
+// the previous update.
+func updateReport(ownerUID string, current, total uint64, progress float64, previous uint64, elapsed time.Duration) {
+	reportLock.Lock()
+	defer reportLock.Unlock()
+
+	report.OwnerUID = ownerUID
+	report.BytesTransferred = current
+	report.TotalBytes = total
+	report.Progress = progress
+	if elapsed > 0 && current >= previous {
+		report.Throughput = uint64(float64(current-previous) / elapsed.Seconds())
+	}
+	report.EstimatedSecondsRemaining = 0
+	if report.Throughput > 0 && total > current {
+		report.EstimatedSecondsRemaining = int64((total - current) / report.Throughput)
+	}
+}
+
+// progressHandler serves the progress report to clients presenting a certificate of the progress client.
+func progressHandler(w http.ResponseWriter, r *http.Request) {
+	if !isProgressClient(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(GetProgressReport()); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func isProgressClient(r *http.Request) bool {
+	if r.TLS == nil {
+		return false
+	}
+	for _, chain := range r.TLS.VerifiedChains {
+		if len(chain) > 0 && chain[0].Subject.CommonName == common.ProgressClientName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Progress report", func() {
+	BeforeEach(func() {
+		reportLock.Lock()
+		report = ProgressReport{}
+		reportLock.Unlock()
+	})
+
+	It("Should compute the throughput and remaining time", func() {
+		SetProgressPhase("TransferDataFile")
+		updateReport(ownerUID, 600, 1000, 60, 400, 2*time.Second)
+		Expect(GetProgressReport()).To(Equal(ProgressReport{
+			OwnerUID:                  ownerUID,
+			Phase:                     "TransferDataFile",
+			BytesTransferred:          600,
+			TotalBytes:                1000,
+			Progress:                  60,
+			Throughput:                100,
+			EstimatedSecondsRemaining: 4,
+		}))
+	})
+
+	It("Should not estimate the remaining time when done", func() {
+		updateReport(ownerUID, 400, 1000, 40, 200, time.Second)
+		updateReport(ownerUID, 1000, 1000, 100, 400, time.Second)
+		result := GetProgressReport()
+		Expect(result.Throughput).To(Equal(uint64(600)))
+		Expect(result.EstimatedSecondsRemaining).To(BeZero())
+	})
+
+	It("Should keep the throughput without elapsed time", func() {
+		updateReport(ownerUID, 400, 1000, 40, 200, time.Second)
+		updateReport(ownerUID, 400, 1000, 40, 400, 0)
+		Expect(GetProgressReport().Throughput).To(Equal(uint64(200)))
+	})
+
+	Context("endpoint", func() {
+		serve := func(state *tls.ConnectionState) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, common.ProgressPath, nil)
+			req.TLS = state
+			w := httptest.NewRecorder()
+			progressHandler(w, req)
+			return w
+		}
+
+		verifiedAs := func(commonName string) *tls.ConnectionState {
+			return &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}},
+			}
+		}
+
+		It("Should serve the report to the progress client", func() {
+			updateReport(ownerUID, 450, 1000, 45, 0, time.Second)
+			w := serve(verifiedAs(common.ProgressClientName))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			result := ProgressReport{}
+			Expect(json.NewDecoder(w.Body).Decode(&result)).To(Succeed())
+			Expect(result).To(Equal(GetProgressReport()))
+		})
+
+		It("Should reject requests without a client certificate", func() {
+			Expect(serve(nil).Code).To(Equal(http.StatusUnauthorized))
+			Expect(serve(&tls.ConnectionState{}).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Should reject other clients", func() {
+			Expect(serve(verifiedAs("client.upload-server.cdi.kubevirt.io")).Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -1,10 +1,13 @@
 package prometheus
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"path"
 	"time"
 
@@ -14,15 +17,21 @@ import (
 	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
+const defaultMetricsPort = 8443
+
 // ProgressReader is a counting reader that reports progress to prometheus.
 type ProgressReader struct {
 	util.CountingReader
 	total    uint64
 	progress *prometheus.CounterVec
 	ownerUID string
+	// previous and lastUpdate are the bytes read and the time of the previous update, to compute the throughput
+	previous   uint64
+	lastUpdate time.Time
 }
 
 // NewProgressReader creates a new instance of a prometheus updating progress reader.
@@ -58,14 +67,22 @@ func (r *ProgressReader) timedUpdateProgress() {
 func (r *ProgressReader) updateProgress() bool {
 	if r.total > 0 {
 		currentProgress := 100.0
-		if !r.Done && r.Current < r.total {
-			currentProgress = float64(r.Current) / float64(r.total) * 100.0
+		current := r.Count()
+		if !r.Done && current < r.total {
+			currentProgress = float64(current) / float64(r.total) * 100.0
 		}
 		metric := &dto.Metric{}
 		r.progress.WithLabelValues(r.ownerUID).Write(metric)
 		if currentProgress > *metric.Counter.Value {
 			r.progress.WithLabelValues(r.ownerUID).Add(currentProgress - *metric.Counter.Value)
 		}
+		now := time.Now()
+		var elapsed time.Duration
+		if !r.lastUpdate.IsZero() {
+			elapsed = now.Sub(r.lastUpdate)
+		}
+		updateReport(r.ownerUID, current, r.total, currentProgress, r.previous, elapsed)
+		r.previous, r.lastUpdate = current, now
 		klog.V(1).Infoln(fmt.Sprintf("%.2f", currentProgress))
 		return !r.Done
 	}
@@ -73,10 +90,28 @@ func (r *ProgressReader) updateProgress() bool {
 }
 
 // StartPrometheusEndpoint starts an http server providing a prometheus endpoint using the passed
-// in directory to store the self signed certificates that will be generated before starting the
-// http server.
+// in directory to store the certificates. The server uses the certificate issued by the controller if
+// there is one, and self signed certificates that will be generated before starting the http server
+// otherwise. The progress report is served to the controller if the pod got the CA of its client
+// certificate.
 func StartPrometheusEndpoint(certsDirectory string) {
-	certBytes, keyBytes, err := cert.GenerateSelfSignedCertKey("cloner_target", nil, nil)
+	StartMetricsEndpoint(certsDirectory, prometheus.DefaultGatherer)
+}
+
+// StartPrometheusEndpointOnPort starts the prometheus endpoint like StartPrometheusEndpoint, listening on the
+// passed in port instead of 8443.
+func StartPrometheusEndpointOnPort(certsDirectory string, port int) {
+	startMetricsEndpoint(certsDirectory, port, prometheus.DefaultGatherer)
+}
+
+// StartMetricsEndpoint starts the prometheus endpoint like StartPrometheusEndpoint, serving the metrics
+// collected by the passed in gatherer.
+func StartMetricsEndpoint(certsDirectory string, gatherer prometheus.Gatherer) {
+	startMetricsEndpoint(certsDirectory, defaultMetricsPort, gatherer)
+}
+
+func startMetricsEndpoint(certsDirectory string, port int, gatherer prometheus.Gatherer) {
+	certBytes, keyBytes, err := getServerCertKey()
 	if err != nil {
 		klog.Error("Error generating cert for prometheus")
 		return
@@ -94,10 +129,35 @@ func StartPrometheusEndpoint(certsDirectory string) {
 		return
 	}
 
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+
+	if clientCA := os.Getenv(common.ProgressClientCA); clientCA != "" {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(clientCA)); !ok {
+			klog.Error("Invalid progress client CA cert")
+		} else {
+			// prometheus scrapes the metrics without a client certificate
+			server.TLSConfig = &tls.Config{
+				ClientCAs:  caCertPool,
+				ClientAuth: tls.VerifyClientCertIfGiven,
+			}
+			mux.HandleFunc(common.ProgressPath, progressHandler)
+		}
+	}
+
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		if err := http.ListenAndServeTLS(":8443", certFile, keyFile, nil); err != nil {
+		if err := server.ListenAndServeTLS(certFile, keyFile); err != nil {
 			return
 		}
 	}()
 }
+
+func getServerCertKey() ([]byte, []byte, error) {
+	serverCert, serverKey := os.Getenv(common.ProgressServerCert), os.Getenv(common.ProgressServerKey)
+	if serverCert != "" && serverKey != "" {
+		return []byte(serverCert), []byte(serverKey), nil
+	}
+	return cert.GenerateSelfSignedCertKey("cloner_target", nil, nil)
+}
@@ -3,8 +3,13 @@ package util
 import (
 	"bufio"
 	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"math"
@@ -14,6 +19,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -29,6 +35,13 @@ const (
 	blockdevFileName = "/usr/sbin/blockdev"
 )
 
+// checksumAlgorithms maps the supported checksum algorithms to their hash constructors
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"md5":    md5.New,
+}
+
 // CountingReader is a reader that keeps track of how much has been read
 type CountingReader struct {
 	Reader  io.ReadCloser
@@ -83,11 +96,16 @@ func ParseEnvVar(envVarName string, decode bool) (string, error) {
 // Read reads bytes from the stream and updates the prometheus clone_progress metric according to the progress.
 func (r *CountingReader) Read(p []byte) (n int, err error) {
 	n, err = r.Reader.Read(p)
-	r.Current += uint64(n)
+	atomic.AddUint64(&r.Current, uint64(n))
 	r.Done = err == io.EOF
 	return n, err
 }
 
+// Count returns the number of bytes read so far, it is safe to call while another goroutine reads from the stream.
+func (r *CountingReader) Count() uint64 {
+	return atomic.LoadUint64(&r.Current)
+}
+
 // Close closes the stream
 func (r *CountingReader) Close() error {
 	return r.Reader.Close()
@@ -204,6 +222,52 @@ func UnArchiveTar(reader io.Reader, destDir string, arg ...string) error {
 	return nil
 }
 
+// execReader streams the standard output of a subprocess, and reports its failure once the output is consumed
+type execReader struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	stderr *bytes.Buffer
+}
+
+func (er *execReader) Read(p []byte) (n int, err error) {
+	n, err = er.stdout.Read(p)
+	if err == io.EOF {
+		if err2 := er.cmd.Wait(); err2 != nil {
+			return n, errors.Errorf("subprocess did not execute successfully, result is: %q\n%s", er.cmd.ProcessState.ExitCode(), er.stderr.String())
+		}
+	}
+	return
+}
+
+func (er *execReader) Close() error {
+	return er.stdout.Close()
+}
+
+// NewTarReader returns a reader streaming a tar archive of the contents of the passed in directory. Sparse files are
+// archived as such when sparse is true.
+func NewTarReader(dir string, sparse bool) (io.ReadCloser, error) {
+	args := "cv"
+	if sparse {
+		args = "S" + args
+	}
+	cmd := exec.Command("/usr/bin/tar", args, ".")
+	cmd.Dir = dir
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &execReader{cmd: cmd, stdout: stdout, stderr: &stderr}, nil
+}
+
 // CopyFile copies a file from one location to another.
 func CopyFile(src, dst string) error {
 	in, err := os.Open(src)
@@ -292,3 +356,22 @@ func RoundUp(number, multiple int64) int64 {
 	partitions := math.Ceil(float64(number) / float64(multiple))
 	return int64(partitions) * multiple
 }
+
+// ParseChecksum parses a checksum of the form <algorithm>:<hex value>. It returns a new hash for the algorithm
+// and the expected value in lower case.
+func ParseChecksum(checksum string) (hash.Hash, string, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 {
+		return nil, "", errors.Errorf("checksum %q is not of the form <algorithm>:<hex value>", checksum)
+	}
+	newHash, ok := checksumAlgorithms[parts[0]]
+	if !ok {
+		return nil, "", errors.Errorf("unsupported checksum algorithm %q, must be one of sha256, sha512 or md5", parts[0])
+	}
+	h := newHash()
+	value := strings.ToLower(parts[1])
+	if decoded, err := hex.DecodeString(value); err != nil || len(decoded) != h.Size() {
+		return nil, "", errors.Errorf("checksum value %q is not a valid %s hex digest", parts[1], parts[0])
+	}
+	return h, value, nil
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -150,6 +151,29 @@ var _ = Describe("Copy files", func() {
 	})
 })
 
+var _ = Describe("Parse checksum", func() {
+	table.DescribeTable("should parse", func(checksum, expected string, size int) {
+		h, value, err := ParseChecksum(checksum)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal(expected))
+		Expect(h.Size()).To(Equal(size))
+	},
+		table.Entry("sha256", "sha256:"+strings.Repeat("a", 64), strings.Repeat("a", 64), 32),
+		table.Entry("sha512", "sha512:"+strings.Repeat("0", 128), strings.Repeat("0", 128), 64),
+		table.Entry("md5 in upper case", "md5:"+strings.Repeat("F", 32), strings.Repeat("f", 32), 16),
+	)
+
+	table.DescribeTable("should reject", func(checksum string) {
+		_, _, err := ParseChecksum(checksum)
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("missing algorithm", strings.Repeat("a", 64)),
+		table.Entry("unknown algorithm", "sha1:"+strings.Repeat("a", 40)),
+		table.Entry("wrong length", "sha256:"+strings.Repeat("a", 32)),
+		table.Entry("not hex", "md5:"+strings.Repeat("g", 32)),
+	)
+})
+
 func md5sum(filePath string) (string, error) {
 	var returnMD5String string
 
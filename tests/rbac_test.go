@@ -226,6 +226,7 @@ var _ = Describe("Aggregated role definition tests", func() {
 			},
 			Resources: []string{
 				"uploadtokenrequests",
+				"downloadtokenrequests",
 			},
 			Verbs: []string{
 				"*",
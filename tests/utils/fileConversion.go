@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 	"github.com/ulikunitz/xz"
 
@@ -18,6 +19,8 @@ import (
 var formatTable = map[string]func(string, string, string) (string, error){
 	image.ExtGz:    toGz,
 	image.ExtXz:    toXz,
+	image.ExtZst:   toZst,
+	image.ExtBz2:   toBz2,
 	image.ExtTar:   toTar,
 	image.ExtQcow2: convertUsingQemuImg,
 	image.ExtVmdk:  convertUsingQemuImg,
@@ -134,6 +137,42 @@ func toXz(src, tgtDir, ext string) (string, error) {
 	return tgtPath, nil
 }
 
+func toZst(src, tgtDir, ext string) (string, error) {
+	tgtFile, tgtPath, _ := createTargetFile(src, tgtDir, image.ExtZst)
+	defer tgtFile.Close()
+
+	w, err := zstd.NewWriter(tgtFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error getting zstd writer for file %s", tgtPath)
+	}
+	defer w.Close()
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error opening file %s", src)
+	}
+	defer srcFile.Close()
+
+	_, err = io.Copy(w, srcFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error writing to file %s", tgtPath)
+	}
+	return tgtPath, nil
+}
+
+// toBz2 uses the bzip2 command, the standard library can only decompress bzip2
+func toBz2(src, tgtDir, ext string) (string, error) {
+	tgtFile, tgtPath, _ := createTargetFile(src, tgtDir, image.ExtBz2)
+	defer tgtFile.Close()
+
+	cmd := exec.Command("bzip2", "-c", src)
+	cmd.Stdout = tgtFile
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "Error writing to file %s", tgtPath)
+	}
+	return tgtPath, nil
+}
+
 func convertUsingQemuImg(srcfile, tgtDir, ext string) (string, error) {
 	base := strings.TrimSuffix(filepath.Base(srcfile), ".iso")
 	tgt := filepath.Join(tgtDir, base+ext)